@@ -0,0 +1,59 @@
+package db
+
+import (
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+type AsyncTaskDAO struct{}
+
+func NewAsyncTaskDAO() *AsyncTaskDAO { return &AsyncTaskDAO{} }
+
+func (d *AsyncTaskDAO) Create(task *po.AsyncTask) error {
+	return DB.Create(task).Error
+}
+
+func (d *AsyncTaskDAO) Save(task *po.AsyncTask) error {
+	return DB.Save(task).Error
+}
+
+func (d *AsyncTaskDAO) FindByTaskID(taskID string) (*po.AsyncTask, error) {
+	var task po.AsyncTask
+	err := DB.Where("task_id = ?", taskID).First(&task).Error
+	return &task, err
+}
+
+func (d *AsyncTaskDAO) List(taskType, status string, page, pageSize int) ([]po.AsyncTask, int64, error) {
+	q := DB.Model(&po.AsyncTask{})
+	if taskType != "" {
+		q = q.Where("type = ?", taskType)
+	}
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var tasks []po.AsyncTask
+	offset := (page - 1) * pageSize
+	err := q.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&tasks).Error
+	return tasks, total, err
+}
+
+// MarkUnfinished 将未完成（排队/运行中）的任务标记为指定状态，用于服务重启后的恢复
+func (d *AsyncTaskDAO) MarkUnfinished(status, errMsg string) (int64, error) {
+	res := DB.Model(&po.AsyncTask{}).
+		Where("status IN ?", []string{po.AsyncTaskStatusQueued, po.AsyncTaskStatusRunning}).
+		Updates(map[string]interface{}{"status": status, "error": errMsg, "end_time": time.Now()})
+	return res.RowsAffected, res.Error
+}
+
+// DeleteFinishedBefore 删除指定时间之前结束的任务
+func (d *AsyncTaskDAO) DeleteFinishedBefore(t time.Time) (int64, error) {
+	res := DB.Where("status IN ? AND end_time < ?",
+		[]string{po.AsyncTaskStatusSuccess, po.AsyncTaskStatusFailed, po.AsyncTaskStatusCancelled}, t).
+		Unscoped().Delete(&po.AsyncTask{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+type BranchPolicyDAO struct{}
+
+func NewBranchPolicyDAO() *BranchPolicyDAO {
+	return &BranchPolicyDAO{}
+}
+
+func (d *BranchPolicyDAO) Save(policy *po.BranchPolicy) error {
+	return DB.Save(policy).Error
+}
+
+func (d *BranchPolicyDAO) FindByID(id uint) (*po.BranchPolicy, error) {
+	var policy po.BranchPolicy
+	err := DB.First(&policy, id).Error
+	return &policy, err
+}
+
+func (d *BranchPolicyDAO) FindByRepoAndBranch(repoKey, branch string) (*po.BranchPolicy, error) {
+	var policy po.BranchPolicy
+	err := DB.Where("repo_key = ? AND branch = ?", repoKey, branch).First(&policy).Error
+	return &policy, err
+}
+
+// FindAll repoKey 不为空时只返回该仓库的策略
+func (d *BranchPolicyDAO) FindAll(repoKey string) ([]po.BranchPolicy, error) {
+	var policies []po.BranchPolicy
+	query := DB.Order("repo_key ASC, branch ASC")
+	if repoKey != "" {
+		query = query.Where("repo_key = ?", repoKey)
+	}
+	err := query.Find(&policies).Error
+	return policies, err
+}
+
+// Delete 物理删除，避免软删除记录占用 (repo_key, branch) 唯一索引
+func (d *BranchPolicyDAO) Delete(id uint) error {
+	return DB.Unscoped().Delete(&po.BranchPolicy{}, id).Error
+}
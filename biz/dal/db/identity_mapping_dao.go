@@ -0,0 +1,65 @@
+// biz/dal/db/identity_mapping_dao.go - Git 身份映射DAO
+
+package db
+
+import (
+	"strings"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+type IdentityMappingDAO struct{}
+
+func NewIdentityMappingDAO() *IdentityMappingDAO {
+	return &IdentityMappingDAO{}
+}
+
+func (d *IdentityMappingDAO) Create(m *po.IdentityMapping) error {
+	return DB.Create(m).Error
+}
+
+func (d *IdentityMappingDAO) Save(m *po.IdentityMapping) error {
+	return DB.Save(m).Error
+}
+
+func (d *IdentityMappingDAO) FindByID(id uint) (*po.IdentityMapping, error) {
+	var m po.IdentityMapping
+	err := DB.First(&m, id).Error
+	return &m, err
+}
+
+// FindByEmails 按邮箱（不区分大小写）批量查询映射
+func (d *IdentityMappingDAO) FindByEmails(emails []string) ([]po.IdentityMapping, error) {
+	if len(emails) == 0 {
+		return nil, nil
+	}
+	lower := make([]string, 0, len(emails))
+	for _, e := range emails {
+		lower = append(lower, strings.ToLower(strings.TrimSpace(e)))
+	}
+	var mappings []po.IdentityMapping
+	err := DB.Where("git_email IN ?", lower).Find(&mappings).Error
+	return mappings, err
+}
+
+// List 分页查询映射，keyword 匹配邮箱或显示名
+func (d *IdentityMappingDAO) List(keyword string, page, pageSize int) ([]po.IdentityMapping, int64, error) {
+	q := DB.Model(&po.IdentityMapping{})
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		q = q.Where("git_email LIKE ? OR display_name LIKE ?", like, like)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var mappings []po.IdentityMapping
+	offset := (page - 1) * pageSize
+	err := q.Order("git_email ASC").Offset(offset).Limit(pageSize).Find(&mappings).Error
+	return mappings, total, err
+}
+
+// Delete 物理删除，释放邮箱唯一索引
+func (d *IdentityMappingDAO) Delete(id uint) error {
+	return DB.Unscoped().Delete(&po.IdentityMapping{}, id).Error
+}
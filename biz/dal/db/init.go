@@ -56,7 +56,9 @@ func Init() {
 	// 检查所有必需的表是否存在，包括代码质量相关的表
 	if migrator.HasTable(&po.Repo{}) &&
 		migrator.HasTable(&po.SyncTask{}) &&
+		migrator.HasColumn(&po.SyncTask{}, "auto_cr") &&
 		migrator.HasTable(&po.SyncRun{}) &&
+		migrator.HasColumn(&po.SyncRun{}, "cr_number") &&
 		migrator.HasTable(&po.AuditLog{}) &&
 		migrator.HasTable(&po.SystemConfig{}) &&
 		migrator.HasTable(&po.CommitStat{}) &&
@@ -70,14 +72,40 @@ func Init() {
 		migrator.HasTable(&po.CommitPattern{}) &&
 		migrator.HasTable(&po.SyncRecommendation{}) &&
 		migrator.HasTable(&po.ProviderConfig{}) &&
+		migrator.HasColumn(&po.ProviderConfig{}, "options_json") &&
 		migrator.HasTable(&po.ChangeRequest{}) &&
+		migrator.HasColumn(&po.ChangeRequest{}, "head_sha") &&
+		migrator.HasColumn(&po.ChangeRequest{}, "sync_run_id") &&
 		migrator.HasTable(&po.WebhookEvent{}) &&
-		migrator.HasTable(&po.WebhookRule{}) {
+		migrator.HasColumn(&po.WebhookEvent{}, "next_retry_at") &&
+		migrator.HasTable(&po.WebhookRule{}) &&
+		migrator.HasColumn(&po.WebhookRule{}, "condition_expr") &&
+		migrator.HasTable(&po.AsyncTask{}) &&
+		migrator.HasTable(&po.WebhookActionExecution{}) &&
+		migrator.HasTable(&po.WebhookEventPayload{}) &&
+		migrator.HasTable(&po.WebhookSyncRoute{}) &&
+		migrator.HasTable(&po.RepoImport{}) &&
+		migrator.HasTable(&po.RepoImportItem{}) &&
+		migrator.HasColumn(&po.RepoImportItem{}, "repo_name") &&
+		migrator.HasColumn(&po.RepoImport{}, "plan_task_id") &&
+		migrator.HasTable(&po.BranchPolicy{}) &&
+		migrator.HasTable(&po.NotificationDelivery{}) &&
+		migrator.HasColumn(&po.NotificationDelivery{}, "data_json") &&
+		migrator.HasTable(&po.NotificationRoutingRule{}) &&
+		migrator.HasColumn(&po.SyncTask{}, "tags_json") &&
+		migrator.HasColumn(&po.NotificationChannel{}, "delivery_mode") &&
+		migrator.HasTable(&po.NotificationDigestItem{}) &&
+		migrator.HasTable(&po.NotificationAlert{}) &&
+		migrator.HasColumn(&po.NotificationChannel{}, "escalation") &&
+		migrator.HasColumn(&po.NotificationChannel{}, "quiet_hours") &&
+		migrator.HasTable(&po.IdentityMapping{}) &&
+		migrator.HasColumn(&po.NotificationDelivery{}, "mentions_json") &&
+		migrator.HasColumn(&po.NotificationDelivery{}, "severity") {
 		log.Println("Database tables exist, skipping schema migration.")
 		return
 	}
 
-	err = DB.AutoMigrate(&po.Repo{}, &po.SyncTask{}, &po.SyncRun{}, &po.AuditLog{}, &po.SystemConfig{}, &po.CommitStat{}, &po.NotificationChannel{}, &po.NotificationEventTemplate{}, &po.SSHKey{}, &po.BackupRecord{}, &po.Credential{}, &po.LintRule{}, &po.CommitAnalysis{}, &po.CommitPattern{}, &po.SyncRecommendation{}, &po.ProviderConfig{}, &po.ChangeRequest{}, &po.WebhookEvent{}, &po.WebhookRule{})
+	err = DB.AutoMigrate(&po.Repo{}, &po.SyncTask{}, &po.SyncRun{}, &po.AuditLog{}, &po.SystemConfig{}, &po.CommitStat{}, &po.NotificationChannel{}, &po.NotificationEventTemplate{}, &po.SSHKey{}, &po.BackupRecord{}, &po.Credential{}, &po.LintRule{}, &po.CommitAnalysis{}, &po.CommitPattern{}, &po.SyncRecommendation{}, &po.ProviderConfig{}, &po.ChangeRequest{}, &po.WebhookEvent{}, &po.WebhookRule{}, &po.AsyncTask{}, &po.WebhookActionExecution{}, &po.WebhookEventPayload{}, &po.WebhookSyncRoute{}, &po.RepoImport{}, &po.RepoImportItem{}, &po.BranchPolicy{}, &po.NotificationDelivery{}, &po.NotificationRoutingRule{}, &po.NotificationDigestItem{}, &po.NotificationAlert{}, &po.IdentityMapping{})
 	if err != nil {
 		log.Fatal("failed to migrate database: ", err)
 	}
@@ -0,0 +1,79 @@
+// biz/dal/db/notification_alert_dao.go - 通知告警状态DAO
+
+package db
+
+import (
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+type NotificationAlertDAO struct{}
+
+func NewNotificationAlertDAO() *NotificationAlertDAO {
+	return &NotificationAlertDAO{}
+}
+
+func (d *NotificationAlertDAO) Create(alert *po.NotificationAlert) error {
+	return DB.Create(alert).Error
+}
+
+func (d *NotificationAlertDAO) Save(alert *po.NotificationAlert) error {
+	return DB.Save(alert).Error
+}
+
+func (d *NotificationAlertDAO) FindByID(id uint) (*po.NotificationAlert, error) {
+	var alert po.NotificationAlert
+	err := DB.First(&alert, id).Error
+	return &alert, err
+}
+
+// FindFiring 查询对象在某事件上正在进行的告警
+func (d *NotificationAlertDAO) FindFiring(subjectKey, eventType string) (*po.NotificationAlert, error) {
+	var alert po.NotificationAlert
+	err := DB.Where("subject_key = ? AND event_type = ? AND state = ?", subjectKey, eventType, po.AlertStateFiring).
+		Order("id DESC").First(&alert).Error
+	return &alert, err
+}
+
+// FindFiringBySubject 查询对象在给定事件上所有正在进行的告警
+func (d *NotificationAlertDAO) FindFiringBySubject(subjectKey string, eventTypes []string) ([]po.NotificationAlert, error) {
+	var alerts []po.NotificationAlert
+	err := DB.Where("subject_key = ? AND event_type IN ? AND state = ?", subjectKey, eventTypes, po.AlertStateFiring).
+		Order("id ASC").Find(&alerts).Error
+	return alerts, err
+}
+
+func (d *NotificationAlertDAO) List(state, taskKey, repoKey string, page, pageSize int) ([]po.NotificationAlert, int64, error) {
+	q := DB.Model(&po.NotificationAlert{})
+	if state != "" {
+		q = q.Where("state = ?", state)
+	}
+	if taskKey != "" {
+		q = q.Where("task_key = ?", taskKey)
+	}
+	if repoKey != "" {
+		q = q.Where("repo_key = ?", repoKey)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var alerts []po.NotificationAlert
+	offset := (page - 1) * pageSize
+	err := q.Order("last_failed_at DESC").Offset(offset).Limit(pageSize).Find(&alerts).Error
+	return alerts, total, err
+}
+
+// CountByState 按状态统计告警数量
+func (d *NotificationAlertDAO) CountByState(state string) (int64, error) {
+	var count int64
+	err := DB.Model(&po.NotificationAlert{}).Where("state = ?", state).Count(&count).Error
+	return count, err
+}
+
+// DeleteResolvedBefore 删除 before 之前已恢复的告警
+func (d *NotificationAlertDAO) DeleteResolvedBefore(before time.Time) (int64, error) {
+	res := DB.Unscoped().Where("state = ? AND resolved_at < ?", po.AlertStateResolved, before).Delete(&po.NotificationAlert{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,103 @@
+// biz/dal/db/notification_delivery_dao.go - 通知投递记录DAO
+
+package db
+
+import (
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+type NotificationDeliveryDAO struct{}
+
+func NewNotificationDeliveryDAO() *NotificationDeliveryDAO {
+	return &NotificationDeliveryDAO{}
+}
+
+func (d *NotificationDeliveryDAO) Create(delivery *po.NotificationDelivery) error {
+	return DB.Create(delivery).Error
+}
+
+func (d *NotificationDeliveryDAO) Save(delivery *po.NotificationDelivery) error {
+	return DB.Save(delivery).Error
+}
+
+func (d *NotificationDeliveryDAO) FindByID(id uint) (*po.NotificationDelivery, error) {
+	var delivery po.NotificationDelivery
+	err := DB.First(&delivery, id).Error
+	return &delivery, err
+}
+
+func (d *NotificationDeliveryDAO) List(channelID uint, triggerEvent, status string, page, pageSize int) ([]po.NotificationDelivery, int64, error) {
+	q := DB.Model(&po.NotificationDelivery{})
+	if channelID > 0 {
+		q = q.Where("channel_id = ?", channelID)
+	}
+	if triggerEvent != "" {
+		q = q.Where("trigger_event = ?", triggerEvent)
+	}
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var deliveries []po.NotificationDelivery
+	offset := (page - 1) * pageSize
+	err := q.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+// Claim 将投递从 fromStatuses 之一原子地切换为 sending，返回是否抢占成功
+func (d *NotificationDeliveryDAO) Claim(id uint, fromStatuses []string) (bool, error) {
+	res := DB.Model(&po.NotificationDelivery{}).
+		Where("id = ? AND status IN ?", id, fromStatuses).
+		Update("status", po.DeliveryStatusSending)
+	return res.RowsAffected == 1, res.Error
+}
+
+// FindDueRetries 查找到期需要自动重试的失败投递，以及静默时段已结束的延后投递
+func (d *NotificationDeliveryDAO) FindDueRetries(now time.Time, limit int) ([]po.NotificationDelivery, error) {
+	var deliveries []po.NotificationDelivery
+	err := DB.Where("status IN ? AND next_retry_at IS NOT NULL AND next_retry_at <= ?",
+		[]string{po.DeliveryStatusFailed, po.DeliveryStatusDeferred}, now).
+		Order("next_retry_at ASC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ResetInterrupted 将进程退出时仍在待发送 / 发送中的投递恢复为失败并立即可重试
+func (d *NotificationDeliveryDAO) ResetInterrupted(now time.Time) (int64, error) {
+	res := DB.Model(&po.NotificationDelivery{}).
+		Where("status IN ?", []string{po.DeliveryStatusPending, po.DeliveryStatusSending}).
+		Updates(map[string]interface{}{
+			"status":        po.DeliveryStatusFailed,
+			"next_retry_at": now,
+			"last_error":    "interrupted by service restart",
+		})
+	return res.RowsAffected, res.Error
+}
+
+// DeliveryStatusCount 按渠道、状态分组的投递数
+type DeliveryStatusCount struct {
+	ChannelID uint
+	Status    string
+	Count     int64
+}
+
+// CountByChannelStatus 统计 since 之后创建的投递，按渠道与状态分组
+func (d *NotificationDeliveryDAO) CountByChannelStatus(since time.Time) ([]DeliveryStatusCount, error) {
+	var rows []DeliveryStatusCount
+	err := DB.Model(&po.NotificationDelivery{}).
+		Select("channel_id, status, COUNT(*) AS count").
+		Where("created_at >= ?", since).
+		Group("channel_id, status").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// DeleteBefore 物理删除早于 t 的投递记录
+func (d *NotificationDeliveryDAO) DeleteBefore(t time.Time) (int64, error) {
+	res := DB.Unscoped().Where("created_at < ?", t).Delete(&po.NotificationDelivery{})
+	return res.RowsAffected, res.Error
+}
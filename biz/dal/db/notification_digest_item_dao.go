@@ -0,0 +1,62 @@
+// biz/dal/db/notification_digest_item_dao.go - 通知汇总队列DAO
+
+package db
+
+import (
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"gorm.io/gorm"
+)
+
+type NotificationDigestItemDAO struct{}
+
+func NewNotificationDigestItemDAO() *NotificationDigestItemDAO {
+	return &NotificationDigestItemDAO{}
+}
+
+func (d *NotificationDigestItemDAO) Create(item *po.NotificationDigestItem) error {
+	return DB.Create(item).Error
+}
+
+// PendingChannelIDs 查询有待汇总事件的渠道
+func (d *NotificationDigestItemDAO) PendingChannelIDs() ([]uint, error) {
+	var ids []uint
+	err := DB.Model(&po.NotificationDigestItem{}).Distinct().Pluck("channel_id", &ids).Error
+	return ids, err
+}
+
+// FindOldest 查询渠道最早入队的事件
+func (d *NotificationDigestItemDAO) FindOldest(channelID uint) (*po.NotificationDigestItem, error) {
+	var item po.NotificationDigestItem
+	err := DB.Where("channel_id = ?", channelID).Order("created_at ASC").First(&item).Error
+	return &item, err
+}
+
+// CountByChannelID 统计渠道待汇总事件数量
+func (d *NotificationDigestItemDAO) CountByChannelID(channelID uint) (int64, error) {
+	var count int64
+	err := DB.Model(&po.NotificationDigestItem{}).Where("channel_id = ?", channelID).Count(&count).Error
+	return count, err
+}
+
+// FindByChannelBefore 查询渠道在 before 之前入队的事件
+func (d *NotificationDigestItemDAO) FindByChannelBefore(channelID uint, before time.Time, limit int) ([]po.NotificationDigestItem, error) {
+	var items []po.NotificationDigestItem
+	err := DB.Where("channel_id = ? AND created_at < ?", channelID, before).
+		Order("created_at ASC").Limit(limit).Find(&items).Error
+	return items, err
+}
+
+// DeleteByIDs 删除已合并发送的事件
+func (d *NotificationDigestItemDAO) DeleteByIDs(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Unscoped().Where("id IN ?", ids).Delete(&po.NotificationDigestItem{}).Error
+}
+
+// DeleteByChannelID 删除渠道的所有待汇总事件
+func (d *NotificationDigestItemDAO) DeleteByChannelID(tx *gorm.DB, channelID uint) error {
+	return tx.Unscoped().Where("channel_id = ?", channelID).Delete(&po.NotificationDigestItem{}).Error
+}
@@ -0,0 +1,44 @@
+// biz/dal/db/notification_routing_rule_dao.go - 通知路由规则DAO
+
+package db
+
+import (
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"gorm.io/gorm"
+)
+
+type NotificationRoutingRuleDAO struct{}
+
+func NewNotificationRoutingRuleDAO() *NotificationRoutingRuleDAO {
+	return &NotificationRoutingRuleDAO{}
+}
+
+// FindAll 查询所有渠道的路由规则
+func (d *NotificationRoutingRuleDAO) FindAll() ([]po.NotificationRoutingRule, error) {
+	var rules []po.NotificationRoutingRule
+	err := DB.Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// FindByChannelID 查询渠道的路由规则
+func (d *NotificationRoutingRuleDAO) FindByChannelID(channelID uint) ([]po.NotificationRoutingRule, error) {
+	var rules []po.NotificationRoutingRule
+	err := DB.Where("channel_id = ?", channelID).Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// ReplaceByChannelID 替换渠道的所有路由规则（在给定事务中先删后插）
+func (d *NotificationRoutingRuleDAO) ReplaceByChannelID(tx *gorm.DB, channelID uint, rules []po.NotificationRoutingRule) error {
+	if err := d.DeleteByChannelID(tx, channelID); err != nil {
+		return err
+	}
+	if len(rules) > 0 {
+		return tx.Create(&rules).Error
+	}
+	return nil
+}
+
+// DeleteByChannelID 删除渠道的所有路由规则
+func (d *NotificationRoutingRuleDAO) DeleteByChannelID(tx *gorm.DB, channelID uint) error {
+	return tx.Unscoped().Where("channel_id = ?", channelID).Delete(&po.NotificationRoutingRule{}).Error
+}
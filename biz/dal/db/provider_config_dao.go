@@ -43,3 +43,8 @@ func (d *ProviderConfigDAO) ExistsByName(name string) (bool, error) {
 	err := DB.Model(&po.ProviderConfig{}).Where("name = ?", name).Count(&count).Error
 	return count > 0, err
 }
+
+// UpdateWebhookEndpoint 只更新接收地址，避免 Save 时重复加密 webhook_secret
+func (d *ProviderConfigDAO) UpdateWebhookEndpoint(id uint, endpoint string) error {
+	return DB.Model(&po.ProviderConfig{}).Where("id = ?", id).UpdateColumn("webhook_endpoint", endpoint).Error
+}
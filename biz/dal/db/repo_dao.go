@@ -46,3 +46,17 @@ func (d *RepoDAO) FindByID(id uint) (*po.Repo, error) {
 	err := DB.First(&repo, id).Error
 	return &repo, err
 }
+
+// ExistsByName 仓库名是否已被注册
+func (d *RepoDAO) ExistsByName(name string) (bool, error) {
+	var count int64
+	err := DB.Model(&po.Repo{}).Where("name = ?", name).Count(&count).Error
+	return count > 0, err
+}
+
+// FindByPlatform 按平台关联查询已注册的仓库
+func (d *RepoDAO) FindByPlatform(providerConfigID uint, owner, repo string) (*po.Repo, error) {
+	var r po.Repo
+	err := DB.Where("provider_config_id = ? AND platform_owner = ? AND platform_repo = ?", providerConfigID, owner, repo).First(&r).Error
+	return &r, err
+}
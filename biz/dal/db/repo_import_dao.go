@@ -0,0 +1,72 @@
+package db
+
+import (
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+type RepoImportDAO struct{}
+
+func NewRepoImportDAO() *RepoImportDAO { return &RepoImportDAO{} }
+
+func (d *RepoImportDAO) Create(job *po.RepoImport) error {
+	return DB.Create(job).Error
+}
+
+func (d *RepoImportDAO) FindByID(id uint) (*po.RepoImport, error) {
+	var job po.RepoImport
+	err := DB.First(&job, id).Error
+	return &job, err
+}
+
+func (d *RepoImportDAO) List(page, pageSize int) ([]po.RepoImport, int64, error) {
+	var jobs []po.RepoImport
+	var total int64
+	query := DB.Model(&po.RepoImport{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&jobs).Error
+	return jobs, total, err
+}
+
+// UpdateStatus 只更新状态列，避免 Save 覆盖 JSON 字段
+func (d *RepoImportDAO) UpdateStatus(id uint, status, errMsg string) error {
+	return DB.Model(&po.RepoImport{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"status": status, "error_message": errMsg}).Error
+}
+
+func (d *RepoImportDAO) UpdatePlanTaskID(id uint, taskID string) error {
+	return DB.Model(&po.RepoImport{}).Where("id = ?", id).UpdateColumn("plan_task_id", taskID).Error
+}
+
+// FinishRunning 仅在任务仍为 running 时更新状态，避免覆盖已标记的失败
+func (d *RepoImportDAO) FinishRunning(id uint, status, errMsg string) error {
+	return DB.Model(&po.RepoImport{}).Where("id = ? AND status = ?", id, po.RepoImportStatusRunning).
+		UpdateColumns(map[string]interface{}{"status": status, "error_message": errMsg}).Error
+}
+
+func (d *RepoImportDAO) CreateItem(item *po.RepoImportItem) error {
+	return DB.Create(item).Error
+}
+
+func (d *RepoImportDAO) SaveItem(item *po.RepoImportItem) error {
+	return DB.Save(item).Error
+}
+
+// UpdateItemTaskID 单独写入克隆任务 ID，避免覆盖已先行完成的回调结果
+func (d *RepoImportDAO) UpdateItemTaskID(id uint, taskID string) error {
+	return DB.Model(&po.RepoImportItem{}).Where("id = ?", id).UpdateColumn("clone_task_id", taskID).Error
+}
+
+func (d *RepoImportDAO) FindItems(importID uint) ([]po.RepoImportItem, error) {
+	var items []po.RepoImportItem
+	err := DB.Where("import_id = ?", importID).Order("id ASC").Find(&items).Error
+	return items, err
+}
+
+// CountItemsByStatus 统计导入任务中处于指定状态的仓库数
+func (d *RepoImportDAO) CountItemsByStatus(importID uint, status string) (int64, error) {
+	var count int64
+	err := DB.Model(&po.RepoImportItem{}).Where("import_id = ? AND status = ?", importID, status).Count(&count).Error
+	return count, err
+}
@@ -34,6 +34,12 @@ func (d *SyncRunDAO) FindByTaskKeys(taskKeys []string, limit int) ([]po.SyncRun,
 	return runs, err
 }
 
+func (d *SyncRunDAO) FindByID(id uint) (*po.SyncRun, error) {
+	var run po.SyncRun
+	err := DB.Preload("Task").Preload("Task.SourceRepo").First(&run, id).Error
+	return &run, err
+}
+
 func (d *SyncRunDAO) Delete(id uint) error {
 	return DB.Delete(&po.SyncRun{}, id).Error
 }
@@ -71,3 +71,11 @@ func (d *SyncTaskDAO) FindByWebhookToken(token string) (*po.SyncTask, error) {
 		Where("webhook_token = ? AND webhook_token != ''", token).First(&task).Error
 	return &task, err
 }
+
+// FindEnabledWithRepos 返回所有启用的任务（含源/目标仓库）
+func (d *SyncTaskDAO) FindEnabledWithRepos() ([]po.SyncTask, error) {
+	var tasks []po.SyncTask
+	err := DB.Preload("SourceRepo").Preload("TargetRepo").
+		Where("enabled = ?", true).Find(&tasks).Error
+	return tasks, err
+}
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"time"
+
 	"github.com/yi-nology/git-manage-service/biz/model/po"
 )
 
@@ -39,10 +41,44 @@ func (d *WebhookEventDAO) List(eventType, source, status string, page, pageSize
 	return events, total, err
 }
 
+func (d *WebhookEventDAO) FindByID(id uint) (*po.WebhookEvent, error) {
+	var event po.WebhookEvent
+	err := DB.First(&event, id).Error
+	return &event, err
+}
+
 func (d *WebhookEventDAO) Save(event *po.WebhookEvent) error {
 	return DB.Save(event).Error
 }
 
+// Claim 将事件从 fromStatuses 之一原子地切换为 processing，返回是否抢占成功
+func (d *WebhookEventDAO) Claim(id uint, fromStatuses []string) (bool, error) {
+	res := DB.Model(&po.WebhookEvent{}).
+		Where("id = ? AND status IN ?", id, fromStatuses).
+		Update("status", po.WebhookEventStatusProcessing)
+	return res.RowsAffected == 1, res.Error
+}
+
+// FindDueRetries 查找到期需要自动重试的失败事件
+func (d *WebhookEventDAO) FindDueRetries(now time.Time, limit int) ([]po.WebhookEvent, error) {
+	var events []po.WebhookEvent
+	err := DB.Where("status = ? AND next_retry_at IS NOT NULL AND next_retry_at <= ?", po.WebhookEventStatusFailed, now).
+		Order("next_retry_at ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// ResetProcessing 将进程退出时仍在处理中的事件恢复为失败并立即可重试
+func (d *WebhookEventDAO) ResetProcessing(now time.Time) (int64, error) {
+	res := DB.Model(&po.WebhookEvent{}).
+		Where("status = ?", po.WebhookEventStatusProcessing).
+		Updates(map[string]interface{}{
+			"status":        po.WebhookEventStatusFailed,
+			"next_retry_at": now,
+			"error_message": "interrupted by service restart",
+		})
+	return res.RowsAffected, res.Error
+}
+
 type WebhookRuleDAO struct{}
 
 func NewWebhookRuleDAO() *WebhookRuleDAO { return &WebhookRuleDAO{} }
@@ -57,6 +93,22 @@ func (d *WebhookRuleDAO) FindAll() ([]po.WebhookRule, error) {
 	return rules, err
 }
 
+func (d *WebhookRuleDAO) FindByID(id uint) (*po.WebhookRule, error) {
+	var rule po.WebhookRule
+	err := DB.First(&rule, id).Error
+	return &rule, err
+}
+
+func (d *WebhookRuleDAO) List(providerConfigID uint) ([]po.WebhookRule, error) {
+	var rules []po.WebhookRule
+	q := DB.Model(&po.WebhookRule{})
+	if providerConfigID > 0 {
+		q = q.Where("provider_config_id = ?", providerConfigID)
+	}
+	err := q.Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
 func (d *WebhookRuleDAO) FindByProviderConfigID(providerConfigID uint) ([]po.WebhookRule, error) {
 	var rules []po.WebhookRule
 	err := DB.Where("provider_config_id = ? OR provider_config_id = 0", providerConfigID).Where("enabled = ?", true).Find(&rules).Error
@@ -70,3 +122,70 @@ func (d *WebhookRuleDAO) Save(rule *po.WebhookRule) error {
 func (d *WebhookRuleDAO) Delete(id uint) error {
 	return DB.Delete(&po.WebhookRule{}, id).Error
 }
+
+type WebhookActionExecutionDAO struct{}
+
+func NewWebhookActionExecutionDAO() *WebhookActionExecutionDAO { return &WebhookActionExecutionDAO{} }
+
+func (d *WebhookActionExecutionDAO) Create(exec *po.WebhookActionExecution) error {
+	return DB.Create(exec).Error
+}
+
+func (d *WebhookActionExecutionDAO) Save(exec *po.WebhookActionExecution) error {
+	return DB.Save(exec).Error
+}
+
+func (d *WebhookActionExecutionDAO) FindByEventID(webhookEventID uint) ([]po.WebhookActionExecution, error) {
+	var execs []po.WebhookActionExecution
+	err := DB.Where("webhook_event_id = ?", webhookEventID).Order("id ASC").Find(&execs).Error
+	return execs, err
+}
+
+type WebhookEventPayloadDAO struct{}
+
+func NewWebhookEventPayloadDAO() *WebhookEventPayloadDAO { return &WebhookEventPayloadDAO{} }
+
+func (d *WebhookEventPayloadDAO) Create(payload *po.WebhookEventPayload) error {
+	return DB.Create(payload).Error
+}
+
+func (d *WebhookEventPayloadDAO) FindByEventID(webhookEventID uint) (*po.WebhookEventPayload, error) {
+	var payload po.WebhookEventPayload
+	err := DB.Where("webhook_event_id = ?", webhookEventID).First(&payload).Error
+	return &payload, err
+}
+
+// DeleteBefore 物理删除早于 t 的原始报文
+func (d *WebhookEventPayloadDAO) DeleteBefore(t time.Time) (int64, error) {
+	res := DB.Unscoped().Where("created_at < ?", t).Delete(&po.WebhookEventPayload{})
+	return res.RowsAffected, res.Error
+}
+
+type WebhookSyncRouteDAO struct{}
+
+func NewWebhookSyncRouteDAO() *WebhookSyncRouteDAO {
+	return &WebhookSyncRouteDAO{}
+}
+
+func (d *WebhookSyncRouteDAO) Create(route *po.WebhookSyncRoute) error {
+	return DB.Create(route).Error
+}
+
+func (d *WebhookSyncRouteDAO) Save(route *po.WebhookSyncRoute) error {
+	return DB.Save(route).Error
+}
+
+func (d *WebhookSyncRouteDAO) FindByEventID(webhookEventID uint) ([]po.WebhookSyncRoute, error) {
+	var routes []po.WebhookSyncRoute
+	err := DB.Where("webhook_event_id = ?", webhookEventID).Order("id ASC").Find(&routes).Error
+	return routes, err
+}
+
+// UpdateRunResult 回填一组路由记录对应的同步执行结果
+func (d *WebhookSyncRouteDAO) UpdateRunResult(ids []uint, runID uint, status string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return DB.Model(&po.WebhookSyncRoute{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{"sync_run_id": runID, "run_status": status}).Error
+}
@@ -0,0 +1,150 @@
+package branchpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/service/audit"
+	"github.com/yi-nology/git-manage-service/biz/service/branchpolicy"
+	"github.com/yi-nology/git-manage-service/biz/service/provider"
+	pkgresponse "github.com/yi-nology/git-manage-service/pkg/response"
+)
+
+// GetProtection 查询平台上的分支保护设置，branch 为空时取默认分支
+// @router /api/v1/branch-protection [GET]
+func GetProtection(ctx context.Context, c *app.RequestContext) {
+	repoKey := c.Query("repo_key")
+	if repoKey == "" {
+		pkgresponse.BadRequest(c, "repo_key is required")
+		return
+	}
+	result, err := branchpolicy.GetProtection(ctx, repoKey, c.Query("branch"))
+	if err != nil {
+		protectionError(c, err)
+		return
+	}
+	pkgresponse.Success(c, result)
+}
+
+// SetProtection 直接写入平台分支保护
+// @router /api/v1/branch-protection [PUT]
+func SetProtection(ctx context.Context, c *app.RequestContext) {
+	var req api.BranchProtectionDTO
+	if err := c.BindJSON(&req); err != nil {
+		pkgresponse.BadRequest(c, "invalid JSON: "+err.Error())
+		return
+	}
+	if req.RepoKey == "" || req.Branch == "" {
+		pkgresponse.BadRequest(c, "repo_key and branch are required")
+		return
+	}
+	result, err := branchpolicy.SetProtection(ctx, &req)
+	if err != nil {
+		protectionError(c, err)
+		return
+	}
+	audit.AuditSvc.Log(c, "SET_BRANCH_PROTECTION", fmt.Sprintf("repo:%s branch:%s", req.RepoKey, req.Branch), result)
+	pkgresponse.Success(c, result)
+}
+
+// ListPolicies 列出分支保护策略，repo_key 为空时返回全部
+// @router /api/v1/branch-protection/policies [GET]
+func ListPolicies(ctx context.Context, c *app.RequestContext) {
+	policies, err := branchpolicy.ListPolicies(c.Query("repo_key"))
+	if err != nil {
+		pkgresponse.InternalServerError(c, err.Error())
+		return
+	}
+	pkgresponse.Success(c, map[string]interface{}{"items": policies, "total": len(policies)})
+}
+
+// SavePolicy 新增或覆盖 (repo_key, branch) 的策略
+// @router /api/v1/branch-protection/policies [POST]
+func SavePolicy(ctx context.Context, c *app.RequestContext) {
+	var req api.BranchPolicyReq
+	if err := c.BindJSON(&req); err != nil {
+		pkgresponse.BadRequest(c, "invalid JSON: "+err.Error())
+		return
+	}
+	policy, err := branchpolicy.SavePolicy(&req)
+	if err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	audit.AuditSvc.Log(c, "SAVE_BRANCH_POLICY", fmt.Sprintf("branch_policy:%d", policy.ID), policy)
+	pkgresponse.Success(c, policy)
+}
+
+// DeletePolicy 删除策略
+// @router /api/v1/branch-protection/policies/:id [DELETE]
+func DeletePolicy(ctx context.Context, c *app.RequestContext) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		pkgresponse.BadRequest(c, "Invalid ID")
+		return
+	}
+	if err := branchpolicy.DeletePolicy(uint(id)); err != nil {
+		pkgresponse.NotFound(c, "Policy not found")
+		return
+	}
+	audit.AuditSvc.Log(c, "DELETE_BRANCH_POLICY", fmt.Sprintf("branch_policy:%d", id), nil)
+	pkgresponse.Success(c, map[string]string{"message": "deleted"})
+}
+
+// Compliance 对比实际分支保护与策略，repo_keys 为逗号分隔的仓库 key，为空时检查全部
+// @router /api/v1/branch-protection/compliance [GET]
+func Compliance(ctx context.Context, c *app.RequestContext) {
+	var repoKeys []string
+	for _, key := range strings.Split(c.Query("repo_keys"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			repoKeys = append(repoKeys, key)
+		}
+	}
+	report, err := branchpolicy.Compliance(ctx, repoKeys)
+	if err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	pkgresponse.Success(c, report)
+}
+
+// Apply 按策略修正不合规的分支保护；dry_run 时等同于合规检查
+// @router /api/v1/branch-protection/apply [POST]
+func Apply(ctx context.Context, c *app.RequestContext) {
+	var req api.BranchPolicyApplyReq
+	if err := c.BindJSON(&req); err != nil {
+		pkgresponse.BadRequest(c, "invalid JSON: "+err.Error())
+		return
+	}
+	if req.DryRun {
+		report, err := branchpolicy.Compliance(ctx, req.RepoKeys)
+		if err != nil {
+			pkgresponse.BadRequest(c, err.Error())
+			return
+		}
+		pkgresponse.Success(c, report)
+		return
+	}
+	report, err := branchpolicy.Apply(ctx, req.RepoKeys)
+	if err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	audit.AuditSvc.Log(c, "APPLY_BRANCH_POLICY", "branch_protection", map[string]int{
+		"applied": report.Applied, "non_compliant": report.NonCompliant, "errors": report.Errors,
+	})
+	pkgresponse.Success(c, report)
+}
+
+func protectionError(c *app.RequestContext, err error) {
+	if errors.Is(err, provider.ErrUnsupported) {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	pkgresponse.InternalServerError(c, err.Error())
+}
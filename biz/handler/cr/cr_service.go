@@ -2,6 +2,7 @@ package cr
 
 import (
 	"context"
+	"errors"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/yi-nology/git-manage-service/biz/dal/db"
@@ -83,7 +84,11 @@ func Merge(ctx context.Context, c *app.RequestContext) {
 		pkgresponse.BadRequest(c, "repo_key and cr_number are required")
 		return
 	}
-	cr, err := crservice.MergeCR(ctx, req.RepoKey, req.CRNumber, req.MergeCommitMessage, req.Squash, req.RemoveSourceBranch)
+	cr, err := crservice.MergeCR(ctx, req.RepoKey, req.CRNumber, req.MergeCommitMessage, req.Squash, req.RemoveSourceBranch, req.RequireChecks)
+	if errors.Is(err, crservice.ErrChecksNotPassed) {
+		pkgresponse.Conflict(c, err.Error())
+		return
+	}
 	if err != nil {
 		pkgresponse.InternalServerError(c, "Failed to merge CR: "+err.Error())
 		return
@@ -127,6 +132,85 @@ func Sync(ctx context.Context, c *app.RequestContext) {
 	pkgresponse.Success(c, map[string]interface{}{"synced_count": count})
 }
 
+func Comments(ctx context.Context, c *app.RequestContext) {
+	var req api.GetCRReq
+	if err := c.BindAndValidate(&req); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	if req.RepoKey == "" || req.CRNumber == 0 {
+		pkgresponse.BadRequest(c, "repo_key and cr_number are required")
+		return
+	}
+	comments, err := crservice.ListComments(ctx, req.RepoKey, req.CRNumber)
+	if err != nil {
+		pkgresponse.InternalServerError(c, "Failed to list comments: "+err.Error())
+		return
+	}
+	pkgresponse.Success(c, comments)
+}
+
+func Comment(ctx context.Context, c *app.RequestContext) {
+	var req api.AddCRCommentReq
+	if err := c.BindAndValidate(&req); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	if req.RepoKey == "" || req.CRNumber == 0 || req.Body == "" {
+		pkgresponse.BadRequest(c, "repo_key, cr_number and body are required")
+		return
+	}
+	if (req.Path == "") != (req.Line == 0) {
+		pkgresponse.BadRequest(c, "path and line must be set together for inline comments")
+		return
+	}
+	comment, err := crservice.AddComment(ctx, &req)
+	if err != nil {
+		pkgresponse.InternalServerError(c, "Failed to add comment: "+err.Error())
+		return
+	}
+	pkgresponse.Success(c, comment)
+}
+
+func Review(ctx context.Context, c *app.RequestContext) {
+	var req api.ReviewCRReq
+	if err := c.BindAndValidate(&req); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	if req.RepoKey == "" || req.CRNumber == 0 {
+		pkgresponse.BadRequest(c, "repo_key and cr_number are required")
+		return
+	}
+	if req.Action != "approve" && req.Action != "request_changes" {
+		pkgresponse.BadRequest(c, "action must be approve or request_changes")
+		return
+	}
+	if err := crservice.ReviewCR(ctx, &req); err != nil {
+		pkgresponse.InternalServerError(c, "Failed to review CR: "+err.Error())
+		return
+	}
+	pkgresponse.Success(c, nil)
+}
+
+func Checks(ctx context.Context, c *app.RequestContext) {
+	var req api.GetCRReq
+	if err := c.BindAndValidate(&req); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	if req.RepoKey == "" || req.CRNumber == 0 {
+		pkgresponse.BadRequest(c, "repo_key and cr_number are required")
+		return
+	}
+	checks, err := crservice.GetChecks(ctx, req.RepoKey, req.CRNumber)
+	if err != nil {
+		pkgresponse.InternalServerError(c, "Failed to get checks: "+err.Error())
+		return
+	}
+	pkgresponse.Success(c, checks)
+}
+
 func Detect(ctx context.Context, c *app.RequestContext) {
 	repoKey := c.Query("repo_key")
 	if repoKey == "" {
@@ -0,0 +1,72 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/service/audit"
+	notificationSvc "github.com/yi-nology/git-manage-service/biz/service/notification"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+	"github.com/yi-nology/git-manage-service/pkg/response"
+)
+
+// ListAlerts 查询告警状态，state 为 firing / resolved
+// @router /api/v1/notification/alerts [GET]
+func ListAlerts(ctx context.Context, c *app.RequestContext) {
+	var req api.ListNotificationAlertsReq
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	alerts, total, err := notificationSvc.NotifySvc.ListAlerts(&req)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list alerts: "+err.Error())
+		return
+	}
+	firing, err := notificationSvc.NotifySvc.CountFiringAlerts()
+	if err != nil {
+		response.InternalServerError(c, "Failed to count alerts: "+err.Error())
+		return
+	}
+	cfg := configs.GlobalConfig.Notification
+	response.Success(c, map[string]interface{}{
+		"items":  alerts,
+		"total":  total,
+		"firing": firing,
+		"policy": map[string]interface{}{
+			"dedup":            cfg.AlertDedup,
+			"reminder_minutes": cfg.AlertReminderMinutes,
+			"escalate_after":   cfg.EscalateAfter,
+		},
+	})
+}
+
+// ResolveAlert 手动关闭告警，不发送恢复通知
+// @router /api/v1/notification/alerts/resolve [POST]
+func ResolveAlert(ctx context.Context, c *app.RequestContext) {
+	var req struct {
+		AlertID uint `json:"alert_id"`
+	}
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if req.AlertID == 0 {
+		response.BadRequest(c, "alert_id is required")
+		return
+	}
+	if err := notificationSvc.NotifySvc.ResolveAlert(req.AlertID); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	audit.AuditSvc.Log(c, "NOTIFICATION_ALERT_RESOLVE", fmt.Sprintf("alert:%d", req.AlertID), nil)
+	response.Success(c, map[string]string{"message": "Alert resolved"})
+}
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/service/audit"
+	notificationSvc "github.com/yi-nology/git-manage-service/biz/service/notification"
+	"github.com/yi-nology/git-manage-service/pkg/response"
+)
+
+// ListDeliveries 查询通知投递记录
+// @router /api/v1/notification/deliveries [GET]
+func ListDeliveries(ctx context.Context, c *app.RequestContext) {
+	var req api.ListNotificationDeliveriesReq
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	deliveries, total, err := notificationSvc.NotifySvc.ListDeliveries(&req)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list deliveries: "+err.Error())
+		return
+	}
+	response.Success(c, map[string]interface{}{
+		"items": deliveries,
+		"total": total,
+	})
+}
+
+// ResendDelivery 重发一条投递，已成功的投递也可以重发
+// @router /api/v1/notification/deliveries/resend [POST]
+func ResendDelivery(ctx context.Context, c *app.RequestContext) {
+	var req struct {
+		DeliveryID uint `json:"delivery_id"`
+	}
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if req.DeliveryID == 0 {
+		response.BadRequest(c, "delivery_id is required")
+		return
+	}
+	if err := notificationSvc.NotifySvc.Resend(req.DeliveryID); err != nil {
+		response.InternalServerError(c, "Failed to resend: "+err.Error())
+		return
+	}
+	audit.AuditSvc.Log(c, "NOTIFICATION_RESEND", fmt.Sprintf("delivery:%d", req.DeliveryID), nil)
+	response.Success(c, map[string]string{"message": "Resend scheduled"})
+}
+
+// DeliveryStats 各渠道最近 days 天（默认 7）的投递成功率
+// @router /api/v1/notification/deliveries/stats [GET]
+func DeliveryStats(ctx context.Context, c *app.RequestContext) {
+	days := 7
+	if v := c.Query("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			response.BadRequest(c, "days must be a positive integer")
+			return
+		}
+		days = n
+	}
+	stats, err := notificationSvc.NotifySvc.DeliveryStats(days)
+	if err != nil {
+		response.InternalServerError(c, "Failed to load delivery stats: "+err.Error())
+		return
+	}
+	response.Success(c, map[string]interface{}{
+		"items": stats,
+		"total": len(stats),
+		"days":  days,
+	})
+}
@@ -0,0 +1,42 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/yi-nology/git-manage-service/biz/service/audit"
+	notificationSvc "github.com/yi-nology/git-manage-service/biz/service/notification"
+	"github.com/yi-nology/git-manage-service/pkg/response"
+)
+
+// FlushDigest 立即发送渠道汇总队列中的全部事件
+// @router /api/v1/notification/digest/flush [POST]
+func FlushDigest(ctx context.Context, c *app.RequestContext) {
+	var req struct {
+		ChannelID uint `json:"channel_id"`
+	}
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if req.ChannelID == 0 {
+		response.BadRequest(c, "channel_id is required")
+		return
+	}
+	n, err := notificationSvc.NotifySvc.FlushDigest(req.ChannelID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to flush digest: "+err.Error())
+		return
+	}
+	audit.AuditSvc.Log(c, "NOTIFICATION_DIGEST_FLUSH", fmt.Sprintf("channel:%d", req.ChannelID), map[string]int{"events": n})
+	response.Success(c, map[string]int{"events": n})
+}
+
+// DigestVariables 汇总模板可用变量
+// @router /api/v1/notification/digest/variables [GET]
+func DigestVariables(ctx context.Context, c *app.RequestContext) {
+	response.Success(c, map[string]interface{}{
+		"variables": notificationSvc.GetDigestVariables(),
+	})
+}
@@ -0,0 +1,97 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/service/audit"
+	notificationSvc "github.com/yi-nology/git-manage-service/biz/service/notification"
+	"github.com/yi-nology/git-manage-service/pkg/response"
+)
+
+// ListIdentities 查询 Git 邮箱与聊天账号的映射
+// @router /api/v1/notification/identities [GET]
+func ListIdentities(ctx context.Context, c *app.RequestContext) {
+	var req api.ListIdentityMappingsReq
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	items, total, err := notificationSvc.NotifySvc.ListIdentities(&req)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list identities: "+err.Error())
+		return
+	}
+	response.Success(c, map[string]interface{}{
+		"items": items,
+		"total": total,
+	})
+}
+
+// CreateIdentity 新增身份映射
+// @router /api/v1/notification/identity/create [POST]
+func CreateIdentity(ctx context.Context, c *app.RequestContext) {
+	var req api.IdentityMappingReq
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	m, err := notificationSvc.NotifySvc.CreateIdentity(&req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	audit.AuditSvc.Log(c, "NOTIFICATION_IDENTITY_CREATE", fmt.Sprintf("identity:%d", m.ID), m)
+	response.Success(c, m)
+}
+
+// UpdateIdentity 更新身份映射
+// @router /api/v1/notification/identity/update [POST]
+func UpdateIdentity(ctx context.Context, c *app.RequestContext) {
+	var req api.IdentityMappingReq
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if req.ID == 0 {
+		response.BadRequest(c, "id is required")
+		return
+	}
+	m, err := notificationSvc.NotifySvc.UpdateIdentity(&req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	audit.AuditSvc.Log(c, "NOTIFICATION_IDENTITY_UPDATE", fmt.Sprintf("identity:%d", m.ID), m)
+	response.Success(c, m)
+}
+
+// DeleteIdentity 删除身份映射
+// @router /api/v1/notification/identity/delete [POST]
+func DeleteIdentity(ctx context.Context, c *app.RequestContext) {
+	var req struct {
+		ID uint `json:"id"`
+	}
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if req.ID == 0 {
+		response.BadRequest(c, "id is required")
+		return
+	}
+	if err := notificationSvc.NotifySvc.DeleteIdentity(req.ID); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	audit.AuditSvc.Log(c, "NOTIFICATION_IDENTITY_DELETE", fmt.Sprintf("identity:%d", req.ID), nil)
+	response.Success(c, map[string]string{"message": "Identity mapping deleted"})
+}
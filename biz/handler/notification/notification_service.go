@@ -112,7 +112,9 @@ func GetChannel(ctx context.Context, c *app.RequestContext) {
 	}
 
 	response.Success(c, map[string]interface{}{
-		"channel": channelToProto(channel),
+		"channel":       channelToProto(channel),
+		"routing_rules": channelRoutingRules(channel.ID),
+		"delivery":      notificationSvc.NotifySvc.DeliverySettings(channel),
 	})
 }
 
@@ -154,6 +156,11 @@ func CreateChannel(ctx context.Context, c *app.RequestContext) {
 		}
 	}
 
+	extra, ok := bindChannelExtra(c)
+	if !ok {
+		return
+	}
+
 	channel := &po.NotificationChannel{
 		Name:            req.Name,
 		Type:            req.Type,
@@ -165,6 +172,10 @@ func CreateChannel(ctx context.Context, c *app.RequestContext) {
 		TitleTemplate:   req.TitleTemplate,
 		ContentTemplate: req.ContentTemplate,
 	}
+	if err := notificationSvc.ApplyDeliveryMode(channel, extra); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
 
 	// 使用事务创建渠道和事件模板
 	err := db.DB.Transaction(func(tx *gorm.DB) error {
@@ -185,6 +196,12 @@ func CreateChannel(ctx context.Context, c *app.RequestContext) {
 				return err
 			}
 		}
+		if extra.RoutingRules != nil {
+			rules, _ := notificationSvc.BuildRoutingRules(channel.ID, *extra.RoutingRules)
+			if len(rules) > 0 {
+				return tx.Create(&rules).Error
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -198,7 +215,9 @@ func CreateChannel(ctx context.Context, c *app.RequestContext) {
 	})
 
 	response.Success(c, map[string]interface{}{
-		"channel": channelToProto(channel),
+		"channel":       channelToProto(channel),
+		"routing_rules": channelRoutingRules(channel.ID),
+		"delivery":      notificationSvc.NotifySvc.DeliverySettings(channel),
 	})
 }
 
@@ -240,6 +259,11 @@ func UpdateChannel(ctx context.Context, c *app.RequestContext) {
 		}
 	}
 
+	extra, ok := bindChannelExtra(c)
+	if !ok {
+		return
+	}
+
 	dao := db.NewNotificationChannelDAO()
 	channel, err := dao.FindByID(uint(req.Id))
 	if err != nil {
@@ -255,6 +279,10 @@ func UpdateChannel(ctx context.Context, c *app.RequestContext) {
 	channel.TriggerEvents = req.TriggerEvents
 	channel.TitleTemplate = req.TitleTemplate
 	channel.ContentTemplate = req.ContentTemplate
+	if err := notificationSvc.ApplyDeliveryMode(channel, extra); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
 
 	// 使用事务更新渠道和事件模板
 	etDAO := db.NewNotificationEventTemplateDAO()
@@ -272,7 +300,15 @@ func UpdateChannel(ctx context.Context, c *app.RequestContext) {
 				ContentTemplate: dto.ContentTemplate,
 			})
 		}
-		return etDAO.ReplaceByChannelID(tx, channel.ID, templates)
+		if err := etDAO.ReplaceByChannelID(tx, channel.ID, templates); err != nil {
+			return err
+		}
+		// 未传 routing_rules 时保留原有路由规则
+		if extra.RoutingRules == nil {
+			return nil
+		}
+		rules, _ := notificationSvc.BuildRoutingRules(channel.ID, *extra.RoutingRules)
+		return db.NewNotificationRoutingRuleDAO().ReplaceByChannelID(tx, channel.ID, rules)
 	})
 	if err != nil {
 		response.InternalServerError(c, err.Error())
@@ -284,7 +320,9 @@ func UpdateChannel(ctx context.Context, c *app.RequestContext) {
 	})
 
 	response.Success(c, map[string]interface{}{
-		"channel": channelToProto(channel),
+		"channel":       channelToProto(channel),
+		"routing_rules": channelRoutingRules(channel.ID),
+		"delivery":      notificationSvc.NotifySvc.DeliverySettings(channel),
 	})
 }
 
@@ -304,12 +342,18 @@ func DeleteChannel(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
-	// 使用事务级联删除事件模板和渠道
+	// 使用事务级联删除事件模板、路由规则、汇总队列和渠道
 	etDAO := db.NewNotificationEventTemplateDAO()
 	err = db.DB.Transaction(func(tx *gorm.DB) error {
 		if err := etDAO.DeleteByChannelID(tx, channel.ID); err != nil {
 			return err
 		}
+		if err := db.NewNotificationRoutingRuleDAO().DeleteByChannelID(tx, channel.ID); err != nil {
+			return err
+		}
+		if err := db.NewNotificationDigestItemDAO().DeleteByChannelID(tx, channel.ID); err != nil {
+			return err
+		}
 		return tx.Delete(channel).Error
 	})
 	if err != nil {
@@ -0,0 +1,71 @@
+package notification
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	notificationSvc "github.com/yi-nology/git-manage-service/biz/service/notification"
+	"github.com/yi-nology/git-manage-service/pkg/response"
+)
+
+// PreviewRouting 预览一个事件会发送到哪些渠道，可带某个渠道的草稿路由规则
+// @router /api/v1/notification/routing/preview [POST]
+func PreviewRouting(ctx context.Context, c *app.RequestContext) {
+	var req api.RoutingPreviewReq
+	if err := c.BindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid JSON: "+err.Error())
+		return
+	}
+	if req.TriggerEvent == "" && req.Status == "" {
+		response.BadRequest(c, "trigger_event or status is required")
+		return
+	}
+	items, err := notificationSvc.NotifySvc.PreviewRouting(&req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	receivers := 0
+	for _, item := range items {
+		if item.Receive {
+			receivers++
+		}
+	}
+	response.Success(c, map[string]interface{}{
+		"items":     items,
+		"total":     len(items),
+		"receivers": receivers,
+	})
+}
+
+// bindChannelExtra 从 JSON 请求体绑定 proto 定义之外的渠道字段（路由规则、投递模式），
+// 并校验路由规则；校验失败时已写入响应
+func bindChannelExtra(c *app.RequestContext) (*api.NotificationChannelExtraReq, bool) {
+	var extra api.NotificationChannelExtraReq
+	if !strings.Contains(string(c.ContentType()), "json") {
+		return &extra, true
+	}
+	if err := c.BindJSON(&extra); err != nil {
+		response.BadRequest(c, err.Error())
+		return nil, false
+	}
+	if extra.RoutingRules != nil {
+		if _, err := notificationSvc.BuildRoutingRules(0, *extra.RoutingRules); err != nil {
+			response.BadRequest(c, err.Error())
+			return nil, false
+		}
+	}
+	return &extra, true
+}
+
+func channelRoutingRules(channelID uint) []po.NotificationRoutingRule {
+	rules, err := db.NewNotificationRoutingRuleDAO().FindByChannelID(channelID)
+	if err != nil || rules == nil {
+		return []po.NotificationRoutingRule{}
+	}
+	return rules
+}
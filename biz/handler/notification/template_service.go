@@ -0,0 +1,26 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	notificationSvc "github.com/yi-nology/git-manage-service/biz/service/notification"
+	"github.com/yi-nology/git-manage-service/pkg/response"
+)
+
+// PreviewTemplate 用示例或历史数据渲染渠道模板，返回各平台将要发送的请求体，不实际发送
+// @router /api/v1/notification/template/preview [POST]
+func PreviewTemplate(ctx context.Context, c *app.RequestContext) {
+	var req api.TemplatePreviewReq
+	if err := c.BindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid JSON: "+err.Error())
+		return
+	}
+	preview, err := notificationSvc.NotifySvc.PreviewTemplate(&req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	response.Success(c, preview)
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 
 	"github.com/cloudwego/hertz/pkg/app"
@@ -12,6 +13,16 @@ import (
 	pkgresponse "github.com/yi-nology/git-manage-service/pkg/response"
 )
 
+var supportedPlatforms = map[provider.Platform]bool{
+	provider.PlatformGitLab:    true,
+	provider.PlatformGitHub:    true,
+	provider.PlatformGitea:     true,
+	provider.PlatformBitbucket: true,
+	provider.PlatformGerrit:    true,
+	provider.PlatformAzure:     true,
+	provider.PlatformGeneric:   true,
+}
+
 func List(ctx context.Context, c *app.RequestContext) {
 	dao := db.NewProviderConfigDAO()
 	configs, err := dao.FindAll()
@@ -65,28 +76,47 @@ func Create(ctx context.Context, c *app.RequestContext) {
 		pkgresponse.BadRequest(c, "name and platform are required")
 		return
 	}
-	if req.Platform != "gitlab" && req.Platform != "github" && req.Platform != "gitea" {
-		pkgresponse.BadRequest(c, "platform must be gitlab, github or gitea")
+	if !supportedPlatforms[provider.Platform(req.Platform)] {
+		pkgresponse.BadRequest(c, "platform must be gitlab, github, gitea, bitbucket, gerrit, azure or generic")
 		return
 	}
-	if req.CredentialID == 0 {
+	generic := req.Platform == string(provider.PlatformGeneric)
+	if req.CredentialID == 0 && !generic {
 		pkgresponse.BadRequest(c, "credential_id is required")
 		return
 	}
-	credDAO := db.NewCredentialDAO()
-	if _, err := credDAO.FindByID(req.CredentialID); err != nil {
-		pkgresponse.BadRequest(c, "credential not found")
-		return
+	if req.CredentialID > 0 {
+		credDAO := db.NewCredentialDAO()
+		if _, err := credDAO.FindByID(req.CredentialID); err != nil {
+			pkgresponse.BadRequest(c, "credential not found")
+			return
+		}
+	}
+	if generic {
+		if _, err := provider.ParseGenericOptions(req.Options); err != nil {
+			pkgresponse.BadRequest(c, err.Error())
+			return
+		}
 	}
 	dao := db.NewProviderConfigDAO()
 	cfg := &po.ProviderConfig{
 		Name: req.Name, Platform: req.Platform, BaseURL: req.BaseURL,
 		CredentialID: req.CredentialID, WebhookSecret: req.WebhookSecret,
+		Options: req.Options,
 	}
 	if err := dao.Create(cfg); err != nil {
 		pkgresponse.InternalServerError(c, "Failed to create provider config: "+err.Error())
 		return
 	}
+	provider.GetManager().Invalidate(cfg.ID)
+	// generic、gerrit、azure 平台无法按请求头识别来源，需使用专属接收地址
+	if provider.NeedsDedicatedEndpoint(provider.Platform(cfg.Platform)) {
+		cfg.WebhookEndpoint = fmt.Sprintf("/api/webhooks/receive/%d", cfg.ID)
+		if err := dao.UpdateWebhookEndpoint(cfg.ID, cfg.WebhookEndpoint); err != nil {
+			pkgresponse.InternalServerError(c, "Failed to create provider config: "+err.Error())
+			return
+		}
+	}
 	pkgresponse.Success(c, toProviderConfigDTO(cfg))
 }
 
@@ -124,6 +154,15 @@ func Update(ctx context.Context, c *app.RequestContext) {
 	if req.WebhookSecret != "" {
 		cfg.WebhookSecret = req.WebhookSecret
 	}
+	if req.Options != nil {
+		if cfg.Platform == string(provider.PlatformGeneric) {
+			if _, err := provider.ParseGenericOptions(req.Options); err != nil {
+				pkgresponse.BadRequest(c, err.Error())
+				return
+			}
+		}
+		cfg.Options = req.Options
+	}
 	if err := dao.Save(cfg); err != nil {
 		pkgresponse.InternalServerError(c, "Failed to update provider config: "+err.Error())
 		return
@@ -176,6 +215,7 @@ func toProviderConfigDTO(cfg *po.ProviderConfig) api.ProviderConfigDTO {
 		BaseURL: cfg.BaseURL, CredentialID: cfg.CredentialID,
 		HasWebhookSecret: cfg.WebhookSecret != "",
 		WebhookEndpoint:  cfg.WebhookEndpoint,
+		Options:          cfg.Options,
 		CreatedAt:        cfg.CreatedAt, UpdatedAt: cfg.UpdatedAt,
 	}
 }
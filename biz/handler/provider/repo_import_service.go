@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/service/audit"
+	"github.com/yi-nology/git-manage-service/biz/service/repoimport"
+	pkgresponse "github.com/yi-nology/git-manage-service/pkg/response"
+)
+
+// Import 从 provider 批量导入组织/组下的仓库
+// @router /api/v1/providers/:id/import [POST]
+func Import(ctx context.Context, c *app.RequestContext) {
+	id, err := parseID(c)
+	if err != nil {
+		pkgresponse.BadRequest(c, "Invalid ID")
+		return
+	}
+	var req api.RepoImportReq
+	if err := c.BindJSON(&req); err != nil {
+		pkgresponse.BadRequest(c, "invalid JSON: "+err.Error())
+		return
+	}
+	result, err := repoimport.Start(ctx, id, &req)
+	if err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	audit.AuditSvc.Log(c, "IMPORT_REPOS", fmt.Sprintf("provider:%d", id), map[string]interface{}{"import_id": result.ID, "owner": req.Owner, "dry_run": req.DryRun})
+	pkgresponse.Accepted(c, "import started", result)
+}
+
+// ListImports 分页列出批量导入任务
+// @router /api/v1/provider-imports [GET]
+func ListImports(ctx context.Context, c *app.RequestContext) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	items, total, err := repoimport.List(page, pageSize)
+	if err != nil {
+		pkgresponse.InternalServerError(c, err.Error())
+		return
+	}
+	pkgresponse.Success(c, map[string]interface{}{"items": items, "total": total})
+}
+
+// GetImport 获取导入报告（含每个仓库的结果）
+// @router /api/v1/provider-imports/:id [GET]
+func GetImport(ctx context.Context, c *app.RequestContext) {
+	id, err := parseID(c)
+	if err != nil {
+		pkgresponse.BadRequest(c, "Invalid ID")
+		return
+	}
+	result, err := repoimport.Get(id)
+	if err != nil {
+		pkgresponse.NotFound(c, "Import not found")
+		return
+	}
+	pkgresponse.Success(c, result)
+}
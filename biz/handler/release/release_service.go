@@ -0,0 +1,126 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/service/audit"
+	"github.com/yi-nology/git-manage-service/biz/service/provider"
+	"github.com/yi-nology/git-manage-service/biz/service/release"
+	pkgresponse "github.com/yi-nology/git-manage-service/pkg/response"
+)
+
+// List 列出平台上的 Release
+// @router /api/v1/release/list [GET]
+func List(ctx context.Context, c *app.RequestContext) {
+	repoKey := c.Query("repo_key")
+	if repoKey == "" {
+		pkgresponse.BadRequest(c, "repo_key is required")
+		return
+	}
+	releases, err := release.List(ctx, repoKey)
+	if err != nil {
+		releaseError(c, err)
+		return
+	}
+	pkgresponse.Success(c, map[string]interface{}{"items": releases, "total": len(releases)})
+}
+
+// Create 为已有 tag 发布 Release 并上传附件
+// @router /api/v1/release/create [POST]
+func Create(ctx context.Context, c *app.RequestContext) {
+	var req api.CreateReleaseReq
+	if err := c.BindJSON(&req); err != nil {
+		pkgresponse.BadRequest(c, "invalid JSON: "+err.Error())
+		return
+	}
+	if req.RepoKey == "" || req.TagName == "" {
+		pkgresponse.BadRequest(c, "repo_key and tag_name are required")
+		return
+	}
+	result, err := release.Create(ctx, &req)
+	if err != nil {
+		releaseError(c, err)
+		return
+	}
+	audit.AuditSvc.Log(c, "CREATE_RELEASE", fmt.Sprintf("repo:%s tag:%s", req.RepoKey, req.TagName), result)
+	pkgresponse.Success(c, result)
+}
+
+// Update 修改 Release 并追加附件
+// @router /api/v1/release/update [POST]
+func Update(ctx context.Context, c *app.RequestContext) {
+	var req api.UpdateReleaseReq
+	if err := c.BindJSON(&req); err != nil {
+		pkgresponse.BadRequest(c, "invalid JSON: "+err.Error())
+		return
+	}
+	if req.RepoKey == "" || req.TagName == "" {
+		pkgresponse.BadRequest(c, "repo_key and tag_name are required")
+		return
+	}
+	result, err := release.Update(ctx, &req)
+	if err != nil {
+		releaseError(c, err)
+		return
+	}
+	audit.AuditSvc.Log(c, "UPDATE_RELEASE", fmt.Sprintf("repo:%s tag:%s", req.RepoKey, req.TagName), result)
+	pkgresponse.Success(c, result)
+}
+
+// Notes 预览 previous_tag..tag 的发布说明，previous_tag 为空时列出 tag 之前的全部提交
+// @router /api/v1/release/notes [GET]
+func Notes(ctx context.Context, c *app.RequestContext) {
+	repoKey, tag := c.Query("repo_key"), c.Query("tag")
+	if repoKey == "" || tag == "" {
+		pkgresponse.BadRequest(c, "repo_key and tag are required")
+		return
+	}
+	repo, err := db.NewRepoDAO().FindByKey(repoKey)
+	if err != nil {
+		pkgresponse.NotFound(c, "repo not found")
+		return
+	}
+	notes, err := release.GenerateNotes(repo.Path, c.Query("previous_tag"), tag)
+	if err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	pkgresponse.Success(c, map[string]string{"notes": notes})
+}
+
+// TagRelease 创建 tag、推送并发布带生成说明的 Release
+// @router /api/v1/tag/release [POST]
+func TagRelease(ctx context.Context, c *app.RequestContext) {
+	var req api.TagReleaseReq
+	if err := c.BindJSON(&req); err != nil {
+		pkgresponse.BadRequest(c, "invalid JSON: "+err.Error())
+		return
+	}
+	if req.RepoKey == "" || req.TagName == "" {
+		pkgresponse.BadRequest(c, "repo_key and tag_name are required")
+		return
+	}
+	result, err := release.TagRelease(ctx, &req)
+	if err != nil {
+		releaseError(c, err)
+		return
+	}
+	audit.AuditSvc.Log(c, "TAG_RELEASE", fmt.Sprintf("repo:%s tag:%s", req.RepoKey, result.TagName), result)
+	pkgresponse.Success(c, result)
+}
+
+func releaseError(c *app.RequestContext, err error) {
+	switch {
+	case errors.Is(err, provider.ErrUnsupported):
+		pkgresponse.BadRequest(c, err.Error())
+	case errors.Is(err, provider.ErrNotFound):
+		pkgresponse.NotFound(c, err.Error())
+	default:
+		pkgresponse.InternalServerError(c, err.Error())
+	}
+}
@@ -15,8 +15,8 @@ import (
 	"github.com/yi-nology/git-manage-service/biz/model/domain"
 	"github.com/yi-nology/git-manage-service/biz/model/po"
 	"github.com/yi-nology/git-manage-service/biz/service/audit"
-	"github.com/yi-nology/git-manage-service/biz/service/auth"
 	"github.com/yi-nology/git-manage-service/biz/service/git"
+	"github.com/yi-nology/git-manage-service/biz/service/repotask"
 	"github.com/yi-nology/git-manage-service/biz/service/stats"
 	"github.com/yi-nology/git-manage-service/pkg/response"
 )
@@ -283,99 +283,27 @@ func Clone(ctx context.Context, c *app.RequestContext) {
 		}
 	}
 
-	taskID := uuid.New().String()
-	git.GlobalTaskManager.AddTask(taskID)
-
-	go func() {
-		progressChan := make(chan string)
-
-		go func() {
-			for msg := range progressChan {
-				git.GlobalTaskManager.AppendLog(taskID, msg)
-			}
-		}()
-
-		var err error
-		authSvc := auth.NewAuthService()
-
-		// 优先使用新凭证系统
-		if req.CredentialID > 0 {
-			if authSvc.IsCredentialDBKey(req.CredentialID) {
-				// 数据库 SSH 密钥凭证 — 使用原生 git 命令
-				privateKey, passphrase, keyErr := authSvc.GetCredentialKeyContent(req.CredentialID)
-				if keyErr != nil {
-					git.GlobalTaskManager.UpdateStatus(taskID, "failed", "Failed to load credential key: "+keyErr.Error())
-					close(progressChan)
-					return
-				}
-				git.GlobalTaskManager.AppendLog(taskID, "Using credential (DB SSH key) for clone...")
-				err = gitSvc.CloneWithDBKey(req.RemoteURL, req.LocalPath, privateKey, passphrase, progressChan)
-			} else {
-				// go-git 方式（HTTP 或本地 SSH 密钥凭证）
-				authMethod, authErr := authSvc.ResolveCredential(req.CredentialID)
-				if authErr != nil {
-					git.GlobalTaskManager.AppendLog(taskID, "Warning: credential resolution failed: "+authErr.Error())
-				}
-				git.GlobalTaskManager.AppendLog(taskID, "Using credential for clone...")
-				err = gitSvc.CloneWithAuthMethod(req.RemoteURL, req.LocalPath, authMethod, progressChan)
-			}
-		} else if req.SSHKeyID > 0 {
-			// 兼容旧接口：数据库SSH密钥
-			privateKey, passphrase, keyErr := authSvc.GetDBSSHKeyContent(req.SSHKeyID)
-			if keyErr != nil {
-				git.GlobalTaskManager.UpdateStatus(taskID, "failed", "Failed to load SSH key: "+keyErr.Error())
-				close(progressChan)
-				return
-			}
-			git.GlobalTaskManager.AppendLog(taskID, "Using database SSH key for clone...")
-			err = gitSvc.CloneWithDBKey(req.RemoteURL, req.LocalPath, privateKey, passphrase, progressChan)
-		} else {
-			// 兼容旧接口：go-git 方式
-			authMethod, authErr := authSvc.ResolveAuthFromParams(req.AuthType, req.AuthKey, req.AuthSecret, 0)
-			if authErr != nil {
-				git.GlobalTaskManager.AppendLog(taskID, "Warning: auth resolution failed: "+authErr.Error())
-			}
-			err = gitSvc.CloneWithAuthMethod(req.RemoteURL, req.LocalPath, authMethod, progressChan)
-		}
-		close(progressChan)
-
-		if err != nil {
-			git.GlobalTaskManager.UpdateStatus(taskID, "failed", err.Error())
-			return
-		}
-
-		git.GlobalTaskManager.UpdateStatus(taskID, "success", "")
-
-		name := filepath.Base(req.LocalPath)
-		repo := po.Repo{
-			Key:                 uuid.New().String(),
-			Name:                name,
-			Path:                req.LocalPath,
-			RemoteURL:           req.RemoteURL,
-			AuthType:            req.AuthType,
-			AuthKey:             req.AuthKey,
-			AuthSecret:          req.AuthSecret,
-			DefaultCredentialID: req.CredentialID,
-		}
-		db.NewRepoDAO().Create(&repo)
-
-		go func() {
-			head, err := gitSvc.GetHeadBranch(repo.Path)
-			if err == nil && head != "" {
-				stats.StatsSvc.SyncRepoStats(repo.ID, repo.Path, head)
-			}
-		}()
-	}()
+	task, err := repotask.SubmitClone(repotask.CloneOptions{
+		RemoteURL:    req.RemoteURL,
+		LocalPath:    req.LocalPath,
+		AuthType:     req.AuthType,
+		AuthKey:      req.AuthKey,
+		AuthSecret:   req.AuthSecret,
+		SSHKeyID:     req.SSHKeyID,
+		CredentialID: req.CredentialID,
+	})
+	if err != nil {
+		response.InternalServerError(c, err.Error())
+		return
+	}
 
-	response.Success(c, map[string]string{"task_id": taskID})
+	response.Success(c, map[string]string{"task_id": task.ID})
 }
 
 // Fetch .
 // @router /api/v1/repo/fetch [POST]
 func Fetch(ctx context.Context, c *app.RequestContext) {
-	var req struct {
-		RepoKey string `json:"repo_key"`
-	}
+	var req api.FetchRepoReq
 	if err := c.BindAndValidate(&req); err != nil {
 		response.BadRequest(c, err.Error())
 		return
@@ -387,48 +315,18 @@ func Fetch(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
-	gitSvc := git.NewGitService()
-	authSvc := auth.NewAuthService()
-
-	// 检查是否有任何远程使用数据库SSH密钥（新凭证系统 + 旧系统）
-	useDBKey := false
-	var dbPrivateKey, dbPassphrase string
-
-	// 优先检查新凭证系统
-	if repo.DefaultCredentialID > 0 && authSvc.IsCredentialDBKey(repo.DefaultCredentialID) {
-		pk, pp, err := authSvc.GetCredentialKeyContent(repo.DefaultCredentialID)
+	if req.Async {
+		task, err := repotask.SubmitFetch(repo)
 		if err != nil {
-			response.InternalServerError(c, "failed to resolve credential key: "+err.Error())
+			response.InternalServerError(c, err.Error())
 			return
 		}
-		dbPrivateKey = pk
-		dbPassphrase = pp
-		useDBKey = true
-	}
-
-	// 如果新系统没有配置，回退到旧系统
-	if !useDBKey && repo.RemoteAuths != nil {
-		for _, authInfo := range repo.RemoteAuths {
-			if authInfo.Type == "ssh" && authInfo.Source == "database" && authInfo.SSHKeyID > 0 {
-				pk, pp, err := authSvc.GetDBSSHKeyContent(authInfo.SSHKeyID)
-				if err != nil {
-					response.InternalServerError(c, "failed to resolve SSH key: "+err.Error())
-					return
-				}
-				dbPrivateKey = pk
-				dbPassphrase = pp
-				useDBKey = true
-				break
-			}
-		}
+		audit.AuditSvc.Log(c, "FETCH_REPO", "repo:"+repo.Key, map[string]string{"task_id": task.ID})
+		response.Success(c, map[string]string{"task_id": task.ID})
+		return
 	}
 
-	var fetchErr error
-	if useDBKey {
-		fetchErr = gitSvc.FetchAllWithDBKey(repo.Path, dbPrivateKey, dbPassphrase)
-	} else {
-		fetchErr = gitSvc.FetchAll(repo.Path)
-	}
+	fetchErr := repotask.FetchRepo(ctx, repo)
 	if fetchErr != nil {
 		response.InternalServerError(c, fetchErr.Error())
 		return
@@ -438,6 +336,31 @@ func Fetch(ctx context.Context, c *app.RequestContext) {
 	response.Success(c, map[string]string{"message": "fetched"})
 }
 
+// Backup .
+// @router /api/v1/repo/backup [POST]
+func Backup(ctx context.Context, c *app.RequestContext) {
+	var req api.BackupRepoReq
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	repo, err := db.NewRepoDAO().FindByKey(req.RepoKey)
+	if err != nil {
+		response.NotFound(c, "repo not found")
+		return
+	}
+
+	task, err := repotask.SubmitBackup(repo)
+	if err != nil {
+		response.InternalServerError(c, err.Error())
+		return
+	}
+
+	audit.AuditSvc.Log(c, "BACKUP_REPO", "repo:"+repo.Key, map[string]string{"task_id": task.ID})
+	response.Success(c, map[string]string{"task_id": task.ID})
+}
+
 // GetCloneTask .
 // @router /api/v1/repo/task [GET]
 func GetCloneTask(ctx context.Context, c *app.RequestContext) {
@@ -4,7 +4,9 @@ package sync
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/google/uuid"
@@ -78,6 +80,45 @@ func CreateTask(ctx context.Context, c *app.RequestContext) {
 		response.BadRequest(c, err.Error())
 		return
 	}
+	// create_target_repo 等扩展字段不在 proto 定义中，需从 JSON 请求体单独绑定
+	var extra api.SyncTaskExtraReq
+	if strings.Contains(string(c.ContentType()), "json") {
+		if err := c.BindJSON(&extra); err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+	if err := validateAutoCR(extra.AutoCR, req.SyncMode, req.TargetBranch); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	var createdTarget *api.TargetRepoDTO
+	if extra.CreateTargetRepo != nil && extra.AutoCR != nil && extra.AutoCR.Enabled {
+		// 新建的平台仓库只有在目标仓库尚未绑定时才会被绑定，否则 CR 会开到原绑定的仓库上
+		targetKey := req.TargetRepoKey
+		if targetKey == "" {
+			targetKey = req.SourceRepoKey
+		}
+		if repo, err := db.NewRepoDAO().FindByKey(targetKey); err == nil && repo.ProviderConfigID != 0 {
+			response.BadRequest(c, fmt.Sprintf("auto_cr: repo %s is already linked to %s/%s, the created repo would not receive the CR", targetKey, repo.PlatformOwner, repo.PlatformRepo))
+			return
+		}
+	}
+	if extra.CreateTargetRepo != nil {
+		// 未指定目标本地仓库时推送到源仓库新增的 remote（镜像场景）
+		if req.TargetRepoKey == "" {
+			req.TargetRepoKey = req.SourceRepoKey
+		}
+		result, err := syncSvc.NewSyncService().ProvisionTargetRepo(ctx, req.TargetRepoKey, req.TargetRemote, extra.CreateTargetRepo)
+		if err != nil {
+			response.InternalServerError(c, "Failed to create target repo: "+err.Error())
+			return
+		}
+		req.TargetRemote = result.RemoteName
+		createdTarget = result
+		audit.AuditSvc.Log(c, "CREATE_TARGET_REPO", "repo:"+req.TargetRepoKey, result)
+	}
 
 	task := po.SyncTask{
 		Key:           uuid.New().String(),
@@ -96,6 +137,16 @@ func CreateTask(ctx context.Context, c *app.RequestContext) {
 		GitPrune:      req.GitPrune,
 		GitNoVerify:   req.GitNoVerify,
 	}
+	applyAutoCR(&task, extra.AutoCR)
+	if extra.Tags != nil {
+		task.Tags = normalizeTags(*extra.Tags)
+	}
+	if task.AutoCR && createdTarget == nil {
+		if err := syncSvc.NewSyncService().CheckAutoCRTarget(&task); err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
 
 	if err := db.NewSyncTaskDAO().Create(&task); err != nil {
 		response.InternalServerError(c, err.Error())
@@ -104,7 +155,9 @@ func CreateTask(ctx context.Context, c *app.RequestContext) {
 
 	syncSvc.CronSvc.UpdateTask(task)
 	audit.AuditSvc.Log(c, "CREATE", "task:"+task.Key, task)
-	response.Success(c, api.NewSyncTaskDTO(task))
+	dto := api.NewSyncTaskDTO(task)
+	dto.CreatedTargetRepo = createdTarget
+	response.Success(c, dto)
 }
 
 // UpdateTask .
@@ -115,6 +168,13 @@ func UpdateTask(ctx context.Context, c *app.RequestContext) {
 		response.BadRequest(c, err.Error())
 		return
 	}
+	var extra api.SyncTaskExtraReq
+	if strings.Contains(string(c.ContentType()), "json") {
+		if err := c.BindJSON(&extra); err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
 
 	taskDAO := db.NewSyncTaskDAO()
 	task, err := taskDAO.FindByKey(req.Key)
@@ -122,6 +182,14 @@ func UpdateTask(ctx context.Context, c *app.RequestContext) {
 		response.NotFound(c, "task not found")
 		return
 	}
+	if extra.AutoCR == nil && task.AutoCR {
+		// 未传 auto_cr 时保留原配置，但仍需校验与新的同步模式 / 分支是否兼容
+		extra.AutoCR = &api.AutoCRReq{Enabled: true, TargetBranch: task.CRTargetBranch, Title: task.CRTitle}
+	}
+	if err := validateAutoCR(extra.AutoCR, req.SyncMode, req.TargetBranch); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
 
 	task.SourceRepoKey = req.SourceRepoKey
 	task.SourceRemote = req.SourceRemote
@@ -137,6 +205,16 @@ func UpdateTask(ctx context.Context, c *app.RequestContext) {
 	task.GitForce = req.GitForce
 	task.GitPrune = req.GitPrune
 	task.GitNoVerify = req.GitNoVerify
+	applyAutoCR(task, extra.AutoCR)
+	if extra.Tags != nil {
+		task.Tags = normalizeTags(*extra.Tags)
+	}
+	if task.AutoCR {
+		if err := syncSvc.NewSyncService().CheckAutoCRTarget(task); err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
 
 	if err := taskDAO.Save(task); err != nil {
 		response.InternalServerError(c, err.Error())
@@ -329,6 +407,47 @@ func normalizeSyncMode(mode string) string {
 	}
 }
 
+// validateAutoCR 自动 CR 只支持单分支同步，且合入分支不能是同步写入的暂存分支
+func validateAutoCR(req *api.AutoCRReq, syncMode, targetBranch string) error {
+	if req == nil || !req.Enabled {
+		return nil
+	}
+	if normalizeSyncMode(syncMode) != "single" {
+		return fmt.Errorf("auto_cr is only supported in single branch sync mode")
+	}
+	if req.TargetBranch == "" {
+		return fmt.Errorf("auto_cr.target_branch is required")
+	}
+	if req.TargetBranch == targetBranch {
+		return fmt.Errorf("auto_cr.target_branch must differ from the sync target branch")
+	}
+	return nil
+}
+
+func applyAutoCR(task *po.SyncTask, req *api.AutoCRReq) {
+	if req == nil {
+		return
+	}
+	task.AutoCR = req.Enabled
+	task.CRTargetBranch = req.TargetBranch
+	task.CRTitle = req.Title
+}
+
+// normalizeTags 去除空白与重复的标签，保持原有顺序
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
 // AnalyzeRepoForSync .
 // @router /api/v1/sync/analyze-repo [POST]
 func AnalyzeRepoForSync(ctx context.Context, c *app.RequestContext) {
@@ -0,0 +1,69 @@
+package task
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/service/git"
+	pkgresponse "github.com/yi-nology/git-manage-service/pkg/response"
+)
+
+// List 列出后台任务（克隆、拉取、备份）
+func List(ctx context.Context, c *app.RequestContext) {
+	var req api.ListAsyncTasksReq
+	if err := c.BindAndValidate(&req); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	tasks, total, err := git.GlobalTaskManager.ListTasks(req.Type, req.Status, req.Page, req.PageSize)
+	if err != nil {
+		pkgresponse.InternalServerError(c, "Failed to list tasks: "+err.Error())
+		return
+	}
+	pkgresponse.Success(c, map[string]interface{}{
+		"items":   tasks,
+		"total":   total,
+		"running": git.GlobalTaskManager.GetRunningTasksCount(),
+		"queued":  git.GlobalTaskManager.GetQueueLength(),
+	})
+}
+
+// Get 获取单个后台任务详情
+func Get(ctx context.Context, c *app.RequestContext) {
+	id := c.Query("task_id")
+	if id == "" {
+		pkgresponse.BadRequest(c, "task_id is required")
+		return
+	}
+	task, ok := git.GlobalTaskManager.GetTask(id)
+	if !ok {
+		pkgresponse.NotFound(c, "task not found")
+		return
+	}
+	pkgresponse.Success(c, task)
+}
+
+// Cancel 取消排队中或运行中的后台任务
+func Cancel(ctx context.Context, c *app.RequestContext) {
+	var req api.CancelAsyncTaskReq
+	if err := c.BindAndValidate(&req); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	if req.TaskID == "" {
+		pkgresponse.BadRequest(c, "task_id is required")
+		return
+	}
+	if err := git.GlobalTaskManager.Cancel(req.TaskID); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	pkgresponse.Success(c, map[string]string{"message": "Task cancelled"})
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/google/uuid"
@@ -126,6 +127,10 @@ func Receive(ctx context.Context, c *app.RequestContext) {
 	var matchedProvider provider.Provider
 	var matchedCfg po.ProviderConfig
 	for _, cfg := range configs {
+		// generic、gerrit、azure 平台需通过 /api/webhooks/receive/:id 接收
+		if provider.NeedsDedicatedEndpoint(provider.Platform(cfg.Platform)) {
+			continue
+		}
 		p, pErr := provider.GetManager().GetProvider(cfg.ID)
 		if pErr != nil {
 			continue
@@ -142,8 +147,12 @@ func Receive(ctx context.Context, c *app.RequestContext) {
 		glEvent := string(c.Request.Header.Peek("X-Gitlab-Event"))
 		ghEvent := string(c.Request.Header.Peek("X-GitHub-Event"))
 		gtEvent := string(c.Request.Header.Peek("X-Gitea-Event"))
-		if glEvent != "" || ghEvent != "" || gtEvent != "" {
+		bbEvent := string(c.Request.Header.Peek("X-Event-Key"))
+		if glEvent != "" || ghEvent != "" || gtEvent != "" || bbEvent != "" {
 			for _, cfg := range configs {
+				if provider.NeedsDedicatedEndpoint(provider.Platform(cfg.Platform)) {
+					continue
+				}
 				p, _ := provider.GetManager().GetProvider(cfg.ID)
 				if p != nil {
 					matchedProvider = p
@@ -159,14 +168,43 @@ func Receive(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
-	event, err := matchedProvider.ParseWebhookEvent(toHTTPRequest(c), matchedCfg.WebhookSecret)
+	acceptEvent(c, matchedProvider, &matchedCfg)
+}
+
+// ReceiveForConfig 接收指定 provider 配置的 webhook，generic、gerrit、azure 平台使用此地址
+func ReceiveForConfig(ctx context.Context, c *app.RequestContext) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid ID")
+		return
+	}
+	cfg, err := db.NewProviderConfigDAO().FindByID(uint(id))
+	if err != nil {
+		response.NotFound(c, "Provider config not found")
+		return
+	}
+	p, err := provider.GetManager().GetProvider(cfg.ID)
+	if err != nil {
+		response.BadRequest(c, "Provider unavailable: "+err.Error())
+		return
+	}
+	acceptEvent(c, p, cfg)
+}
+
+// acceptEvent 解析事件并异步入库、执行规则
+func acceptEvent(c *app.RequestContext, p provider.Provider, cfg *po.ProviderConfig) {
+	rawReq := toHTTPRequest(c)
+	event, err := p.ParseWebhookEvent(rawReq, cfg.WebhookSecret)
 	if err != nil {
 		response.BadRequest(c, "Failed to parse webhook: "+err.Error())
 		return
 	}
+	body, _ := c.Body()
+	rawBody := append([]byte(nil), body...)
 
+	cfgID := cfg.ID
 	go func() {
-		if processErr := webhookevent.ProcessIncomingEvent(event, matchedCfg.ID); processErr != nil {
+		if processErr := webhookevent.ProcessIncomingEvent(event, cfgID, rawReq.Header, rawBody); processErr != nil {
 			_ = processErr
 		}
 	}()
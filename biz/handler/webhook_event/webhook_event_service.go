@@ -2,9 +2,11 @@ package webhook_event
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
 	"github.com/yi-nology/git-manage-service/biz/service/webhookevent"
 	pkgresponse "github.com/yi-nology/git-manage-service/pkg/response"
 )
@@ -48,5 +50,98 @@ func Retry(ctx context.Context, c *app.RequestContext) {
 		pkgresponse.InternalServerError(c, "Failed to retry event: "+err.Error())
 		return
 	}
-	pkgresponse.Success(c, map[string]string{"message": "Event retried"})
+	pkgresponse.Success(c, map[string]string{"message": "Event replay scheduled"})
+}
+
+// DeadLetters 列出重试耗尽的死信事件
+func DeadLetters(ctx context.Context, c *app.RequestContext) {
+	var req api.ListWebhookEventsReq
+	if err := c.BindAndValidate(&req); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	events, total, err := webhookevent.List(req.EventType, req.Source, po.WebhookEventStatusDead, req.Page, req.PageSize)
+	if err != nil {
+		pkgresponse.InternalServerError(c, "Failed to list dead-letter events: "+err.Error())
+		return
+	}
+	pkgresponse.Success(c, map[string]interface{}{
+		"items": events,
+		"total": total,
+	})
+}
+
+func Executions(ctx context.Context, c *app.RequestContext) {
+	eventID, err := strconv.ParseUint(c.Query("event_id"), 10, 64)
+	if err != nil || eventID == 0 {
+		pkgresponse.BadRequest(c, "event_id is required")
+		return
+	}
+	execs, err := webhookevent.ListExecutions(uint(eventID))
+	if err != nil {
+		pkgresponse.InternalServerError(c, "Failed to list executions: "+err.Error())
+		return
+	}
+	pkgresponse.Success(c, map[string]interface{}{
+		"items": execs,
+		"total": len(execs),
+	})
+}
+
+// SyncRoutes 查看 push 事件自动路由到同步任务的决策
+func SyncRoutes(ctx context.Context, c *app.RequestContext) {
+	eventID, err := strconv.ParseUint(c.Query("event_id"), 10, 64)
+	if err != nil || eventID == 0 {
+		pkgresponse.BadRequest(c, "event_id is required")
+		return
+	}
+	routes, err := webhookevent.ListSyncRoutes(uint(eventID))
+	if err != nil {
+		pkgresponse.InternalServerError(c, "Failed to list sync routes: "+err.Error())
+		return
+	}
+	pkgresponse.Success(c, map[string]interface{}{
+		"items": routes,
+		"total": len(routes),
+	})
+}
+
+// Payload 查看事件的原始请求（已脱敏）
+func Payload(ctx context.Context, c *app.RequestContext) {
+	eventID, err := strconv.ParseUint(c.Query("event_id"), 10, 64)
+	if err != nil || eventID == 0 {
+		pkgresponse.BadRequest(c, "event_id is required")
+		return
+	}
+	payload, err := webhookevent.GetPayload(uint(eventID))
+	if err != nil {
+		pkgresponse.NotFound(c, err.Error())
+		return
+	}
+	pkgresponse.Success(c, payload)
+}
+
+// Replay 重新解析已存储的事件并匹配规则，execute 为 true 时执行动作
+func Replay(ctx context.Context, c *app.RequestContext) {
+	var req api.ReplayWebhookEventReq
+	if err := c.BindAndValidate(&req); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	if req.EventID == 0 {
+		pkgresponse.BadRequest(c, "event_id is required")
+		return
+	}
+	result, err := webhookevent.Replay(req.EventID, req.Execute)
+	if err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	pkgresponse.Success(c, result)
 }
@@ -0,0 +1,98 @@
+package webhook_event
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/service/webhookevent"
+	pkgresponse "github.com/yi-nology/git-manage-service/pkg/response"
+)
+
+func ListRules(ctx context.Context, c *app.RequestContext) {
+	var req api.ListWebhookRulesReq
+	if err := c.BindAndValidate(&req); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	rules, err := webhookevent.ListRules(req.ProviderConfigID)
+	if err != nil {
+		pkgresponse.InternalServerError(c, "Failed to list webhook rules: "+err.Error())
+		return
+	}
+	pkgresponse.Success(c, map[string]interface{}{
+		"items": rules,
+		"total": len(rules),
+	})
+}
+
+func CreateRule(ctx context.Context, c *app.RequestContext) {
+	var req api.CreateWebhookRuleReq
+	if err := c.BindAndValidate(&req); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	rule, err := webhookevent.CreateRule(&req)
+	if err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	pkgresponse.Success(c, rule)
+}
+
+func UpdateRule(ctx context.Context, c *app.RequestContext) {
+	id, err := parseID(c)
+	if err != nil {
+		pkgresponse.BadRequest(c, "Invalid ID")
+		return
+	}
+	var req api.CreateWebhookRuleReq
+	if err := c.BindAndValidate(&req); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	rule, err := webhookevent.UpdateRule(id, &req)
+	if err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	pkgresponse.Success(c, rule)
+}
+
+func DeleteRule(ctx context.Context, c *app.RequestContext) {
+	id, err := parseID(c)
+	if err != nil {
+		pkgresponse.BadRequest(c, "Invalid ID")
+		return
+	}
+	if err := webhookevent.DeleteRule(id); err != nil {
+		pkgresponse.InternalServerError(c, "Failed to delete rule: "+err.Error())
+		return
+	}
+	pkgresponse.Success(c, map[string]string{"message": "Rule deleted"})
+}
+
+// TestRule 使用已存储的事件对草稿规则求值
+func TestRule(ctx context.Context, c *app.RequestContext) {
+	var req api.TestWebhookRuleReq
+	if err := c.BindAndValidate(&req); err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	if req.EventID == 0 {
+		pkgresponse.BadRequest(c, "event_id is required")
+		return
+	}
+	result, err := webhookevent.TestRule(&req)
+	if err != nil {
+		pkgresponse.BadRequest(c, err.Error())
+		return
+	}
+	pkgresponse.Success(c, result)
+}
+
+func parseID(c *app.RequestContext) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	return uint(id), err
+}
@@ -0,0 +1,66 @@
+package api
+
+// BranchProtectionDTO 平台上的分支保护设置，也用作直接写入的请求体（protected=false 表示解除保护）
+type BranchProtectionDTO struct {
+	RepoKey         string   `json:"repo_key"`
+	Branch          string   `json:"branch"`
+	Protected       bool     `json:"protected"`
+	RequiredReviews int      `json:"required_reviews"`
+	AllowForcePush  bool     `json:"allow_force_push"`
+	RequireChecks   bool     `json:"require_checks"`
+	RequiredChecks  []string `json:"required_checks"`
+}
+
+// BranchPolicyReq 新增或更新分支保护策略，按 (repo_key, branch) 覆盖
+type BranchPolicyReq struct {
+	RepoKey         string   `json:"repo_key"` // 为空时为默认策略
+	Branch          string   `json:"branch"`   // 为空时为平台默认分支
+	RequiredReviews int      `json:"required_reviews"`
+	AllowForcePush  bool     `json:"allow_force_push"`
+	RequireChecks   bool     `json:"require_checks"`
+	RequiredChecks  []string `json:"required_checks"`
+}
+
+// BranchPolicyApplyReq 按策略修正不合规的分支保护；repo_keys 为空时处理所有已绑定平台的仓库
+type BranchPolicyApplyReq struct {
+	RepoKeys []string `json:"repo_keys"`
+	DryRun   bool     `json:"dry_run"`
+}
+
+// 合规检查结果
+const (
+	ComplianceCompliant    = "compliant"
+	ComplianceNonCompliant = "non_compliant"
+	ComplianceApplied      = "applied"     // 不合规，已按策略修正
+	ComplianceUnsupported  = "unsupported" // 平台不支持分支保护 API
+	ComplianceError        = "error"
+)
+
+// ProtectionDiffDTO 实际设置与策略不一致的字段
+type ProtectionDiffDTO struct {
+	Field    string      `json:"field"`
+	Expected interface{} `json:"expected"`
+	Actual   interface{} `json:"actual"`
+}
+
+type ComplianceItemDTO struct {
+	RepoKey  string               `json:"repo_key"`
+	Platform string               `json:"platform"`
+	FullName string               `json:"full_name"`
+	Branch   string               `json:"branch"`
+	PolicyID uint                 `json:"policy_id"`
+	Status   string               `json:"status"`
+	Diffs    []ProtectionDiffDTO  `json:"diffs,omitempty"`
+	Actual   *BranchProtectionDTO `json:"actual,omitempty"`
+	Error    string               `json:"error,omitempty"`
+}
+
+type ComplianceReportDTO struct {
+	Items        []ComplianceItemDTO `json:"items"`
+	Total        int                 `json:"total"`
+	Compliant    int                 `json:"compliant"`
+	NonCompliant int                 `json:"non_compliant"`
+	Applied      int                 `json:"applied"`
+	Unsupported  int                 `json:"unsupported"`
+	Errors       int                 `json:"errors"`
+}
@@ -0,0 +1,126 @@
+package api
+
+import "github.com/yi-nology/git-manage-service/biz/model/po"
+
+type ListNotificationDeliveriesReq struct {
+	ChannelID    uint   `json:"channel_id" query:"channel_id"`
+	TriggerEvent string `json:"trigger_event" query:"trigger_event"`
+	Status       string `json:"status" query:"status"`
+	Page         int    `json:"page" query:"page"`
+	PageSize     int    `json:"page_size" query:"page_size"`
+}
+
+// ChannelDeliveryStatsDTO 渠道投递统计；success_rate = 成功 / (成功 + 重试中 + 放弃)，无已发送投递时为 null
+type ChannelDeliveryStatsDTO struct {
+	ChannelID   uint     `json:"channel_id"`
+	ChannelName string   `json:"channel_name"`
+	ChannelType string   `json:"channel_type"`
+	Enabled     bool     `json:"enabled"`
+	Deleted     bool     `json:"deleted,omitempty"`
+	Total       int64    `json:"total"`
+	Succeeded   int64    `json:"succeeded"`
+	Retrying    int64    `json:"retrying"`
+	Dead        int64    `json:"dead"`
+	Pending     int64    `json:"pending"`
+	Deferred    int64    `json:"deferred"` // 静默时段内延后
+	SuccessRate *float64 `json:"success_rate"`
+}
+
+// NotificationRoutingRuleReq 渠道路由规则：effect 为 include / exclude，
+// field 为 repo / task / task_tag / trigger_source，pattern 支持 * ? [] 通配符
+type NotificationRoutingRuleReq struct {
+	Effect  string `json:"effect"`
+	Field   string `json:"field"`
+	Pattern string `json:"pattern"`
+}
+
+// NotificationChannelExtraReq notification.CreateChannelRequest / UpdateChannelRequest 之外的扩展字段
+type NotificationChannelExtraReq struct {
+	// RoutingRules 更新渠道时为空表示保持不变，传空数组清空（接收全部事件）
+	RoutingRules *[]NotificationRoutingRuleReq `json:"routing_rules"`
+
+	// 投递模式设置，未传的字段保持不变
+	DeliveryMode          *string   `json:"delivery_mode"` // immediate, batch, digest
+	BatchIntervalMinutes  *int      `json:"batch_interval_minutes"`
+	DigestTime            *string   `json:"digest_time"`      // HH:MM
+	ImmediateEvents       *[]string `json:"immediate_events"` // 汇总模式下仍立即发送的事件
+	DigestTitleTemplate   *string   `json:"digest_title_template"`
+	DigestContentTemplate *string   `json:"digest_content_template"`
+
+	// Escalation 是否为升级渠道（只接收连续失败达到阈值后的告警及其恢复通知）
+	Escalation *bool `json:"escalation"`
+	// QuietHours 静默时段，传不含 windows 的对象表示关闭
+	QuietHours *po.QuietHoursConfig `json:"quiet_hours"`
+}
+
+// ChannelDeliveryModeDTO 渠道投递模式；immediate_events 为 null 时失败与冲突事件立即发送
+type ChannelDeliveryModeDTO struct {
+	DeliveryMode          string               `json:"delivery_mode"`
+	BatchIntervalMinutes  int                  `json:"batch_interval_minutes"`
+	DigestTime            string               `json:"digest_time"`
+	ImmediateEvents       []string             `json:"immediate_events"`
+	DigestTitleTemplate   string               `json:"digest_title_template"`
+	DigestContentTemplate string               `json:"digest_content_template"`
+	PendingCount          int64                `json:"pending_count"` // 队列中等待合并的事件数
+	Escalation            bool                 `json:"escalation"`
+	QuietHours            *po.QuietHoursConfig `json:"quiet_hours"`
+}
+
+type ListNotificationAlertsReq struct {
+	State    string `json:"state" query:"state"` // firing, resolved
+	TaskKey  string `json:"task_key" query:"task_key"`
+	RepoKey  string `json:"repo_key" query:"repo_key"`
+	Page     int    `json:"page" query:"page"`
+	PageSize int    `json:"page_size" query:"page_size"`
+}
+
+// RoutingPreviewReq 预览一个事件会发送到哪些渠道；
+// channel_id 与 routing_rules 同时传入时，该渠道使用草稿规则求值
+type RoutingPreviewReq struct {
+	TriggerEvent  string                        `json:"trigger_event"`
+	Status        string                        `json:"status"`
+	RepoKey       string                        `json:"repo_key"`
+	TaskKey       string                        `json:"task_key"`
+	TriggerSource string                        `json:"trigger_source"`
+	ChannelID     uint                          `json:"channel_id"`
+	RoutingRules  *[]NotificationRoutingRuleReq `json:"routing_rules"`
+}
+
+type RoutingPreviewItemDTO struct {
+	ChannelID   uint   `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	ChannelType string `json:"channel_type"`
+	Receive     bool   `json:"receive"`
+	Reason      string `json:"reason"`
+}
+
+type ListIdentityMappingsReq struct {
+	Keyword  string `json:"keyword" query:"keyword"` // 匹配邮箱或显示名
+	Page     int    `json:"page" query:"page"`
+	PageSize int    `json:"page_size" query:"page_size"`
+}
+
+// IdentityMappingReq 创建或更新身份映射，更新时需传 id
+type IdentityMappingReq struct {
+	ID             uint   `json:"id"`
+	GitEmail       string `json:"git_email"`
+	DisplayName    string `json:"display_name"`
+	Mobile         string `json:"mobile"`
+	DingTalkUserID string `json:"dingtalk_user_id"`
+	FeishuUserID   string `json:"feishu_user_id"`
+	WeComUserID    string `json:"wecom_user_id"`
+}
+
+// TemplatePreviewReq 模板预览：channel_id 为已保存渠道，type/config 为草稿渠道（同时传入时覆盖已保存配置）；
+// 数据来源优先级 sync_run_id > backup_record_id > trigger_event 对应的示例数据；
+// title_template/content_template 非空时代替渠道当前模板
+type TemplatePreviewReq struct {
+	ChannelID       uint   `json:"channel_id"`
+	Type            string `json:"type"`
+	Config          string `json:"config"`
+	TriggerEvent    string `json:"trigger_event"`
+	SyncRunID       uint   `json:"sync_run_id"`
+	BackupRecordID  uint   `json:"backup_record_id"`
+	TitleTemplate   string `json:"title_template"`
+	ContentTemplate string `json:"content_template"`
+}
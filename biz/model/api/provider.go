@@ -1,18 +1,22 @@
 package api
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type ProviderConfigDTO struct {
-	ID               uint      `json:"id"`
-	Name             string    `json:"name"`
-	Platform         string    `json:"platform"`
-	BaseURL          string    `json:"base_url"`
-	CredentialID     uint      `json:"credential_id"`
-	CredentialName   string    `json:"credential_name,omitempty"`
-	WebhookEndpoint  string    `json:"webhook_endpoint,omitempty"`
-	HasWebhookSecret bool      `json:"has_webhook_secret"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	ID               uint                   `json:"id"`
+	Name             string                 `json:"name"`
+	Platform         string                 `json:"platform"`
+	BaseURL          string                 `json:"base_url"`
+	CredentialID     uint                   `json:"credential_id"`
+	CredentialName   string                 `json:"credential_name,omitempty"`
+	WebhookEndpoint  string                 `json:"webhook_endpoint,omitempty"`
+	HasWebhookSecret bool                   `json:"has_webhook_secret"`
+	Options          map[string]interface{} `json:"options,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
 }
 
 type CreateProviderConfigReq struct {
@@ -21,13 +25,16 @@ type CreateProviderConfigReq struct {
 	BaseURL       string `json:"base_url"`
 	CredentialID  uint   `json:"credential_id"`
 	WebhookSecret string `json:"webhook_secret"`
+	// Options 平台扩展配置；generic 平台为 signature_header / signature_algorithm / signature_prefix / mapping
+	Options map[string]interface{} `json:"options"`
 }
 
 type UpdateProviderConfigReq struct {
-	Name          string `json:"name"`
-	BaseURL       string `json:"base_url"`
-	CredentialID  uint   `json:"credential_id"`
-	WebhookSecret string `json:"webhook_secret"`
+	Name          string                 `json:"name"`
+	BaseURL       string                 `json:"base_url"`
+	CredentialID  uint                   `json:"credential_id"`
+	WebhookSecret string                 `json:"webhook_secret"`
+	Options       map[string]interface{} `json:"options"`
 }
 
 type TestProviderConfigResp struct {
@@ -54,6 +61,10 @@ type CRDTO struct {
 	WebURL         string     `json:"web_url"`
 	MergeStatus    string     `json:"merge_status"`
 	Labels         []string   `json:"labels"`
+	HeadSHA        string     `json:"head_sha,omitempty"`
+	CheckState     string     `json:"check_state,omitempty"`
+	CheckUpdatedAt *time.Time `json:"check_updated_at,omitempty"`
+	SyncRunID      uint       `json:"sync_run_id,omitempty"`
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 	MergedAt       *time.Time `json:"merged_at,omitempty"`
@@ -75,6 +86,8 @@ type MergeCRReq struct {
 	MergeCommitMessage string `json:"merge_commit_message"`
 	Squash             bool   `json:"squash"`
 	RemoveSourceBranch bool   `json:"remove_source_branch"`
+	// RequireChecks 合并前刷新 CI 状态，未全部通过时拒绝合并
+	RequireChecks bool `json:"require_checks"`
 }
 
 type CloseCRReq struct {
@@ -101,6 +114,48 @@ type SyncCRsReq struct {
 	State   string `json:"state"`
 }
 
+type CRCommentDTO struct {
+	ID             int64     `json:"id"`
+	AuthorName     string    `json:"author_name"`
+	AuthorUsername string    `json:"author_username"`
+	Body           string    `json:"body"`
+	Path           string    `json:"path,omitempty"`
+	Line           int       `json:"line,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AddCRCommentReq Path + Line 指定时为行内评论
+type AddCRCommentReq struct {
+	RepoKey   string `json:"repo_key"`
+	CRNumber  int    `json:"cr_number"`
+	Body      string `json:"body"`
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	CommitSHA string `json:"commit_sha"`
+}
+
+type ReviewCRReq struct {
+	RepoKey  string `json:"repo_key"`
+	CRNumber int    `json:"cr_number"`
+	Action   string `json:"action"` // approve | request_changes
+	Body     string `json:"body"`
+}
+
+type CommitStatusDTO struct {
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	Description string `json:"description,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+// CRChecksDTO CR 源分支最新提交的 CI 检查
+type CRChecksDTO struct {
+	CRNumber int               `json:"cr_number"`
+	HeadSHA  string            `json:"head_sha"`
+	State    string            `json:"state"`
+	Checks   []CommitStatusDTO `json:"checks"`
+}
+
 type WebhookEventDTO struct {
 	ID               uint       `json:"id"`
 	EventID          string     `json:"event_id"`
@@ -112,11 +167,50 @@ type WebhookEventDTO struct {
 	ActorName        string     `json:"actor_name"`
 	ActorUsername    string     `json:"actor_username"`
 	Status           string     `json:"status"`
+	Attempts         int        `json:"attempts"`
+	NextRetryAt      *time.Time `json:"next_retry_at,omitempty"`
 	ProcessedAt      *time.Time `json:"processed_at,omitempty"`
 	CreatedAt        time.Time  `json:"created_at"`
 	ErrorMessage     string     `json:"error_message,omitempty"`
 }
 
+type WebhookActionExecutionDTO struct {
+	ID           uint       `json:"id"`
+	RuleID       uint       `json:"rule_id"`
+	RuleName     string     `json:"rule_name"`
+	Action       string     `json:"action"`
+	Status       string     `json:"status"`
+	Result       string     `json:"result,omitempty"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
+
+// WebhookSyncRouteDTO push 事件到同步任务的路由决策
+type WebhookSyncRouteDTO struct {
+	ID        uint      `json:"id"`
+	TaskKey   string    `json:"task_key"`
+	Branch    string    `json:"branch"`
+	Decision  string    `json:"decision"` // queued / debounced / skipped
+	Reason    string    `json:"reason,omitempty"`
+	SyncRunID uint      `json:"sync_run_id,omitempty"`
+	RunStatus string    `json:"run_status,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookEventPayloadDTO 事件的原始请求（已脱敏）
+type WebhookEventPayloadDTO struct {
+	EventID  uint                `json:"event_id"`
+	Headers  map[string][]string `json:"headers"`
+	Body     json.RawMessage     `json:"body,omitempty"`
+	BodyText string              `json:"body_text,omitempty"` // 非 JSON 请求体
+}
+
+type ReplayWebhookEventReq struct {
+	EventID uint `json:"event_id"`
+	Execute bool `json:"execute"` // false 时仅解析与匹配，不执行动作
+}
+
 type ListWebhookEventsReq struct {
 	EventType string `json:"event_type" query:"event_type"`
 	Source    string `json:"source" query:"source"`
@@ -131,6 +225,7 @@ type WebhookRuleDTO struct {
 	ProviderConfigID uint                   `json:"provider_config_id"`
 	EventTypePattern string                 `json:"event_type_pattern"`
 	RepoPattern      string                 `json:"repo_pattern"`
+	Condition        string                 `json:"condition"`
 	Action           string                 `json:"action"`
 	ActionConfig     map[string]interface{} `json:"action_config"`
 	Enabled          bool                   `json:"enabled"`
@@ -142,7 +237,27 @@ type CreateWebhookRuleReq struct {
 	ProviderConfigID uint                   `json:"provider_config_id"`
 	EventTypePattern string                 `json:"event_type_pattern"`
 	RepoPattern      string                 `json:"repo_pattern"`
+	Condition        string                 `json:"condition"`
 	Action           string                 `json:"action"`
 	ActionConfig     map[string]interface{} `json:"action_config"`
 	Enabled          bool                   `json:"enabled"`
 }
+
+type ListWebhookRulesReq struct {
+	ProviderConfigID uint `json:"provider_config_id" query:"provider_config_id"`
+}
+
+// TestWebhookRuleReq 使用已存储的事件测试草稿规则
+type TestWebhookRuleReq struct {
+	EventID uint                 `json:"event_id"`
+	Rule    CreateWebhookRuleReq `json:"rule"`
+}
+
+type WebhookRuleTestResult struct {
+	Matched          bool                   `json:"matched"`
+	EventTypeMatched bool                   `json:"event_type_matched"`
+	RepoMatched      bool                   `json:"repo_matched"`
+	ConditionResult  bool                   `json:"condition_result"`
+	ConditionError   string                 `json:"condition_error,omitempty"`
+	Env              map[string]interface{} `json:"env,omitempty"`
+}
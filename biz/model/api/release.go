@@ -0,0 +1,78 @@
+package api
+
+import "time"
+
+// ReleaseAssetSource 发布附件来源，path 与 key 二选一：
+// path 为仓库工作目录内的相对路径；key 为对象存储中的对象，bucket 为空时使用 storage.repo_bucket
+type ReleaseAssetSource struct {
+	Name        string `json:"name"` // 为空时取文件名
+	Path        string `json:"path"`
+	Bucket      string `json:"bucket"`
+	Key         string `json:"key"`
+	ContentType string `json:"content_type"`
+}
+
+// CreateReleaseReq 为已存在（或由平台按 target 创建）的 tag 发布 Release
+type CreateReleaseReq struct {
+	RepoKey    string               `json:"repo_key"`
+	TagName    string               `json:"tag_name"`
+	Target     string               `json:"target"`
+	Name       string               `json:"name"`
+	Body       string               `json:"body"`
+	Draft      bool                 `json:"draft"`
+	Prerelease bool                 `json:"prerelease"`
+	Assets     []ReleaseAssetSource `json:"assets"`
+}
+
+// UpdateReleaseReq 未传的字段保持不变，assets 追加上传
+type UpdateReleaseReq struct {
+	RepoKey    string               `json:"repo_key"`
+	TagName    string               `json:"tag_name"`
+	Name       *string              `json:"name"`
+	Body       *string              `json:"body"`
+	Draft      *bool                `json:"draft"`
+	Prerelease *bool                `json:"prerelease"`
+	Assets     []ReleaseAssetSource `json:"assets"`
+}
+
+// TagReleaseReq 一步完成：创建 tag、推送、生成发布说明并发布 Release
+type TagReleaseReq struct {
+	RepoKey     string               `json:"repo_key"`
+	TagName     string               `json:"tag_name"` // "auto" 表示在最新版本号上递增
+	Ref         string               `json:"ref"`
+	Message     string               `json:"message"`
+	Remote      string               `json:"remote"` // 默认 origin
+	Name        string               `json:"name"`   // 默认同 tag 名
+	Body        string               `json:"body"`   // 为空时根据提交生成
+	PreviousTag string               `json:"previous_tag"`
+	Draft       bool                 `json:"draft"`
+	Prerelease  bool                 `json:"prerelease"`
+	Assets      []ReleaseAssetSource `json:"assets"`
+}
+
+// ReleaseDTO 平台 Release；附件上传失败不会回滚 Release，错误记录在 asset_errors
+type ReleaseDTO struct {
+	RepoKey     string             `json:"repo_key"`
+	TagName     string             `json:"tag_name"`
+	Name        string             `json:"name"`
+	Body        string             `json:"body"`
+	Draft       bool               `json:"draft"`
+	Prerelease  bool               `json:"prerelease"`
+	WebURL      string             `json:"web_url"`
+	CreatedAt   time.Time          `json:"created_at"`
+	Assets      []*ReleaseAssetDTO `json:"assets"`
+	AssetErrors []string           `json:"asset_errors,omitempty"`
+}
+
+type ReleaseAssetDTO struct {
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	DownloadURL string `json:"download_url"`
+}
+
+// TagReleaseResultDTO 一步发布的结果；tag 推送失败时不会创建 Release
+type TagReleaseResultDTO struct {
+	TagName     string      `json:"tag_name"`
+	PreviousTag string      `json:"previous_tag,omitempty"`
+	Release     *ReleaseDTO `json:"release"`
+}
@@ -35,6 +35,17 @@ type CloneRepoReq struct {
 	CredentialID uint   `json:"credential_id"` // 凭证 ID (新字段)
 }
 
+// FetchRepoReq 拉取仓库请求
+type FetchRepoReq struct {
+	RepoKey string `json:"repo_key"`
+	Async   bool   `json:"async"` // 为 true 时提交到后台任务执行器，返回 task_id
+}
+
+// BackupRepoReq 备份仓库请求
+type BackupRepoReq struct {
+	RepoKey string `json:"repo_key"`
+}
+
 type TestConnectionReq struct {
 	URL string `json:"url"`
 }
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+// RepoImportReq 从 provider 批量导入组织/组下的仓库
+type RepoImportReq struct {
+	Owner  string              `json:"owner"` // 组 / 组织 / 项目，为空时导入凭证用户可见的仓库
+	Filter po.RepoImportFilter `json:"filter"`
+	// PathTemplate 本地路径模板（text/template），可用 {{.Platform}} {{.Owner}} {{.Name}} {{.FullName}}
+	PathTemplate string `json:"path_template"`
+	// CredentialID 克隆使用的凭证，为空时使用 provider 配置的凭证
+	CredentialID   uint                         `json:"credential_id"`
+	MirrorTemplate *po.RepoImportMirrorTemplate `json:"mirror_template"`
+	DryRun         bool                         `json:"dry_run"`
+}
+
+type RepoImportSummary struct {
+	Total    int `json:"total"`
+	Planned  int `json:"planned"`
+	Cloning  int `json:"cloning"`
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+}
+
+type RepoImportItemDTO struct {
+	ID          uint      `json:"id"`
+	FullName    string    `json:"full_name"`
+	RemoteURL   string    `json:"remote_url"`
+	LocalPath   string    `json:"local_path"`
+	RepoName    string    `json:"repo_name,omitempty"`
+	Status      string    `json:"status"`
+	Reason      string    `json:"reason,omitempty"`
+	CloneTaskID string    `json:"clone_task_id,omitempty"`
+	RepoKey     string    `json:"repo_key,omitempty"`
+	SyncTaskKey string    `json:"sync_task_key,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type RepoImportDTO struct {
+	ID               uint                         `json:"id"`
+	ProviderConfigID uint                         `json:"provider_config_id"`
+	Owner            string                       `json:"owner"`
+	Filter           po.RepoImportFilter          `json:"filter"`
+	PathTemplate     string                       `json:"path_template"`
+	CredentialID     uint                         `json:"credential_id"`
+	MirrorTemplate   *po.RepoImportMirrorTemplate `json:"mirror_template,omitempty"`
+	DryRun           bool                         `json:"dry_run"`
+	Status           string                       `json:"status"`
+	ErrorMessage     string                       `json:"error_message,omitempty"`
+	PlanTaskID       string                       `json:"plan_task_id,omitempty"`
+	Summary          RepoImportSummary            `json:"summary"`
+	Items            []RepoImportItemDTO          `json:"items,omitempty"`
+	CreatedAt        time.Time                    `json:"created_at"`
+	UpdatedAt        time.Time                    `json:"updated_at"`
+}
+
+func NewRepoImportDTO(job *po.RepoImport, items []po.RepoImportItem, withItems bool) RepoImportDTO {
+	dto := RepoImportDTO{
+		ID: job.ID, ProviderConfigID: job.ProviderConfigID, Owner: job.Owner,
+		Filter: job.Filter, PathTemplate: job.PathTemplate, CredentialID: job.CredentialID,
+		MirrorTemplate: job.MirrorTemplate, DryRun: job.DryRun,
+		Status: job.Status, ErrorMessage: job.ErrorMessage, PlanTaskID: job.PlanTaskID,
+		CreatedAt: job.CreatedAt, UpdatedAt: job.UpdatedAt,
+	}
+	dto.Summary.Total = len(items)
+	for _, it := range items {
+		switch it.Status {
+		case po.RepoImportItemPlanned:
+			dto.Summary.Planned++
+		case po.RepoImportItemCloning:
+			dto.Summary.Cloning++
+		case po.RepoImportItemImported:
+			dto.Summary.Imported++
+		case po.RepoImportItemSkipped:
+			dto.Summary.Skipped++
+		case po.RepoImportItemFailed:
+			dto.Summary.Failed++
+		}
+		if withItems {
+			dto.Items = append(dto.Items, RepoImportItemDTO{
+				ID: it.ID, FullName: it.FullName, RemoteURL: it.RemoteURL, LocalPath: it.LocalPath,
+				RepoName: it.RepoName, Status: it.Status, Reason: it.Reason, CloneTaskID: it.CloneTaskID,
+				RepoKey: it.RepoKey, SyncTaskKey: it.SyncTaskKey, UpdatedAt: it.UpdatedAt,
+			})
+		}
+	}
+	return dto
+}
@@ -15,6 +15,8 @@ type SyncRunDTO struct {
 	Details      string      `json:"details"`
 	StartTime    time.Time   `json:"start_time"`
 	EndTime      time.Time   `json:"end_time"`
+	CRNumber     int         `json:"cr_number,omitempty"`
+	CRWebURL     string      `json:"cr_web_url,omitempty"`
 	CreatedAt    time.Time   `json:"created_at"`
 	UpdatedAt    time.Time   `json:"updated_at"`
 	Task         SyncTaskDTO `json:"task"`
@@ -30,6 +32,8 @@ func NewSyncRunDTO(r po.SyncRun) SyncRunDTO {
 		Details:      r.Details,
 		StartTime:    r.StartTime,
 		EndTime:      r.EndTime,
+		CRNumber:     r.CRNumber,
+		CRWebURL:     r.CRWebURL,
 		CreatedAt:    r.CreatedAt,
 		UpdatedAt:    r.UpdatedAt,
 	}
@@ -38,3 +42,41 @@ func NewSyncRunDTO(r po.SyncRun) SyncRunDTO {
 	}
 	return dto
 }
+
+// CreateTargetRepoReq 创建同步任务时在目标平台新建空仓库，并注册为目标本地仓库的 remote
+type CreateTargetRepoReq struct {
+	ProviderConfigID uint   `json:"provider_config_id"`
+	Owner            string `json:"owner"` // 组 / 组织 / 项目，为空时建在凭证用户名下
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	Visibility       string `json:"visibility"` // private | internal | public，默认 private
+	DefaultBranch    string `json:"default_branch"`
+	// RemoteName 为空时依次取任务的 target_remote、平台名
+	RemoteName string `json:"remote_name"`
+}
+
+// AutoCRReq 同步推送到暂存分支后自动创建 / 更新合入 CRTargetBranch 的 CR
+type AutoCRReq struct {
+	Enabled      bool   `json:"enabled"`
+	TargetBranch string `json:"target_branch"`
+	Title        string `json:"title"`
+}
+
+// SyncTaskExtraReq sync.CreateTaskRequest / UpdateTaskRequest 之外的扩展字段
+type SyncTaskExtraReq struct {
+	CreateTargetRepo *CreateTargetRepoReq `json:"create_target_repo"`
+	// AutoCR 更新任务时为空表示保持不变
+	AutoCR *AutoCRReq `json:"auto_cr"`
+	// Tags 更新任务时为空表示保持不变，传空数组清空
+	Tags *[]string `json:"tags"`
+}
+
+type TargetRepoDTO struct {
+	ProviderConfigID uint   `json:"provider_config_id"`
+	Platform         string `json:"platform"`
+	FullName         string `json:"full_name"`
+	RemoteName       string `json:"remote_name"`
+	RemoteURL        string `json:"remote_url"`
+	// Linked 目标本地仓库此前未绑定平台仓库时，会绑定到新建的仓库
+	Linked bool `json:"linked"`
+}
@@ -26,8 +26,17 @@ type SyncTaskDTO struct {
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 
+	AutoCR         bool   `json:"auto_cr"`
+	CRTargetBranch string `json:"cr_target_branch,omitempty"`
+	CRTitle        string `json:"cr_title,omitempty"`
+
+	Tags []string `json:"tags"`
+
 	SourceRepo RepoDTO `json:"source_repo"`
 	TargetRepo RepoDTO `json:"target_repo"`
+
+	// CreatedTargetRepo 仅在创建任务时新建了目标平台仓库时返回
+	CreatedTargetRepo *TargetRepoDTO `json:"created_target_repo,omitempty"`
 }
 
 func NewSyncTaskDTO(t po.SyncTask) SyncTaskDTO {
@@ -50,6 +59,12 @@ func NewSyncTaskDTO(t po.SyncTask) SyncTaskDTO {
 		GitNoVerify:   t.GitNoVerify,
 		CreatedAt:     t.CreatedAt,
 		UpdatedAt:     t.UpdatedAt,
+
+		AutoCR:         t.AutoCR,
+		CRTargetBranch: t.CRTargetBranch,
+		CRTitle:        t.CRTitle,
+
+		Tags: t.Tags,
 	}
 	if t.SourceRepo.ID != 0 {
 		dto.SourceRepo = NewRepoDTO(t.SourceRepo)
@@ -59,3 +74,16 @@ func NewSyncTaskDTO(t po.SyncTask) SyncTaskDTO {
 	}
 	return dto
 }
+
+// ListAsyncTasksReq 后台任务列表请求
+type ListAsyncTasksReq struct {
+	Type     string `json:"type" query:"type"`
+	Status   string `json:"status" query:"status"`
+	Page     int    `json:"page" query:"page"`
+	PageSize int    `json:"page_size" query:"page_size"`
+}
+
+// CancelAsyncTaskReq 取消后台任务请求
+type CancelAsyncTaskReq struct {
+	TaskID string `json:"task_id"`
+}
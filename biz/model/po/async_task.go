@@ -0,0 +1,59 @@
+package po
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 异步任务类型
+const (
+	AsyncTaskTypeClone  = "clone"  // 克隆仓库
+	AsyncTaskTypeFetch  = "fetch"  // 拉取远程
+	AsyncTaskTypeBackup = "backup" // 备份仓库
+	AsyncTaskTypeImport = "import" // 列举并规划批量导入
+)
+
+// 异步任务状态
+const (
+	AsyncTaskStatusQueued    = "queued"
+	AsyncTaskStatusRunning   = "running"
+	AsyncTaskStatusSuccess   = "success"
+	AsyncTaskStatusFailed    = "failed"
+	AsyncTaskStatusCancelled = "cancelled"
+)
+
+// AsyncTask 异步任务记录（克隆、拉取、备份等后台任务）
+type AsyncTask struct {
+	gorm.Model
+	TaskID       string    `gorm:"uniqueIndex;size:64" json:"task_id"`
+	Type         string    `gorm:"size:20;index" json:"type"`
+	Target       string    `gorm:"size:500" json:"target"` // 仓库 Key 或远程 URL
+	Status       string    `gorm:"size:20;index" json:"status"`
+	ProgressJSON string    `gorm:"type:text" json:"-"`
+	Progress     []string  `gorm:"-" json:"progress"`
+	Error        string    `gorm:"type:text" json:"error"`
+	StartTime    time.Time `json:"start_time"`
+	EndTime      time.Time `json:"end_time"`
+}
+
+func (AsyncTask) TableName() string { return "async_tasks" }
+
+func (t *AsyncTask) BeforeSave(tx *gorm.DB) error {
+	if t.Progress != nil {
+		b, err := json.Marshal(t.Progress)
+		if err != nil {
+			return err
+		}
+		t.ProgressJSON = string(b)
+	}
+	return nil
+}
+
+func (t *AsyncTask) AfterFind(tx *gorm.DB) error {
+	if t.ProgressJSON != "" {
+		json.Unmarshal([]byte(t.ProgressJSON), &t.Progress)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package po
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// BranchPolicy 期望的分支保护策略（最低要求）。RepoKey 为空时作为所有已绑定平台仓库的默认策略，
+// 同一分支另有仓库级策略时以仓库级为准；Branch 为空表示平台上的默认分支
+type BranchPolicy struct {
+	gorm.Model
+	RepoKey            string   `gorm:"size:100;uniqueIndex:idx_branch_policy_repo_branch" json:"repo_key"`
+	Branch             string   `gorm:"size:200;uniqueIndex:idx_branch_policy_repo_branch" json:"branch"`
+	RequiredReviews    int      `json:"required_reviews"`
+	AllowForcePush     bool     `json:"allow_force_push"`
+	RequireChecks      bool     `json:"require_checks"`
+	RequiredChecksJSON string   `gorm:"type:text" json:"-"`
+	RequiredChecks     []string `gorm:"-" json:"required_checks"`
+}
+
+func (BranchPolicy) TableName() string { return "branch_policies" }
+
+func (p *BranchPolicy) BeforeSave(tx *gorm.DB) error {
+	b, err := json.Marshal(p.RequiredChecks)
+	if err != nil {
+		return err
+	}
+	p.RequiredChecksJSON = string(b)
+	return nil
+}
+
+func (p *BranchPolicy) AfterFind(tx *gorm.DB) error {
+	if p.RequiredChecksJSON != "" {
+		json.Unmarshal([]byte(p.RequiredChecksJSON), &p.RequiredChecks)
+	}
+	return nil
+}
@@ -26,6 +26,12 @@ type ChangeRequest struct {
 	Labels           []string   `gorm:"-" json:"labels"`
 	MergedAt         *time.Time `json:"merged_at"`
 	ClosedAt         *time.Time `json:"closed_at"`
+	// HeadSHA 源分支最新提交；CheckState 为该提交 CI 检查的汇总状态（none/pending/success/failure）
+	HeadSHA        string     `gorm:"size:64" json:"head_sha"`
+	CheckState     string     `gorm:"size:20" json:"check_state"`
+	CheckUpdatedAt *time.Time `json:"check_updated_at"`
+	// SyncRunID 由同步任务自动创建 / 更新时，最近一次写入该 CR 的 SyncRun
+	SyncRunID uint `gorm:"index" json:"sync_run_id"`
 }
 
 func (ChangeRequest) TableName() string { return "change_requests" }
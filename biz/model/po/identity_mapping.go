@@ -0,0 +1,22 @@
+// biz/model/po/identity_mapping.go - Git 身份与聊天账号映射PO
+
+package po
+
+import (
+	"gorm.io/gorm"
+)
+
+// IdentityMapping Git 提交邮箱到各聊天平台账号的映射，用于通知中 @ 提交作者
+type IdentityMapping struct {
+	gorm.Model
+	GitEmail       string `gorm:"size:200;uniqueIndex" json:"git_email"` // 统一小写
+	DisplayName    string `gorm:"size:100" json:"display_name"`
+	Mobile         string `gorm:"size:30" json:"mobile"`            // 钉钉 atMobiles、企业微信 mentioned_mobile_list
+	DingTalkUserID string `gorm:"size:100" json:"dingtalk_user_id"` // 钉钉 atUserIds
+	FeishuUserID   string `gorm:"size:100" json:"feishu_user_id"`   // 飞书 open_id 或 user_id
+	WeComUserID    string `gorm:"size:100" json:"wecom_user_id"`    // 企业微信 userid，mentioned_list
+}
+
+func (IdentityMapping) TableName() string {
+	return "identity_mappings"
+}
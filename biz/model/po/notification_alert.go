@@ -0,0 +1,40 @@
+// biz/model/po/notification_alert.go - 通知告警状态PO
+
+package po
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 告警状态
+const (
+	AlertStateFiring   = "firing"   // 持续失败中
+	AlertStateResolved = "resolved" // 已恢复或手动关闭
+)
+
+// NotificationAlert 同一任务（无任务时为仓库）同一失败事件的告警状态，
+// 用于重复失败去重、提醒、升级和恢复通知
+type NotificationAlert struct {
+	gorm.Model
+	SubjectKey          string     `gorm:"size:100;index:idx_alert_subject" json:"subject_key"` // 任务 key，无任务时为仓库 key
+	TaskKey             string     `gorm:"size:100" json:"task_key"`
+	RepoKey             string     `gorm:"size:100" json:"repo_key"`
+	EventType           string     `gorm:"size:50;index:idx_alert_subject" json:"event_type"` // sync_failure, sync_conflict, backup_failure
+	State               string     `gorm:"size:20;index" json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	SuppressedCount     int        `json:"suppressed_count"` // 被去重未发送的次数
+	LastError           string     `gorm:"size:500" json:"last_error"`
+	FirstFailedAt       time.Time  `json:"first_failed_at"`
+	LastFailedAt        time.Time  `json:"last_failed_at"`
+	LastNotifiedAt      *time.Time `json:"last_notified_at"`
+	Escalated           bool       `json:"escalated"`
+	EscalatedAt         *time.Time `json:"escalated_at"`
+	ResolvedAt          *time.Time `json:"resolved_at"`
+	ResolvedBy          string     `gorm:"size:20" json:"resolved_by"` // recovery, manual
+}
+
+func (NotificationAlert) TableName() string {
+	return "notification_alerts"
+}
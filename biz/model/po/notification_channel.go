@@ -3,6 +3,8 @@
 package po
 
 import (
+	"encoding/json"
+
 	"gorm.io/gorm"
 )
 
@@ -10,7 +12,7 @@ import (
 type NotificationChannel struct {
 	gorm.Model
 	Name            string `gorm:"size:100" json:"name"`
-	Type            string `gorm:"size:50;index" json:"type"` // email, dingtalk, wechat, webhook, lanxin, feishu
+	Type            string `gorm:"size:50;index" json:"type"` // email, dingtalk, wechat, webhook, lanxin, feishu, slack, teams, discord, telegram, matrix
 	Config          string `gorm:"type:text" json:"config"`   // JSON配置
 	Enabled         bool   `json:"enabled"`
 	NotifyOnSuccess bool   `json:"notify_on_success"`                 // 向后兼容
@@ -18,8 +20,33 @@ type NotificationChannel struct {
 	TriggerEvents   string `gorm:"type:text" json:"trigger_events"`   // JSON数组，触发事件列表
 	TitleTemplate   string `gorm:"type:text" json:"title_template"`   // 自定义标题模板
 	ContentTemplate string `gorm:"type:text" json:"content_template"` // 自定义内容模板
+
+	// 投递模式：batch / digest 下事件先进入汇总队列，到期后合并成一条消息发送
+	DeliveryMode          string `gorm:"size:20;default:immediate" json:"delivery_mode"` // immediate, batch, digest
+	BatchIntervalMinutes  int    `json:"batch_interval_minutes"`                         // batch：合并间隔（分钟）
+	DigestTime            string `gorm:"size:5" json:"digest_time"`                      // digest：每日发送时间 HH:MM（服务器本地时间）
+	ImmediateEvents       string `gorm:"type:text" json:"immediate_events"`              // JSON数组，汇总模式下仍立即发送的事件；为空时失败与冲突立即发送
+	DigestTitleTemplate   string `gorm:"type:text" json:"digest_title_template"`         // 汇总消息标题模板
+	DigestContentTemplate string `gorm:"type:text" json:"digest_content_template"`       // 汇总消息内容模板
+
+	// Escalation 升级渠道：不接收普通通知，只接收连续失败达到阈值后的告警及其恢复通知
+	Escalation bool `json:"escalation"`
+
+	// QuietHours 静默时段：时段内的非紧急消息延后到时段结束再发送
+	QuietHoursJSON string            `gorm:"column:quiet_hours;type:text" json:"-"`
+	QuietHours     *QuietHoursConfig `gorm:"-" json:"quiet_hours"`
 }
 
+// 渠道投递模式
+const (
+	DeliveryModeImmediate = "immediate" // 立即发送
+	DeliveryModeBatch     = "batch"     // 每 N 分钟合并发送
+	DeliveryModeDigest    = "digest"    // 每日定时摘要
+)
+
+// DigestEventType 汇总消息在投递记录中的事件类型
+const DigestEventType = "digest"
+
 // TriggerEvent 触发事件类型
 const (
 	TriggerSyncSuccess     = "sync_success"     // 同步成功
@@ -30,12 +57,55 @@ const (
 	TriggerCronTriggered   = "cron_triggered"   // 定时任务触发
 	TriggerBackupSuccess   = "backup_success"   // 备份成功
 	TriggerBackupFailure   = "backup_failure"   // 备份失败
+	TriggerAlertRecovered  = "alert_recovered"  // 告警恢复（失败后再次成功）
 )
 
 func (NotificationChannel) TableName() string {
 	return "notification_channels"
 }
 
+func (c *NotificationChannel) BeforeSave(tx *gorm.DB) error {
+	if c.QuietHours == nil {
+		c.QuietHoursJSON = ""
+		return nil
+	}
+	b, err := json.Marshal(c.QuietHours)
+	if err != nil {
+		return err
+	}
+	c.QuietHoursJSON = string(b)
+	return nil
+}
+
+func (c *NotificationChannel) AfterFind(tx *gorm.DB) error {
+	if c.QuietHoursJSON != "" {
+		json.Unmarshal([]byte(c.QuietHoursJSON), &c.QuietHours)
+	}
+	return nil
+}
+
+// 消息级别，静默时段内达到 Breakthrough 级别的消息照常发送
+const (
+	SeverityInfo     = "info"     // 成功、接收、恢复等
+	SeverityWarning  = "warning"  // 同步冲突
+	SeverityCritical = "critical" // 失败、升级告警
+	SeverityNone     = "none"     // 仅用于 Breakthrough：全部延后
+)
+
+// QuietHoursConfig 渠道静默时段
+type QuietHoursConfig struct {
+	Timezone     string        `json:"timezone"`     // IANA 时区，如 Asia/Shanghai；为空使用服务器时区
+	Windows      []QuietWindow `json:"windows"`      // 多个时段，相邻或重叠时连续延后
+	Breakthrough string        `json:"breakthrough"` // 可穿透静默的最低级别：warning（默认）、critical、none
+}
+
+// QuietWindow 一个静默时段，End 不晚于 Start 表示跨天（如 22:00-08:00）
+type QuietWindow struct {
+	Start string   `json:"start"` // HH:MM
+	End   string   `json:"end"`   // HH:MM
+	Days  []string `json:"days"`  // mon..sun，按时段开始的那天判断；为空表示每天
+}
+
 // EmailConfig 邮件配置
 type EmailConfig struct {
 	SMTPHost    string   `json:"smtp_host"`
@@ -85,6 +155,59 @@ type FeishuConfig struct {
 	Keywords     string `json:"keywords"`      // 关键字（keyword模式）
 }
 
+// SlackConfig Slack Incoming Webhook 配置
+type SlackConfig struct {
+	WebhookURL string `json:"webhook_url"`
+	Channel    string `json:"channel"`    // 覆盖默认频道（可选，旧版 webhook 支持）
+	Username   string `json:"username"`   // 覆盖显示名称（可选）
+	IconEmoji  string `json:"icon_emoji"` // 如 :rocket:（可选）
+	PlainText  bool   `json:"plain_text"` // 为 true 时只发送纯文本，不使用 Block Kit
+}
+
+// TeamsConfig Microsoft Teams Incoming Webhook / Workflows 配置
+type TeamsConfig struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+// DiscordConfig Discord Webhook 配置
+type DiscordConfig struct {
+	WebhookURL string `json:"webhook_url"`
+	Username   string `json:"username"`   // 覆盖显示名称（可选）
+	AvatarURL  string `json:"avatar_url"` // 覆盖头像（可选）
+}
+
+// TelegramConfig Telegram Bot 配置
+type TelegramConfig struct {
+	BotToken   string `json:"bot_token"`
+	ChatID     string `json:"chat_id"`      // 用户、群组 ID 或 @channelusername
+	APIBaseURL string `json:"api_base_url"` // 默认 https://api.telegram.org，可指向自建 Bot API 服务
+	Silent     bool   `json:"silent"`       // 静默发送（不响铃）
+}
+
+// MatrixConfig Matrix 配置
+type MatrixConfig struct {
+	HomeserverURL string `json:"homeserver_url"` // 如 https://matrix.org
+	AccessToken   string `json:"access_token"`
+	RoomID        string `json:"room_id"` // 如 !abc:matrix.org
+	MsgType       string `json:"msgtype"` // m.text（默认）或 m.notice
+}
+
+// NotificationDigestItem 等待合并发送的事件，发送汇总后删除
+type NotificationDigestItem struct {
+	gorm.Model
+	ChannelID    uint   `gorm:"not null;index" json:"channel_id"`
+	TriggerEvent string `gorm:"size:50" json:"trigger_event"`
+	Status       string `gorm:"size:20" json:"status"` // success, failure
+	TaskKey      string `gorm:"size:100" json:"task_key"`
+	RepoKey      string `gorm:"size:100" json:"repo_key"`
+	Title        string `gorm:"type:text" json:"title"`
+	DataJSON     string `gorm:"type:text" json:"-"`
+}
+
+func (NotificationDigestItem) TableName() string {
+	return "notification_digest_items"
+}
+
 // NotificationEventTemplate 事件级消息模板
 type NotificationEventTemplate struct {
 	gorm.Model
@@ -97,3 +220,29 @@ type NotificationEventTemplate struct {
 func (NotificationEventTemplate) TableName() string {
 	return "notification_event_templates"
 }
+
+// 路由规则动作与匹配字段
+const (
+	RoutingEffectInclude = "include"
+	RoutingEffectExclude = "exclude"
+
+	RoutingFieldRepo          = "repo"           // 仓库 key 或名称
+	RoutingFieldTask          = "task"           // 同步任务 key
+	RoutingFieldTaskTag       = "task_tag"       // 同步任务标签
+	RoutingFieldTriggerSource = "trigger_source" // manual, cron, webhook
+)
+
+// NotificationRoutingRule 渠道路由规则，Pattern 支持 * ? [] 通配符。
+// 渠道没有规则时接收全部事件；任一 exclude 命中即不发送；
+// 存在 include 规则的字段，事件至少要命中其中一条（不同字段之间为且）
+type NotificationRoutingRule struct {
+	gorm.Model
+	ChannelID uint   `gorm:"not null;index" json:"channel_id"`
+	Effect    string `gorm:"size:20" json:"effect"`
+	Field     string `gorm:"size:30" json:"field"`
+	Pattern   string `gorm:"size:200" json:"pattern"`
+}
+
+func (NotificationRoutingRule) TableName() string {
+	return "notification_routing_rules"
+}
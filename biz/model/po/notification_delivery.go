@@ -0,0 +1,45 @@
+// biz/model/po/notification_delivery.go - 通知投递记录PO
+
+package po
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 通知投递状态
+const (
+	DeliveryStatusPending   = "pending"   // 待发送
+	DeliveryStatusSending   = "sending"   // 发送中
+	DeliveryStatusSucceeded = "succeeded" // 发送成功
+	DeliveryStatusFailed    = "failed"    // 发送失败，等待自动重试
+	DeliveryStatusDead      = "dead"      // 重试次数耗尽
+	DeliveryStatusDeferred  = "deferred"  // 渠道静默时段内延后，NextRetryAt 为时段结束时间
+)
+
+// NotificationDelivery 一条通知在一个渠道上的投递记录，保存渲染后的标题与内容用于重试和重发
+type NotificationDelivery struct {
+	gorm.Model
+	ChannelID     uint       `gorm:"index" json:"channel_id"`
+	ChannelName   string     `gorm:"size:100" json:"channel_name"`
+	ChannelType   string     `gorm:"size:50" json:"channel_type"`
+	TriggerEvent  string     `gorm:"size:50;index" json:"trigger_event"`
+	MessageStatus string     `gorm:"size:20" json:"message_status"` // 消息本身的状态：success, failure
+	TaskKey       string     `gorm:"size:100" json:"task_key"`
+	RepoKey       string     `gorm:"size:100" json:"repo_key"`
+	Title         string     `gorm:"type:text" json:"title"`
+	Content       string     `gorm:"type:text" json:"content"`
+	DataJSON      string     `gorm:"type:text" json:"-"`      // 模板数据，供富文本渠道重试时还原字段
+	MentionsJSON  string     `gorm:"type:text" json:"-"`      // 渲染时解析出的 @ 提及账号
+	Severity      string     `gorm:"size:20" json:"severity"` // 消息级别，自动重试时据此判断是否受静默时段限制
+	Status        string     `gorm:"size:20;index" json:"status"`
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	LastError     string     `gorm:"size:500" json:"last_error"`
+	NextRetryAt   *time.Time `gorm:"index" json:"next_retry_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+}
+
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
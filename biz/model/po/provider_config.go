@@ -1,6 +1,8 @@
 package po
 
 import (
+	"encoding/json"
+
 	"github.com/yi-nology/git-manage-service/biz/utils"
 	"gorm.io/gorm"
 )
@@ -13,6 +15,9 @@ type ProviderConfig struct {
 	CredentialID    uint   `gorm:"index" json:"credential_id"`
 	WebhookSecret   string `gorm:"size:200" json:"webhook_secret"`
 	WebhookEndpoint string `gorm:"size:500" json:"webhook_endpoint"`
+	// 平台相关的扩展配置，如 generic 平台的字段映射与签名设置
+	OptionsJSON string                 `gorm:"type:text" json:"-"`
+	Options     map[string]interface{} `gorm:"-" json:"options"`
 }
 
 func (ProviderConfig) TableName() string { return "provider_configs" }
@@ -25,6 +30,13 @@ func (p *ProviderConfig) BeforeSave(tx *gorm.DB) error {
 		}
 		p.WebhookSecret = enc
 	}
+	if p.Options != nil {
+		b, err := json.Marshal(p.Options)
+		if err != nil {
+			return err
+		}
+		p.OptionsJSON = string(b)
+	}
 	return nil
 }
 
@@ -35,5 +47,8 @@ func (p *ProviderConfig) AfterFind(tx *gorm.DB) error {
 			p.WebhookSecret = dec
 		}
 	}
+	if p.OptionsJSON != "" {
+		json.Unmarshal([]byte(p.OptionsJSON), &p.Options)
+	}
 	return nil
 }
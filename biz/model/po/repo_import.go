@@ -0,0 +1,119 @@
+package po
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// 批量导入任务状态
+const (
+	RepoImportStatusRunning   = "running"   // 列举规划或克隆进行中
+	RepoImportStatusCompleted = "completed" // 所有仓库处理完毕
+	RepoImportStatusFailed    = "failed"    // 列举或规划失败
+)
+
+// 单个仓库的导入结果
+const (
+	RepoImportItemPlanned  = "planned"  // dry run，仅列出将导入的仓库
+	RepoImportItemCloning  = "cloning"  // 已提交克隆任务
+	RepoImportItemImported = "imported" // 克隆并注册成功
+	RepoImportItemSkipped  = "skipped"  // 已注册、路径已存在等
+	RepoImportItemFailed   = "failed"
+)
+
+// RepoImportFilter 导入过滤条件
+type RepoImportFilter struct {
+	NamePattern     string   `json:"name_pattern"` // glob，匹配 full_name 或仓库名，为空不过滤
+	IncludeArchived bool     `json:"include_archived"`
+	Visibility      []string `json:"visibility"` // private / internal / public，为空不过滤
+}
+
+// RepoImportTargetRepo 镜像任务在目标平台新建的仓库，仓库名与源仓库相同
+type RepoImportTargetRepo struct {
+	ProviderConfigID uint   `json:"provider_config_id"`
+	Owner            string `json:"owner"`
+	Visibility       string `json:"visibility"`
+}
+
+// RepoImportMirrorTemplate 为每个导入的仓库创建同步任务的模板；
+// 目标 remote 由 CreateTargetRepo 新建，或按 TargetURLTemplate 渲染地址后添加
+type RepoImportMirrorTemplate struct {
+	TargetRemote       string                `json:"target_remote"`
+	TargetURLTemplate  string                `json:"target_url_template"` // 如 git@mirror:{{.Owner}}/{{.Name}}.git
+	TargetCredentialID uint                  `json:"target_credential_id"`
+	CreateTargetRepo   *RepoImportTargetRepo `json:"create_target_repo"`
+	SourceBranch       string                `json:"source_branch"`
+	TargetBranch       string                `json:"target_branch"`
+	SyncMode           string                `json:"sync_mode"`
+	PushOptions        string                `json:"push_options"`
+	Cron               string                `json:"cron"`
+	Enabled            bool                  `json:"enabled"`
+	GitTags            bool                  `json:"git_tags"`
+	GitForce           bool                  `json:"git_force"`
+	GitPrune           bool                  `json:"git_prune"`
+}
+
+// RepoImport 从 provider 批量导入组织/组下仓库的任务
+type RepoImport struct {
+	gorm.Model
+	ProviderConfigID   uint                      `gorm:"index" json:"provider_config_id"`
+	Owner              string                    `gorm:"size:200" json:"owner"`
+	FilterJSON         string                    `gorm:"type:text" json:"-"`
+	Filter             RepoImportFilter          `gorm:"-" json:"filter"`
+	PathTemplate       string                    `gorm:"size:500" json:"path_template"`
+	CredentialID       uint                      `json:"credential_id"`
+	MirrorTemplateJSON string                    `gorm:"type:text" json:"-"`
+	MirrorTemplate     *RepoImportMirrorTemplate `gorm:"-" json:"mirror_template"`
+	DryRun             bool                      `json:"dry_run"`
+	Status             string                    `gorm:"size:20;index" json:"status"`
+	ErrorMessage       string                    `gorm:"size:500" json:"error_message"`
+	PlanTaskID         string                    `gorm:"size:64" json:"plan_task_id"` // 列举与规划所在的异步任务
+}
+
+func (RepoImport) TableName() string { return "repo_imports" }
+
+func (r *RepoImport) BeforeSave(tx *gorm.DB) error {
+	b, err := json.Marshal(r.Filter)
+	if err != nil {
+		return err
+	}
+	r.FilterJSON = string(b)
+	r.MirrorTemplateJSON = ""
+	if r.MirrorTemplate != nil {
+		b, err := json.Marshal(r.MirrorTemplate)
+		if err != nil {
+			return err
+		}
+		r.MirrorTemplateJSON = string(b)
+	}
+	return nil
+}
+
+func (r *RepoImport) AfterFind(tx *gorm.DB) error {
+	if r.FilterJSON != "" {
+		json.Unmarshal([]byte(r.FilterJSON), &r.Filter)
+	}
+	if r.MirrorTemplateJSON != "" {
+		r.MirrorTemplate = &RepoImportMirrorTemplate{}
+		json.Unmarshal([]byte(r.MirrorTemplateJSON), r.MirrorTemplate)
+	}
+	return nil
+}
+
+// RepoImportItem 导入任务中单个平台仓库的处理结果
+type RepoImportItem struct {
+	gorm.Model
+	ImportID    uint   `gorm:"index" json:"import_id"`
+	FullName    string `gorm:"size:300" json:"full_name"`
+	RemoteURL   string `gorm:"size:500" json:"remote_url"`
+	LocalPath   string `gorm:"size:500" json:"local_path"`
+	RepoName    string `gorm:"size:200" json:"repo_name"` // 规划阶段预留的仓库名，同一次导入内不重复
+	Status      string `gorm:"size:20;index" json:"status"`
+	Reason      string `gorm:"size:500" json:"reason"`
+	CloneTaskID string `gorm:"size:64" json:"clone_task_id"`
+	RepoKey     string `gorm:"size:100" json:"repo_key"`
+	SyncTaskKey string `gorm:"size:100" json:"sync_task_key"`
+}
+
+func (RepoImportItem) TableName() string { return "repo_import_items" }
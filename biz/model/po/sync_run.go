@@ -23,6 +23,9 @@ type SyncRun struct {
 	Details       string    `json:"details" gorm:"type:text"` // Execution logs
 	StartTime     time.Time `json:"start_time"`
 	EndTime       time.Time `json:"end_time"`
+	// 开启 AutoCR 的任务本次创建 / 更新的 CR
+	CRNumber int    `json:"cr_number"`
+	CRWebURL string `json:"cr_web_url"`
 
 	// Associations
 	Task SyncTask `gorm:"foreignKey:TaskKey;references:Key" json:"task"`
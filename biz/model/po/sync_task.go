@@ -1,6 +1,8 @@
 package po
 
 import (
+	"encoding/json"
+
 	"gorm.io/gorm"
 )
 
@@ -23,6 +25,13 @@ type SyncTask struct {
 	GitForce      bool   `gorm:"default:false" json:"git_force"`
 	GitPrune      bool   `gorm:"default:false" json:"git_prune"`
 	GitNoVerify   bool   `gorm:"default:false" json:"git_no_verify"`
+	// AutoCR 推送成功后，在目标仓库的平台上创建或更新 TargetBranch -> CRTargetBranch 的 CR（仅单分支模式）
+	AutoCR         bool   `gorm:"default:false" json:"auto_cr"`
+	CRTargetBranch string `json:"cr_target_branch"`
+	CRTitle        string `json:"cr_title"` // 为空时自动生成
+	// Tags 任务标签，用于通知路由等按组筛选
+	TagsJSON string   `gorm:"type:text" json:"-"`
+	Tags     []string `gorm:"-" json:"tags"`
 
 	// Associations
 	SourceRepo Repo `gorm:"foreignKey:SourceRepoKey;references:Key" json:"source_repo"`
@@ -32,3 +41,19 @@ type SyncTask struct {
 func (SyncTask) TableName() string {
 	return "sync_tasks"
 }
+
+func (t *SyncTask) BeforeSave(tx *gorm.DB) error {
+	b, err := json.Marshal(t.Tags)
+	if err != nil {
+		return err
+	}
+	t.TagsJSON = string(b)
+	return nil
+}
+
+func (t *SyncTask) AfterFind(tx *gorm.DB) error {
+	if t.TagsJSON != "" {
+		json.Unmarshal([]byte(t.TagsJSON), &t.Tags)
+	}
+	return nil
+}
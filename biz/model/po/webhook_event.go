@@ -7,6 +7,15 @@ import (
 	"gorm.io/gorm"
 )
 
+// Webhook 事件处理状态
+const (
+	WebhookEventStatusPending    = "pending"    // 待处理
+	WebhookEventStatusProcessing = "processing" // 处理中
+	WebhookEventStatusSucceeded  = "succeeded"  // 全部动作成功（或无匹配规则）
+	WebhookEventStatusFailed     = "failed"     // 存在失败动作，等待自动重试
+	WebhookEventStatusDead       = "dead"       // 重试次数耗尽，进入死信
+)
+
 type WebhookEvent struct {
 	gorm.Model
 	EventID          string                 `gorm:"uniqueIndex;size:100" json:"event_id"`
@@ -21,6 +30,8 @@ type WebhookEvent struct {
 	PayloadJSON      string                 `gorm:"type:text" json:"-"`
 	Payload          map[string]interface{} `gorm:"-" json:"payload"`
 	Status           string                 `gorm:"size:20;index" json:"status"`
+	Attempts         int                    `gorm:"default:0" json:"attempts"`
+	NextRetryAt      *time.Time             `gorm:"index" json:"next_retry_at"`
 	ProcessedAt      *time.Time             `json:"processed_at"`
 	ErrorMessage     string                 `gorm:"size:500" json:"error_message"`
 }
@@ -45,12 +56,53 @@ func (e *WebhookEvent) AfterFind(tx *gorm.DB) error {
 	return nil
 }
 
+// WebhookEventPayload Webhook 事件的原始请求（已脱敏），Body 为 gzip 压缩后的请求体
+type WebhookEventPayload struct {
+	gorm.Model
+	WebhookEventID uint                `gorm:"uniqueIndex" json:"webhook_event_id"`
+	HeadersJSON    string              `gorm:"type:text" json:"-"`
+	Headers        map[string][]string `gorm:"-" json:"headers"`
+	Body           []byte              `json:"-"`
+	Encoding       string              `gorm:"size:20" json:"encoding"` // gzip
+	Size           int                 `json:"size"`                    // 压缩前字节数
+}
+
+func (WebhookEventPayload) TableName() string { return "webhook_event_payloads" }
+
+func (p *WebhookEventPayload) BeforeSave(tx *gorm.DB) error {
+	if p.Headers != nil {
+		b, err := json.Marshal(p.Headers)
+		if err != nil {
+			return err
+		}
+		p.HeadersJSON = string(b)
+	}
+	return nil
+}
+
+func (p *WebhookEventPayload) AfterFind(tx *gorm.DB) error {
+	if p.HeadersJSON != "" {
+		json.Unmarshal([]byte(p.HeadersJSON), &p.Headers)
+	}
+	return nil
+}
+
+// Webhook 规则动作类型
+const (
+	WebhookActionSync     = "sync"      // 执行同步任务
+	WebhookActionNotify   = "notify"    // 发送通知
+	WebhookActionCreateCR = "create_cr" // 创建 CR/MR
+	WebhookActionLint     = "lint"      // 对仓库文件执行 lint
+	WebhookActionStats    = "stats"     // 刷新仓库统计
+)
+
 type WebhookRule struct {
 	gorm.Model
 	Name             string                 `gorm:"size:100" json:"name"`
 	ProviderConfigID uint                   `gorm:"index" json:"provider_config_id"`
 	EventTypePattern string                 `gorm:"size:100" json:"event_type_pattern"`
 	RepoPattern      string                 `gorm:"size:200" json:"repo_pattern"`
+	Condition        string                 `gorm:"column:condition_expr;type:text" json:"condition"` // 条件表达式，见 pkg/expr
 	Action           string                 `gorm:"size:50" json:"action"`
 	ActionConfigJSON string                 `gorm:"type:text" json:"-"`
 	ActionConfig     map[string]interface{} `gorm:"-" json:"action_config"`
@@ -76,3 +128,47 @@ func (r *WebhookRule) AfterFind(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// Webhook 规则动作执行状态
+const (
+	ActionExecStatusRunning = "running"
+	ActionExecStatusSuccess = "success"
+	ActionExecStatusFailed  = "failed"
+)
+
+// WebhookActionExecution 规则动作针对某个 Webhook 事件的执行记录
+type WebhookActionExecution struct {
+	gorm.Model
+	WebhookEventID uint       `gorm:"index" json:"webhook_event_id"`
+	RuleID         uint       `gorm:"index" json:"rule_id"`
+	RuleName       string     `gorm:"size:100" json:"rule_name"`
+	Action         string     `gorm:"size:50" json:"action"`
+	Status         string     `gorm:"size:20;index" json:"status"`
+	Result         string     `gorm:"type:text" json:"result"`
+	ErrorMessage   string     `gorm:"size:500" json:"error_message"`
+	StartedAt      time.Time  `json:"started_at"`
+	FinishedAt     *time.Time `json:"finished_at"`
+}
+
+func (WebhookActionExecution) TableName() string { return "webhook_action_executions" }
+
+// push 事件自动路由到同步任务的决策
+const (
+	SyncRouteQueued    = "queued"    // 已排队，等待去抖结束后执行
+	SyncRouteDebounced = "debounced" // 合并到已排队的同一任务
+	SyncRouteSkipped   = "skipped"   // 仓库匹配但未触发（分支不匹配等）
+)
+
+// WebhookSyncRoute push 事件到同步任务的路由记录
+type WebhookSyncRoute struct {
+	gorm.Model
+	WebhookEventID uint   `gorm:"index" json:"webhook_event_id"`
+	TaskKey        string `gorm:"size:100;index" json:"task_key"`
+	Branch         string `gorm:"size:200" json:"branch"`
+	Decision       string `gorm:"size:20" json:"decision"`
+	Reason         string `gorm:"size:500" json:"reason"`
+	SyncRunID      uint   `json:"sync_run_id"`
+	RunStatus      string `gorm:"size:20" json:"run_status"` // 触发后同步执行结果
+}
+
+func (WebhookSyncRoute) TableName() string { return "webhook_sync_routes" }
@@ -10,8 +10,13 @@ import (
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
+	branchpolicyhandler "github.com/yi-nology/git-manage-service/biz/handler/branchpolicy"
 	"github.com/yi-nology/git-manage-service/biz/handler/cr"
+	notificationhandler "github.com/yi-nology/git-manage-service/biz/handler/notification"
 	providerhandler "github.com/yi-nology/git-manage-service/biz/handler/provider"
+	releasehandler "github.com/yi-nology/git-manage-service/biz/handler/release"
+	repohandler "github.com/yi-nology/git-manage-service/biz/handler/repo"
+	taskhandler "github.com/yi-nology/git-manage-service/biz/handler/task"
 	webhookhandler "github.com/yi-nology/git-manage-service/biz/handler/webhook"
 	eventhandler "github.com/yi-nology/git-manage-service/biz/handler/webhook_event"
 	"github.com/yi-nology/git-manage-service/biz/middleware"
@@ -80,6 +85,11 @@ func GeneratedRegister(h *server.Hertz) {
 	h.DELETE("/api/v1/providers/:id", providerhandler.Delete)
 	h.POST("/api/v1/providers/:id/test", providerhandler.Test)
 
+	// Bulk repo import from provider
+	h.POST("/api/v1/providers/:id/import", providerhandler.Import)
+	h.GET("/api/v1/provider-imports", providerhandler.ListImports)
+	h.GET("/api/v1/provider-imports/:id", providerhandler.GetImport)
+
 	// Change Request (CR/MR) management
 	h.POST("/api/v1/cr/create", cr.Create)
 	h.GET("/api/v1/cr/detail", cr.Get)
@@ -87,14 +97,68 @@ func GeneratedRegister(h *server.Hertz) {
 	h.POST("/api/v1/cr/merge", cr.Merge)
 	h.POST("/api/v1/cr/close", cr.Close)
 	h.POST("/api/v1/cr/sync", cr.Sync)
+	h.GET("/api/v1/cr/comments", cr.Comments)
+	h.POST("/api/v1/cr/comment", cr.Comment)
+	h.POST("/api/v1/cr/review", cr.Review)
+	h.GET("/api/v1/cr/checks", cr.Checks)
 	h.GET("/api/v1/cr/detect", cr.Detect)
 
+	// Branch protection via provider + policy compliance
+	h.GET("/api/v1/branch-protection", branchpolicyhandler.GetProtection)
+	h.PUT("/api/v1/branch-protection", branchpolicyhandler.SetProtection)
+	h.GET("/api/v1/branch-protection/policies", branchpolicyhandler.ListPolicies)
+	h.POST("/api/v1/branch-protection/policies", branchpolicyhandler.SavePolicy)
+	h.DELETE("/api/v1/branch-protection/policies/:id", branchpolicyhandler.DeletePolicy)
+	h.GET("/api/v1/branch-protection/compliance", branchpolicyhandler.Compliance)
+	h.POST("/api/v1/branch-protection/apply", branchpolicyhandler.Apply)
+
+	// Provider releases + one-step tag release
+	h.GET("/api/v1/release/list", releasehandler.List)
+	h.POST("/api/v1/release/create", releasehandler.Create)
+	h.POST("/api/v1/release/update", releasehandler.Update)
+	h.GET("/api/v1/release/notes", releasehandler.Notes)
+	h.POST("/api/v1/tag/release", releasehandler.TagRelease)
+
 	// Webhook Events
 	h.GET("/api/v1/webhook/events", eventhandler.List)
 	h.POST("/api/v1/webhook/events/retry", eventhandler.Retry)
+	h.GET("/api/v1/webhook/events/dead", eventhandler.DeadLetters)
+	h.GET("/api/v1/webhook/events/payload", eventhandler.Payload)
+	h.POST("/api/v1/webhook/events/replay", eventhandler.Replay)
+	h.GET("/api/v1/webhook/events/executions", eventhandler.Executions)
+	h.GET("/api/v1/webhook/events/sync-routes", eventhandler.SyncRoutes)
+
+	// Webhook Rules
+	h.GET("/api/v1/webhook/rules", eventhandler.ListRules)
+	h.POST("/api/v1/webhook/rules", eventhandler.CreateRule)
+	h.PUT("/api/v1/webhook/rules/:id", eventhandler.UpdateRule)
+	h.DELETE("/api/v1/webhook/rules/:id", eventhandler.DeleteRule)
+	h.POST("/api/v1/webhook/rules/test", eventhandler.TestRule)
+
+	// Notification deliveries + routing preview
+	h.GET("/api/v1/notification/deliveries", notificationhandler.ListDeliveries)
+	h.GET("/api/v1/notification/deliveries/stats", notificationhandler.DeliveryStats)
+	h.POST("/api/v1/notification/deliveries/resend", notificationhandler.ResendDelivery)
+	h.POST("/api/v1/notification/routing/preview", notificationhandler.PreviewRouting)
+	h.POST("/api/v1/notification/template/preview", notificationhandler.PreviewTemplate)
+	h.GET("/api/v1/notification/digest/variables", notificationhandler.DigestVariables)
+	h.POST("/api/v1/notification/digest/flush", notificationhandler.FlushDigest)
+	h.GET("/api/v1/notification/alerts", notificationhandler.ListAlerts)
+	h.POST("/api/v1/notification/alerts/resolve", notificationhandler.ResolveAlert)
+	h.GET("/api/v1/notification/identities", notificationhandler.ListIdentities)
+	h.POST("/api/v1/notification/identity/create", notificationhandler.CreateIdentity)
+	h.POST("/api/v1/notification/identity/update", notificationhandler.UpdateIdentity)
+	h.POST("/api/v1/notification/identity/delete", notificationhandler.DeleteIdentity)
+
+	// Async tasks (clone / fetch / backup)
+	h.GET("/api/v1/tasks", taskhandler.List)
+	h.GET("/api/v1/tasks/detail", taskhandler.Get)
+	h.POST("/api/v1/tasks/cancel", taskhandler.Cancel)
+	h.POST("/api/v1/repo/backup", repohandler.Backup)
 
 	// Incoming webhook receiver
 	h.POST("/api/webhooks/receive", webhookhandler.Receive)
+	h.POST("/api/webhooks/receive/:id", webhookhandler.ReceiveForConfig)
 
 	// 根路径
 	h.GET("/", func(ctx context.Context, c *app.RequestContext) {
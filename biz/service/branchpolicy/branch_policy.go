@@ -0,0 +1,307 @@
+package branchpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/service/provider"
+)
+
+func ListPolicies(repoKey string) ([]po.BranchPolicy, error) {
+	return db.NewBranchPolicyDAO().FindAll(repoKey)
+}
+
+// SavePolicy 按 (repo_key, branch) 新增或覆盖策略
+func SavePolicy(req *api.BranchPolicyReq) (*po.BranchPolicy, error) {
+	if req.RequiredReviews < 0 {
+		return nil, fmt.Errorf("required_reviews must not be negative")
+	}
+	if req.RepoKey != "" {
+		if _, err := db.NewRepoDAO().FindByKey(req.RepoKey); err != nil {
+			return nil, fmt.Errorf("repo not found: %w", err)
+		}
+	}
+	dao := db.NewBranchPolicyDAO()
+	policy, err := dao.FindByRepoAndBranch(req.RepoKey, req.Branch)
+	if err != nil {
+		policy = &po.BranchPolicy{RepoKey: req.RepoKey, Branch: req.Branch}
+	}
+	policy.RequiredReviews = req.RequiredReviews
+	policy.AllowForcePush = req.AllowForcePush
+	policy.RequireChecks = req.RequireChecks || len(req.RequiredChecks) > 0
+	policy.RequiredChecks = req.RequiredChecks
+	if err := dao.Save(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func DeletePolicy(id uint) error {
+	dao := db.NewBranchPolicyDAO()
+	if _, err := dao.FindByID(id); err != nil {
+		return err
+	}
+	return dao.Delete(id)
+}
+
+func GetProtection(ctx context.Context, repoKey, branch string) (*api.BranchProtectionDTO, error) {
+	t, err := resolveTarget(repoKey)
+	if err != nil {
+		return nil, err
+	}
+	if branch == "" {
+		if branch, err = t.defaultBranch(ctx); err != nil {
+			return nil, err
+		}
+	}
+	bp, err := t.Provider.GetBranchProtection(ctx, t.Owner, t.Name, branch)
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(repoKey, bp), nil
+}
+
+func SetProtection(ctx context.Context, req *api.BranchProtectionDTO) (*api.BranchProtectionDTO, error) {
+	if req.Branch == "" {
+		return nil, fmt.Errorf("branch is required")
+	}
+	t, err := resolveTarget(req.RepoKey)
+	if err != nil {
+		return nil, err
+	}
+	bp, err := t.Provider.SetBranchProtection(ctx, t.Owner, t.Name, provider.BranchProtection{
+		Branch: req.Branch, Protected: req.Protected,
+		RequiredReviews: req.RequiredReviews, AllowForcePush: req.AllowForcePush,
+		RequireChecks: req.RequireChecks || len(req.RequiredChecks) > 0, RequiredChecks: req.RequiredChecks,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(req.RepoKey, bp), nil
+}
+
+// Compliance 对比仓库实际分支保护与策略；repoKeys 为空时检查所有已绑定平台的仓库
+func Compliance(ctx context.Context, repoKeys []string) (*api.ComplianceReportDTO, error) {
+	return run(ctx, repoKeys, false)
+}
+
+// Apply 检查合规性，并把不合规的分支保护提升到策略要求（只收紧，不放宽已有的更严格设置）
+func Apply(ctx context.Context, repoKeys []string) (*api.ComplianceReportDTO, error) {
+	return run(ctx, repoKeys, true)
+}
+
+func run(ctx context.Context, repoKeys []string, apply bool) (*api.ComplianceReportDTO, error) {
+	policies, err := db.NewBranchPolicyDAO().FindAll("")
+	if err != nil {
+		return nil, err
+	}
+	repos, err := targetRepos(repoKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &api.ComplianceReportDTO{Items: []api.ComplianceItemDTO{}}
+	for _, repo := range repos {
+		for _, policy := range effectivePolicies(policies, repo.Key) {
+			item := checkRepo(ctx, repo, policy, apply)
+			report.Items = append(report.Items, item)
+			switch item.Status {
+			case api.ComplianceCompliant:
+				report.Compliant++
+			case api.ComplianceNonCompliant:
+				report.NonCompliant++
+			case api.ComplianceApplied:
+				report.Applied++
+			case api.ComplianceUnsupported:
+				report.Unsupported++
+			default:
+				report.Errors++
+			}
+		}
+	}
+	report.Total = len(report.Items)
+	return report, nil
+}
+
+func checkRepo(ctx context.Context, repo po.Repo, policy po.BranchPolicy, apply bool) api.ComplianceItemDTO {
+	item := api.ComplianceItemDTO{RepoKey: repo.Key, Branch: policy.Branch, PolicyID: policy.ID, FullName: repo.PlatformOwner + "/" + repo.PlatformRepo}
+	fail := func(err error) api.ComplianceItemDTO {
+		item.Status = api.ComplianceError
+		if errors.Is(err, provider.ErrUnsupported) {
+			item.Status = api.ComplianceUnsupported
+		}
+		item.Error = err.Error()
+		return item
+	}
+
+	t, err := resolveTarget(repo.Key)
+	if err != nil {
+		return fail(err)
+	}
+	item.Platform = string(t.Provider.Platform())
+	if item.Branch == "" {
+		if item.Branch, err = t.defaultBranch(ctx); err != nil {
+			return fail(err)
+		}
+	}
+	actual, err := t.Provider.GetBranchProtection(ctx, t.Owner, t.Name, item.Branch)
+	if err != nil {
+		return fail(err)
+	}
+	item.Actual = toDTO(repo.Key, actual)
+	item.Diffs = diffProtection(policy, actual, t.Provider.Platform())
+	if len(item.Diffs) == 0 {
+		item.Status = api.ComplianceCompliant
+		return item
+	}
+	item.Status = api.ComplianceNonCompliant
+	if !apply {
+		return item
+	}
+
+	updated, err := t.Provider.SetBranchProtection(ctx, t.Owner, t.Name, tighten(actual, policy))
+	if err != nil {
+		return fail(fmt.Errorf("apply: %w", err))
+	}
+	item.Actual = toDTO(repo.Key, updated)
+	item.Status = api.ComplianceApplied
+	return item
+}
+
+// diffProtection 策略是最低要求：批准数不少于策略值、策略禁止时不允许强推、要求的检查都已配置
+func diffProtection(policy po.BranchPolicy, actual *provider.BranchProtection, platform provider.Platform) []api.ProtectionDiffDTO {
+	var diffs []api.ProtectionDiffDTO
+	if !actual.Protected {
+		return append(diffs, api.ProtectionDiffDTO{Field: "protected", Expected: true, Actual: false})
+	}
+	if actual.RequiredReviews < policy.RequiredReviews {
+		diffs = append(diffs, api.ProtectionDiffDTO{Field: "required_reviews", Expected: policy.RequiredReviews, Actual: actual.RequiredReviews})
+	}
+	if !policy.AllowForcePush && actual.AllowForcePush {
+		diffs = append(diffs, api.ProtectionDiffDTO{Field: "allow_force_push", Expected: false, Actual: true})
+	}
+	if policy.RequireChecks && !actual.RequireChecks {
+		diffs = append(diffs, api.ProtectionDiffDTO{Field: "require_checks", Expected: true, Actual: false})
+	}
+	// GitLab 只能要求流水线整体通过，不比较检查名
+	if platform != provider.PlatformGitLab {
+		if missing := missingChecks(policy.RequiredChecks, actual.RequiredChecks); len(missing) > 0 {
+			diffs = append(diffs, api.ProtectionDiffDTO{Field: "required_checks", Expected: policy.RequiredChecks, Actual: actual.RequiredChecks})
+		}
+	}
+	return diffs
+}
+
+// tighten 在实际设置基础上补齐策略要求，保留比策略更严格的部分
+func tighten(actual *provider.BranchProtection, policy po.BranchPolicy) provider.BranchProtection {
+	result := *actual
+	result.Protected = true
+	if result.RequiredReviews < policy.RequiredReviews {
+		result.RequiredReviews = policy.RequiredReviews
+	}
+	if !policy.AllowForcePush {
+		result.AllowForcePush = false
+	}
+	if policy.RequireChecks {
+		result.RequireChecks = true
+		result.RequiredChecks = append(append([]string{}, actual.RequiredChecks...), missingChecks(policy.RequiredChecks, actual.RequiredChecks)...)
+	}
+	return result
+}
+
+func missingChecks(required, actual []string) []string {
+	have := make(map[string]bool, len(actual))
+	for _, c := range actual {
+		have[c] = true
+	}
+	var missing []string
+	for _, c := range required {
+		if !have[c] {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// effectivePolicies 默认策略叠加仓库级策略，同一分支以仓库级为准
+func effectivePolicies(policies []po.BranchPolicy, repoKey string) []po.BranchPolicy {
+	byBranch := map[string]po.BranchPolicy{}
+	for _, p := range policies {
+		if p.RepoKey == "" {
+			if _, ok := byBranch[p.Branch]; !ok {
+				byBranch[p.Branch] = p
+			}
+		} else if p.RepoKey == repoKey {
+			byBranch[p.Branch] = p
+		}
+	}
+	result := make([]po.BranchPolicy, 0, len(byBranch))
+	for _, p := range byBranch {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Branch < result[j].Branch })
+	return result
+}
+
+// targetRepos 返回已绑定平台仓库的本地仓库；指定 repoKeys 时未绑定平台的仓库也会返回，由检查结果报错
+func targetRepos(repoKeys []string) ([]po.Repo, error) {
+	repoDAO := db.NewRepoDAO()
+	if len(repoKeys) > 0 {
+		repos := make([]po.Repo, 0, len(repoKeys))
+		for _, key := range repoKeys {
+			repo, err := repoDAO.FindByKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("repo %s not found: %w", key, err)
+			}
+			repos = append(repos, *repo)
+		}
+		return repos, nil
+	}
+	all, err := repoDAO.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	repos := make([]po.Repo, 0, len(all))
+	for _, repo := range all {
+		if repo.ProviderConfigID != 0 && repo.PlatformOwner != "" && repo.PlatformRepo != "" {
+			repos = append(repos, repo)
+		}
+	}
+	return repos, nil
+}
+
+type target struct {
+	*provider.LinkedRepo
+}
+
+func resolveTarget(repoKey string) (*target, error) {
+	lr, err := provider.ResolveLinkedRepo(repoKey)
+	if err != nil {
+		return nil, err
+	}
+	return &target{lr}, nil
+}
+
+func (t *target) defaultBranch(ctx context.Context) (string, error) {
+	r, err := t.Provider.GetRepo(ctx, t.Owner, t.Name)
+	if err != nil {
+		return "", err
+	}
+	if r.DefaultBranch == "" {
+		return "", fmt.Errorf("%s/%s has no default branch", t.Owner, t.Name)
+	}
+	return r.DefaultBranch, nil
+}
+
+func toDTO(repoKey string, bp *provider.BranchProtection) *api.BranchProtectionDTO {
+	return &api.BranchProtectionDTO{
+		RepoKey: repoKey, Branch: bp.Branch, Protected: bp.Protected,
+		RequiredReviews: bp.RequiredReviews, AllowForcePush: bp.AllowForcePush,
+		RequireChecks: bp.RequireChecks, RequiredChecks: bp.RequiredChecks,
+	}
+}
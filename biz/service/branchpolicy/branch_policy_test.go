@@ -0,0 +1,82 @@
+package branchpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	sqlite "github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/utils"
+)
+
+func TestApplyKeepsGitHubProtectionExtras(t *testing.T) {
+	var put map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/acme/app/branches/main/protection", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			if err := json.NewDecoder(r.Body).Decode(&put); err != nil {
+				t.Error(err)
+			}
+			w.Write([]byte(`{"required_status_checks":{"strict":true,"contexts":["ci"]},"required_pull_request_reviews":{"required_approving_review_count":2,"require_code_owner_reviews":true}}`))
+			return
+		}
+		w.Write([]byte(`{"required_status_checks":{"strict":true,"contexts":["ci"]},"required_pull_request_reviews":{"required_approving_review_count":1,"require_code_owner_reviews":true,"dismiss_stale_reviews":true},"required_linear_history":{"enabled":true}}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	utils.InitEncryption()
+	conn, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.AutoMigrate(&po.Repo{}, &po.ProviderConfig{}, &po.Credential{}, &po.BranchPolicy{}); err != nil {
+		t.Fatal(err)
+	}
+	old := db.DB
+	db.DB = conn
+	defer func() { db.DB = old }()
+
+	cred := &po.Credential{Name: "gh", Type: "http_token", Secret: "tok"}
+	if err := conn.Create(cred).Error; err != nil {
+		t.Fatal(err)
+	}
+	cfg := &po.ProviderConfig{Name: "gh", Platform: "github", BaseURL: srv.URL, CredentialID: cred.ID}
+	if err := conn.Create(cfg).Error; err != nil {
+		t.Fatal(err)
+	}
+	repo := &po.Repo{Key: "app", Name: "app", ProviderConfigID: cfg.ID, PlatformOwner: "acme", PlatformRepo: "app"}
+	if err := conn.Create(repo).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SavePolicy(&api.BranchPolicyReq{Branch: "main", RequiredReviews: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Apply(context.Background(), []string{"app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Applied != 1 {
+		t.Fatalf("expected protection applied, got %+v", report.Items)
+	}
+	checks, _ := put["required_status_checks"].(map[string]interface{})
+	if checks["strict"] != true {
+		t.Errorf("strict not preserved: %v", put["required_status_checks"])
+	}
+	reviews, _ := put["required_pull_request_reviews"].(map[string]interface{})
+	if reviews["require_code_owner_reviews"] != true || reviews["dismiss_stale_reviews"] != true || reviews["required_approving_review_count"] != float64(2) {
+		t.Errorf("review settings not preserved: %v", put["required_pull_request_reviews"])
+	}
+	if put["required_linear_history"] != true {
+		t.Errorf("required_linear_history not preserved: %v", put["required_linear_history"])
+	}
+}
@@ -2,6 +2,7 @@ package crservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -12,6 +13,9 @@ import (
 	"github.com/yi-nology/git-manage-service/biz/service/provider"
 )
 
+// ErrChecksNotPassed 要求 CI 通过才能合并，但检查失败、未完成或不存在
+var ErrChecksNotPassed = errors.New("CI checks have not passed")
+
 func CreateCR(ctx context.Context, req *api.CreateCRReq) (*api.CRDTO, error) {
 	repo, p, owner, repoName, err := resolveRepoProvider(req.RepoKey)
 	if err != nil {
@@ -21,6 +25,7 @@ func CreateCR(ctx context.Context, req *api.CreateCRReq) (*api.CRDTO, error) {
 		Owner: owner, Repo: repoName, Title: req.Title, Description: req.Description,
 		SourceBranch: req.SourceBranch, TargetBranch: req.TargetBranch,
 		Labels: req.Labels, RemoveSourceBranch: req.RemoveSourceBranch,
+		RepoPath: repo.Path,
 	})
 	if err != nil {
 		return nil, err
@@ -73,11 +78,25 @@ func ListCRs(ctx context.Context, repoKey, state, sourceBranch, targetBranch str
 	return dtos, int(total), nil
 }
 
-func MergeCR(ctx context.Context, repoKey string, crNumber int, mergeMsg string, squash, removeBranch bool) (*api.CRDTO, error) {
+// MergeCR requireChecks 为 true 时先刷新源分支最新提交的 CI 状态，全部成功才合并
+func MergeCR(ctx context.Context, repoKey string, crNumber int, mergeMsg string, squash, removeBranch, requireChecks bool) (*api.CRDTO, error) {
 	repo, p, owner, repoName, err := resolveRepoProvider(repoKey)
 	if err != nil {
 		return nil, err
 	}
+	if requireChecks {
+		current, err := p.GetCR(ctx, owner, repoName, crNumber)
+		if err != nil {
+			return nil, err
+		}
+		state, _, err := refreshChecks(ctx, repo, p, owner, repoName, current)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch CI checks: %w", err)
+		}
+		if state != provider.CheckStateSuccess {
+			return nil, fmt.Errorf("%w (state: %s)", ErrChecksNotPassed, state)
+		}
+	}
 	cr, err := p.MergeCR(ctx, owner, repoName, crNumber, provider.MergeCROptions{
 		MergeCommitMessage: mergeMsg, Squash: squash, RemoveSourceBranch: removeBranch,
 	})
@@ -115,6 +134,150 @@ func CloseCR(ctx context.Context, repoKey string, crNumber int) (*api.CRDTO, err
 	return platformCRToAPI(cr), nil
 }
 
+// findOpenCRMaxPages FindOpenCR 最多翻阅的页数
+const findOpenCRMaxPages = 50
+
+// FindOpenCR 在平台上查找 sourceBranch -> targetBranch 的打开状态 CR，不存在时返回 nil。
+// 部分平台忽略分支过滤参数或在客户端按页过滤，因此不按源分支过滤，逐页查找直到找到或翻完
+func FindOpenCR(ctx context.Context, repoKey, sourceBranch, targetBranch string) (*api.CRDTO, error) {
+	_, p, owner, repoName, err := resolveRepoProvider(repoKey)
+	if err != nil {
+		return nil, err
+	}
+	const perPage = 100
+	for page := 1; page <= findOpenCRMaxPages; page++ {
+		crs, _, err := p.ListCRs(ctx, provider.ListCROptions{
+			Owner: owner, Repo: repoName, State: provider.CRStateOpened,
+			TargetBranch: targetBranch, Page: page, PerPage: perPage,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, cr := range crs {
+			if cr.SourceBranch == sourceBranch && cr.TargetBranch == targetBranch {
+				return platformCRToAPI(cr), nil
+			}
+		}
+		if len(crs) < perPage {
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: more than %d open CRs into %s", provider.ErrPageLimit, findOpenCRMaxPages*perPage, targetBranch)
+}
+
+// UpdateCR 更新 CR 标题 / 描述，为空的字段保持不变
+func UpdateCR(ctx context.Context, repoKey string, crNumber int, title, description string) (*api.CRDTO, error) {
+	repo, p, owner, repoName, err := resolveRepoProvider(repoKey)
+	if err != nil {
+		return nil, err
+	}
+	cr, err := p.UpdateCR(ctx, owner, repoName, crNumber, provider.UpdateCROptions{Title: title, Description: description})
+	if err != nil {
+		return nil, err
+	}
+	crDAO := db.NewChangeRequestDAO()
+	localCR, dbErr := crDAO.FindByRepoAndNumber(repo.ID, crNumber)
+	if dbErr != nil {
+		localCR = platformCRToLocal(repo.ID, repo.ProviderConfigID, cr)
+		if err := crDAO.Create(localCR); err != nil {
+			log.Printf("Warning: failed to save CR locally: %v", err)
+		}
+		return toCRDTO(localCR), nil
+	}
+	localCR.Title = cr.Title
+	localCR.Description = cr.Description
+	localCR.HeadSHA = cr.HeadSHA
+	crDAO.Save(localCR)
+	return toCRDTO(localCR), nil
+}
+
+func ListComments(ctx context.Context, repoKey string, crNumber int) ([]api.CRCommentDTO, error) {
+	_, p, owner, repoName, err := resolveRepoProvider(repoKey)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := p.ListCRComments(ctx, owner, repoName, crNumber)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]api.CRCommentDTO, 0, len(comments))
+	for _, c := range comments {
+		result = append(result, toCommentDTO(c))
+	}
+	return result, nil
+}
+
+func AddComment(ctx context.Context, req *api.AddCRCommentReq) (*api.CRCommentDTO, error) {
+	_, p, owner, repoName, err := resolveRepoProvider(req.RepoKey)
+	if err != nil {
+		return nil, err
+	}
+	c, err := p.AddCRComment(ctx, owner, repoName, req.CRNumber, provider.AddCRCommentOptions{
+		Body: req.Body, Path: req.Path, Line: req.Line, CommitSHA: req.CommitSHA,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dto := toCommentDTO(c)
+	return &dto, nil
+}
+
+func ReviewCR(ctx context.Context, req *api.ReviewCRReq) error {
+	action := provider.ReviewAction(req.Action)
+	if action != provider.ReviewApprove && action != provider.ReviewRequestChanges {
+		return fmt.Errorf("action must be approve or request_changes")
+	}
+	_, p, owner, repoName, err := resolveRepoProvider(req.RepoKey)
+	if err != nil {
+		return err
+	}
+	return p.ReviewCR(ctx, owner, repoName, req.CRNumber, provider.ReviewCROptions{Action: action, Body: req.Body})
+}
+
+// GetChecks 查询 CR 当前源提交的 CI 检查，并更新本地记录的汇总状态
+func GetChecks(ctx context.Context, repoKey string, crNumber int) (*api.CRChecksDTO, error) {
+	repo, p, owner, repoName, err := resolveRepoProvider(repoKey)
+	if err != nil {
+		return nil, err
+	}
+	cr, err := p.GetCR(ctx, owner, repoName, crNumber)
+	if err != nil {
+		return nil, err
+	}
+	state, statuses, err := refreshChecks(ctx, repo, p, owner, repoName, cr)
+	if err != nil {
+		return nil, err
+	}
+	result := &api.CRChecksDTO{CRNumber: crNumber, HeadSHA: cr.HeadSHA, State: string(state), Checks: make([]api.CommitStatusDTO, 0, len(statuses))}
+	for _, st := range statuses {
+		result.Checks = append(result.Checks, api.CommitStatusDTO{
+			Name: st.Name, State: string(st.State), Description: st.Description, TargetURL: st.TargetURL,
+		})
+	}
+	return result, nil
+}
+
+// refreshChecks 拉取 cr.HeadSHA 的检查并写入本地 CR 记录（存在时）
+func refreshChecks(ctx context.Context, repo *po.Repo, p provider.Provider, owner, repoName string, cr *provider.ChangeRequest) (provider.CheckState, []*provider.CommitStatus, error) {
+	if cr.HeadSHA == "" {
+		return provider.CheckStateNone, nil, nil
+	}
+	statuses, err := p.GetCommitStatuses(ctx, owner, repoName, cr.HeadSHA)
+	if err != nil {
+		return "", nil, err
+	}
+	state := provider.AggregateCheckState(statuses)
+	crDAO := db.NewChangeRequestDAO()
+	if localCR, dbErr := crDAO.FindByRepoAndNumber(repo.ID, cr.Number); dbErr == nil {
+		now := time.Now()
+		localCR.HeadSHA = cr.HeadSHA
+		localCR.CheckState = string(state)
+		localCR.CheckUpdatedAt = &now
+		crDAO.Save(localCR)
+	}
+	return state, statuses, nil
+}
+
 func SyncCRs(ctx context.Context, repoKey, state string) (int, error) {
 	repo, p, owner, repoName, err := resolveRepoProvider(repoKey)
 	if err != nil {
@@ -142,6 +305,7 @@ func SyncCRs(ctx context.Context, repoKey, state string) (int, error) {
 			existing.MergeStatus = cr.MergeStatus
 			existing.Labels = cr.Labels
 			existing.WebURL = cr.WebURL
+			existing.HeadSHA = cr.HeadSHA
 			if cr.State == provider.CRStateMerged {
 				now := time.Now()
 				existing.MergedAt = &now
@@ -153,29 +317,21 @@ func SyncCRs(ctx context.Context, repoKey, state string) (int, error) {
 			crDAO.Save(existing)
 			synced++
 		}
+		if cr.State == provider.CRStateOpened {
+			if _, _, err := refreshChecks(ctx, repo, p, owner, repoName, cr); err != nil {
+				log.Printf("Warning: failed to refresh checks for CR #%d: %v", cr.Number, err)
+			}
+		}
 	}
 	return synced, nil
 }
 
 func resolveRepoProvider(repoKey string) (*po.Repo, provider.Provider, string, string, error) {
-	repoDAO := db.NewRepoDAO()
-	repo, err := repoDAO.FindByKey(repoKey)
-	if err != nil {
-		return nil, nil, "", "", fmt.Errorf("repo not found: %w", err)
-	}
-	if repo.ProviderConfigID == 0 {
-		return nil, nil, "", "", fmt.Errorf("repo %s has no provider configured", repoKey)
-	}
-	p, err := provider.GetManager().GetProvider(repo.ProviderConfigID)
+	lr, err := provider.ResolveLinkedRepo(repoKey)
 	if err != nil {
 		return nil, nil, "", "", err
 	}
-	owner := repo.PlatformOwner
-	repoName := repo.PlatformRepo
-	if owner == "" || repoName == "" {
-		return nil, nil, "", "", fmt.Errorf("repo %s missing platform owner/repo info", repoKey)
-	}
-	return repo, p, owner, repoName, nil
+	return lr.Repo, lr.Provider, lr.Owner, lr.Name, nil
 }
 
 func platformCRToLocal(repoID, providerConfigID uint, cr *provider.ChangeRequest) *po.ChangeRequest {
@@ -204,6 +360,7 @@ func platformCRToLocal(repoID, providerConfigID uint, cr *provider.ChangeRequest
 		WebURL:           cr.WebURL,
 		MergeStatus:      cr.MergeStatus,
 		Labels:           labels,
+		HeadSHA:          cr.HeadSHA,
 	}
 }
 
@@ -220,6 +377,7 @@ func platformCRToAPI(cr *provider.ChangeRequest) *api.CRDTO {
 		WebURL:         cr.WebURL,
 		MergeStatus:    cr.MergeStatus,
 		Labels:         cr.Labels,
+		HeadSHA:        cr.HeadSHA,
 		CreatedAt:      cr.CreatedAt,
 		UpdatedAt:      cr.UpdatedAt,
 	}
@@ -241,8 +399,21 @@ func toCRDTO(cr *po.ChangeRequest) *api.CRDTO {
 		WebURL:         cr.WebURL,
 		MergeStatus:    cr.MergeStatus,
 		Labels:         cr.Labels,
+		HeadSHA:        cr.HeadSHA,
+		CheckState:     cr.CheckState,
+		CheckUpdatedAt: cr.CheckUpdatedAt,
+		SyncRunID:      cr.SyncRunID,
 		CreatedAt:      cr.CreatedAt,
 		UpdatedAt:      cr.UpdatedAt,
 		MergedAt:       cr.MergedAt,
 	}
 }
+
+func toCommentDTO(c *provider.CRComment) api.CRCommentDTO {
+	dto := api.CRCommentDTO{ID: c.ID, Body: c.Body, Path: c.Path, Line: c.Line, CreatedAt: c.CreatedAt}
+	if c.Author != nil {
+		dto.AuthorName = c.Author.Name
+		dto.AuthorUsername = c.Author.Username
+	}
+	return dto
+}
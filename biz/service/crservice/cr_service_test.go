@@ -0,0 +1,76 @@
+package crservice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	sqlite "github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/utils"
+)
+
+func TestFindOpenCRPaginates(t *testing.T) {
+	// 150 个打开的 PR，自动 CR 位于第二页；Bitbucket 只能在客户端按源分支过滤
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/projects/PROJ/repos/app/pull-requests") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		var values []string
+		for i := start; i < start+limit && i < 150; i++ {
+			from := fmt.Sprintf("feature-%d", i)
+			if i == 120 {
+				from = "sync/staging"
+			}
+			values = append(values, fmt.Sprintf(`{"id":%d,"state":"OPEN","fromRef":{"displayId":%q},"toRef":{"displayId":"main"},"author":{"user":{"name":"bot","slug":"bot"}}}`, i+1, from))
+		}
+		fmt.Fprintf(w, `{"values":[%s],"isLastPage":%v}`, strings.Join(values, ","), start+limit >= 150)
+	}))
+	defer srv.Close()
+
+	utils.InitEncryption()
+	conn, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.AutoMigrate(&po.Repo{}, &po.ProviderConfig{}, &po.Credential{}); err != nil {
+		t.Fatal(err)
+	}
+	old := db.DB
+	db.DB = conn
+	defer func() { db.DB = old }()
+
+	cred := &po.Credential{Name: "bb", Type: "http_token", Secret: "tok"}
+	if err := conn.Create(cred).Error; err != nil {
+		t.Fatal(err)
+	}
+	cfg := &po.ProviderConfig{Name: "bb", Platform: "bitbucket", BaseURL: srv.URL, CredentialID: cred.ID}
+	if err := conn.Create(cfg).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Create(&po.Repo{Key: "app", Name: "app", ProviderConfigID: cfg.ID, PlatformOwner: "PROJ", PlatformRepo: "app"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	cr, err := FindOpenCR(context.Background(), "app", "sync/staging", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cr == nil || cr.CRNumber != 121 {
+		t.Fatalf("expected CR #121, got %+v", cr)
+	}
+	if cr, err := FindOpenCR(context.Background(), "app", "missing", "main"); err != nil || cr != nil {
+		t.Errorf("expected no CR, got %+v, %v", cr, err)
+	}
+}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -209,6 +210,11 @@ func (s *GitService) UpdateBranchFastForward(path, remote, branch, remoteBranch
 
 // FetchAll fetches all remotes
 func (s *GitService) FetchAll(path string) error {
+	return s.FetchAllContext(context.Background(), path)
+}
+
+// FetchAllContext fetches all remotes, aborting when ctx is cancelled
+func (s *GitService) FetchAllContext(ctx context.Context, path string) error {
 	r, err := s.openRepo(path)
 	if err != nil {
 		return err
@@ -237,7 +243,10 @@ func (s *GitService) FetchAll(path string) error {
 			fetchOptions.Auth = auth
 		}
 
-		err := remote.Fetch(fetchOptions)
+		err := remote.FetchContext(ctx, fetchOptions)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if err != nil && err != git.NoErrAlreadyUpToDate {
 			// Log error but continue?
 			_ = err // 暂时使用下划线忽略错误，避免空分支
@@ -381,6 +390,11 @@ func (s *GitService) PullBranchWithDBKey(path, remote, branch, privateKey, passp
 
 // FetchAllWithDBKey fetches all remotes using database SSH key (native git command)
 func (s *GitService) FetchAllWithDBKey(path, privateKey, passphrase string) error {
+	return s.FetchAllWithDBKeyContext(context.Background(), path, privateKey, passphrase)
+}
+
+// FetchAllWithDBKeyContext fetches all remotes using database SSH key, killing git when ctx is cancelled
+func (s *GitService) FetchAllWithDBKeyContext(ctx context.Context, path, privateKey, passphrase string) error {
 	tmpFile, err := os.CreateTemp("", "git_ssh_key_*")
 	if err != nil {
 		return fmt.Errorf("failed to create temp key file: %v", err)
@@ -426,7 +440,7 @@ func (s *GitService) FetchAllWithDBKey(path, privateKey, passphrase string) erro
 
 	sshCmd := fmt.Sprintf("ssh -i %s -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null -o IdentitiesOnly=yes", tmpFile.Name())
 
-	cmd := exec.Command("git", "fetch", "--all")
+	cmd := exec.CommandContext(ctx, "git", "fetch", "--all")
 	cmd.Dir = path
 	cmd.Env = append(os.Environ(), "GIT_SSH_COMMAND="+sshCmd)
 
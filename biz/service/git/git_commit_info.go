@@ -8,6 +8,7 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 )
 
 type CommitInfo struct {
@@ -124,3 +125,62 @@ func (s *GitService) GetCommitDiffSimple(repoPath, hashStr string) (string, erro
 
 	return result.String(), nil
 }
+
+// GetDivergentAuthors 获取两个提交自合并基点以来各自新增提交的作者邮箱（去重、小写），limit 限制遍历的提交数
+func (s *GitService) GetDivergentAuthors(repoPath, hashA, hashB string, limit int) ([]string, error) {
+	r, err := s.openRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	commitA, err := r.CommitObject(plumbing.NewHash(hashA))
+	if err != nil {
+		return nil, err
+	}
+	commitB, err := r.CommitObject(plumbing.NewHash(hashB))
+	if err != nil {
+		return nil, err
+	}
+
+	bases, err := commitA.MergeBase(commitB)
+	if err != nil {
+		return nil, err
+	}
+	stop := make([]plumbing.Hash, 0, len(bases))
+	for _, b := range bases {
+		stop = append(stop, b.Hash)
+	}
+
+	seen := make(map[string]bool)
+	var authors []string
+	visited := 0
+	for _, tip := range []*object.Commit{commitA, commitB} {
+		iter := object.NewCommitPreorderIter(tip, nil, stop)
+		err := iter.ForEach(func(c *object.Commit) error {
+			if limit > 0 && visited >= limit {
+				return storer.ErrStop
+			}
+			visited++
+			email := strings.ToLower(strings.TrimSpace(c.Author.Email))
+			if email != "" && !seen[email] {
+				seen[email] = true
+				authors = append(authors, email)
+			}
+			return nil
+		})
+		iter.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return authors, nil
+}
+
+// GetRangeDivergentAuthors 按 "目标..源" 形式的提交范围获取双方分叉提交的作者邮箱
+func (s *GitService) GetRangeDivergentAuthors(repoPath, commitRange string, limit int) ([]string, error) {
+	targetHash, sourceHash, ok := strings.Cut(commitRange, "..")
+	if !ok || targetHash == "" || sourceHash == "" {
+		return nil, fmt.Errorf("invalid commit range: %s", commitRange)
+	}
+	return s.GetDivergentAuthors(repoPath, targetHash, sourceHash, limit)
+}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -160,6 +161,11 @@ func (s *GitService) CloneWithProgress(remoteURL, localPath, authType, authKey,
 
 // CloneWithAuthMethod 使用已解析的认证方法进行克隆
 func (s *GitService) CloneWithAuthMethod(remoteURL, localPath string, auth transport.AuthMethod, progressChan chan string) error {
+	return s.CloneWithAuthMethodContext(context.Background(), remoteURL, localPath, auth, progressChan)
+}
+
+// CloneWithAuthMethodContext 同 CloneWithAuthMethod，ctx 取消时中止克隆
+func (s *GitService) CloneWithAuthMethodContext(ctx context.Context, remoteURL, localPath string, auth transport.AuthMethod, progressChan chan string) error {
 	if auth == nil {
 		auth = s.detectSSHAuth(remoteURL)
 	}
@@ -169,7 +175,7 @@ func (s *GitService) CloneWithAuthMethod(remoteURL, localPath string, auth trans
 		progress = &channelWriter{ch: progressChan}
 	}
 
-	_, err := git.PlainClone(localPath, false, &git.CloneOptions{
+	_, err := git.PlainCloneContext(ctx, localPath, false, &git.CloneOptions{
 		URL:      remoteURL,
 		Auth:     auth,
 		Progress: progress,
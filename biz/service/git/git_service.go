@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -100,6 +101,11 @@ func (s *GitService) FetchWithDBKey(path, remoteURL, privateKey, passphrase stri
 
 // CloneWithDBKey 使用数据库 SSH密钥进行克隆（使用原生 git 命令，更可靠）
 func (s *GitService) CloneWithDBKey(remoteURL, localPath, privateKey, passphrase string, progressChan chan string) error {
+	return s.CloneWithDBKeyContext(context.Background(), remoteURL, localPath, privateKey, passphrase, progressChan)
+}
+
+// CloneWithDBKeyContext 同 CloneWithDBKey，ctx 取消时终止 git 进程
+func (s *GitService) CloneWithDBKeyContext(ctx context.Context, remoteURL, localPath, privateKey, passphrase string, progressChan chan string) error {
 	log.Printf("[INFO] Starting git clone with DB key: %s -> %s", remoteURL, localPath)
 	helper := NewSSHKeyHelper()
 
@@ -123,7 +129,7 @@ func (s *GitService) CloneWithDBKey(remoteURL, localPath, privateKey, passphrase
 	// 构建 SSH 命令
 	sshCmd := helper.BuildSSHCommand(tmpFile)
 
-	cmd := exec.Command("git", "clone", remoteURL, localPath)
+	cmd := exec.CommandContext(ctx, "git", "clone", remoteURL, localPath)
 	cmd.Env = append(os.Environ(), "GIT_SSH_COMMAND="+sshCmd)
 
 	if progressChan != nil {
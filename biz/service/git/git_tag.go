@@ -8,6 +8,7 @@ import (
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/sirupsen/logrus"
 	"github.com/yi-nology/git-manage-service/pkg/logger"
 )
@@ -115,6 +116,35 @@ func (s *GitService) PushTag(path, remoteName, tagName, authType, authKey, authS
 	return nil
 }
 
+// PushTagWithAuth 使用已解析的认证推送标签（凭证系统 / 数据库 SSH 密钥），auth 为 nil 时按远程 URL 探测 SSH 认证
+func (s *GitService) PushTagWithAuth(path, remoteName, tagName string, auth transport.AuthMethod) error {
+	r, err := s.openRepo(path)
+	if err != nil {
+		return err
+	}
+	if auth == nil {
+		if rem, err := r.Remote(remoteName); err == nil && len(rem.Config().URLs) > 0 {
+			auth = s.detectSSHAuth(rem.Config().URLs[0])
+		}
+	}
+
+	refSpec := config.RefSpec(fmt.Sprintf("refs/tags/%s:refs/tags/%s", tagName, tagName))
+	err = r.Push(&git.PushOptions{
+		RemoteName: remoteName,
+		RefSpecs:   []config.RefSpec{refSpec},
+		Auth:       auth,
+	})
+	if err == git.NoErrAlreadyUpToDate {
+		return nil
+	}
+	if err != nil {
+		logger.ErrorWithErr("Failed to push tag", err, logrus.Fields{"tag": tagName})
+		return err
+	}
+	logger.Info("Tag pushed successfully", logrus.Fields{"tag": tagName, "remote": remoteName})
+	return nil
+}
+
 // DeleteTag 删除本地标签
 func (s *GitService) DeleteTag(path, tagName string) error {
 	logger.Info("Deleting tag", logrus.Fields{"path": path, "tag": tagName})
@@ -1,134 +1,330 @@
 package git
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"sync"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+)
+
+const (
+	// maxTaskProgressLines 每个任务保留的进度日志行数上限
+	maxTaskProgressLines = 500
+	// progressFlushInterval 进度日志落库的最小间隔
+	progressFlushInterval = time.Second
 )
 
-// Task Manager for Async Clones
+// TaskFunc 任务执行函数，需响应 ctx 取消；进度通过 TaskManager.AppendLog 上报
+type TaskFunc func(ctx context.Context, taskID string) error
+
+// Task Manager for Async Clones / Fetches / Backups
 type TaskManager struct {
-	tasks         sync.Map
+	tasks         sync.Map // 排队或运行中的任务: id -> *taskEntry
 	maxConcurrent int
 	runningTasks  int
 	mutex         sync.Mutex
-	taskQueue     chan *Task
+	taskQueue     chan *taskEntry
 	cleanupTicker *time.Ticker
+	retention     time.Duration
+	dao           *db.AsyncTaskDAO
+	initOnce      sync.Once
 }
 
 type Task struct {
-	ID        string    `json:"id"`
-	Status    string    `json:"status"` // running, success, failed, queued
-	Progress  []string  `json:"progress"`
-	Error     string    `json:"error"`
-	StartTime time.Time `json:"startTime"`
-	EndTime   time.Time `json:"endTime"`
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	Target     string    `json:"target"`
+	Status     string    `json:"status"` // queued, running, success, failed, cancelled
+	Progress   []string  `json:"progress"`
+	Error      string    `json:"error"`
+	CreateTime time.Time `json:"createTime"`
+	StartTime  time.Time `json:"startTime"`
+	EndTime    time.Time `json:"endTime"`
+}
+
+// taskEntry 内存中的任务状态，附带执行函数与取消句柄
+type taskEntry struct {
+	mu        sync.Mutex
+	saveMu    sync.Mutex // 串行化落库，保证后写入的状态总是更新
+	task      Task
+	record    *po.AsyncTask
+	fn        TaskFunc
+	ctx       context.Context
+	cancel    context.CancelFunc
+	lastFlush time.Time
 }
 
 var GlobalTaskManager = &TaskManager{
-	maxConcurrent: 100,                    // 支持并发处理100个任务
-	taskQueue:     make(chan *Task, 1000), // 任务队列容量
+	dao: db.NewAsyncTaskDAO(),
 }
 
-// Init 初始化任务管理器
+// Init 初始化任务管理器：恢复中断的任务、启动工作协程与清理定时器
 func (tm *TaskManager) Init() {
-	// 启动任务处理器
-	go tm.processTaskQueue()
+	tm.initOnce.Do(tm.start)
+}
+
+func (tm *TaskManager) start() {
+	cfg := configs.GlobalConfig.Task
+	tm.maxConcurrent = cfg.MaxConcurrent
+	if tm.maxConcurrent <= 0 {
+		tm.maxConcurrent = 4
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+	retentionDays := cfg.RetentionDays
+	if retentionDays <= 0 {
+		retentionDays = 7
+	}
+	tm.retention = time.Duration(retentionDays) * 24 * time.Hour
+	tm.taskQueue = make(chan *taskEntry, queueSize)
+
+	// 上次进程退出时未完成的任务无法恢复执行，统一标记为失败
+	if n, err := tm.dao.MarkUnfinished(po.AsyncTaskStatusFailed, "interrupted by service restart"); err != nil {
+		log.Printf("[WARN] Failed to recover unfinished tasks: %v", err)
+	} else if n > 0 {
+		log.Printf("[INFO] Marked %d unfinished tasks as failed after restart", n)
+	}
+
+	// 固定数量的工作协程保证并发上限
+	for i := 0; i < tm.maxConcurrent; i++ {
+		go tm.worker()
+	}
 
-	// 启动清理定时器，每小时清理一次已完成的任务
+	// 启动清理定时器，每小时清理一次过期的已完成任务
 	tm.cleanupTicker = time.NewTicker(time.Hour)
 	go tm.cleanupTasks()
+
+	log.Printf("[INFO] Task manager started: max_concurrent=%d, queue_size=%d", tm.maxConcurrent, queueSize)
 }
 
-// processTaskQueue 处理任务队列
-func (tm *TaskManager) processTaskQueue() {
-	for task := range tm.taskQueue {
-		tm.mutex.Lock()
-		if tm.runningTasks >= tm.maxConcurrent {
-			tm.mutex.Unlock()
-			// 等待有任务完成
-			time.Sleep(100 * time.Millisecond)
-			tm.taskQueue <- task // 重新入队
-			continue
-		}
-		tm.runningTasks++
-		tm.mutex.Unlock()
+// worker 从队列中取出任务并执行
+func (tm *TaskManager) worker() {
+	for e := range tm.taskQueue {
+		tm.run(e)
+	}
+}
 
-		// 任务已经在AddTask中标记为running，这里不需要再修改状态
-		log.Printf("[INFO] Starting task: %s", task.ID)
+// Submit 提交任务，返回任务快照；队列已满时返回错误
+func (tm *TaskManager) Submit(taskType, target string, fn TaskFunc) (*Task, error) {
+	tm.Init()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	now := time.Now()
+	e := &taskEntry{
+		task: Task{
+			ID:         uuid.New().String(),
+			Type:       taskType,
+			Target:     target,
+			Status:     po.AsyncTaskStatusQueued,
+			Progress:   []string{},
+			CreateTime: now,
+		},
+		fn:     fn,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	e.record = &po.AsyncTask{
+		TaskID:   e.task.ID,
+		Type:     taskType,
+		Target:   target,
+		Status:   po.AsyncTaskStatusQueued,
+		Progress: []string{},
+	}
+	if err := tm.dao.Create(e.record); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to persist task: %w", err)
+	}
+	tm.tasks.Store(e.task.ID, e)
+
+	select {
+	case tm.taskQueue <- e:
+	default:
+		tm.finish(e, po.AsyncTaskStatusFailed, "task queue is full")
+		return nil, fmt.Errorf("task queue is full (%d queued)", cap(tm.taskQueue))
 	}
+
+	snapshot := e.snapshot()
+	return &snapshot, nil
 }
 
-// AddTask 添加任务
-func (tm *TaskManager) AddTask(id string) *Task {
-	t := &Task{
-		ID:        id,
-		Status:    "queued",
-		Progress:  []string{},
-		StartTime: time.Now(),
+// run 执行单个任务
+func (tm *TaskManager) run(e *taskEntry) {
+	e.mu.Lock()
+	if e.task.Status != po.AsyncTaskStatusQueued {
+		// 排队期间已被取消
+		e.mu.Unlock()
+		return
 	}
-	tm.tasks.Store(id, t)
+	e.task.Status = po.AsyncTaskStatusRunning
+	e.task.StartTime = time.Now()
+	e.mu.Unlock()
+	tm.persist(e, true)
 
-	// 将任务加入队列
-	tm.taskQueue <- t
+	tm.mutex.Lock()
+	tm.runningTasks++
+	tm.mutex.Unlock()
 
-	// 立即更新状态为running
-	t.Status = "running"
+	log.Printf("[INFO] Starting task: %s (%s)", e.task.ID, e.task.Type)
+	err := tm.invoke(e)
 
-	return t
+	tm.mutex.Lock()
+	tm.runningTasks--
+	tm.mutex.Unlock()
+
+	switch {
+	case e.ctx.Err() == context.Canceled:
+		tm.finish(e, po.AsyncTaskStatusCancelled, "cancelled")
+	case err != nil:
+		tm.finish(e, po.AsyncTaskStatusFailed, err.Error())
+	default:
+		tm.finish(e, po.AsyncTaskStatusSuccess, "")
+	}
+}
+
+// invoke 调用任务函数并捕获 panic
+func (tm *TaskManager) invoke(e *taskEntry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panic: %v", r)
+		}
+	}()
+	return e.fn(e.ctx, e.task.ID)
+}
+
+// finish 结束任务并落库
+func (tm *TaskManager) finish(e *taskEntry, status, errStr string) {
+	e.mu.Lock()
+	e.task.Status = status
+	e.task.Error = errStr
+	e.task.EndTime = time.Now()
+	e.mu.Unlock()
+	tm.complete(e)
+}
+
+// complete 释放任务资源、写入最终状态并移出内存
+func (tm *TaskManager) complete(e *taskEntry) {
+	e.cancel()
+	tm.persist(e, true)
+	tm.tasks.Delete(e.task.ID)
+
+	e.mu.Lock()
+	status := e.task.Status
+	e.mu.Unlock()
+	log.Printf("[INFO] Task %s completed with status: %s", e.task.ID, status)
 }
 
-// GetTask 获取任务
+// persist 将任务状态写入数据库；force 为 false 时按 progressFlushInterval 节流
+func (tm *TaskManager) persist(e *taskEntry, force bool) {
+	e.saveMu.Lock()
+	defer e.saveMu.Unlock()
+
+	e.mu.Lock()
+	if !force && time.Since(e.lastFlush) < progressFlushInterval {
+		e.mu.Unlock()
+		return
+	}
+	e.lastFlush = time.Now()
+	e.record.Status = e.task.Status
+	e.record.Error = e.task.Error
+	e.record.StartTime = e.task.StartTime
+	e.record.EndTime = e.task.EndTime
+	e.record.Progress = append([]string{}, e.task.Progress...)
+	record := *e.record
+	e.mu.Unlock()
+
+	if err := tm.dao.Save(&record); err != nil {
+		log.Printf("[WARN] Failed to persist task %s: %v", record.TaskID, err)
+	}
+}
+
+// GetTask 获取任务：优先读取内存中的实时状态，否则从数据库加载
 func (tm *TaskManager) GetTask(id string) (*Task, bool) {
-	v, ok := tm.tasks.Load(id)
-	if !ok {
+	if v, ok := tm.tasks.Load(id); ok {
+		snapshot := v.(*taskEntry).snapshot()
+		return &snapshot, true
+	}
+	record, err := tm.dao.FindByTaskID(id)
+	if err != nil {
 		return nil, false
 	}
-	return v.(*Task), true
+	t := taskFromRecord(record)
+	return &t, true
 }
 
-// AppendLog 追加任务日志
-func (tm *TaskManager) AppendLog(id string, log string) {
-	if v, ok := tm.tasks.Load(id); ok {
-		t := v.(*Task)
-		t.Progress = append(t.Progress, log)
+// ListTasks 分页列出任务
+func (tm *TaskManager) ListTasks(taskType, status string, page, pageSize int) ([]Task, int64, error) {
+	records, total, err := tm.dao.List(taskType, status, page, pageSize)
+	if err != nil {
+		return nil, 0, err
 	}
+	tasks := make([]Task, 0, len(records))
+	for i := range records {
+		if v, ok := tm.tasks.Load(records[i].TaskID); ok {
+			tasks = append(tasks, v.(*taskEntry).snapshot())
+			continue
+		}
+		tasks = append(tasks, taskFromRecord(&records[i]))
+	}
+	return tasks, total, nil
 }
 
-// UpdateStatus 更新任务状态
-func (tm *TaskManager) UpdateStatus(id string, status string, errStr string) {
-	if v, ok := tm.tasks.Load(id); ok {
-		t := v.(*Task)
-		t.Status = status
-		t.Error = errStr
-		t.EndTime = time.Now()
-
-		// 如果任务完成，减少运行任务数
-		if status == "success" || status == "failed" {
-			tm.mutex.Lock()
-			tm.runningTasks--
-			tm.mutex.Unlock()
-			log.Printf("[INFO] Task %s completed with status: %s", id, status)
+// Cancel 取消任务：排队中的任务直接标记取消，运行中的任务通过 ctx 通知退出
+func (tm *TaskManager) Cancel(id string) error {
+	v, ok := tm.tasks.Load(id)
+	if !ok {
+		if _, err := tm.dao.FindByTaskID(id); err != nil {
+			return fmt.Errorf("task not found")
 		}
+		return fmt.Errorf("task already finished")
 	}
+	e := v.(*taskEntry)
+
+	e.mu.Lock()
+	if e.task.Status == po.AsyncTaskStatusQueued {
+		e.task.Status = po.AsyncTaskStatusCancelled
+		e.task.Error = "cancelled"
+		e.task.EndTime = time.Now()
+		e.mu.Unlock()
+		tm.complete(e)
+		return nil
+	}
+	e.mu.Unlock()
+	e.cancel()
+	return nil
+}
+
+// AppendLog 追加任务日志
+func (tm *TaskManager) AppendLog(id string, log string) {
+	v, ok := tm.tasks.Load(id)
+	if !ok {
+		return
+	}
+	e := v.(*taskEntry)
+	e.mu.Lock()
+	e.task.Progress = append(e.task.Progress, log)
+	if len(e.task.Progress) > maxTaskProgressLines {
+		e.task.Progress = e.task.Progress[len(e.task.Progress)-maxTaskProgressLines:]
+	}
+	e.mu.Unlock()
+	tm.persist(e, false)
 }
 
 // cleanupTasks 清理已完成的任务
 func (tm *TaskManager) cleanupTasks() {
 	for range tm.cleanupTicker.C {
-		log.Printf("[INFO] Starting task cleanup")
-		count := 0
-		tm.tasks.Range(func(key, value interface{}) bool {
-			task := value.(*Task)
-			if task.Status == "success" || task.Status == "failed" {
-				// 清理24小时前完成的任务
-				if time.Since(task.EndTime) > 24*time.Hour {
-					tm.tasks.Delete(key)
-					count++
-				}
-			}
-			return true
-		})
+		count, err := tm.dao.DeleteFinishedBefore(time.Now().Add(-tm.retention))
+		if err != nil {
+			log.Printf("[WARN] Task cleanup failed: %v", err)
+			continue
+		}
 		log.Printf("[INFO] Cleaned up %d completed tasks", count)
 	}
 }
@@ -144,3 +340,29 @@ func (tm *TaskManager) GetRunningTasksCount() int {
 func (tm *TaskManager) GetQueueLength() int {
 	return len(tm.taskQueue)
 }
+
+func (e *taskEntry) snapshot() Task {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	t := e.task
+	t.Progress = append([]string{}, e.task.Progress...)
+	return t
+}
+
+func taskFromRecord(r *po.AsyncTask) Task {
+	progress := r.Progress
+	if progress == nil {
+		progress = []string{}
+	}
+	return Task{
+		ID:         r.TaskID,
+		Type:       r.Type,
+		Target:     r.Target,
+		Status:     r.Status,
+		Progress:   progress,
+		Error:      r.Error,
+		CreateTime: r.CreatedAt,
+		StartTime:  r.StartTime,
+		EndTime:    r.EndTime,
+	}
+}
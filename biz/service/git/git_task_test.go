@@ -0,0 +1,89 @@
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sqlite "github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+)
+
+func TestTaskManagerQueueFullAndCancel(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.AutoMigrate(&po.AsyncTask{}); err != nil {
+		t.Fatal(err)
+	}
+	oldDB, oldCfg := db.DB, configs.GlobalConfig.Task
+	db.DB = conn
+	configs.GlobalConfig.Task.MaxConcurrent = 1
+	configs.GlobalConfig.Task.QueueSize = 1
+	defer func() {
+		db.DB = oldDB
+		configs.GlobalConfig.Task = oldCfg
+	}()
+
+	tm := &TaskManager{dao: db.NewAsyncTaskDAO()}
+	started := make(chan struct{})
+	running, err := tm.Submit("test", "running", func(ctx context.Context, taskID string) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	// 唯一的工作协程被占用，第二个任务留在队列中，第三个任务超出队列容量
+	queued, err := tm.Submit("test", "queued", func(ctx context.Context, taskID string) error {
+		t.Error("cancelled queued task must not run")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.Submit("test", "overflow", func(ctx context.Context, taskID string) error { return nil }); err == nil {
+		t.Fatal("expected queue full error")
+	}
+	failed, total, err := tm.ListTasks("test", po.AsyncTaskStatusFailed, 1, 10)
+	if err != nil || total != 1 || failed[0].Target != "overflow" || failed[0].Error != "task queue is full" {
+		t.Errorf("rejected task should be recorded as failed: %+v (%v)", failed, err)
+	}
+
+	if err := tm.Cancel(queued.ID); err != nil {
+		t.Fatal(err)
+	}
+	if task, ok := tm.GetTask(queued.ID); !ok || task.Status != po.AsyncTaskStatusCancelled {
+		t.Errorf("queued task should be cancelled immediately: %+v", task)
+	}
+	if err := tm.Cancel(queued.ID); err == nil {
+		t.Error("cancelling a finished task should fail")
+	}
+
+	if err := tm.Cancel(running.ID); err != nil {
+		t.Fatal(err)
+	}
+	// 等待任务落库并移出内存，避免测试结束后协程仍访问数据库
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := tm.tasks.Load(running.ID); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("running task not finished after cancel")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if task, ok := tm.GetTask(running.ID); !ok || task.Status != po.AsyncTaskStatusCancelled {
+		t.Errorf("running task should be cancelled: %+v", task)
+	}
+}
@@ -0,0 +1,226 @@
+// biz/service/notification/alert.go - 告警去重、升级与恢复通知
+
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	stdsync "sync"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+	"github.com/yi-nology/git-manage-service/pkg/strutil"
+	"gorm.io/gorm"
+)
+
+// alertFamilies 需要跟踪告警状态的失败事件，以及能使其恢复的成功事件
+var (
+	alertFailureEvents = map[string]string{
+		po.TriggerSyncFailure:   "sync",
+		po.TriggerSyncConflict:  "sync",
+		po.TriggerBackupFailure: "backup",
+	}
+	alertRecoveryEvents = map[string][]string{
+		po.TriggerSyncSuccess:   {po.TriggerSyncFailure, po.TriggerSyncConflict},
+		po.TriggerBackupSuccess: {po.TriggerBackupFailure},
+	}
+)
+
+// alertMu 串行化告警状态更新，避免同一任务并发结果重复创建告警
+var alertMu stdsync.Mutex
+
+// alertPlan 一次事件的告警处理结果
+type alertPlan struct {
+	notify     bool                   // 是否发送本次事件
+	escalated  bool                   // 是否同时发送到升级渠道
+	recoveries []*NotificationMessage // 需要额外发送的恢复通知
+	// recoveryEscalated 对应恢复通知是否发送到升级渠道
+	recoveryEscalated []bool
+}
+
+func alertSubjectKey(msg *NotificationMessage) string {
+	if msg.TaskKey != "" {
+		return msg.TaskKey
+	}
+	return msg.RepoKey
+}
+
+// trackAlert 更新告警状态并决定本次事件如何发送；不需要跟踪的事件返回 nil（照常发送）。
+// 告警状态读写失败时同样返回 nil，宁可重复发送也不丢失通知
+func (s *NotificationService) trackAlert(msg *NotificationMessage) *alertPlan {
+	subject := alertSubjectKey(msg)
+	if subject == "" {
+		return nil
+	}
+	if _, ok := alertFailureEvents[msg.TriggerEvent]; ok {
+		alertMu.Lock()
+		defer alertMu.Unlock()
+		return s.recordFailure(subject, msg, time.Now())
+	}
+	if events, ok := alertRecoveryEvents[msg.TriggerEvent]; ok {
+		alertMu.Lock()
+		defer alertMu.Unlock()
+		return s.resolveAlerts(subject, events, msg, time.Now())
+	}
+	return nil
+}
+
+func (s *NotificationService) recordFailure(subject string, msg *NotificationMessage, now time.Time) *alertPlan {
+	cfg := configs.GlobalConfig.Notification
+	errMsg := ""
+	if msg.Data != nil {
+		errMsg = strutil.Truncate(msg.Data.ErrorMessage, 500)
+	}
+
+	alert, err := s.alertDAO.FindFiring(subject, msg.TriggerEvent)
+	isNew := errors.Is(err, gorm.ErrRecordNotFound)
+	if err != nil && !isNew {
+		log.Printf("[Notification] Failed to load alert state for %s: %v", subject, err)
+		return nil
+	}
+	if isNew {
+		alert = &po.NotificationAlert{
+			SubjectKey:    subject,
+			TaskKey:       msg.TaskKey,
+			RepoKey:       msg.RepoKey,
+			EventType:     msg.TriggerEvent,
+			State:         po.AlertStateFiring,
+			FirstFailedAt: now,
+		}
+	}
+	alert.ConsecutiveFailures++
+	alert.LastFailedAt = now
+	alert.LastError = errMsg
+
+	notify, prefix := decideAlertNotify(alert, cfg, now)
+	plan := &alertPlan{notify: notify}
+	if notify {
+		alert.LastNotifiedAt = &now
+	} else {
+		alert.SuppressedCount++
+	}
+	plan.escalated = alert.Escalated
+
+	if isNew {
+		err = s.alertDAO.Create(alert)
+	} else {
+		err = s.alertDAO.Save(alert)
+	}
+	if err != nil {
+		log.Printf("[Notification] Failed to save alert state for %s: %v", subject, err)
+		return nil
+	}
+
+	if msg.Data != nil {
+		msg.Data.ConsecutiveFailures = alert.ConsecutiveFailures
+		msg.Data.FailingSince = alert.FirstFailedAt.Format("2006-01-02 15:04:05")
+	}
+	msg.titlePrefix = prefix
+	if !notify {
+		log.Printf("[Notification] Suppressed repeated %s for %s (%d consecutive failures)", msg.TriggerEvent, subject, alert.ConsecutiveFailures)
+	}
+	return plan
+}
+
+// decideAlertNotify 判断本次失败是否发送，并在升级时标记告警；返回附加在标题前的提示
+func decideAlertNotify(alert *po.NotificationAlert, cfg configs.NotificationConfig, now time.Time) (bool, string) {
+	if cfg.EscalateAfter > 0 && !alert.Escalated && alert.ConsecutiveFailures >= cfg.EscalateAfter {
+		alert.Escalated = true
+		alert.EscalatedAt = &now
+		return true, fmt.Sprintf("[升级·连续失败%d次] ", alert.ConsecutiveFailures)
+	}
+	if alert.ConsecutiveFailures == 1 || !cfg.AlertDedup {
+		return true, ""
+	}
+	if cfg.AlertReminderMinutes > 0 && alert.LastNotifiedAt != nil &&
+		!now.Before(alert.LastNotifiedAt.Add(time.Duration(cfg.AlertReminderMinutes)*time.Minute)) {
+		return true, fmt.Sprintf("[仍未恢复·连续失败%d次] ", alert.ConsecutiveFailures)
+	}
+	return false, ""
+}
+
+// resolveAlerts 成功事件关闭对象上的告警，并为每个告警生成恢复通知
+func (s *NotificationService) resolveAlerts(subject string, events []string, msg *NotificationMessage, now time.Time) *alertPlan {
+	alerts, err := s.alertDAO.FindFiringBySubject(subject, events)
+	if err != nil {
+		log.Printf("[Notification] Failed to load alert state for %s: %v", subject, err)
+		return nil
+	}
+	plan := &alertPlan{notify: true}
+	for i := range alerts {
+		alert := &alerts[i]
+		alert.State = po.AlertStateResolved
+		alert.ResolvedAt = &now
+		alert.ResolvedBy = "recovery"
+		if err := s.alertDAO.Save(alert); err != nil {
+			log.Printf("[Notification] Failed to resolve alert %d: %v", alert.ID, err)
+			continue
+		}
+		// 被去重的告警也需要恢复通知，接收方此前收到过首次告警
+		plan.recoveries = append(plan.recoveries, recoveryMessage(alert, msg, now))
+		plan.recoveryEscalated = append(plan.recoveryEscalated, alert.Escalated)
+	}
+	return plan
+}
+
+func recoveryMessage(alert *po.NotificationAlert, msg *NotificationMessage, now time.Time) *NotificationMessage {
+	data := &TemplateData{
+		TaskKey:             alert.TaskKey,
+		RepoKey:             alert.RepoKey,
+		Status:              "success",
+		EventType:           po.TriggerAlertRecovered,
+		ConsecutiveFailures: alert.ConsecutiveFailures,
+		FailingSince:        alert.FirstFailedAt.Format("2006-01-02 15:04:05"),
+		Timestamp:           now.Format("2006-01-02 15:04:05"),
+	}
+	if msg.Data != nil {
+		data.TaskName = msg.Data.TaskName
+		data.RepoName = msg.Data.RepoName
+		data.SourceRemote, data.SourceBranch = msg.Data.SourceRemote, msg.Data.SourceBranch
+		data.TargetRemote, data.TargetBranch = msg.Data.TargetRemote, msg.Data.TargetBranch
+	}
+	title, content := RenderTitleAndContent("", "", data)
+	return &NotificationMessage{
+		Title:         title,
+		Content:       content,
+		Status:        "success",
+		TriggerEvent:  po.TriggerAlertRecovered,
+		TaskKey:       alert.TaskKey,
+		RepoKey:       alert.RepoKey,
+		Data:          data,
+		TriggerSource: msg.TriggerSource,
+
+		RecoveredEvent: alert.EventType,
+	}
+}
+
+// ListAlerts 查询告警状态
+func (s *NotificationService) ListAlerts(req *api.ListNotificationAlertsReq) ([]po.NotificationAlert, int64, error) {
+	return s.alertDAO.List(req.State, req.TaskKey, req.RepoKey, req.Page, req.PageSize)
+}
+
+// CountFiringAlerts 正在进行的告警数量
+func (s *NotificationService) CountFiringAlerts() (int64, error) {
+	return s.alertDAO.CountByState(po.AlertStateFiring)
+}
+
+// ResolveAlert 手动关闭告警（不发送恢复通知），下次失败重新开始计数
+func (s *NotificationService) ResolveAlert(id uint) error {
+	alertMu.Lock()
+	defer alertMu.Unlock()
+	alert, err := s.alertDAO.FindByID(id)
+	if err != nil {
+		return fmt.Errorf("alert not found")
+	}
+	if alert.State != po.AlertStateFiring {
+		return fmt.Errorf("alert is already resolved")
+	}
+	now := time.Now()
+	alert.State = po.AlertStateResolved
+	alert.ResolvedAt = &now
+	alert.ResolvedBy = "manual"
+	return s.alertDAO.Save(alert)
+}
@@ -0,0 +1,45 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+)
+
+func TestDecideAlertNotify(t *testing.T) {
+	cfg := configs.NotificationConfig{AlertDedup: true, AlertReminderMinutes: 60, EscalateAfter: 3}
+	start := time.Date(2026, 2, 16, 2, 0, 0, 0, time.UTC)
+	alert := &po.NotificationAlert{}
+
+	// 每 10 分钟失败一次
+	var sent []string
+	for i := 0; i < 12; i++ {
+		now := start.Add(time.Duration(i*10) * time.Minute)
+		alert.ConsecutiveFailures++
+		notify, prefix := decideAlertNotify(alert, cfg, now)
+		if notify {
+			alert.LastNotifiedAt = &now
+			sent = append(sent, now.Format("15:04")+" "+prefix)
+		}
+	}
+	want := []string{"02:00 ", "02:20 [升级", "03:20 [仍未恢复"}
+	if len(sent) != len(want) {
+		t.Fatalf("sent %v, want %d notifications", sent, len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(sent[i], want[i]) {
+			t.Errorf("notification %d = %q, want prefix %q", i, sent[i], want[i])
+		}
+	}
+	if !alert.Escalated || alert.EscalatedAt == nil {
+		t.Error("alert should be escalated after 3 failures")
+	}
+
+	noDedup := &po.NotificationAlert{ConsecutiveFailures: 2}
+	if notify, _ := decideAlertNotify(noDedup, configs.NotificationConfig{}, start); !notify {
+		t.Error("dedup disabled: every failure should be sent")
+	}
+}
@@ -0,0 +1,260 @@
+// biz/service/notification/delivery.go - 通知投递、自动重试与统计
+
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	stdsync "sync"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+	"github.com/yi-nology/git-manage-service/pkg/strutil"
+)
+
+const (
+	retryPollInterval = 15 * time.Second
+	retryBatchSize    = 20
+	maxRetryBackoff   = time.Hour
+)
+
+var retryWorkerOnce stdsync.Once
+
+// errChannelGone 渠道已删除或停用，重试没有意义
+var errChannelGone = errors.New("channel deleted or disabled")
+
+// InitWorkers 启动失败投递的自动重试、汇总发送与过期记录清理协程，并恢复上次退出时未完成的投递
+func InitWorkers() {
+	retryWorkerOnce.Do(func() {
+		dao := db.NewNotificationDeliveryDAO()
+		if n, err := dao.ResetInterrupted(time.Now()); err != nil {
+			log.Printf("[Notification] Failed to recover interrupted deliveries: %v", err)
+		} else if n > 0 {
+			log.Printf("[Notification] Rescheduled %d interrupted deliveries", n)
+		}
+		go retryLoop()
+		go cleanupLoop()
+		go digestLoop()
+	})
+}
+
+func retryLoop() {
+	ticker := time.NewTicker(retryPollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		deliveries, err := db.NewNotificationDeliveryDAO().FindDueRetries(time.Now(), retryBatchSize)
+		if err != nil {
+			log.Printf("[Notification] Failed to load deliveries for retry: %v", err)
+			continue
+		}
+		for i := range deliveries {
+			d := deliveries[i]
+			go NotifySvc.deliver(&d, true)
+		}
+	}
+}
+
+func cleanupLoop() {
+	cleanupDeliveries()
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		cleanupDeliveries()
+	}
+}
+
+func cleanupDeliveries() {
+	days := configs.GlobalConfig.Notification.RetentionDays
+	if days <= 0 {
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+	if n, err := db.NewNotificationDeliveryDAO().DeleteBefore(cutoff); err != nil {
+		log.Printf("[Notification] Failed to clean up deliveries: %v", err)
+	} else if n > 0 {
+		log.Printf("[Notification] Removed %d deliveries older than %d days", n, days)
+	}
+	if n, err := db.NewNotificationAlertDAO().DeleteResolvedBefore(cutoff); err != nil {
+		log.Printf("[Notification] Failed to clean up resolved alerts: %v", err)
+	} else if n > 0 {
+		log.Printf("[Notification] Removed %d resolved alerts older than %d days", n, days)
+	}
+}
+
+// deliver 抢占投递（包括静默时段结束的延后投递）并发送一次；失败时按指数退避安排下一次重试，次数耗尽后标记为 dead。
+// honorQuiet 为 true 时（自动重试与重启恢复）先检查静默时段，时段内的非紧急投递延后到时段结束
+func (s *NotificationService) deliver(d *po.NotificationDelivery, honorQuiet bool) {
+	claimed, err := s.deliveryDAO.Claim(d.ID, []string{po.DeliveryStatusPending, po.DeliveryStatusFailed, po.DeliveryStatusDeferred})
+	if err != nil || !claimed {
+		return
+	}
+
+	if honorQuiet {
+		if ch, err := s.dao.FindByID(d.ChannelID); err == nil && deferIfQuiet(ch, d, deliverySeverity(d), time.Now()) {
+			if err := s.deliveryDAO.Save(d); err != nil {
+				log.Printf("[Notification] Failed to save delivery %d: %v", d.ID, err)
+			}
+			log.Printf("[Notification] Deferred retry to channel %s until %s (quiet hours)", d.ChannelName, d.NextRetryAt.Format(time.RFC3339))
+			return
+		}
+	}
+
+	sendErr := s.sendDelivery(d)
+
+	now := time.Now()
+	d.Attempts++
+	d.NextRetryAt = nil
+	d.LastError = ""
+	switch {
+	case sendErr == nil:
+		d.Status = po.DeliveryStatusSucceeded
+		d.DeliveredAt = &now
+		log.Printf("[Notification] Sent to channel %s successfully", d.ChannelName)
+	case errors.Is(sendErr, errChannelGone) || d.Attempts > maxRetries():
+		d.Status = po.DeliveryStatusDead
+		d.LastError = strutil.Truncate(sendErr.Error(), 500)
+		log.Printf("[Notification] Giving up on channel %s after %d attempts: %v", d.ChannelName, d.Attempts, sendErr)
+	default:
+		d.Status = po.DeliveryStatusFailed
+		d.LastError = strutil.Truncate(sendErr.Error(), 500)
+		next := now.Add(retryDelay(d.Attempts))
+		d.NextRetryAt = &next
+		log.Printf("[Notification] Failed to send to channel %s (attempt %d): %v", d.ChannelName, d.Attempts, sendErr)
+	}
+	if err := s.deliveryDAO.Save(d); err != nil {
+		log.Printf("[Notification] Failed to save delivery %d: %v", d.ID, err)
+	}
+}
+
+// sendDelivery 使用渠道的当前配置发送已渲染的内容
+func (s *NotificationService) sendDelivery(d *po.NotificationDelivery) error {
+	channel, err := s.dao.FindByID(d.ChannelID)
+	if err != nil || !channel.Enabled {
+		return errChannelGone
+	}
+	sender, err := s.createSender(channel)
+	if err != nil {
+		return err
+	}
+	msg := &NotificationMessage{
+		Title:        d.Title,
+		Content:      d.Content,
+		Status:       d.MessageStatus,
+		TriggerEvent: d.TriggerEvent,
+		TaskKey:      d.TaskKey,
+		RepoKey:      d.RepoKey,
+	}
+	if d.DataJSON != "" {
+		var data TemplateData
+		if json.Unmarshal([]byte(d.DataJSON), &data) == nil {
+			msg.Data = &data
+		}
+	}
+	if d.MentionsJSON != "" {
+		_ = json.Unmarshal([]byte(d.MentionsJSON), &msg.Mentions)
+	}
+	return sender.Send(msg)
+}
+
+// Resend 重发一条投递（包括已成功的），重置自动重试计数
+func (s *NotificationService) Resend(id uint) error {
+	d, err := s.deliveryDAO.FindByID(id)
+	if err != nil {
+		return fmt.Errorf("delivery not found: %w", err)
+	}
+	if d.Status == po.DeliveryStatusSending {
+		return fmt.Errorf("delivery is being sent")
+	}
+	d.Attempts = 0
+	d.NextRetryAt = nil
+	d.LastError = ""
+	d.Status = po.DeliveryStatusPending
+	if err := s.deliveryDAO.Save(d); err != nil {
+		return err
+	}
+	go s.deliver(d, false)
+	return nil
+}
+
+func (s *NotificationService) ListDeliveries(req *api.ListNotificationDeliveriesReq) ([]po.NotificationDelivery, int64, error) {
+	return s.deliveryDAO.List(req.ChannelID, req.TriggerEvent, req.Status, req.Page, req.PageSize)
+}
+
+// DeliveryStats 统计最近 days 天每个渠道的投递结果；成功率只计算已结束（成功或放弃）与等待重试的投递
+func (s *NotificationService) DeliveryStats(days int) ([]*api.ChannelDeliveryStatsDTO, error) {
+	rows, err := s.deliveryDAO.CountByChannelStatus(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, err
+	}
+	channels, err := s.dao.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byChannel := make(map[uint]*api.ChannelDeliveryStatsDTO, len(channels))
+	for _, ch := range channels {
+		byChannel[ch.ID] = &api.ChannelDeliveryStatsDTO{ChannelID: ch.ID, ChannelName: ch.Name, ChannelType: ch.Type, Enabled: ch.Enabled}
+	}
+	for _, row := range rows {
+		stats, ok := byChannel[row.ChannelID]
+		if !ok {
+			// 渠道已删除，仍保留其历史投递统计
+			stats = &api.ChannelDeliveryStatsDTO{ChannelID: row.ChannelID, Deleted: true}
+			byChannel[row.ChannelID] = stats
+		}
+		stats.Total += row.Count
+		switch row.Status {
+		case po.DeliveryStatusSucceeded:
+			stats.Succeeded += row.Count
+		case po.DeliveryStatusFailed:
+			stats.Retrying += row.Count
+		case po.DeliveryStatusDead:
+			stats.Dead += row.Count
+		case po.DeliveryStatusDeferred:
+			stats.Deferred += row.Count
+		default:
+			stats.Pending += row.Count
+		}
+	}
+
+	result := make([]*api.ChannelDeliveryStatsDTO, 0, len(byChannel))
+	for _, stats := range byChannel {
+		if attempted := stats.Succeeded + stats.Retrying + stats.Dead; attempted > 0 {
+			rate := float64(stats.Succeeded) / float64(attempted)
+			stats.SuccessRate = &rate
+		}
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ChannelID < result[j].ChannelID })
+	return result, nil
+}
+
+func maxRetries() int {
+	n := configs.GlobalConfig.Notification.MaxRetries
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// retryDelay 指数退避：retry_backoff * 2^(attempts-1)，上限 maxRetryBackoff
+func retryDelay(attempts int) time.Duration {
+	base := time.Duration(configs.GlobalConfig.Notification.RetryBackoff) * time.Second
+	if base <= 0 {
+		base = 30 * time.Second
+	}
+	delay := base
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	return delay
+}
@@ -0,0 +1,157 @@
+package notification
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sqlite "github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+)
+
+func TestDeliverDefersRetryInQuietHours(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer srv.Close()
+
+	s := openDeliveryTestDB(t)
+	ch := &po.NotificationChannel{
+		Name: "hook", Type: "webhook", Enabled: true, Config: `{"url":"` + srv.URL + `"}`,
+		QuietHours: &po.QuietHoursConfig{Windows: []po.QuietWindow{{Start: "00:00", End: "00:00"}}},
+	}
+	if err := db.DB.Create(ch).Error; err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Minute)
+	newRetry := func(severity, messageStatus string) *po.NotificationDelivery {
+		d := &po.NotificationDelivery{
+			ChannelID: ch.ID, ChannelName: ch.Name, TriggerEvent: po.TriggerSyncSuccess, MessageStatus: messageStatus,
+			Severity: severity, Status: po.DeliveryStatusFailed, Attempts: 1, NextRetryAt: &past,
+		}
+		if err := db.DB.Create(d).Error; err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	info := newRetry(po.SeverityInfo, "success")
+	s.deliver(info, true)
+	stored, _ := s.deliveryDAO.FindByID(info.ID)
+	if atomic.LoadInt32(&hits) != 0 || stored.Status != po.DeliveryStatusDeferred || stored.Attempts != 1 ||
+		stored.NextRetryAt == nil || !stored.NextRetryAt.After(time.Now()) {
+		t.Errorf("info retry should be deferred without sending: hits=%d %+v", hits, stored)
+	}
+
+	// 未记录级别的旧记录按消息状态推断，失败消息照常穿透
+	for _, d := range []*po.NotificationDelivery{newRetry(po.SeverityCritical, "failure"), newRetry("", "failure")} {
+		s.deliver(d, true)
+		if stored, _ := s.deliveryDAO.FindByID(d.ID); stored.Status != po.DeliveryStatusSucceeded {
+			t.Errorf("critical retry should break through: %+v", stored)
+		}
+	}
+	if atomic.LoadInt32(&hits) != 2 {
+		t.Errorf("expected 2 sends, got %d", hits)
+	}
+}
+
+func TestDeliverStateTransitions(t *testing.T) {
+	var fail int32 = 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fail) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	s := openDeliveryTestDB(t)
+	old := configs.GlobalConfig.Notification
+	configs.GlobalConfig.Notification.MaxRetries = 1
+	configs.GlobalConfig.Notification.RetryBackoff = 10
+	defer func() { configs.GlobalConfig.Notification = old }()
+
+	ch := &po.NotificationChannel{Name: "hook", Type: "webhook", Enabled: true, Config: `{"url":"` + srv.URL + `"}`}
+	if err := db.DB.Create(ch).Error; err != nil {
+		t.Fatal(err)
+	}
+	newDelivery := func(channelID uint, status string) *po.NotificationDelivery {
+		d := &po.NotificationDelivery{ChannelID: channelID, ChannelName: "hook", TriggerEvent: po.TriggerSyncSuccess, Status: status}
+		if err := db.DB.Create(d).Error; err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	load := func(id uint) *po.NotificationDelivery {
+		d, err := s.deliveryDAO.FindByID(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	// pending -> failed（安排重试）-> dead（重试次数用尽）
+	d := newDelivery(ch.ID, po.DeliveryStatusPending)
+	before := time.Now()
+	s.deliver(d, false)
+	got := load(d.ID)
+	if got.Status != po.DeliveryStatusFailed || got.Attempts != 1 || got.LastError == "" ||
+		got.NextRetryAt == nil || got.NextRetryAt.Before(before.Add(10*time.Second)) {
+		t.Fatalf("first failure should schedule a retry: %+v", got)
+	}
+	s.deliver(got, false)
+	got = load(d.ID)
+	if got.Status != po.DeliveryStatusDead || got.Attempts != 2 || got.NextRetryAt != nil {
+		t.Fatalf("exhausted retries should give up: %+v", got)
+	}
+	s.deliver(got, false)
+	if again := load(d.ID); again.Attempts != 2 {
+		t.Errorf("dead delivery must not be claimed: %+v", again)
+	}
+
+	// 渠道已删除时不再重试
+	gone := newDelivery(ch.ID+100, po.DeliveryStatusPending)
+	s.deliver(gone, false)
+	if got := load(gone.ID); got.Status != po.DeliveryStatusDead || got.Attempts != 1 {
+		t.Errorf("missing channel should be dead immediately: %+v", got)
+	}
+
+	// 发送中的投递已被其他协程抢占
+	sending := newDelivery(ch.ID, po.DeliveryStatusSending)
+	s.deliver(sending, false)
+	if got := load(sending.ID); got.Status != po.DeliveryStatusSending || got.Attempts != 0 {
+		t.Errorf("sending delivery must be left alone: %+v", got)
+	}
+
+	// failed -> succeeded，清除错误与重试时间
+	atomic.StoreInt32(&fail, 0)
+	retry := newDelivery(ch.ID, po.DeliveryStatusFailed)
+	s.deliver(retry, false)
+	got = load(retry.ID)
+	if got.Status != po.DeliveryStatusSucceeded || got.DeliveredAt == nil || got.LastError != "" || got.NextRetryAt != nil {
+		t.Errorf("successful retry: %+v", got)
+	}
+}
+
+// openDeliveryTestDB 使用临时 sqlite 数据库替换 db.DB，返回基于该库的通知服务
+func openDeliveryTestDB(t *testing.T) *NotificationService {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.AutoMigrate(&po.NotificationChannel{}, &po.NotificationDelivery{}); err != nil {
+		t.Fatal(err)
+	}
+	old := db.DB
+	db.DB = conn
+	t.Cleanup(func() { db.DB = old })
+	return NewNotificationService()
+}
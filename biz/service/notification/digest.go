@@ -0,0 +1,361 @@
+// biz/service/notification/digest.go - 通知批量合并与每日摘要
+
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"gorm.io/gorm"
+)
+
+const (
+	digestPollInterval   = 30 * time.Second
+	digestMaxItems       = 1000
+	defaultBatchInterval = 15
+	defaultDigestTime    = "09:00"
+	maxBatchInterval     = 24 * 60
+)
+
+// ApplyDeliveryMode 将请求中的投递模式、升级渠道与静默时段设置合并到渠道并校验，未传的字段保持不变
+func ApplyDeliveryMode(ch *po.NotificationChannel, req *api.NotificationChannelExtraReq) error {
+	if req.DeliveryMode != nil {
+		ch.DeliveryMode = *req.DeliveryMode
+	}
+	if req.BatchIntervalMinutes != nil {
+		ch.BatchIntervalMinutes = *req.BatchIntervalMinutes
+	}
+	if req.DigestTime != nil {
+		ch.DigestTime = *req.DigestTime
+	}
+	if req.ImmediateEvents != nil {
+		data, err := json.Marshal(*req.ImmediateEvents)
+		if err != nil {
+			return err
+		}
+		ch.ImmediateEvents = string(data)
+	}
+	if req.DigestTitleTemplate != nil {
+		ch.DigestTitleTemplate = *req.DigestTitleTemplate
+	}
+	if req.DigestContentTemplate != nil {
+		ch.DigestContentTemplate = *req.DigestContentTemplate
+	}
+	if req.Escalation != nil {
+		ch.Escalation = *req.Escalation
+	}
+	if req.QuietHours != nil {
+		// 传空对象或不含时段时关闭静默
+		ch.QuietHours = req.QuietHours
+		if len(ch.QuietHours.Windows) == 0 {
+			ch.QuietHours = nil
+		}
+	}
+	if err := ValidateQuietHours(ch.QuietHours); err != nil {
+		return err
+	}
+
+	switch ch.DeliveryMode {
+	case "", po.DeliveryModeImmediate:
+		ch.DeliveryMode = po.DeliveryModeImmediate
+	case po.DeliveryModeBatch:
+		if ch.BatchIntervalMinutes == 0 {
+			ch.BatchIntervalMinutes = defaultBatchInterval
+		}
+		if ch.BatchIntervalMinutes < 1 || ch.BatchIntervalMinutes > maxBatchInterval {
+			return fmt.Errorf("batch_interval_minutes must be between 1 and %d", maxBatchInterval)
+		}
+	case po.DeliveryModeDigest:
+		if ch.DigestTime == "" {
+			ch.DigestTime = defaultDigestTime
+		}
+		if _, err := time.Parse("15:04", ch.DigestTime); err != nil {
+			return fmt.Errorf("digest_time must be HH:MM")
+		}
+	default:
+		return fmt.Errorf("unknown delivery_mode %q", ch.DeliveryMode)
+	}
+
+	if err := ValidateTemplate(ch.DigestTitleTemplate); err != nil {
+		return fmt.Errorf("汇总标题模板语法错误: %w", err)
+	}
+	if err := ValidateTemplate(ch.DigestContentTemplate); err != nil {
+		return fmt.Errorf("汇总内容模板语法错误: %w", err)
+	}
+	return nil
+}
+
+// DeliverySettings 渠道投递模式设置；immediate_events 为 null 表示失败与冲突立即发送
+func (s *NotificationService) DeliverySettings(ch *po.NotificationChannel) *api.ChannelDeliveryModeDTO {
+	dto := &api.ChannelDeliveryModeDTO{
+		DeliveryMode:          ch.DeliveryMode,
+		BatchIntervalMinutes:  ch.BatchIntervalMinutes,
+		DigestTime:            ch.DigestTime,
+		DigestTitleTemplate:   ch.DigestTitleTemplate,
+		DigestContentTemplate: ch.DigestContentTemplate,
+		Escalation:            ch.Escalation,
+		QuietHours:            ch.QuietHours,
+	}
+	if dto.DeliveryMode == "" {
+		dto.DeliveryMode = po.DeliveryModeImmediate
+	}
+	if ch.ImmediateEvents != "" {
+		_ = json.Unmarshal([]byte(ch.ImmediateEvents), &dto.ImmediateEvents)
+	}
+	dto.PendingCount, _ = s.digestDAO.CountByChannelID(ch.ID)
+	return dto
+}
+
+// sendsImmediately 判断事件是否绕过汇总队列直接发送
+func sendsImmediately(ch *po.NotificationChannel, msg *NotificationMessage) bool {
+	if ch.DeliveryMode != po.DeliveryModeBatch && ch.DeliveryMode != po.DeliveryModeDigest {
+		return true
+	}
+	if ch.ImmediateEvents == "" {
+		return msg.Status == "failure" || msg.TriggerEvent == po.TriggerSyncConflict || msg.TriggerEvent == po.TriggerAlertRecovered
+	}
+	var events []string
+	if err := json.Unmarshal([]byte(ch.ImmediateEvents), &events); err != nil {
+		return true
+	}
+	for _, e := range events {
+		if e == msg.TriggerEvent {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueueDigest 将事件加入渠道的汇总队列
+func (s *NotificationService) enqueueDigest(ch *po.NotificationChannel, msg *NotificationMessage) {
+	item := &po.NotificationDigestItem{
+		ChannelID:    ch.ID,
+		TriggerEvent: msg.TriggerEvent,
+		Status:       msg.Status,
+		TaskKey:      msg.TaskKey,
+		RepoKey:      msg.RepoKey,
+		Title:        msg.Title,
+	}
+	if msg.Data != nil {
+		data := *msg.Data
+		fillDefaults(&data)
+		if b, err := json.Marshal(&data); err == nil {
+			item.DataJSON = string(b)
+		}
+	}
+	if err := s.digestDAO.Create(item); err != nil {
+		// 入队失败时退回立即发送，避免丢失通知
+		log.Printf("[Notification] Failed to queue digest item for channel %s: %v", ch.Name, err)
+		go s.sendUnrecorded(*ch, s.renderMessage(ch, msg))
+	}
+}
+
+func digestLoop() {
+	ticker := time.NewTicker(digestPollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		NotifySvc.flushDueDigests(time.Now())
+	}
+}
+
+// flushDueDigests 发送所有到期渠道的汇总消息
+func (s *NotificationService) flushDueDigests(now time.Time) {
+	ids, err := s.digestDAO.PendingChannelIDs()
+	if err != nil {
+		log.Printf("[Notification] Failed to load digest queue: %v", err)
+		return
+	}
+	for _, id := range ids {
+		ch, err := s.dao.FindByID(id)
+		if err != nil || !ch.Enabled {
+			// 停用的渠道保留队列，重新启用后再发送
+			continue
+		}
+		oldest, err := s.digestDAO.FindOldest(id)
+		if err != nil {
+			continue
+		}
+		cutoff, due := digestCutoff(ch, oldest.CreatedAt, now)
+		if !due {
+			continue
+		}
+		if _, err := s.flushDigest(ch, cutoff); err != nil {
+			log.Printf("[Notification] Failed to flush digest for channel %s: %v", ch.Name, err)
+		}
+	}
+}
+
+// digestCutoff 计算本次应合并发送的事件截止时间（不含），due 为 false 表示尚未到期。
+// batch：最早事件已等待满间隔时发送全部；digest：发送最近一次发送时刻之前入队的事件；
+// 渠道切回 immediate 时立即清空队列
+func digestCutoff(ch *po.NotificationChannel, oldest, now time.Time) (time.Time, bool) {
+	switch ch.DeliveryMode {
+	case po.DeliveryModeBatch:
+		interval := ch.BatchIntervalMinutes
+		if interval <= 0 {
+			interval = defaultBatchInterval
+		}
+		return now, !now.Before(oldest.Add(time.Duration(interval) * time.Minute))
+	case po.DeliveryModeDigest:
+		at, err := time.Parse("15:04", ch.DigestTime)
+		if err != nil {
+			at, _ = time.Parse("15:04", defaultDigestTime)
+		}
+		scheduled := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, now.Location())
+		if scheduled.After(now) {
+			scheduled = scheduled.AddDate(0, 0, -1)
+		}
+		return scheduled, oldest.Before(scheduled)
+	}
+	return now, true
+}
+
+// FlushDigest 立即发送渠道队列中的全部事件，返回合并的事件数
+func (s *NotificationService) FlushDigest(channelID uint) (int, error) {
+	ch, err := s.dao.FindByID(channelID)
+	if err != nil {
+		return 0, fmt.Errorf("channel not found")
+	}
+	return s.flushDigest(ch, time.Now().Add(time.Second))
+}
+
+// flushDigest 将 before 之前入队的事件合并为一条投递记录，与删除队列在同一事务中完成
+func (s *NotificationService) flushDigest(ch *po.NotificationChannel, before time.Time) (int, error) {
+	items, err := s.digestDAO.FindByChannelBefore(ch.ID, before, digestMaxItems)
+	if err != nil || len(items) == 0 {
+		return 0, err
+	}
+	data := buildDigestData(ch, items, time.Now())
+	title, content := RenderDigest(ch.DigestTitleTemplate, ch.DigestContentTemplate, data)
+	status := "success"
+	if data.FailureCount+data.ConflictCount > 0 {
+		status = "failure"
+	}
+	delivery := &po.NotificationDelivery{
+		ChannelID:     ch.ID,
+		ChannelName:   ch.Name,
+		ChannelType:   ch.Type,
+		TriggerEvent:  po.DigestEventType,
+		MessageStatus: status,
+		Title:         title,
+		Content:       content,
+		Status:        po.DeliveryStatusPending,
+	}
+	severity := po.SeverityInfo
+	if status == "failure" {
+		severity = po.SeverityWarning
+	}
+	delivery.Severity = severity
+	deferred := deferIfQuiet(ch, delivery, severity, time.Now())
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(delivery).Error; err != nil {
+			return err
+		}
+		return s.digestDAO.DeleteByIDs(tx, ids)
+	})
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("[Notification] Flushed %d queued events to channel %s", len(items), ch.Name)
+	if !deferred {
+		go s.deliver(delivery, false)
+	}
+	return len(items), nil
+}
+
+// buildDigestData 统计队列事件，items 需按入队时间升序
+func buildDigestData(ch *po.NotificationChannel, items []po.NotificationDigestItem, now time.Time) *DigestData {
+	data := &DigestData{
+		ChannelName: ch.Name,
+		Mode:        ch.DeliveryMode,
+		ModeText:    "批量汇总",
+		Timestamp:   now.Format("2006-01-02 15:04:05"),
+		Total:       len(items),
+	}
+	if ch.DeliveryMode == po.DeliveryModeDigest {
+		data.ModeText = "每日摘要"
+	}
+	if len(items) > 0 {
+		data.PeriodStart = items[0].CreatedAt.Local().Format("2006-01-02 15:04:05")
+		data.PeriodEnd = items[len(items)-1].CreatedAt.Local().Format("2006-01-02 15:04:05")
+	}
+
+	counts := map[string]*DigestEventCount{}
+	for _, item := range items {
+		entry := digestEntry(&item)
+		data.Entries = append(data.Entries, entry)
+
+		switch {
+		case item.TriggerEvent == po.TriggerSyncConflict:
+			data.ConflictCount++
+			data.Failures = append(data.Failures, entry)
+		case item.Status == "failure":
+			data.FailureCount++
+			data.Failures = append(data.Failures, entry)
+		case item.Status == "success":
+			data.SuccessCount++
+		}
+
+		c, ok := counts[entry.EventType]
+		if !ok {
+			c = &DigestEventCount{EventType: entry.EventType, EventLabel: entry.EventLabel}
+			counts[entry.EventType] = c
+		}
+		c.Count++
+	}
+	for _, c := range counts {
+		data.EventCounts = append(data.EventCounts, *c)
+	}
+	sort.Slice(data.EventCounts, func(i, j int) bool {
+		if data.EventCounts[i].Count != data.EventCounts[j].Count {
+			return data.EventCounts[i].Count > data.EventCounts[j].Count
+		}
+		return data.EventCounts[i].EventType < data.EventCounts[j].EventType
+	})
+	return data
+}
+
+func digestEntry(item *po.NotificationDigestItem) DigestEntry {
+	var td TemplateData
+	if item.DataJSON != "" {
+		_ = json.Unmarshal([]byte(item.DataJSON), &td)
+	}
+	if td.TaskKey == "" {
+		td.TaskKey = item.TaskKey
+	}
+	if td.RepoKey == "" {
+		td.RepoKey = item.RepoKey
+	}
+	if td.EventType == "" {
+		td.EventType = item.TriggerEvent
+	}
+	if td.Status == "" {
+		td.Status = item.Status
+	}
+	if td.Timestamp == "" {
+		td.Timestamp = item.CreatedAt.Local().Format("2006-01-02 15:04:05")
+	}
+	fillDefaults(&td)
+	if td.EventLabel == "" {
+		td.EventLabel = item.Title
+	}
+	return DigestEntry{
+		TaskName:     td.TaskName,
+		RepoName:     td.RepoName,
+		EventType:    td.EventType,
+		EventLabel:   td.EventLabel,
+		StatusText:   td.StatusText,
+		ErrorMessage: td.ErrorMessage,
+		Timestamp:    td.Timestamp,
+	}
+}
@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+func TestDigestCutoff(t *testing.T) {
+	now := time.Date(2026, 2, 16, 9, 30, 0, 0, time.Local)
+	batch := &po.NotificationChannel{DeliveryMode: po.DeliveryModeBatch, BatchIntervalMinutes: 15}
+	digest := &po.NotificationChannel{DeliveryMode: po.DeliveryModeDigest, DigestTime: "09:00"}
+
+	if _, due := digestCutoff(batch, now.Add(-10*time.Minute), now); due {
+		t.Error("batch: should wait for the interval")
+	}
+	if cutoff, due := digestCutoff(batch, now.Add(-15*time.Minute), now); !due || !cutoff.Equal(now) {
+		t.Errorf("batch: got %v %v", cutoff, due)
+	}
+
+	today := time.Date(2026, 2, 16, 9, 0, 0, 0, time.Local)
+	if cutoff, due := digestCutoff(digest, now.Add(-8*time.Hour), now); !due || !cutoff.Equal(today) {
+		t.Errorf("digest: got %v %v, want %v", cutoff, due, today)
+	}
+	if _, due := digestCutoff(digest, now.Add(-10*time.Minute), now); due {
+		t.Error("digest: events after today's send time wait until tomorrow")
+	}
+	early := time.Date(2026, 2, 16, 8, 0, 0, 0, time.Local)
+	if cutoff, due := digestCutoff(digest, early.Add(-24*time.Hour), early); !due || !cutoff.Equal(today.AddDate(0, 0, -1)) {
+		t.Errorf("digest before send time: got %v %v", cutoff, due)
+	}
+	if _, due := digestCutoff(digest, early.Add(-2*time.Hour), early); due {
+		t.Error("digest: events queued this morning go out at today's send time")
+	}
+}
+
+func TestSendsImmediately(t *testing.T) {
+	success := &NotificationMessage{Status: "success", TriggerEvent: po.TriggerSyncSuccess}
+	failure := &NotificationMessage{Status: "failure", TriggerEvent: po.TriggerSyncFailure}
+	conflict := &NotificationMessage{Status: "failure", TriggerEvent: po.TriggerSyncConflict}
+
+	ch := &po.NotificationChannel{DeliveryMode: po.DeliveryModeDigest}
+	if sendsImmediately(ch, success) || !sendsImmediately(ch, failure) || !sendsImmediately(ch, conflict) {
+		t.Error("default: only failures and conflicts bypass the queue")
+	}
+	ch.ImmediateEvents = `["sync_conflict"]`
+	if sendsImmediately(ch, failure) || !sendsImmediately(ch, conflict) {
+		t.Error("explicit list should be honoured")
+	}
+	ch.ImmediateEvents = `[]`
+	if sendsImmediately(ch, conflict) {
+		t.Error("empty list queues everything")
+	}
+	if !sendsImmediately(&po.NotificationChannel{}, success) {
+		t.Error("immediate mode never queues")
+	}
+}
+
+func TestBuildDigestData(t *testing.T) {
+	item := func(event, status, task, errMsg string) po.NotificationDigestItem {
+		data, _ := json.Marshal(&TemplateData{TaskKey: task, EventType: event, Status: status, ErrorMessage: errMsg})
+		return po.NotificationDigestItem{TriggerEvent: event, Status: status, TaskKey: task, DataJSON: string(data)}
+	}
+	items := []po.NotificationDigestItem{
+		item(po.TriggerSyncSuccess, "success", "a", ""),
+		item(po.TriggerSyncSuccess, "success", "b", ""),
+		item(po.TriggerSyncFailure, "failure", "c", "push rejected"),
+		item(po.TriggerSyncConflict, "failure", "d", "diverged"),
+	}
+	ch := &po.NotificationChannel{Name: "ops", DeliveryMode: po.DeliveryModeDigest}
+	data := buildDigestData(ch, items, time.Now())
+
+	if data.Total != 4 || data.SuccessCount != 2 || data.FailureCount != 1 || data.ConflictCount != 1 {
+		t.Fatalf("unexpected counts: %+v", data)
+	}
+	if len(data.Failures) != 2 || data.EventCounts[0].EventType != po.TriggerSyncSuccess || data.EventCounts[0].Count != 2 {
+		t.Fatalf("unexpected breakdown: %+v %+v", data.Failures, data.EventCounts)
+	}
+
+	title, content := RenderDigest("", "", data)
+	if !strings.Contains(title, "每日摘要") || !strings.Contains(content, "| c |") || !strings.Contains(content, "push rejected") {
+		t.Fatalf("unexpected digest:\n%s\n%s", title, content)
+	}
+}
@@ -31,6 +31,7 @@ func NewDingTalkSender(config *po.DingTalkConfig) *DingTalkSender {
 type DingTalkMessage struct {
 	MsgType  string           `json:"msgtype"`
 	Markdown DingTalkMarkdown `json:"markdown"`
+	At       *DingTalkAt      `json:"at,omitempty"`
 }
 
 // DingTalkMarkdown 钉钉Markdown消息
@@ -39,6 +40,13 @@ type DingTalkMarkdown struct {
 	Text  string `json:"text"`
 }
 
+// DingTalkAt 钉钉 @ 提及，被提及的手机号或 userId 需同时出现在正文中
+type DingTalkAt struct {
+	AtMobiles []string `json:"atMobiles,omitempty"`
+	AtUserIds []string `json:"atUserIds,omitempty"`
+	IsAtAll   bool     `json:"isAtAll"`
+}
+
 // Send 发送钉钉消息
 func (s *DingTalkSender) Send(msg *NotificationMessage) error {
 	webhookURL := s.config.WebhookURL
@@ -56,6 +64,36 @@ func (s *DingTalkSender) Send(msg *NotificationMessage) error {
 		webhookURL = fmt.Sprintf("%s&timestamp=%d&sign=%s", webhookURL, timestamp, url.QueryEscape(sign))
 	}
 
+	dingMsg, err := s.Payload(msg)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(dingMsg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("dingtalk api error: %s", string(respBody))
+	}
+
+	return nil
+}
+
+// Payload 构建钉钉 Markdown 消息体
+func (s *DingTalkSender) Payload(msg *NotificationMessage) (interface{}, error) {
+	securityType := s.config.SecurityType
+	if securityType == "" && s.config.Secret != "" {
+		securityType = "sign"
+	}
+
 	// 构建消息
 	statusEmoji := "✅"
 	if msg.Status == "failure" {
@@ -76,25 +114,25 @@ func (s *DingTalkSender) Send(msg *NotificationMessage) error {
 			Title: msg.Title,
 			Text:  text,
 		},
+		At: dingTalkAt(msg.Mentions),
 	}
+	return dingMsg, nil
+}
 
-	body, err := json.Marshal(dingMsg)
-	if err != nil {
-		return err
-	}
-
-	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		return err
+// dingTalkAt 将提及账号转换为钉钉 at 字段，优先使用手机号
+func dingTalkAt(mentions []Mention) *DingTalkAt {
+	if len(mentions) == 0 {
+		return nil
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("dingtalk api error: %s", string(respBody))
+	at := &DingTalkAt{}
+	for _, m := range mentions {
+		if m.Mobile != "" {
+			at.AtMobiles = append(at.AtMobiles, m.Mobile)
+		} else if m.UserID != "" {
+			at.AtUserIds = append(at.AtUserIds, m.UserID)
+		}
 	}
-
-	return nil
+	return at
 }
 
 // sign 生成签名
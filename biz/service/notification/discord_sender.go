@@ -0,0 +1,79 @@
+// biz/service/notification/discord_sender.go - Discord Webhook 发送器
+
+package notification
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+// DiscordSender Discord 发送器
+type DiscordSender struct {
+	config *po.DiscordConfig
+}
+
+// NewDiscordSender 创建 Discord 发送器
+func NewDiscordSender(config *po.DiscordConfig) *DiscordSender {
+	return &DiscordSender{config: config}
+}
+
+// DiscordMessage Discord webhook 消息
+type DiscordMessage struct {
+	Username  string         `json:"username,omitempty"`
+	AvatarURL string         `json:"avatar_url,omitempty"`
+	Embeds    []DiscordEmbed `json:"embeds"`
+}
+
+// DiscordEmbed Discord 嵌入卡片
+type DiscordEmbed struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []DiscordEmbedField `json:"fields,omitempty"`
+	Timestamp   string              `json:"timestamp"`
+}
+
+// DiscordEmbedField Discord 卡片字段
+type DiscordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// Send 发送 Discord 消息
+func (s *DiscordSender) Send(msg *NotificationMessage) error {
+	discordMsg, _ := s.Payload(msg)
+	_, err := doJSON("discord", http.MethodPost, s.config.WebhookURL, discordMsg, nil)
+	return err
+}
+
+// Payload 构建 Discord embed 消息体
+func (s *DiscordSender) Payload(msg *NotificationMessage) (interface{}, error) {
+	embed := DiscordEmbed{
+		Title:       truncateRunes(fmt.Sprintf("%s %s", statusEmoji(msg.Status), msg.Title), 256),
+		Description: truncateRunes(msg.Content, 4096),
+		Color:       statusColor(msg.Status),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, f := range richFields(msg) {
+		if len(embed.Fields) == 25 {
+			break
+		}
+		embed.Fields = append(embed.Fields, DiscordEmbedField{
+			Name:  f.Name,
+			Value: truncateRunes(f.Value, 1024),
+			// 错误信息较长，单独占一行
+			Inline: f.Name != "Error",
+		})
+	}
+
+	discordMsg := DiscordMessage{
+		Username:  s.config.Username,
+		AvatarURL: s.config.AvatarURL,
+		Embeds:    []DiscordEmbed{embed},
+	}
+	return discordMsg, nil
+}
@@ -22,6 +22,25 @@ func NewEmailSender(config *po.EmailConfig) *EmailSender {
 
 // Send 发送邮件
 func (s *EmailSender) Send(msg *NotificationMessage) error {
+	message := s.message(msg)
+
+	// 认证
+	auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.SMTPHost)
+
+	// 发送
+	addr := fmt.Sprintf("%s:%d", s.config.SMTPHost, s.config.SMTPPort)
+	err := smtp.SendMail(addr, auth, s.config.FromAddress, s.config.ToAddresses, []byte(message))
+
+	return err
+}
+
+// Payload 构建邮件原文（含邮件头）
+func (s *EmailSender) Payload(msg *NotificationMessage) (interface{}, error) {
+	return s.message(msg), nil
+}
+
+// message 构建纯文本邮件
+func (s *EmailSender) message(msg *NotificationMessage) string {
 	from := s.config.FromAddress
 	if s.config.FromName != "" {
 		from = fmt.Sprintf("%s <%s>", s.config.FromName, s.config.FromAddress)
@@ -38,13 +57,5 @@ func (s *EmailSender) Send(msg *NotificationMessage) error {
 	message += "Content-Type: text/plain; charset=\"UTF-8\"\r\n"
 	message += "\r\n"
 	message += body
-
-	// 认证
-	auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.SMTPHost)
-
-	// 发送
-	addr := fmt.Sprintf("%s:%d", s.config.SMTPHost, s.config.SMTPPort)
-	err := smtp.SendMail(addr, auth, s.config.FromAddress, s.config.ToAddresses, []byte(message))
-
-	return err
+	return message
 }
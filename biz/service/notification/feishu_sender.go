@@ -48,12 +48,52 @@ type FeishuPostLang struct {
 
 // FeishuPostEntry 飞书富文本条目
 type FeishuPostEntry struct {
-	Tag  string `json:"tag"`
-	Text string `json:"text,omitempty"`
+	Tag    string `json:"tag"`
+	Text   string `json:"text,omitempty"`
+	UserID string `json:"user_id,omitempty"` // tag 为 at 时的被提及用户
 }
 
 // Send 发送飞书消息
 func (s *FeishuSender) Send(msg *NotificationMessage) error {
+	feishuMsg, err := s.message(msg)
+	if err != nil {
+		return err
+	}
+
+	// 签名模式：将 timestamp 和 sign 放入 body
+	if s.config.SecurityType == "sign" && s.config.Secret != "" {
+		timestamp := time.Now().Unix()
+		sign := s.sign(timestamp, s.config.Secret)
+		feishuMsg.Timestamp = strconv.FormatInt(timestamp, 10)
+		feishuMsg.Sign = sign
+	}
+
+	body, err := json.Marshal(feishuMsg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(s.config.WebhookURL, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("feishu api error: %s", string(respBody))
+	}
+
+	return nil
+}
+
+// Payload 构建飞书 post 消息体（签名字段在发送时生成）
+func (s *FeishuSender) Payload(msg *NotificationMessage) (interface{}, error) {
+	return s.message(msg)
+}
+
+// message 构建飞书 post 富文本消息
+func (s *FeishuSender) message(msg *NotificationMessage) (*FeishuMessage, error) {
 	// 确定安全模式
 	securityType := s.config.SecurityType
 
@@ -73,53 +113,43 @@ func (s *FeishuSender) Send(msg *NotificationMessage) error {
 	}
 
 	// 构建飞书 post 富文本
+	lines := [][]FeishuPostEntry{
+		{{Tag: "text", Text: contentText}},
+		{{Tag: "text", Text: detailText}},
+	}
+	if atLine := feishuAtLine(msg.Mentions); atLine != nil {
+		lines = append(lines, atLine)
+	}
 	postContent := FeishuPostContent{
 		Post: map[string]FeishuPostLang{
 			"zh_cn": {
-				Title: titleText,
-				Content: [][]FeishuPostEntry{
-					{{Tag: "text", Text: contentText}},
-					{{Tag: "text", Text: detailText}},
-				},
+				Title:   titleText,
+				Content: lines,
 			},
 		},
 	}
 
 	contentBytes, err := json.Marshal(postContent)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	feishuMsg := FeishuMessage{
+	feishuMsg := &FeishuMessage{
 		MsgType: "post",
 		Content: contentBytes,
 	}
+	return feishuMsg, nil
+}
 
-	// 签名模式：将 timestamp 和 sign 放入 body
-	if securityType == "sign" && s.config.Secret != "" {
-		timestamp := time.Now().Unix()
-		sign := s.sign(timestamp, s.config.Secret)
-		feishuMsg.Timestamp = strconv.FormatInt(timestamp, 10)
-		feishuMsg.Sign = sign
-	}
-
-	body, err := json.Marshal(feishuMsg)
-	if err != nil {
-		return err
-	}
-
-	resp, err := http.Post(s.config.WebhookURL, "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("feishu api error: %s", string(respBody))
+// feishuAtLine 将提及账号转换为一行 <at> 条目
+func feishuAtLine(mentions []Mention) []FeishuPostEntry {
+	var line []FeishuPostEntry
+	for _, m := range mentions {
+		if m.UserID != "" {
+			line = append(line, FeishuPostEntry{Tag: "at", UserID: m.UserID})
+		}
 	}
-
-	return nil
+	return line
 }
 
 // sign 生成飞书签名（timestamp + "\n" + secret -> HMAC-SHA256 -> Base64）
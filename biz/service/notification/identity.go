@@ -0,0 +1,75 @@
+// biz/service/notification/identity.go - Git 身份映射管理
+
+package notification
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+// ListIdentities 分页查询身份映射
+func (s *NotificationService) ListIdentities(req *api.ListIdentityMappingsReq) ([]po.IdentityMapping, int64, error) {
+	return s.identityDAO.List(strings.TrimSpace(req.Keyword), req.Page, req.PageSize)
+}
+
+// CreateIdentity 新增身份映射，邮箱统一转为小写
+func (s *NotificationService) CreateIdentity(req *api.IdentityMappingReq) (*po.IdentityMapping, error) {
+	m := &po.IdentityMapping{}
+	if err := applyIdentity(m, req); err != nil {
+		return nil, err
+	}
+	if existing, _ := s.identityDAO.FindByEmails([]string{m.GitEmail}); len(existing) > 0 {
+		return nil, fmt.Errorf("identity mapping for %s already exists", m.GitEmail)
+	}
+	if err := s.identityDAO.Create(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UpdateIdentity 更新身份映射
+func (s *NotificationService) UpdateIdentity(req *api.IdentityMappingReq) (*po.IdentityMapping, error) {
+	m, err := s.identityDAO.FindByID(req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("identity mapping not found")
+	}
+	if err := applyIdentity(m, req); err != nil {
+		return nil, err
+	}
+	if existing, _ := s.identityDAO.FindByEmails([]string{m.GitEmail}); len(existing) > 0 && existing[0].ID != m.ID {
+		return nil, fmt.Errorf("identity mapping for %s already exists", m.GitEmail)
+	}
+	if err := s.identityDAO.Save(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DeleteIdentity 删除身份映射
+func (s *NotificationService) DeleteIdentity(id uint) error {
+	if _, err := s.identityDAO.FindByID(id); err != nil {
+		return fmt.Errorf("identity mapping not found")
+	}
+	return s.identityDAO.Delete(id)
+}
+
+// applyIdentity 校验请求并写入映射，至少需要一个可提及的账号
+func applyIdentity(m *po.IdentityMapping, req *api.IdentityMappingReq) error {
+	email := strings.ToLower(strings.TrimSpace(req.GitEmail))
+	if email == "" || !strings.Contains(email, "@") {
+		return fmt.Errorf("git_email is invalid")
+	}
+	m.GitEmail = email
+	m.DisplayName = strings.TrimSpace(req.DisplayName)
+	m.Mobile = strings.TrimSpace(req.Mobile)
+	m.DingTalkUserID = strings.TrimSpace(req.DingTalkUserID)
+	m.FeishuUserID = strings.TrimSpace(req.FeishuUserID)
+	m.WeComUserID = strings.TrimSpace(req.WeComUserID)
+	if m.Mobile == "" && m.DingTalkUserID == "" && m.FeishuUserID == "" && m.WeComUserID == "" {
+		return fmt.Errorf("at least one of mobile, dingtalk_user_id, feishu_user_id, wecom_user_id is required")
+	}
+	return nil
+}
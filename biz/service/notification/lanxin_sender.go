@@ -60,8 +60,33 @@ type lanxinAppCardData struct {
 
 // Send 发送蓝信消息
 func (s *LanxinSender) Send(msg *NotificationMessage) error {
-	webhookURL := s.config.WebhookURL
+	req := s.message(msg)
 
+	// 加签模式：把 timestamp 和 sign 放在请求体中
+	if s.securityType() == "sign" && s.config.Sign != "" {
+		ts := fmt.Sprintf("%d", time.Now().Unix())
+		req.Timestamp = ts
+		req.Sign = s.genSign(ts, s.config.Sign)
+	}
+
+	return s.doSend(s.config.WebhookURL, req)
+}
+
+// Payload 构建蓝信 text 消息体（签名字段在发送时生成）
+func (s *LanxinSender) Payload(msg *NotificationMessage) (interface{}, error) {
+	return s.message(msg), nil
+}
+
+// securityType 确定安全模式（向后兼容：SecurityType为空但Sign非空时按sign处理）
+func (s *LanxinSender) securityType() string {
+	if s.config.SecurityType == "" && s.config.Sign != "" {
+		return "sign"
+	}
+	return s.config.SecurityType
+}
+
+// message 构建 text 类型请求体
+func (s *LanxinSender) message(msg *NotificationMessage) *lanxinRequest {
 	// 构建消息内容
 	statusEmoji := "✅"
 	if msg.Status == "failure" {
@@ -72,11 +97,7 @@ func (s *LanxinSender) Send(msg *NotificationMessage) error {
 		statusEmoji, msg.Title, msg.Content, msg.TaskKey, msg.RepoKey)
 
 	// 关键字模式：在消息中添加关键字
-	securityType := s.config.SecurityType
-	if securityType == "" && s.config.Sign != "" {
-		securityType = "sign"
-	}
-	if securityType == "keyword" && s.config.Keywords != "" {
+	if s.securityType() == "keyword" && s.config.Keywords != "" {
 		content = s.config.Keywords + "\n" + content
 	}
 
@@ -84,19 +105,10 @@ func (s *LanxinSender) Send(msg *NotificationMessage) error {
 	textData := lanxinTextData{}
 	textData.Text.Content = content
 
-	req := &lanxinRequest{
+	return &lanxinRequest{
 		MsgType: "text",
 		MsgData: textData,
 	}
-
-	// 加签模式：把 timestamp 和 sign 放在请求体中
-	if securityType == "sign" && s.config.Sign != "" {
-		ts := fmt.Sprintf("%d", time.Now().Unix())
-		req.Timestamp = ts
-		req.Sign = s.genSign(ts, s.config.Sign)
-	}
-
-	return s.doSend(webhookURL, req)
 }
 
 // doSend 执行实际的 HTTP 请求
@@ -149,17 +161,12 @@ func (s *LanxinSender) sendAppCard(webhookURL string, title, content, signature
 	cardData.AppCard.BodyContent = strings.ReplaceAll(content, "\n", "<br/>")
 	cardData.AppCard.Signature = signature
 
-	securityType := s.config.SecurityType
-	if securityType == "" && s.config.Sign != "" {
-		securityType = "sign"
-	}
-
 	req := &lanxinRequest{
 		MsgType: "appCard",
 		MsgData: cardData,
 	}
 
-	if securityType == "sign" && s.config.Sign != "" {
+	if s.securityType() == "sign" && s.config.Sign != "" {
 		ts := fmt.Sprintf("%d", time.Now().Unix())
 		req.Timestamp = ts
 		req.Sign = s.genSign(ts, s.config.Sign)
@@ -0,0 +1,86 @@
+// biz/service/notification/matrix_sender.go - Matrix 发送器
+
+package notification
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+// matrixTxnSeq 事务 ID 序号，与时间戳组合保证同一进程内唯一
+var matrixTxnSeq uint64
+
+// MatrixSender Matrix 发送器
+type MatrixSender struct {
+	config *po.MatrixConfig
+}
+
+// NewMatrixSender 创建 Matrix 发送器
+func NewMatrixSender(config *po.MatrixConfig) *MatrixSender {
+	return &MatrixSender{config: config}
+}
+
+// MatrixMessage m.room.message 事件内容
+type MatrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+// Send 发送 Matrix 房间消息
+func (s *MatrixSender) Send(msg *NotificationMessage) error {
+	txnID := fmt.Sprintf("gms-%d-%d", time.Now().UnixNano(), atomic.AddUint64(&matrixTxnSeq, 1))
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimRight(s.config.HomeserverURL, "/"), url.PathEscape(s.config.RoomID), txnID)
+
+	matrixMsg, _ := s.Payload(msg)
+	_, err := doJSON("matrix", http.MethodPut, endpoint, matrixMsg, map[string]string{
+		"Authorization": "Bearer " + s.config.AccessToken,
+	})
+	return err
+}
+
+// Payload 构建 m.room.message 事件内容
+func (s *MatrixSender) Payload(msg *NotificationMessage) (interface{}, error) {
+	msgType := s.config.MsgType
+	if msgType == "" {
+		msgType = "m.text"
+	}
+	plain, formatted := s.render(msg)
+	return MatrixMessage{
+		MsgType:       msgType,
+		Body:          plain,
+		Format:        "org.matrix.custom.html",
+		FormattedBody: formatted,
+	}, nil
+}
+
+// render 同时生成纯文本与 HTML 两种正文
+func (s *MatrixSender) render(msg *NotificationMessage) (string, string) {
+	title := fmt.Sprintf("%s %s", statusEmoji(msg.Status), msg.Title)
+	var plain, formatted strings.Builder
+	plain.WriteString(title)
+	fmt.Fprintf(&formatted, "<h4>%s</h4>", html.EscapeString(title))
+	if msg.Content != "" {
+		fmt.Fprintf(&plain, "\n\n%s", msg.Content)
+		fmt.Fprintf(&formatted, "<p>%s</p>", strings.ReplaceAll(html.EscapeString(msg.Content), "\n", "<br/>"))
+	}
+	if fields := richFields(msg); len(fields) > 0 {
+		plain.WriteString("\n")
+		formatted.WriteString("<ul>")
+		for _, f := range fields {
+			fmt.Fprintf(&plain, "\n%s: %s", f.Name, f.Value)
+			fmt.Fprintf(&formatted, "<li><strong>%s:</strong> %s</li>", f.Name, html.EscapeString(f.Value))
+		}
+		formatted.WriteString("</ul>")
+	}
+	return plain.String(), formatted.String()
+}
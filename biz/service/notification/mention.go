@@ -0,0 +1,149 @@
+// biz/service/notification/mention.go - 模板 @ 提及渲染
+
+package notification
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+// Mention 一次 @ 提及，由发送器转换为平台原生的提及字段
+type Mention struct {
+	Email  string `json:"email"`
+	Name   string `json:"name,omitempty"`
+	Mobile string `json:"mobile,omitempty"`
+	UserID string `json:"user_id,omitempty"` // 当前渠道平台的用户ID
+}
+
+// plainMention 未绑定渠道时的 mention 实现：仅输出 @邮箱
+func plainMention(v interface{}) string {
+	emails := mentionEmails(v)
+	parts := make([]string, 0, len(emails))
+	for _, e := range emails {
+		parts = append(parts, "@"+e)
+	}
+	return strings.Join(parts, " ")
+}
+
+// mentionEmails 将模板参数（单个邮箱或邮箱列表）统一为去重后的小写邮箱列表
+func mentionEmails(v interface{}) []string {
+	var raw []string
+	switch val := v.(type) {
+	case string:
+		raw = []string{val}
+	case []string:
+		raw = val
+	case nil:
+	default:
+		raw = []string{fmt.Sprint(val)}
+	}
+	seen := make(map[string]bool, len(raw))
+	emails := make([]string, 0, len(raw))
+	for _, e := range raw {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "" || seen[e] {
+			continue
+		}
+		seen[e] = true
+		emails = append(emails, e)
+	}
+	return emails
+}
+
+// mentionRenderer 单次渲染的 mention 实现：按渠道类型查询身份映射，输出内联文本并收集提及账号
+type mentionRenderer struct {
+	channelType string
+	lookup      func(emails []string) []po.IdentityMapping
+	mentions    []Mention
+	seen        map[string]bool
+}
+
+func newMentionRenderer(channelType string) *mentionRenderer {
+	return &mentionRenderer{
+		channelType: channelType,
+		lookup:      lookupIdentities,
+		seen:        make(map[string]bool),
+	}
+}
+
+// lookupIdentities 从数据库批量查询身份映射
+func lookupIdentities(emails []string) []po.IdentityMapping {
+	if db.DB == nil {
+		return nil
+	}
+	mappings, err := db.NewIdentityMappingDAO().FindByEmails(emails)
+	if err != nil {
+		log.Printf("[Notification] Failed to load identity mappings: %v", err)
+		return nil
+	}
+	return mappings
+}
+
+func (r *mentionRenderer) funcs() template.FuncMap {
+	return template.FuncMap{"mention": r.mention}
+}
+
+// mention 模板函数：{{mention .Authors}} 或 {{mention "a@example.com"}}
+func (r *mentionRenderer) mention(v interface{}) string {
+	emails := mentionEmails(v)
+	if len(emails) == 0 {
+		return ""
+	}
+	byEmail := make(map[string]po.IdentityMapping)
+	for _, m := range r.lookup(emails) {
+		byEmail[strings.ToLower(m.GitEmail)] = m
+	}
+
+	parts := make([]string, 0, len(emails))
+	for _, email := range emails {
+		m, ok := byEmail[email]
+		if !ok {
+			parts = append(parts, "@"+email)
+			continue
+		}
+		mention := r.platformMention(email, m)
+		parts = append(parts, mentionText(r.channelType, mention))
+		if (mention.Mobile != "" || mention.UserID != "") && !r.seen[email] {
+			r.seen[email] = true
+			r.mentions = append(r.mentions, mention)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// platformMention 取出映射中当前渠道平台可用的账号
+func (r *mentionRenderer) platformMention(email string, m po.IdentityMapping) Mention {
+	mention := Mention{Email: email, Name: m.DisplayName}
+	if mention.Name == "" {
+		mention.Name = email
+	}
+	switch r.channelType {
+	case "dingtalk":
+		mention.Mobile = m.Mobile
+		mention.UserID = m.DingTalkUserID
+	case "feishu":
+		mention.UserID = m.FeishuUserID
+	case "wechat":
+		mention.Mobile = m.Mobile
+		mention.UserID = m.WeComUserID
+	}
+	return mention
+}
+
+// mentionText 正文中的内联提及文本；钉钉要求正文包含 @手机号 或 @userId 才会高亮
+func mentionText(channelType string, m Mention) string {
+	if channelType == "dingtalk" {
+		if m.Mobile != "" {
+			return "@" + m.Mobile
+		}
+		if m.UserID != "" {
+			return "@" + m.UserID
+		}
+	}
+	return "@" + m.Name
+}
@@ -0,0 +1,93 @@
+package notification
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+func stubMentionRenderer(channelType string) *mentionRenderer {
+	r := newMentionRenderer(channelType)
+	r.lookup = func(emails []string) []po.IdentityMapping {
+		return []po.IdentityMapping{{
+			GitEmail:       "alice@example.com",
+			DisplayName:    "Alice",
+			Mobile:         "13800000000",
+			DingTalkUserID: "ding-alice",
+			FeishuUserID:   "ou_alice",
+			WeComUserID:    "alice",
+		}}
+	}
+	return r
+}
+
+func TestMentionRendering(t *testing.T) {
+	data := &TemplateData{EventType: po.TriggerSyncConflict, Authors: []string{"Alice@Example.com", "bob@example.com"}}
+
+	cases := map[string]string{
+		"dingtalk": "@13800000000 @bob@example.com",
+		"feishu":   "@Alice @bob@example.com",
+		"wechat":   "@Alice @bob@example.com",
+	}
+	for channelType, want := range cases {
+		r := stubMentionRenderer(channelType)
+		_, content := renderTitleAndContent("", "", data, r.funcs())
+		if !strings.Contains(content, "相关提交作者: "+want) {
+			t.Errorf("%s: content = %q, want mention %q", channelType, content, want)
+		}
+		if len(r.mentions) != 1 || r.mentions[0].Email != "alice@example.com" {
+			t.Errorf("%s: mentions = %+v, want only the mapped author", channelType, r.mentions)
+		}
+	}
+
+	if got := plainMention([]string{"a@x.io", "A@x.io"}); got != "@a@x.io" {
+		t.Errorf("plainMention = %q", got)
+	}
+}
+
+func TestSendersNativeMentions(t *testing.T) {
+	msg := sampleMessage()
+	msg.Mentions = []Mention{{Email: "alice@example.com", Name: "Alice", Mobile: "13800000000", UserID: "alice"}}
+
+	t.Run("dingtalk", func(t *testing.T) {
+		srv, got := standIn(t, http.StatusOK, `{"errcode":0}`)
+		if err := NewDingTalkSender(&po.DingTalkConfig{WebhookURL: srv.URL + "/robot/send?access_token=x"}).Send(msg); err != nil {
+			t.Fatal(err)
+		}
+		at, _ := got.body["at"].(map[string]interface{})
+		mobiles, _ := at["atMobiles"].([]interface{})
+		if len(mobiles) != 1 || mobiles[0] != "13800000000" {
+			t.Errorf("at = %v", got.body["at"])
+		}
+	})
+
+	t.Run("feishu", func(t *testing.T) {
+		srv, got := standIn(t, http.StatusOK, `{"code":0}`)
+		feishuMsg := *msg
+		feishuMsg.Mentions = []Mention{{Email: "alice@example.com", UserID: "ou_alice"}}
+		if err := NewFeishuSender(&po.FeishuConfig{WebhookURL: srv.URL}).Send(&feishuMsg); err != nil {
+			t.Fatal(err)
+		}
+		content, _ := got.body["content"].(map[string]interface{})
+		post := content["post"].(map[string]interface{})["zh_cn"].(map[string]interface{})
+		lines := post["content"].([]interface{})
+		last := lines[len(lines)-1].([]interface{})[0].(map[string]interface{})
+		if last["tag"] != "at" || last["user_id"] != "ou_alice" {
+			t.Errorf("last line = %v", last)
+		}
+	})
+
+	t.Run("wechat", func(t *testing.T) {
+		srv, got := standIn(t, http.StatusOK, `{"errcode":0}`)
+		if err := NewWeChatSender(&po.WeChatConfig{WebhookURL: srv.URL}).Send(msg); err != nil {
+			t.Fatal(err)
+		}
+		text, _ := got.body["text"].(map[string]interface{})
+		list, _ := text["mentioned_list"].([]interface{})
+		if got.body["msgtype"] != "text" || len(list) != 1 || list[0] != "alice" {
+			t.Errorf("body = %v", got.body)
+		}
+	})
+}
@@ -0,0 +1,268 @@
+// biz/service/notification/preview.go - 通知模板预览
+
+package notification
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/service/git"
+)
+
+// 预览数据来源
+const (
+	PreviewSourceSample       = "sample"
+	PreviewSourceSyncRun      = "sync_run"
+	PreviewSourceBackupRecord = "backup_record"
+)
+
+// TemplatePreview 模板预览结果，payload 为发送器实际发出的请求体
+type TemplatePreview struct {
+	ChannelType  string        `json:"channel_type"`
+	TriggerEvent string        `json:"trigger_event"`
+	DataSource   string        `json:"data_source"`
+	Title        string        `json:"title"`
+	Content      string        `json:"content"`
+	Mentions     []Mention     `json:"mentions"`
+	Payload      interface{}   `json:"payload"`
+	Data         *TemplateData `json:"data"`
+	Warnings     []string      `json:"warnings"` // 模板渲染错误等，发送时会回退到默认格式
+}
+
+// PreviewTemplate 按渠道模板渲染示例或历史数据，并构建各平台请求体，不发送、不记录投递
+func (s *NotificationService) PreviewTemplate(req *api.TemplatePreviewReq) (*TemplatePreview, error) {
+	channel, err := s.previewChannel(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateTemplate(req.TitleTemplate); err != nil {
+		return nil, err
+	}
+	if err := ValidateTemplate(req.ContentTemplate); err != nil {
+		return nil, err
+	}
+
+	msg, source, err := previewMessage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	titleTmpl, contentTmpl := resolveTemplates(channel, msg.TriggerEvent)
+	if req.TitleTemplate != "" {
+		titleTmpl = req.TitleTemplate
+	}
+	if req.ContentTemplate != "" {
+		contentTmpl = req.ContentTemplate
+	}
+	warnings := templateWarnings(titleTmpl, contentTmpl, msg.Data)
+	rendered := renderWithTemplates(channel, msg, titleTmpl, contentTmpl)
+
+	sender, err := s.createSender(channel)
+	if err != nil {
+		return nil, fmt.Errorf("invalid channel config: %w", err)
+	}
+	payload, err := sender.Payload(rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplatePreview{
+		ChannelType:  channel.Type,
+		TriggerEvent: rendered.TriggerEvent,
+		DataSource:   source,
+		Title:        rendered.Title,
+		Content:      rendered.Content,
+		Mentions:     rendered.Mentions,
+		Payload:      payload,
+		Data:         rendered.Data,
+		Warnings:     warnings,
+	}, nil
+}
+
+// previewChannel 已保存渠道或草稿渠道；草稿的 type/config 覆盖已保存的配置
+func (s *NotificationService) previewChannel(req *api.TemplatePreviewReq) (*po.NotificationChannel, error) {
+	channel := &po.NotificationChannel{}
+	if req.ChannelID != 0 {
+		saved, err := s.dao.FindByID(req.ChannelID)
+		if err != nil {
+			return nil, fmt.Errorf("channel not found")
+		}
+		channel = saved
+	}
+	if req.Type != "" {
+		channel.Type = req.Type
+	}
+	if req.Config != "" {
+		channel.Config = req.Config
+	}
+	if channel.Type == "" {
+		return nil, fmt.Errorf("channel_id or type is required")
+	}
+	if channel.Config == "" {
+		channel.Config = "{}"
+	}
+	return channel, nil
+}
+
+// previewMessage 按数据来源构建待渲染的消息
+func previewMessage(req *api.TemplatePreviewReq) (*NotificationMessage, string, error) {
+	switch {
+	case req.SyncRunID != 0:
+		run, err := db.NewSyncRunDAO().FindByID(req.SyncRunID)
+		if err != nil {
+			return nil, "", fmt.Errorf("sync run not found")
+		}
+		var authors []string
+		if run.Status == "conflict" && run.Task.SourceRepo.Path != "" {
+			authors, _ = git.NewGitService().GetRangeDivergentAuthors(run.Task.SourceRepo.Path, run.CommitRange, ConflictAuthorLimit)
+		}
+		msg := SyncRunMessage(&run.Task, run, authors)
+		if msg == nil {
+			return nil, "", fmt.Errorf("sync run %d is %s and has no notification", run.ID, run.Status)
+		}
+		return msg, PreviewSourceSyncRun, nil
+
+	case req.BackupRecordID != 0:
+		record, err := db.NewBackupDAO().FindByID(req.BackupRecordID)
+		if err != nil {
+			return nil, "", fmt.Errorf("backup record not found")
+		}
+		msg := backupRecordMessage(record)
+		if msg == nil {
+			return nil, "", fmt.Errorf("backup record %d is %s and has no notification", record.ID, record.Status)
+		}
+		return msg, PreviewSourceBackupRecord, nil
+	}
+
+	if req.TriggerEvent == "" {
+		return nil, "", fmt.Errorf("trigger_event, sync_run_id or backup_record_id is required")
+	}
+	if _, ok := eventLabels[req.TriggerEvent]; !ok {
+		return nil, "", fmt.Errorf("unsupported trigger_event: %s", req.TriggerEvent)
+	}
+	return sampleEventMessage(req.TriggerEvent), PreviewSourceSample, nil
+}
+
+// backupRecordMessage 备份记录对应的通知消息；未完成的备份返回 nil
+func backupRecordMessage(record *po.BackupRecord) *NotificationMessage {
+	var triggerEvent, status string
+	switch record.Status {
+	case "success":
+		triggerEvent, status = po.TriggerBackupSuccess, "success"
+	case "failed":
+		triggerEvent, status = po.TriggerBackupFailure, "failure"
+	default:
+		return nil
+	}
+
+	data := &TemplateData{
+		Status:       status,
+		EventType:    triggerEvent,
+		RepoKey:      record.RepoKey,
+		ErrorMessage: record.ErrorMsg,
+		BackupPath:   record.StorageKey,
+	}
+	if repo, err := db.NewRepoDAO().FindByKey(record.RepoKey); err == nil {
+		data.RepoName = repo.Name
+	}
+	if !record.CompletedAt.IsZero() && !record.StartedAt.IsZero() {
+		data.Duration = record.CompletedAt.Sub(record.StartedAt).Round(time.Millisecond).String()
+		data.Timestamp = record.CompletedAt.Format("2006-01-02 15:04:05")
+	}
+	return &NotificationMessage{
+		Status:       status,
+		TriggerEvent: triggerEvent,
+		RepoKey:      record.RepoKey,
+		Data:         data,
+	}
+}
+
+// sampleEventMessage 事件的示例消息，字段取值与模板变量说明中的示例一致
+func sampleEventMessage(triggerEvent string) *NotificationMessage {
+	data := &TemplateData{
+		TaskKey:      "task-abc123",
+		TaskName:     "我的同步任务",
+		Status:       "success",
+		EventType:    triggerEvent,
+		SourceRemote: "origin",
+		SourceBranch: "main",
+		TargetRemote: "backup",
+		TargetBranch: "main",
+		RepoKey:      "repo-abc123",
+		RepoName:     "前端项目仓库",
+		SyncMode:     "single",
+		Timestamp:    time.Now().Format("2006-01-02 15:04:05"),
+	}
+	msg := &NotificationMessage{
+		Status:       "success",
+		TriggerEvent: triggerEvent,
+		TaskKey:      data.TaskKey,
+		RepoKey:      data.RepoKey,
+		Data:         data,
+	}
+
+	switch triggerEvent {
+	case po.TriggerSyncSuccess:
+		data.CommitRange = "abc123..def456"
+		data.Duration = "3.2s"
+	case po.TriggerSyncFailure:
+		data.Status, msg.Status = "failed", "failure"
+		data.ErrorMessage = "push failed: non-fast-forward update rejected"
+		data.Duration = "1.5s"
+		data.ConsecutiveFailures = 1
+		data.FailingSince = data.Timestamp
+	case po.TriggerSyncConflict:
+		data.Status, msg.Status = "conflict", "failure"
+		data.ErrorMessage = "conflict"
+		data.CommitRange = "abc123..def456"
+		data.Authors = []string{"dev@example.com", "ops@example.com"}
+		data.ConsecutiveFailures = 1
+		data.FailingSince = data.Timestamp
+	case po.TriggerWebhookReceived:
+		data.WebhookSource = "github"
+	case po.TriggerWebhookError:
+		data.Status, msg.Status = "failure", "failure"
+		data.WebhookSource = "github"
+		data.ErrorMessage = "invalid signature"
+	case po.TriggerCronTriggered:
+		data.CronExpression = "0 2 * * *"
+	case po.TriggerBackupSuccess:
+		data.BackupPath = "/backups/repo.tar.gz"
+		data.Duration = "12.4s"
+	case po.TriggerBackupFailure:
+		data.Status, msg.Status = "failure", "failure"
+		data.BackupPath = "/backups/repo.tar.gz"
+		data.ErrorMessage = "upload failed: connection reset"
+	case po.TriggerAlertRecovered:
+		msg.RecoveredEvent = po.TriggerSyncFailure
+		data.ConsecutiveFailures = 5
+		data.FailingSince = time.Now().Add(-2 * time.Hour).Format("2006-01-02 15:04:05")
+	}
+	return msg
+}
+
+// templateWarnings 检查模板能否用当前数据渲染；渲染失败时发送会回退到默认格式
+func templateWarnings(titleTmpl, contentTmpl string, data *TemplateData) []string {
+	warnings := []string{}
+	if data == nil {
+		return warnings
+	}
+	probe := *data
+	fillDefaults(&probe)
+	if titleTmpl == "" {
+		titleTmpl = GetDefaultTitleTemplate(probe.EventType)
+	}
+	if contentTmpl == "" {
+		contentTmpl = GetDefaultContentTemplate(probe.EventType)
+	}
+	if _, err := renderTemplate(titleTmpl, &probe); err != nil {
+		warnings = append(warnings, "标题"+err.Error()+"，发送时将使用默认格式")
+	}
+	if _, err := renderTemplate(contentTmpl, &probe); err != nil {
+		warnings = append(warnings, "内容"+err.Error()+"，发送时将使用默认格式")
+	}
+	return warnings
+}
@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+func TestPreviewTemplateDraftChannel(t *testing.T) {
+	s := &NotificationService{}
+	preview, err := s.PreviewTemplate(&api.TemplatePreviewReq{
+		Type:            "dingtalk",
+		Config:          `{"webhook_url":"https://example.com/robot","security_type":"keyword","keywords":"GMS"}`,
+		TriggerEvent:    po.TriggerSyncConflict,
+		ContentTemplate: `冲突: {{.SourceBranch}} -> {{.TargetBranch}}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.DataSource != PreviewSourceSample || preview.Title != "[冲突] 同步任务 我的同步任务" {
+		t.Errorf("preview = %+v", preview)
+	}
+	body, _ := json.Marshal(preview.Payload)
+	var dingMsg DingTalkMessage
+	if err := json.Unmarshal(body, &dingMsg); err != nil {
+		t.Fatal(err)
+	}
+	if dingMsg.MsgType != "markdown" || !strings.HasPrefix(dingMsg.Markdown.Text, "GMS\n") ||
+		!strings.Contains(dingMsg.Markdown.Text, "冲突: main -> main") {
+		t.Errorf("payload = %s", body)
+	}
+	if len(preview.Warnings) != 0 {
+		t.Errorf("warnings = %v", preview.Warnings)
+	}
+}
+
+func TestPreviewTemplateErrors(t *testing.T) {
+	s := &NotificationService{}
+	cases := map[string]*api.TemplatePreviewReq{
+		"missing channel": {TriggerEvent: po.TriggerSyncSuccess},
+		"missing event":   {Type: "slack"},
+		"unknown event":   {Type: "slack", TriggerEvent: "nope"},
+		"bad syntax":      {Type: "slack", TriggerEvent: po.TriggerSyncSuccess, TitleTemplate: "{{.TaskName"},
+	}
+	for name, req := range cases {
+		if _, err := s.PreviewTemplate(req); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	preview, err := s.PreviewTemplate(&api.TemplatePreviewReq{
+		Type: "slack", TriggerEvent: po.TriggerSyncSuccess, ContentTemplate: "{{.Missing}}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Warnings) != 1 {
+		t.Errorf("warnings = %v", preview.Warnings)
+	}
+}
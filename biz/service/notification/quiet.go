@@ -0,0 +1,179 @@
+// biz/service/notification/quiet.go - 渠道静默时段
+
+package notification
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+var severityRank = map[string]int{
+	po.SeverityInfo:     1,
+	po.SeverityWarning:  2,
+	po.SeverityCritical: 3,
+	po.SeverityNone:     4,
+}
+
+// messageSeverity 消息级别：失败与升级告警为 critical，冲突为 warning，其余为 info
+func messageSeverity(msg *NotificationMessage, escalated bool) string {
+	switch {
+	case escalated:
+		return po.SeverityCritical
+	case msg.TriggerEvent == po.TriggerSyncConflict:
+		return po.SeverityWarning
+	case msg.Status == "failure":
+		return po.SeverityCritical
+	}
+	return po.SeverityInfo
+}
+
+// deliverySeverity 投递记录的消息级别，未记录级别的旧记录按触发事件与消息状态推断
+func deliverySeverity(d *po.NotificationDelivery) string {
+	if d.Severity != "" {
+		return d.Severity
+	}
+	return messageSeverity(&NotificationMessage{TriggerEvent: d.TriggerEvent, Status: d.MessageStatus}, false)
+}
+
+// ValidateQuietHours 校验静默时段配置
+func ValidateQuietHours(cfg *po.QuietHoursConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+			return fmt.Errorf("quiet_hours: unknown timezone %q", cfg.Timezone)
+		}
+	}
+	switch cfg.Breakthrough {
+	case "", po.SeverityWarning, po.SeverityCritical, po.SeverityNone:
+	default:
+		return fmt.Errorf("quiet_hours: breakthrough must be warning, critical or none")
+	}
+	for i, w := range cfg.Windows {
+		if _, err := time.Parse("15:04", w.Start); err != nil {
+			return fmt.Errorf("quiet_hours window %d: start must be HH:MM", i+1)
+		}
+		if _, err := time.Parse("15:04", w.End); err != nil {
+			return fmt.Errorf("quiet_hours window %d: end must be HH:MM", i+1)
+		}
+		for _, d := range w.Days {
+			if _, ok := weekdayNames[strings.ToLower(d)]; !ok {
+				return fmt.Errorf("quiet_hours window %d: unknown day %q", i+1, d)
+			}
+		}
+	}
+	return nil
+}
+
+// quietUntil 判断 now 是否处于静默时段，返回时段结束时间；相邻或重叠的时段连续计算
+func quietUntil(cfg *po.QuietHoursConfig, now time.Time) (time.Time, bool) {
+	if cfg == nil || len(cfg.Windows) == 0 {
+		return time.Time{}, false
+	}
+	loc := time.Local
+	if cfg.Timezone != "" {
+		if l, err := time.LoadLocation(cfg.Timezone); err == nil {
+			loc = l
+		}
+	}
+	t := now.In(loc)
+	until, quiet := time.Time{}, false
+	// 最多跨越一周的连续时段，防止配置成全天静默时死循环
+	for i := 0; i < 8*len(cfg.Windows); i++ {
+		end, ok := windowEnd(cfg.Windows, t)
+		if !ok {
+			break
+		}
+		until, quiet = end, true
+		t = end
+	}
+	return until, quiet
+}
+
+// windowEnd 返回包含 t 的时段中最晚的结束时间
+func windowEnd(windows []po.QuietWindow, t time.Time) (time.Time, bool) {
+	var latest time.Time
+	found := false
+	for _, w := range windows {
+		start, err1 := time.Parse("15:04", w.Start)
+		end, err2 := time.Parse("15:04", w.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		// 跨天时段可能从前一天开始
+		for _, offset := range []int{-1, 0} {
+			day := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, t.Location())
+			if !windowOnDay(w.Days, day.Weekday()) {
+				continue
+			}
+			from := day.Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute)
+			to := day.Add(time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute)
+			if !to.After(from) {
+				to = to.AddDate(0, 0, 1)
+			}
+			if !t.Before(from) && t.Before(to) && to.After(latest) {
+				latest, found = to, true
+			}
+		}
+	}
+	return latest, found
+}
+
+func windowOnDay(days []string, weekday time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		if weekdayNames[strings.ToLower(d)] == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// deferIfQuiet 渠道处于静默时段且消息级别不足以穿透时，将投递标记为延后到时段结束
+func deferIfQuiet(ch *po.NotificationChannel, d *po.NotificationDelivery, severity string, now time.Time) bool {
+	cfg := ch.QuietHours
+	if cfg == nil {
+		return false
+	}
+	breakthrough := cfg.Breakthrough
+	if breakthrough == "" {
+		breakthrough = po.SeverityWarning
+	}
+	if severityRank[severity] >= severityRank[breakthrough] {
+		return false
+	}
+	until, quiet := quietUntil(cfg, now)
+	if !quiet {
+		return false
+	}
+	d.Status = po.DeliveryStatusDeferred
+	d.NextRetryAt = &until
+	return true
+}
+
+// queueDelivery 写入投递记录并发送；静默时段内的投递只写入记录，由 retryLoop 在时段结束后发送
+func (s *NotificationService) queueDelivery(ch *po.NotificationChannel, d *po.NotificationDelivery, severity string) error {
+	d.Severity = severity
+	deferred := deferIfQuiet(ch, d, severity, time.Now())
+	if err := s.deliveryDAO.Create(d); err != nil {
+		return err
+	}
+	if deferred {
+		log.Printf("[Notification] Deferred %s to channel %s until %s (quiet hours)", d.TriggerEvent, ch.Name, d.NextRetryAt.Format(time.RFC3339))
+		return nil
+	}
+	go s.deliver(d, false)
+	return nil
+}
@@ -0,0 +1,66 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+func TestQuietUntil(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	cfg := &po.QuietHoursConfig{
+		Timezone: "Asia/Shanghai",
+		Windows: []po.QuietWindow{
+			{Start: "22:00", End: "08:00"},
+			{Start: "08:00", End: "10:00", Days: []string{"sat", "sun"}},
+		},
+	}
+	at := func(day, hour, min int) time.Time {
+		// 2026-02-16 是周一
+		return time.Date(2026, 2, day, hour, min, 0, 0, shanghai).UTC()
+	}
+
+	cases := []struct {
+		name  string
+		now   time.Time
+		quiet bool
+		until time.Time
+	}{
+		{"daytime", at(16, 14, 0), false, time.Time{}},
+		{"before midnight", at(16, 23, 30), true, at(17, 8, 0)},
+		{"after midnight", at(17, 3, 0), true, at(17, 8, 0)},
+		{"window end is exclusive", at(17, 8, 0), false, time.Time{}},
+		{"weekend chains windows", at(21, 23, 0), true, at(22, 10, 0)},
+	}
+	for _, c := range cases {
+		until, quiet := quietUntil(cfg, c.now)
+		if quiet != c.quiet || (quiet && !until.Equal(c.until)) {
+			t.Errorf("%s: got %v %v, want %v %v", c.name, quiet, until.In(shanghai), c.quiet, c.until.In(shanghai))
+		}
+	}
+}
+
+func TestDeferIfQuiet(t *testing.T) {
+	ch := &po.NotificationChannel{QuietHours: &po.QuietHoursConfig{Windows: []po.QuietWindow{{Start: "00:00", End: "23:59"}}}}
+	now := time.Date(2026, 2, 16, 12, 0, 0, 0, time.Local)
+
+	info := &po.NotificationDelivery{}
+	if !deferIfQuiet(ch, info, po.SeverityInfo, now) || info.Status != po.DeliveryStatusDeferred || info.NextRetryAt == nil {
+		t.Errorf("info should be deferred: %+v", info)
+	}
+	if deferIfQuiet(ch, &po.NotificationDelivery{}, po.SeverityWarning, now) {
+		t.Error("warning breaks through by default")
+	}
+	ch.QuietHours.Breakthrough = po.SeverityCritical
+	if !deferIfQuiet(ch, &po.NotificationDelivery{}, po.SeverityWarning, now) {
+		t.Error("warning should be deferred when breakthrough is critical")
+	}
+	ch.QuietHours.Breakthrough = po.SeverityNone
+	if !deferIfQuiet(ch, &po.NotificationDelivery{}, po.SeverityCritical, now) {
+		t.Error("nothing breaks through when breakthrough is none")
+	}
+}
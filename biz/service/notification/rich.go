@@ -0,0 +1,139 @@
+// biz/service/notification/rich.go - 富文本渠道公共工具
+
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yi-nology/git-manage-service/pkg/strutil"
+)
+
+// richHTTPClient 富文本渠道共用的 HTTP 客户端
+var richHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// richField 富文本消息中的一个键值字段
+type richField struct {
+	Name  string
+	Value string
+}
+
+// richFields 从模板数据提取展示字段；没有模板数据时退回到任务与仓库 key
+func richFields(msg *NotificationMessage) []richField {
+	d := msg.Data
+	if d == nil {
+		return compactFields(
+			richField{"Task", msg.TaskKey},
+			richField{"Repo", msg.RepoKey},
+		)
+	}
+	task := d.TaskName
+	if task == "" {
+		task = d.TaskKey
+	}
+	repo := d.RepoName
+	if repo == "" {
+		repo = d.RepoKey
+	}
+	var source, target string
+	if d.SourceRemote != "" || d.SourceBranch != "" {
+		source = d.SourceRemote + "/" + d.SourceBranch
+	}
+	if d.TargetRemote != "" || d.TargetBranch != "" {
+		target = d.TargetRemote + "/" + d.TargetBranch
+	}
+	var branches string
+	if d.SyncMode == "all-branch" && d.BranchCount > 0 {
+		branches = fmt.Sprintf("%d/%d succeeded, %d failed", d.SuccessCount, d.BranchCount, d.FailedCount)
+	}
+	return compactFields(
+		richField{"Event", d.EventLabel},
+		richField{"Task", task},
+		richField{"Repo", repo},
+		richField{"Source", source},
+		richField{"Target", target},
+		richField{"Branches", branches},
+		richField{"Commits", d.CommitRange},
+		richField{"Duration", d.Duration},
+		richField{"Cron", d.CronExpression},
+		richField{"Webhook", d.WebhookSource},
+		richField{"Backup", d.BackupPath},
+		richField{"Time", d.Timestamp},
+		richField{"Error", d.ErrorMessage},
+	)
+}
+
+func compactFields(fields ...richField) []richField {
+	result := make([]richField, 0, len(fields))
+	for _, f := range fields {
+		if f.Value != "" {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// statusEmoji 与钉钉/飞书等渠道一致的状态标识
+func statusEmoji(status string) string {
+	switch status {
+	case "failure":
+		return "❌"
+	case "conflict":
+		return "⚠️"
+	}
+	return "✅"
+}
+
+// statusColor 状态对应的 RGB 颜色
+func statusColor(status string) int {
+	switch status {
+	case "failure":
+		return 0xD93F0B
+	case "conflict":
+		return 0xFBCA04
+	}
+	return 0x2EA043
+}
+
+// doJSON 发送 JSON 请求，返回 2xx 响应体；非 2xx 时返回带平台名的错误
+func doJSON(platform, method, url string, payload interface{}, headers map[string]string) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := richHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s api error: %d %s", platform, resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
+
+// truncateRunes 按字符截断，避免切断多字节字符；超长时以省略号结尾
+func truncateRunes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return strutil.Truncate(s, n-1) + "…"
+}
@@ -0,0 +1,195 @@
+// biz/service/notification/routing.go - 通知路由规则
+
+package notification
+
+import (
+	"fmt"
+	"log"
+	"path"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+// routingSubject 路由规则求值所需的事件属性，每次发送只解析一次
+type routingSubject struct {
+	repoKey       string
+	repoName      string
+	taskKey       string
+	taskTags      []string
+	triggerSource string
+}
+
+func newRoutingSubject(msg *NotificationMessage) *routingSubject {
+	subject := &routingSubject{repoKey: msg.RepoKey, taskKey: msg.TaskKey, triggerSource: msg.TriggerSource}
+	if msg.TaskKey != "" {
+		if task, err := db.NewSyncTaskDAO().FindByKey(msg.TaskKey); err == nil {
+			subject.taskTags = task.Tags
+			if subject.repoKey == "" {
+				subject.repoKey = task.SourceRepoKey
+			}
+		}
+	}
+	if subject.repoKey != "" {
+		if repo, err := db.NewRepoDAO().FindByKey(subject.repoKey); err == nil {
+			subject.repoName = repo.Name
+		}
+	}
+	return subject
+}
+
+func (s *routingSubject) values(field string) []string {
+	switch field {
+	case po.RoutingFieldRepo:
+		return nonEmpty(s.repoKey, s.repoName)
+	case po.RoutingFieldTask:
+		return nonEmpty(s.taskKey)
+	case po.RoutingFieldTaskTag:
+		return s.taskTags
+	case po.RoutingFieldTriggerSource:
+		return nonEmpty(s.triggerSource)
+	}
+	return nil
+}
+
+// evaluateRouting 按规则判断渠道是否接收事件，返回不接收的原因
+func evaluateRouting(rules []po.NotificationRoutingRule, subject *routingSubject) (bool, string) {
+	if len(rules) == 0 {
+		return true, "no routing rules"
+	}
+	for _, r := range rules {
+		if r.Effect == po.RoutingEffectExclude && matchAny(r.Pattern, subject.values(r.Field)) {
+			return false, fmt.Sprintf("excluded by %s=%s", r.Field, r.Pattern)
+		}
+	}
+
+	// 存在 include 规则的字段各自至少命中一条
+	included := map[string]bool{}
+	var fields []string
+	for _, r := range rules {
+		if r.Effect != po.RoutingEffectInclude {
+			continue
+		}
+		if _, ok := included[r.Field]; !ok {
+			included[r.Field] = false
+			fields = append(fields, r.Field)
+		}
+		if matchAny(r.Pattern, subject.values(r.Field)) {
+			included[r.Field] = true
+		}
+	}
+	for _, field := range fields {
+		if !included[field] {
+			return false, fmt.Sprintf("no include rule matched %s", field)
+		}
+	}
+	if len(fields) == 0 {
+		return true, "not excluded"
+	}
+	return true, "included"
+}
+
+func matchAny(pattern string, values []string) bool {
+	for _, v := range values {
+		if ok, _ := path.Match(pattern, v); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func nonEmpty(values ...string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// loadRoutingRules 按渠道分组加载路由规则；加载失败时返回 nil，所有渠道按无规则处理
+func (s *NotificationService) loadRoutingRules() map[uint][]po.NotificationRoutingRule {
+	rules, err := db.NewNotificationRoutingRuleDAO().FindAll()
+	if err != nil {
+		log.Printf("[Notification] Failed to load routing rules: %v", err)
+		return nil
+	}
+	byChannel := make(map[uint][]po.NotificationRoutingRule)
+	for _, r := range rules {
+		byChannel[r.ChannelID] = append(byChannel[r.ChannelID], r)
+	}
+	return byChannel
+}
+
+// BuildRoutingRules 校验并转换路由规则请求
+func BuildRoutingRules(channelID uint, reqs []api.NotificationRoutingRuleReq) ([]po.NotificationRoutingRule, error) {
+	rules := make([]po.NotificationRoutingRule, 0, len(reqs))
+	for i, r := range reqs {
+		if r.Effect != po.RoutingEffectInclude && r.Effect != po.RoutingEffectExclude {
+			return nil, fmt.Errorf("routing rule %d: effect must be include or exclude", i+1)
+		}
+		switch r.Field {
+		case po.RoutingFieldRepo, po.RoutingFieldTask, po.RoutingFieldTaskTag, po.RoutingFieldTriggerSource:
+		default:
+			return nil, fmt.Errorf("routing rule %d: unknown field %q", i+1, r.Field)
+		}
+		if r.Pattern == "" {
+			return nil, fmt.Errorf("routing rule %d: pattern is required", i+1)
+		}
+		if _, err := path.Match(r.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("routing rule %d: invalid pattern %q", i+1, r.Pattern)
+		}
+		rules = append(rules, po.NotificationRoutingRule{ChannelID: channelID, Effect: r.Effect, Field: r.Field, Pattern: r.Pattern})
+	}
+	return rules, nil
+}
+
+// PreviewRouting 预览事件会发送到哪些渠道（包括停用的渠道及不发送的原因）
+func (s *NotificationService) PreviewRouting(req *api.RoutingPreviewReq) ([]*api.RoutingPreviewItemDTO, error) {
+	var draft []po.NotificationRoutingRule
+	if req.ChannelID > 0 && req.RoutingRules != nil {
+		rules, err := BuildRoutingRules(req.ChannelID, *req.RoutingRules)
+		if err != nil {
+			return nil, err
+		}
+		draft = rules
+	}
+	channels, err := s.dao.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &NotificationMessage{
+		Status:        req.Status,
+		TriggerEvent:  req.TriggerEvent,
+		TaskKey:       req.TaskKey,
+		RepoKey:       req.RepoKey,
+		TriggerSource: req.TriggerSource,
+	}
+	subject := newRoutingSubject(msg)
+	rulesByChannel := s.loadRoutingRules()
+
+	items := make([]*api.RoutingPreviewItemDTO, 0, len(channels))
+	for i := range channels {
+		ch := &channels[i]
+		item := &api.RoutingPreviewItemDTO{ChannelID: ch.ID, ChannelName: ch.Name, ChannelType: ch.Type}
+		rules := rulesByChannel[ch.ID]
+		if ch.ID == req.ChannelID && req.RoutingRules != nil {
+			rules = draft
+		}
+		switch {
+		case !ch.Enabled:
+			item.Reason = "channel disabled"
+		case ch.Escalation:
+			item.Reason = "escalation channel, receives escalated alerts only"
+		case !s.shouldNotify(ch, msg):
+			item.Reason = "trigger event not subscribed"
+		default:
+			item.Receive, item.Reason = evaluateRouting(rules, subject)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
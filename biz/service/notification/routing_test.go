@@ -0,0 +1,36 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+func TestEvaluateRouting(t *testing.T) {
+	rule := func(effect, field, pattern string) po.NotificationRoutingRule {
+		return po.NotificationRoutingRule{Effect: effect, Field: field, Pattern: pattern}
+	}
+	mobile := &routingSubject{repoKey: "k1", repoName: "mobile-app", taskKey: "t1", taskTags: []string{"mobile", "ios"}, triggerSource: "cron"}
+	backend := &routingSubject{repoKey: "k2", repoName: "backend-mirror", taskKey: "t2", taskTags: []string{"backend"}, triggerSource: "webhook"}
+
+	cases := []struct {
+		name    string
+		rules   []po.NotificationRoutingRule
+		subject *routingSubject
+		want    bool
+	}{
+		{"no rules", nil, backend, true},
+		{"include repo glob", []po.NotificationRoutingRule{rule("include", "repo", "mobile-*")}, mobile, true},
+		{"include repo glob miss", []po.NotificationRoutingRule{rule("include", "repo", "mobile-*")}, backend, false},
+		{"include tag or", []po.NotificationRoutingRule{rule("include", "task_tag", "android"), rule("include", "task_tag", "ios")}, mobile, true},
+		{"include fields and", []po.NotificationRoutingRule{rule("include", "task_tag", "mobile"), rule("include", "trigger_source", "webhook")}, mobile, false},
+		{"exclude wins", []po.NotificationRoutingRule{rule("include", "repo", "*"), rule("exclude", "trigger_source", "cron")}, mobile, false},
+		{"exclude only", []po.NotificationRoutingRule{rule("exclude", "task_tag", "backend")}, mobile, true},
+		{"missing value", []po.NotificationRoutingRule{rule("include", "task", "*")}, &routingSubject{repoKey: "k1"}, false},
+	}
+	for _, c := range cases {
+		if got, reason := evaluateRouting(c.rules, c.subject); got != c.want {
+			t.Errorf("%s: got %v (%s), want %v", c.name, got, reason, c.want)
+		}
+	}
+}
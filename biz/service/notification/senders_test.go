@@ -0,0 +1,159 @@
+package notification
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+type capturedRequest struct {
+	method string
+	path   string
+	auth   string
+	body   map[string]interface{}
+}
+
+func standIn(t *testing.T, status int, reply string) (*httptest.Server, *capturedRequest) {
+	t.Helper()
+	captured := &capturedRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured.method = r.Method
+		captured.path = r.URL.EscapedPath()
+		captured.auth = r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, &captured.body); err != nil {
+			t.Errorf("invalid JSON body: %v", err)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(reply))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, captured
+}
+
+func sampleMessage() *NotificationMessage {
+	return &NotificationMessage{
+		Title:   "同步失败: mirror",
+		Content: "push rejected <non-fast-forward>",
+		Status:  "failure",
+		TaskKey: "t1",
+		RepoKey: "r1",
+		Data: &TemplateData{
+			TaskName:     "mirror",
+			RepoName:     "backend",
+			SourceRemote: "origin",
+			SourceBranch: "main",
+			TargetRemote: "backup",
+			TargetBranch: "main",
+			ErrorMessage: "non-fast-forward",
+		},
+	}
+}
+
+func TestRichSenders(t *testing.T) {
+	t.Run("slack", func(t *testing.T) {
+		srv, got := standIn(t, http.StatusOK, "ok")
+		if err := NewSlackSender(&po.SlackConfig{WebhookURL: srv.URL}).Send(sampleMessage()); err != nil {
+			t.Fatal(err)
+		}
+		blocks, _ := got.body["blocks"].([]interface{})
+		if len(blocks) != 3 || !strings.Contains(got.body["text"].(string), "同步失败") {
+			t.Fatalf("unexpected slack payload: %v", got.body)
+		}
+	})
+
+	t.Run("teams", func(t *testing.T) {
+		srv, got := standIn(t, http.StatusAccepted, "")
+		if err := NewTeamsSender(&po.TeamsConfig{WebhookURL: srv.URL}).Send(sampleMessage()); err != nil {
+			t.Fatal(err)
+		}
+		att := got.body["attachments"].([]interface{})[0].(map[string]interface{})
+		if att["contentType"] != "application/vnd.microsoft.card.adaptive" {
+			t.Fatalf("unexpected teams attachment: %v", att)
+		}
+		body := att["content"].(map[string]interface{})["body"].([]interface{})
+		if body[0].(map[string]interface{})["color"] != "Attention" || len(body) != 3 {
+			t.Fatalf("unexpected card body: %v", body)
+		}
+	})
+
+	t.Run("discord", func(t *testing.T) {
+		srv, got := standIn(t, http.StatusNoContent, "")
+		if err := NewDiscordSender(&po.DiscordConfig{WebhookURL: srv.URL}).Send(sampleMessage()); err != nil {
+			t.Fatal(err)
+		}
+		embed := got.body["embeds"].([]interface{})[0].(map[string]interface{})
+		if int(embed["color"].(float64)) != statusColor("failure") || len(embed["fields"].([]interface{})) != 5 {
+			t.Fatalf("unexpected discord embed: %v", embed)
+		}
+	})
+
+	t.Run("telegram", func(t *testing.T) {
+		srv, got := standIn(t, http.StatusOK, `{"ok":true}`)
+		cfg := &po.TelegramConfig{BotToken: "123:abc", ChatID: "-100", APIBaseURL: srv.URL}
+		if err := NewTelegramSender(cfg).Send(sampleMessage()); err != nil {
+			t.Fatal(err)
+		}
+		text := got.body["text"].(string)
+		if got.path != "/bot123:abc/sendMessage" || got.body["parse_mode"] != "HTML" || !strings.Contains(text, "&lt;non-fast-forward&gt;") {
+			t.Fatalf("unexpected telegram request %s: %v", got.path, got.body)
+		}
+	})
+
+	t.Run("telegram error hides token", func(t *testing.T) {
+		srv, _ := standIn(t, http.StatusUnauthorized, `{"ok":false,"description":"Unauthorized"}`)
+		cfg := &po.TelegramConfig{BotToken: "123:abc", ChatID: "-100", APIBaseURL: srv.URL}
+		err := NewTelegramSender(cfg).Send(sampleMessage())
+		if err == nil || strings.Contains(err.Error(), "123:abc") {
+			t.Fatalf("expected redacted error, got %v", err)
+		}
+	})
+
+	t.Run("matrix", func(t *testing.T) {
+		srv, got := standIn(t, http.StatusOK, `{"event_id":"$1"}`)
+		cfg := &po.MatrixConfig{HomeserverURL: srv.URL, AccessToken: "tok", RoomID: "!room:example.org"}
+		if err := NewMatrixSender(cfg).Send(sampleMessage()); err != nil {
+			t.Fatal(err)
+		}
+		if got.method != http.MethodPut || got.auth != "Bearer tok" ||
+			!strings.HasPrefix(got.path, "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/") {
+			t.Fatalf("unexpected matrix request %s %s", got.method, got.path)
+		}
+		if got.body["format"] != "org.matrix.custom.html" || !strings.Contains(got.body["formatted_body"].(string), "<li><strong>Error:</strong>") {
+			t.Fatalf("unexpected matrix body: %v", got.body)
+		}
+	})
+
+	t.Run("http error", func(t *testing.T) {
+		srv, _ := standIn(t, http.StatusBadRequest, "invalid_payload")
+		err := NewSlackSender(&po.SlackConfig{WebhookURL: srv.URL}).Send(sampleMessage())
+		if err == nil || !strings.Contains(err.Error(), "invalid_payload") {
+			t.Fatalf("expected slack error, got %v", err)
+		}
+	})
+}
+
+func TestTruncateRunes(t *testing.T) {
+	cases := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"hello", 5, "hello"},
+		{"hello", 4, "hel…"},
+		{"推送失败", 3, "推送…"},
+		{"hello", 1, "…"},
+		{"hello", 0, ""},
+		{"hello", -1, ""},
+	}
+	for _, c := range cases {
+		if got := truncateRunes(c.in, c.n); got != c.want {
+			t.Errorf("truncateRunes(%q, %d) = %q, want %q", c.in, c.n, got, c.want)
+		}
+	}
+}
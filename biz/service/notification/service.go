@@ -13,13 +13,21 @@ import (
 
 // NotificationService 通知服务
 type NotificationService struct {
-	dao *db.NotificationChannelDAO
+	dao         *db.NotificationChannelDAO
+	deliveryDAO *db.NotificationDeliveryDAO
+	digestDAO   *db.NotificationDigestItemDAO
+	alertDAO    *db.NotificationAlertDAO
+	identityDAO *db.IdentityMappingDAO
 }
 
 // NewNotificationService 创建通知服务
 func NewNotificationService() *NotificationService {
 	return &NotificationService{
-		dao: db.NewNotificationChannelDAO(),
+		dao:         db.NewNotificationChannelDAO(),
+		deliveryDAO: db.NewNotificationDeliveryDAO(),
+		digestDAO:   db.NewNotificationDigestItemDAO(),
+		alertDAO:    db.NewNotificationAlertDAO(),
+		identityDAO: db.NewIdentityMappingDAO(),
 	}
 }
 
@@ -32,44 +40,113 @@ type NotificationMessage struct {
 	TaskKey      string        `json:"task_key"`
 	RepoKey      string        `json:"repo_key"`
 	Data         *TemplateData `json:"-"` // 模板渲染数据（可选）
+
+	// TriggerSource 触发来源（manual, cron, webhook），用于路由规则
+	TriggerSource string `json:"trigger_source"`
+	// RecoveredEvent 恢复通知（alert_recovered）对应的失败事件
+	RecoveredEvent string `json:"recovered_event,omitempty"`
+	// Mentions 渲染 {{mention}} 时解析出的提及账号，由发送器转为平台原生提及
+	Mentions []Mention `json:"mentions,omitempty"`
+
+	// titlePrefix 告警提醒或升级时加在渲染后标题前的提示
+	titlePrefix string
 }
 
 // Sender 发送器接口
 type Sender interface {
 	Send(msg *NotificationMessage) error
+	// Payload 构建将要发送的请求体（不发送），用于模板预览
+	Payload(msg *NotificationMessage) (interface{}, error)
 }
 
-// Send 发送通知到所有启用的渠道
+// Send 发送通知到所有启用的渠道；失败与恢复事件先经过告警状态跟踪（去重、升级、恢复通知）
 func (s *NotificationService) Send(msg *NotificationMessage) {
+	plan := s.trackAlert(msg)
+	if plan == nil {
+		s.dispatch(msg, false)
+		return
+	}
+	if plan.notify {
+		s.dispatch(msg, plan.escalated)
+	}
+	for i, recovery := range plan.recoveries {
+		s.dispatch(recovery, plan.recoveryEscalated[i])
+	}
+}
+
+// dispatch 将消息发送到订阅的渠道；escalated 为 true 时同时发送到升级渠道
+func (s *NotificationService) dispatch(msg *NotificationMessage, escalated bool) {
 	channels, err := s.dao.FindEnabled()
 	if err != nil {
 		log.Printf("[Notification] Failed to get enabled channels: %v", err)
 		return
 	}
 
+	var subject *routingSubject
+	var rulesByChannel map[uint][]po.NotificationRoutingRule
 	for _, channel := range channels {
-		// 检查是否需要通知
-		if !s.shouldNotify(&channel, msg) {
+		// 检查是否需要通知：升级渠道只接收升级后的告警，不看事件订阅
+		if channel.Escalation {
+			if !escalated {
+				continue
+			}
+		} else if !s.shouldNotify(&channel, msg) {
+			continue
+		}
+		// 路由规则：事件属性与规则只在第一个需要通知的渠道处加载一次
+		if subject == nil {
+			subject = newRoutingSubject(msg)
+			rulesByChannel = s.loadRoutingRules()
+		}
+		if ok, reason := evaluateRouting(rulesByChannel[channel.ID], subject); !ok {
+			log.Printf("[Notification] Skipped channel %s: %s", channel.Name, reason)
+			continue
+		}
+		// 批量 / 摘要模式：事件进入汇总队列，由 digestLoop 到期合并发送
+		if !channel.Escalation && !sendsImmediately(&channel, msg) {
+			s.enqueueDigest(&channel, msg)
 			continue
 		}
 
-		// 发送通知
-		go func(ch po.NotificationChannel) {
-			// 渲染模板：为每个渠道使用其自定义模板或默认模板
-			renderedMsg := s.renderMessage(&ch, msg)
-
-			sender, err := s.createSender(&ch)
-			if err != nil {
-				log.Printf("[Notification] Failed to create sender for channel %s: %v", ch.Name, err)
-				return
+		// 渲染模板：为每个渠道使用其自定义模板或默认模板，渲染结果随投递记录保存，重试与重发不再重新渲染
+		renderedMsg := s.renderMessage(&channel, msg)
+		delivery := &po.NotificationDelivery{
+			ChannelID:     channel.ID,
+			ChannelName:   channel.Name,
+			ChannelType:   channel.Type,
+			TriggerEvent:  renderedMsg.TriggerEvent,
+			MessageStatus: renderedMsg.Status,
+			TaskKey:       renderedMsg.TaskKey,
+			RepoKey:       renderedMsg.RepoKey,
+			Title:         renderedMsg.Title,
+			Content:       renderedMsg.Content,
+			Status:        po.DeliveryStatusPending,
+		}
+		if renderedMsg.Data != nil {
+			if data, err := json.Marshal(renderedMsg.Data); err == nil {
+				delivery.DataJSON = string(data)
 			}
-
-			if err := sender.Send(renderedMsg); err != nil {
-				log.Printf("[Notification] Failed to send to channel %s: %v", ch.Name, err)
-			} else {
-				log.Printf("[Notification] Sent to channel %s successfully", ch.Name)
+		}
+		if len(renderedMsg.Mentions) > 0 {
+			if mentions, err := json.Marshal(renderedMsg.Mentions); err == nil {
+				delivery.MentionsJSON = string(mentions)
 			}
-		}(channel)
+		}
+		if err := s.queueDelivery(&channel, delivery, messageSeverity(msg, escalated)); err != nil {
+			log.Printf("[Notification] Failed to record delivery for channel %s: %v", channel.Name, err)
+			go s.sendUnrecorded(channel, renderedMsg)
+		}
+	}
+}
+
+// sendUnrecorded 投递记录写入失败时直接发送一次，不重试
+func (s *NotificationService) sendUnrecorded(ch po.NotificationChannel, msg *NotificationMessage) {
+	sender, err := s.createSender(&ch)
+	if err == nil {
+		err = sender.Send(msg)
+	}
+	if err != nil {
+		log.Printf("[Notification] Failed to send to channel %s: %v", ch.Name, err)
 	}
 }
 
@@ -77,16 +154,26 @@ func (s *NotificationService) Send(msg *NotificationMessage) {
 func (s *NotificationService) renderMessage(channel *po.NotificationChannel, msg *NotificationMessage) *NotificationMessage {
 	// 如果没有模板数据，直接返回原始消息
 	if msg.Data == nil {
-		return msg
+		if msg.titlePrefix == "" {
+			return msg
+		}
+		prefixed := *msg
+		prefixed.Title = msg.titlePrefix + msg.Title
+		return &prefixed
 	}
 
-	titleTmpl := channel.TitleTemplate
-	contentTmpl := channel.ContentTemplate
+	titleTmpl, contentTmpl := resolveTemplates(channel, msg.TriggerEvent)
+	return renderWithTemplates(channel, msg, titleTmpl, contentTmpl)
+}
+
+// resolveTemplates 确定渠道在指定事件下使用的模板：事件级模板优先，留空则回退到渠道级模板
+func resolveTemplates(channel *po.NotificationChannel, triggerEvent string) (titleTmpl, contentTmpl string) {
+	titleTmpl = channel.TitleTemplate
+	contentTmpl = channel.ContentTemplate
 
-	// 优先使用事件级模板（留空则回退到渠道级模板）
-	if msg.TriggerEvent != "" {
+	if triggerEvent != "" && channel.ID != 0 {
 		etDAO := db.NewNotificationEventTemplateDAO()
-		et, err := etDAO.FindByChannelAndEvent(channel.ID, msg.TriggerEvent)
+		et, err := etDAO.FindByChannelAndEvent(channel.ID, triggerEvent)
 		if err == nil && et != nil {
 			if et.TitleTemplate != "" {
 				titleTmpl = et.TitleTemplate
@@ -96,21 +183,35 @@ func (s *NotificationService) renderMessage(channel *po.NotificationChannel, msg
 			}
 		}
 	}
+	return titleTmpl, contentTmpl
+}
 
-	title, content := RenderTitleAndContent(titleTmpl, contentTmpl, msg.Data)
+// renderWithTemplates 使用给定模板为渠道渲染消息，并解析 @ 提及
+func renderWithTemplates(channel *po.NotificationChannel, msg *NotificationMessage, titleTmpl, contentTmpl string) *NotificationMessage {
+	mentions := newMentionRenderer(channel.Type)
+	title, content := renderTitleAndContent(titleTmpl, contentTmpl, msg.Data, mentions.funcs())
 
 	return &NotificationMessage{
-		Title:        title,
+		Title:        msg.titlePrefix + title,
 		Content:      content,
 		Status:       msg.Status,
 		TriggerEvent: msg.TriggerEvent,
 		TaskKey:      msg.TaskKey,
 		RepoKey:      msg.RepoKey,
+		Data:         msg.Data,
+
+		TriggerSource:  msg.TriggerSource,
+		RecoveredEvent: msg.RecoveredEvent,
+		Mentions:       mentions.mentions,
 	}
 }
 
 // shouldNotify 检查是否应该发送通知
 func (s *NotificationService) shouldNotify(channel *po.NotificationChannel, msg *NotificationMessage) bool {
+	if msg.TriggerEvent == po.TriggerAlertRecovered {
+		return receivesRecovery(channel, msg.RecoveredEvent)
+	}
+
 	// 优先使用 TriggerEvents 配置
 	if channel.TriggerEvents != "" {
 		var events []string
@@ -147,6 +248,22 @@ func (s *NotificationService) shouldNotify(channel *po.NotificationChannel, msg
 	return true
 }
 
+// receivesRecovery 订阅了 alert_recovered 或对应失败事件的渠道接收恢复通知
+func receivesRecovery(channel *po.NotificationChannel, failureEvent string) bool {
+	if channel.TriggerEvents != "" {
+		var events []string
+		if err := json.Unmarshal([]byte(channel.TriggerEvents), &events); err == nil && len(events) > 0 {
+			for _, event := range events {
+				if event == po.TriggerAlertRecovered || event == failureEvent {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return channel.NotifyOnFailure
+}
+
 // Test 测试通知渠道
 func (s *NotificationService) Test(channelID uint, message string) error {
 	channel, err := s.dao.FindByID(channelID)
@@ -213,6 +330,41 @@ func (s *NotificationService) createSender(channel *po.NotificationChannel) (Sen
 		}
 		return NewFeishuSender(&config), nil
 
+	case "slack":
+		var config po.SlackConfig
+		if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
+			return nil, err
+		}
+		return NewSlackSender(&config), nil
+
+	case "teams":
+		var config po.TeamsConfig
+		if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
+			return nil, err
+		}
+		return NewTeamsSender(&config), nil
+
+	case "discord":
+		var config po.DiscordConfig
+		if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
+			return nil, err
+		}
+		return NewDiscordSender(&config), nil
+
+	case "telegram":
+		var config po.TelegramConfig
+		if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
+			return nil, err
+		}
+		return NewTelegramSender(&config), nil
+
+	case "matrix":
+		var config po.MatrixConfig
+		if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
+			return nil, err
+		}
+		return NewMatrixSender(&config), nil
+
 	default:
 		return nil, fmt.Errorf("unknown channel type: %s", channel.Type)
 	}
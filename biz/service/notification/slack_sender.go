@@ -0,0 +1,85 @@
+// biz/service/notification/slack_sender.go - Slack Incoming Webhook 发送器
+
+package notification
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+// SlackSender Slack 发送器
+type SlackSender struct {
+	config *po.SlackConfig
+}
+
+// NewSlackSender 创建 Slack 发送器
+func NewSlackSender(config *po.SlackConfig) *SlackSender {
+	return &SlackSender{config: config}
+}
+
+// SlackMessage Slack webhook 消息，text 在使用 blocks 时作为通知预览
+type SlackMessage struct {
+	Text      string                   `json:"text"`
+	Blocks    []map[string]interface{} `json:"blocks,omitempty"`
+	Channel   string                   `json:"channel,omitempty"`
+	Username  string                   `json:"username,omitempty"`
+	IconEmoji string                   `json:"icon_emoji,omitempty"`
+}
+
+// Send 发送 Slack 消息
+func (s *SlackSender) Send(msg *NotificationMessage) error {
+	slackMsg, _ := s.Payload(msg)
+	_, err := doJSON("slack", http.MethodPost, s.config.WebhookURL, slackMsg, nil)
+	return err
+}
+
+// Payload 构建 Slack 消息体
+func (s *SlackSender) Payload(msg *NotificationMessage) (interface{}, error) {
+	title := fmt.Sprintf("%s %s", statusEmoji(msg.Status), msg.Title)
+	slackMsg := SlackMessage{
+		Text:      title,
+		Channel:   s.config.Channel,
+		Username:  s.config.Username,
+		IconEmoji: s.config.IconEmoji,
+	}
+	if s.config.PlainText {
+		slackMsg.Text = title + "\n" + msg.Content
+	} else {
+		slackMsg.Blocks = s.blocks(title, msg)
+	}
+	return slackMsg, nil
+}
+
+// blocks 构建 Block Kit：标题、正文、字段（每个 section 最多 10 个）
+func (s *SlackSender) blocks(title string, msg *NotificationMessage) []map[string]interface{} {
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": truncateRunes(title, 150), "emoji": true},
+		},
+	}
+	if msg.Content != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": truncateRunes(msg.Content, 3000)},
+		})
+	}
+	var fields []map[string]string
+	for _, f := range richFields(msg) {
+		fields = append(fields, map[string]string{
+			"type": "mrkdwn",
+			"text": truncateRunes(fmt.Sprintf("*%s*\n%s", f.Name, f.Value), 2000),
+		})
+	}
+	for len(fields) > 0 {
+		n := len(fields)
+		if n > 10 {
+			n = 10
+		}
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields[:n]})
+		fields = fields[n:]
+	}
+	return blocks
+}
@@ -0,0 +1,76 @@
+// biz/service/notification/sync_message.go - 同步执行记录转通知消息
+
+package notification
+
+import (
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+// ConflictAuthorLimit 收集冲突作者时最多遍历的提交数
+const ConflictAuthorLimit = 200
+
+// SyncRunMessage 根据同步执行记录构建通知消息（同步执行与模板预览共用），
+// authors 为冲突时分叉提交的作者；运行中等无需通知的状态返回 nil
+func SyncRunMessage(task *po.SyncTask, run *po.SyncRun, authors []string) *NotificationMessage {
+	var triggerEvent string
+	var status string
+
+	switch run.Status {
+	case "success":
+		triggerEvent = po.TriggerSyncSuccess
+		status = "success"
+	case "failed":
+		triggerEvent = po.TriggerSyncFailure
+		status = "failure"
+	case "conflict":
+		triggerEvent = po.TriggerSyncConflict
+		status = "failure"
+	default:
+		return nil
+	}
+
+	// 计算耗时
+	duration := ""
+	if !run.EndTime.IsZero() && !run.StartTime.IsZero() {
+		d := run.EndTime.Sub(run.StartTime)
+		duration = d.Round(time.Millisecond).String()
+	}
+
+	data := &TemplateData{
+		TaskKey:      task.Key,
+		TaskName:     task.Key,
+		Status:       run.Status,
+		EventType:    triggerEvent,
+		SourceRemote: task.SourceRemote,
+		SourceBranch: task.SourceBranch,
+		TargetRemote: task.TargetRemote,
+		TargetBranch: task.TargetBranch,
+		RepoKey:      task.SourceRepoKey,
+		RepoName:     task.SourceRepo.Name,
+		ErrorMessage: run.ErrorMessage,
+		CommitRange:  run.CommitRange,
+		Duration:     duration,
+		SyncMode:     task.SyncMode,
+		Authors:      authors,
+	}
+	if task.Cron != "" {
+		data.CronExpression = task.Cron
+	}
+
+	// 使用模板渲染的默认标题和内容作为 fallback
+	title, content := RenderTitleAndContent("", "", data)
+
+	return &NotificationMessage{
+		Title:        title,
+		Content:      content,
+		Status:       status,
+		TriggerEvent: triggerEvent,
+		TaskKey:      task.Key,
+		RepoKey:      task.SourceRepoKey,
+		Data:         data,
+
+		TriggerSource: run.TriggerSource,
+	}
+}
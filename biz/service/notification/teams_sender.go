@@ -0,0 +1,86 @@
+// biz/service/notification/teams_sender.go - Microsoft Teams 发送器（Adaptive Card）
+
+package notification
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+// TeamsSender Microsoft Teams 发送器
+type TeamsSender struct {
+	config *po.TeamsConfig
+}
+
+// NewTeamsSender 创建 Teams 发送器
+func NewTeamsSender(config *po.TeamsConfig) *TeamsSender {
+	return &TeamsSender{config: config}
+}
+
+// TeamsMessage Teams webhook 消息，Incoming Webhook 与 Workflows 均接受该格式
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+// TeamsAttachment Teams 消息附件
+type TeamsAttachment struct {
+	ContentType string                 `json:"contentType"`
+	ContentURL  *string                `json:"contentUrl"`
+	Content     map[string]interface{} `json:"content"`
+}
+
+// Send 发送 Teams 消息
+func (s *TeamsSender) Send(msg *NotificationMessage) error {
+	teamsMsg, _ := s.Payload(msg)
+	_, err := doJSON("teams", http.MethodPost, s.config.WebhookURL, teamsMsg, nil)
+	return err
+}
+
+// Payload 构建 Teams Adaptive Card 消息体
+func (s *TeamsSender) Payload(msg *NotificationMessage) (interface{}, error) {
+	color := "Good"
+	switch msg.Status {
+	case "failure":
+		color = "Attention"
+	case "conflict":
+		color = "Warning"
+	}
+
+	body := []map[string]interface{}{
+		{
+			"type":   "TextBlock",
+			"text":   fmt.Sprintf("%s %s", statusEmoji(msg.Status), msg.Title),
+			"size":   "Medium",
+			"weight": "Bolder",
+			"color":  color,
+			"wrap":   true,
+		},
+	}
+	if msg.Content != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": msg.Content, "wrap": true})
+	}
+	if fields := richFields(msg); len(fields) > 0 {
+		facts := make([]map[string]string, 0, len(fields))
+		for _, f := range fields {
+			facts = append(facts, map[string]string{"title": f.Name, "value": f.Value})
+		}
+		body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+	}
+
+	teamsMsg := TeamsMessage{
+		Type: "message",
+		Attachments: []TeamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: map[string]interface{}{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    body,
+			},
+		}},
+	}
+	return teamsMsg, nil
+}
@@ -0,0 +1,86 @@
+// biz/service/notification/telegram_sender.go - Telegram Bot 发送器
+
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+const defaultTelegramAPIBaseURL = "https://api.telegram.org"
+
+// TelegramSender Telegram 发送器
+type TelegramSender struct {
+	config *po.TelegramConfig
+}
+
+// NewTelegramSender 创建 Telegram 发送器
+func NewTelegramSender(config *po.TelegramConfig) *TelegramSender {
+	return &TelegramSender{config: config}
+}
+
+// TelegramMessage sendMessage 请求体
+type TelegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+	DisableNotification   bool   `json:"disable_notification,omitempty"`
+}
+
+// Send 发送 Telegram 消息（HTML 格式）
+func (s *TelegramSender) Send(msg *NotificationMessage) error {
+	baseURL := strings.TrimRight(s.config.APIBaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultTelegramAPIBaseURL
+	}
+	url := fmt.Sprintf("%s/bot%s/sendMessage", baseURL, s.config.BotToken)
+
+	telegramMsg, _ := s.Payload(msg)
+	body, err := doJSON("telegram", http.MethodPost, url, telegramMsg, nil)
+	if err != nil {
+		// 错误信息中不暴露 bot token
+		return fmt.Errorf("%s", strings.ReplaceAll(err.Error(), s.config.BotToken, "***"))
+	}
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(body, &result); err == nil && !result.OK {
+		return fmt.Errorf("telegram api error: %s", result.Description)
+	}
+	return nil
+}
+
+// Payload 构建 sendMessage 请求体
+func (s *TelegramSender) Payload(msg *NotificationMessage) (interface{}, error) {
+	return TelegramMessage{
+		ChatID:                s.config.ChatID,
+		Text:                  truncateRunes(s.render(msg), 4096),
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+		DisableNotification:   s.config.Silent,
+	}, nil
+}
+
+// render 生成 HTML 文本：粗体标题、正文、字段列表
+func (s *TelegramSender) render(msg *NotificationMessage) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<b>%s %s</b>\n", statusEmoji(msg.Status), html.EscapeString(msg.Title))
+	if msg.Content != "" {
+		fmt.Fprintf(&b, "\n%s\n", html.EscapeString(msg.Content))
+	}
+	if fields := richFields(msg); len(fields) > 0 {
+		b.WriteString("\n")
+		for _, f := range fields {
+			fmt.Fprintf(&b, "<b>%s:</b> %s\n", f.Name, html.EscapeString(f.Value))
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -37,6 +37,11 @@ type TemplateData struct {
 	BranchCount    int    // 全分支同步: 总分支数
 	SuccessCount   int    // 全分支同步: 成功数
 	FailedCount    int    // 全分支同步: 失败数
+
+	ConsecutiveFailures int    // 告警: 连续失败次数
+	FailingSince        string // 告警: 首次失败时间
+
+	Authors []string // 同步冲突: 分叉提交的作者邮箱，配合 {{mention .Authors}} 使用
 }
 
 // VariableInfo 模板变量说明
@@ -61,15 +66,27 @@ var templateFuncMap = template.FuncMap{
 		}
 		return s[:maxLen] + "..."
 	},
+	// mention 渲染时按渠道替换为平台原生提及，此处为未绑定渠道时的回退实现
+	"mention": plainMention,
 }
 
 // RenderTemplate 渲染模板
 func RenderTemplate(tmplStr string, data *TemplateData) (string, error) {
+	return renderTemplate(tmplStr, data)
+}
+
+// renderTemplate 使用任意数据渲染模板（事件模板与汇总模板共用）
+func renderTemplate(tmplStr string, data interface{}) (string, error) {
+	return renderTemplateFuncs(tmplStr, data, nil)
+}
+
+// renderTemplateFuncs 渲染模板，extra 中的函数覆盖同名的默认函数
+func renderTemplateFuncs(tmplStr string, data interface{}, extra template.FuncMap) (string, error) {
 	if tmplStr == "" {
 		return "", nil
 	}
 
-	t, err := template.New("notification").Funcs(templateFuncMap).Parse(tmplStr)
+	t, err := template.New("notification").Funcs(templateFuncMap).Funcs(extra).Parse(tmplStr)
 	if err != nil {
 		return "", fmt.Errorf("模板解析失败: %w", err)
 	}
@@ -96,6 +113,11 @@ func ValidateTemplate(tmplStr string) error {
 
 // RenderTitleAndContent 渲染标题和内容，优先使用自定义模板，否则使用默认模板
 func RenderTitleAndContent(titleTmpl, contentTmpl string, data *TemplateData) (title, content string) {
+	return renderTitleAndContent(titleTmpl, contentTmpl, data, nil)
+}
+
+// renderTitleAndContent 渲染标题和内容，extra 为按渠道替换的模板函数（如 mention）
+func renderTitleAndContent(titleTmpl, contentTmpl string, data *TemplateData, extra template.FuncMap) (title, content string) {
 	if data == nil {
 		return "通知", ""
 	}
@@ -108,7 +130,7 @@ func RenderTitleAndContent(titleTmpl, contentTmpl string, data *TemplateData) (t
 		titleTmpl = GetDefaultTitleTemplate(data.EventType)
 	}
 	var err error
-	title, err = RenderTemplate(titleTmpl, data)
+	title, err = renderTemplateFuncs(titleTmpl, data, extra)
 	if err != nil {
 		// fallback
 		title = fmt.Sprintf("[%s] %s", data.EventLabel, data.TaskName)
@@ -118,7 +140,7 @@ func RenderTitleAndContent(titleTmpl, contentTmpl string, data *TemplateData) (t
 	if contentTmpl == "" {
 		contentTmpl = GetDefaultContentTemplate(data.EventType)
 	}
-	content, err = RenderTemplate(contentTmpl, data)
+	content, err = renderTemplateFuncs(contentTmpl, data, extra)
 	if err != nil {
 		// fallback
 		content = fmt.Sprintf("任务: %s\n状态: %s\n时间: %s", data.TaskName, data.StatusText, data.Timestamp)
@@ -172,6 +194,7 @@ var eventLabels = map[string]string{
 	po.TriggerCronTriggered:   "定时任务触发",
 	po.TriggerBackupSuccess:   "备份成功",
 	po.TriggerBackupFailure:   "备份失败",
+	po.TriggerAlertRecovered:  "告警恢复",
 }
 
 // ===================== 默认模板 =====================
@@ -186,6 +209,7 @@ var defaultTitleTemplates = map[string]string{
 	po.TriggerCronTriggered:   `[定时] 任务触发: {{.TaskName}}`,
 	po.TriggerBackupSuccess:   `[备份成功] {{.RepoName}}`,
 	po.TriggerBackupFailure:   `[备份失败] {{.RepoName}}`,
+	po.TriggerAlertRecovered:  `[恢复] {{if .TaskName}}同步任务 {{.TaskName}}{{else}}{{.RepoName}}{{end}}`,
 }
 
 // 默认内容模板
@@ -223,7 +247,8 @@ var defaultContentTemplates = map[string]string{
 源: {{.SourceRemote}}/{{.SourceBranch}}
 目标: {{.TargetRemote}}/{{.TargetBranch}}
 说明: 源分支和目标分支存在分叉，无法快进合并{{if .ErrorMessage}}
-详情: {{.ErrorMessage}}{{end}}
+详情: {{.ErrorMessage}}{{end}}{{if .Authors}}
+相关提交作者: {{mention .Authors}}{{end}}
 时间: {{.Timestamp}}
 `),
 
@@ -266,6 +291,14 @@ Cron表达式: {{.CronExpression}}{{end}}
 错误: {{.ErrorMessage}}{{end}}{{if .BackupPath}}
 备份路径: {{.BackupPath}}{{end}}
 时间: {{.Timestamp}}
+`),
+
+	po.TriggerAlertRecovered: strings.TrimSpace(`
+{{if .TaskName}}任务: {{.TaskName}}
+{{end}}仓库: {{.RepoName}}
+状态: 已恢复
+此前连续失败: {{.ConsecutiveFailures}} 次（自 {{.FailingSince}} 起）
+时间: {{.Timestamp}}
 `),
 }
 
@@ -311,5 +344,97 @@ func GetAvailableVariables() []VariableInfo {
 		{Name: "BranchCount", Description: "全分支同步-总分支数", Example: "10", Events: "sync_*"},
 		{Name: "SuccessCount", Description: "全分支同步-成功数", Example: "8", Events: "sync_*"},
 		{Name: "FailedCount", Description: "全分支同步-失败数", Example: "2", Events: "sync_*"},
+		{Name: "ConsecutiveFailures", Description: "告警-连续失败次数", Example: "5", Events: "sync_failure,sync_conflict,backup_failure,alert_recovered"},
+		{Name: "FailingSince", Description: "告警-首次失败时间", Example: "2026-02-16 02:00:00", Events: "sync_failure,sync_conflict,backup_failure,alert_recovered"},
+		{Name: "Authors", Description: "分叉提交的作者邮箱列表，用 {{mention .Authors}} 输出平台原生 @ 提及", Example: "[dev@example.com]", Events: "sync_conflict"},
+	}
+}
+
+// ===================== 汇总模板 =====================
+
+// DigestData 汇总消息模板变量
+type DigestData struct {
+	ChannelName   string             // 渠道名称
+	Mode          string             // batch / digest
+	ModeText      string             // 批量汇总 / 每日摘要
+	PeriodStart   string             // 最早事件时间
+	PeriodEnd     string             // 最晚事件时间
+	Timestamp     string             // 发送时间
+	Total         int                // 事件总数
+	SuccessCount  int                // 成功数
+	FailureCount  int                // 失败数（不含冲突）
+	ConflictCount int                // 冲突数
+	EventCounts   []DigestEventCount // 按事件类型计数
+	Failures      []DigestEntry      // 失败与冲突明细
+	Entries       []DigestEntry      // 全部事件明细
+}
+
+// DigestEventCount 单个事件类型的计数
+type DigestEventCount struct {
+	EventType  string
+	EventLabel string
+	Count      int
+}
+
+// DigestEntry 汇总中的一条事件
+type DigestEntry struct {
+	TaskName     string
+	RepoName     string
+	EventType    string
+	EventLabel   string
+	StatusText   string
+	ErrorMessage string
+	Timestamp    string
+}
+
+const defaultDigestTitleTemplate = `[{{.ModeText}}] 共 {{.Total}} 条通知：成功 {{.SuccessCount}}，失败 {{.FailureCount}}{{if .ConflictCount}}，冲突 {{.ConflictCount}}{{end}}`
+
+var defaultDigestContentTemplate = strings.TrimSpace(`
+时间范围: {{.PeriodStart}} ~ {{.PeriodEnd}}
+总计: {{.Total}}（成功 {{.SuccessCount}}，失败 {{.FailureCount}}，冲突 {{.ConflictCount}}）
+{{range .EventCounts}}
+- {{.EventLabel}}: {{.Count}}{{end}}{{if .Failures}}
+
+失败明细:
+| 任务 | 仓库 | 事件 | 错误 | 时间 |
+|------|------|------|------|------|{{range .Failures}}
+| {{.TaskName}} | {{.RepoName}} | {{.EventLabel}} | {{truncate 120 .ErrorMessage}} | {{.Timestamp}} |{{end}}{{end}}
+`)
+
+// RenderDigest 渲染汇总消息，模板为空时使用默认汇总模板
+func RenderDigest(titleTmpl, contentTmpl string, data *DigestData) (title, content string) {
+	if titleTmpl == "" {
+		titleTmpl = defaultDigestTitleTemplate
+	}
+	title, err := renderTemplate(titleTmpl, data)
+	if err != nil {
+		title = fmt.Sprintf("[%s] 共 %d 条通知", data.ModeText, data.Total)
+	}
+	if contentTmpl == "" {
+		contentTmpl = defaultDigestContentTemplate
+	}
+	content, err = renderTemplate(contentTmpl, data)
+	if err != nil {
+		content = fmt.Sprintf("总计: %d（成功 %d，失败 %d，冲突 %d）", data.Total, data.SuccessCount, data.FailureCount, data.ConflictCount)
+	}
+	return title, content
+}
+
+// GetDigestVariables 获取汇总模板可用变量列表
+func GetDigestVariables() []VariableInfo {
+	return []VariableInfo{
+		{Name: "ChannelName", Description: "渠道名称", Example: "运维群", Events: "digest"},
+		{Name: "Mode", Description: "投递模式", Example: "digest", Events: "digest"},
+		{Name: "ModeText", Description: "投递模式名称", Example: "每日摘要", Events: "digest"},
+		{Name: "PeriodStart", Description: "最早事件时间", Example: "2026-02-16 02:00:05", Events: "digest"},
+		{Name: "PeriodEnd", Description: "最晚事件时间", Example: "2026-02-16 02:41:37", Events: "digest"},
+		{Name: "Timestamp", Description: "发送时间", Example: "2026-02-16 09:00:00", Events: "digest"},
+		{Name: "Total", Description: "事件总数", Example: "80", Events: "digest"},
+		{Name: "SuccessCount", Description: "成功数", Example: "77", Events: "digest"},
+		{Name: "FailureCount", Description: "失败数（不含冲突）", Example: "2", Events: "digest"},
+		{Name: "ConflictCount", Description: "冲突数", Example: "1", Events: "digest"},
+		{Name: "EventCounts", Description: "按事件计数列表，元素含 EventType / EventLabel / Count", Example: "{{range .EventCounts}}...{{end}}", Events: "digest"},
+		{Name: "Failures", Description: "失败与冲突明细，元素含 TaskName / RepoName / EventType / EventLabel / StatusText / ErrorMessage / Timestamp", Example: "{{range .Failures}}...{{end}}", Events: "digest"},
+		{Name: "Entries", Description: "全部事件明细，字段同 Failures", Example: "{{range .Entries}}...{{end}}", Events: "digest"},
 	}
 }
@@ -31,30 +31,19 @@ func (s *WebhookSender) Send(msg *NotificationMessage) error {
 		method = "POST"
 	}
 
-	contentType := s.config.ContentType
-	if contentType == "" {
-		contentType = "application/json"
+	payload, err := s.Payload(msg)
+	if err != nil {
+		return err
 	}
-
 	var body io.Reader
-
-	if contentType == "application/json" {
-		data, err := json.Marshal(msg)
+	if form, ok := payload.(string); ok {
+		body = strings.NewReader(form)
+	} else {
+		data, err := json.Marshal(payload)
 		if err != nil {
 			return err
 		}
 		body = bytes.NewBuffer(data)
-	} else if contentType == "application/x-www-form-urlencoded" {
-		form := url.Values{}
-		form.Set("title", msg.Title)
-		form.Set("content", msg.Content)
-		form.Set("status", msg.Status)
-		form.Set("task_key", msg.TaskKey)
-		form.Set("repo_key", msg.RepoKey)
-		body = strings.NewReader(form.Encode())
-	} else {
-		data, _ := json.Marshal(msg)
-		body = bytes.NewBuffer(data)
 	}
 
 	req, err := http.NewRequest(method, s.config.URL, body)
@@ -62,7 +51,7 @@ func (s *WebhookSender) Send(msg *NotificationMessage) error {
 		return err
 	}
 
-	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Content-Type", s.contentType())
 
 	// 添加自定义headers
 	for k, v := range s.config.Headers {
@@ -83,3 +72,24 @@ func (s *WebhookSender) Send(msg *NotificationMessage) error {
 
 	return nil
 }
+
+// Payload 构建请求体：表单格式返回编码后的字符串，其余为通知消息 JSON
+func (s *WebhookSender) Payload(msg *NotificationMessage) (interface{}, error) {
+	if s.contentType() == "application/x-www-form-urlencoded" {
+		form := url.Values{}
+		form.Set("title", msg.Title)
+		form.Set("content", msg.Content)
+		form.Set("status", msg.Status)
+		form.Set("task_key", msg.TaskKey)
+		form.Set("repo_key", msg.RepoKey)
+		return form.Encode(), nil
+	}
+	return msg, nil
+}
+
+func (s *WebhookSender) contentType() string {
+	if s.config.ContentType == "" {
+		return "application/json"
+	}
+	return s.config.ContentType
+}
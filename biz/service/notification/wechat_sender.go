@@ -24,8 +24,9 @@ func NewWeChatSender(config *po.WeChatConfig) *WeChatSender {
 
 // WeChatMessage 企业微信消息
 type WeChatMessage struct {
-	MsgType  string         `json:"msgtype"`
-	Markdown WeChatMarkdown `json:"markdown"`
+	MsgType  string          `json:"msgtype"`
+	Markdown *WeChatMarkdown `json:"markdown,omitempty"`
+	Text     *WeChatText     `json:"text,omitempty"`
 }
 
 // WeChatMarkdown 企业微信Markdown消息
@@ -33,26 +34,19 @@ type WeChatMarkdown struct {
 	Content string `json:"content"`
 }
 
+// WeChatText 企业微信文本消息，markdown 消息不支持 mentioned_list，有提及时改用文本消息
+type WeChatText struct {
+	Content             string   `json:"content"`
+	MentionedList       []string `json:"mentioned_list,omitempty"`
+	MentionedMobileList []string `json:"mentioned_mobile_list,omitempty"`
+}
+
 // Send 发送企业微信消息
 func (s *WeChatSender) Send(msg *NotificationMessage) error {
-	// 构建消息
-	statusColor := "info"
-	statusEmoji := "✅"
-	if msg.Status == "failure" {
-		statusColor = "warning"
-		statusEmoji = "❌"
-	}
-
-	content := fmt.Sprintf("### %s %s\n%s\n> <font color=\"%s\">Task:</font> %s\n> <font color=\"%s\">Repo:</font> %s",
-		statusEmoji, msg.Title, msg.Content, statusColor, msg.TaskKey, statusColor, msg.RepoKey)
-
-	wechatMsg := WeChatMessage{
-		MsgType: "markdown",
-		Markdown: WeChatMarkdown{
-			Content: content,
-		},
+	wechatMsg, err := s.Payload(msg)
+	if err != nil {
+		return err
 	}
-
 	body, err := json.Marshal(wechatMsg)
 	if err != nil {
 		return err
@@ -82,3 +76,51 @@ func (s *WeChatSender) Send(msg *NotificationMessage) error {
 
 	return nil
 }
+
+// Payload 构建企业微信消息体：默认 Markdown，有提及时为文本消息
+func (s *WeChatSender) Payload(msg *NotificationMessage) (interface{}, error) {
+	if len(msg.Mentions) > 0 {
+		return WeChatMessage{
+			MsgType: "text",
+			Text:    weChatText(msg),
+		}, nil
+	}
+
+	// 构建消息
+	statusColor := "info"
+	statusEmoji := "✅"
+	if msg.Status == "failure" {
+		statusColor = "warning"
+		statusEmoji = "❌"
+	}
+
+	content := fmt.Sprintf("### %s %s\n%s\n> <font color=\"%s\">Task:</font> %s\n> <font color=\"%s\">Repo:</font> %s",
+		statusEmoji, msg.Title, msg.Content, statusColor, msg.TaskKey, statusColor, msg.RepoKey)
+
+	return WeChatMessage{
+		MsgType: "markdown",
+		Markdown: &WeChatMarkdown{
+			Content: content,
+		},
+	}, nil
+}
+
+// weChatText 构建带原生提及的文本消息
+func weChatText(msg *NotificationMessage) *WeChatText {
+	statusEmoji := "✅"
+	if msg.Status == "failure" {
+		statusEmoji = "❌"
+	}
+	text := &WeChatText{
+		Content: fmt.Sprintf("%s %s\n%s\nTask: %s\nRepo: %s",
+			statusEmoji, msg.Title, msg.Content, msg.TaskKey, msg.RepoKey),
+	}
+	for _, m := range msg.Mentions {
+		if m.UserID != "" {
+			text.MentionedList = append(text.MentionedList, m.UserID)
+		} else if m.Mobile != "" {
+			text.MentionedMobileList = append(text.MentionedMobileList, m.Mobile)
+		}
+	}
+	return text
+}
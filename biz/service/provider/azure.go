@@ -0,0 +1,848 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Azure DevOps REST 版本（Azure DevOps Server 2022 及以上支持）
+const azureAPIVersion = "7.0"
+
+// azureHookUsername 创建服务挂钩时使用的 Basic 认证用户名，密码为 webhook 密钥
+const azureHookUsername = "git-manage-service"
+
+// azureZeroObjectID 分支/标签创建或删除时 refUpdates 中的空提交
+const azureZeroObjectID = "0000000000000000000000000000000000000000"
+
+// azureProvider Azure DevOps Services / Server。baseURL 为组织（或集合）地址，
+// owner 对应项目、repo 对应仓库；凭证为 PAT，以 Basic 认证发送。
+type azureProvider struct {
+	baseURL  string
+	username string
+	token    string
+	api      *apiClient
+}
+
+func NewAzureProvider(baseURL, username, token string) *azureProvider {
+	a := &azureProvider{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		username: username,
+		token:    token,
+	}
+	// PAT 以 Basic 认证发送，用户名可为空
+	a.api = newAPIClient("Azure DevOps", a.baseURL, func(req *http.Request) {
+		req.SetBasicAuth(username, token)
+	})
+	return a
+}
+
+func (a *azureProvider) Platform() Platform { return PlatformAzure }
+
+func (a *azureProvider) TestConnection(ctx context.Context) (*TestConnectionResult, error) {
+	var data struct {
+		AuthenticatedUser struct {
+			ProviderDisplayName string `json:"providerDisplayName"`
+		} `json:"authenticatedUser"`
+	}
+	if err := a.doRequest(ctx, "GET", "/_apis/connectionData?api-version="+azureAPIVersion+"-preview", nil, &data); err != nil {
+		return &TestConnectionResult{Connected: false, Message: err.Error()}, nil
+	}
+	return &TestConnectionResult{Connected: true, Platform: string(a.Platform()), UserName: data.AuthenticatedUser.ProviderDisplayName}, nil
+}
+
+// ListRepos 仓库列表接口不支持分页，取全量后在本地分页
+func (a *azureProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([]*PlatformRepo, error) {
+	if opts.Page == 0 {
+		opts.Page = 1
+	}
+	if opts.PerPage == 0 {
+		opts.PerPage = 20
+	}
+	path := "/_apis/git/repositories"
+	if opts.Owner != "" {
+		path = "/" + url.PathEscape(opts.Owner) + path
+	}
+	var list struct {
+		Value []azureRepo `json:"value"`
+	}
+	if err := a.doRequest(ctx, "GET", path, nil, &list); err != nil {
+		return nil, err
+	}
+	// 仓库接口不分页，一次返回项目下全部仓库
+	start := (opts.Page - 1) * opts.PerPage
+	if opts.All {
+		start, opts.PerPage = 0, len(list.Value)
+	}
+	if start >= len(list.Value) {
+		return []*PlatformRepo{}, nil
+	}
+	end := start + opts.PerPage
+	if end > len(list.Value) {
+		end = len(list.Value)
+	}
+	result := make([]*PlatformRepo, 0, end-start)
+	for i := start; i < end; i++ {
+		result = append(result, list.Value[i].toRepo())
+	}
+	return result, nil
+}
+
+func (a *azureProvider) GetRepo(ctx context.Context, owner, repo string) (*PlatformRepo, error) {
+	r, err := a.getRepo(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return r.toRepo(), nil
+}
+
+// CreateRepo 在项目 Owner 下创建仓库；可见性属于项目级设置，空仓库的默认分支由首次推送决定
+func (a *azureProvider) CreateRepo(ctx context.Context, opts CreateRepoOptions) (*PlatformRepo, error) {
+	if opts.Owner == "" {
+		return nil, fmt.Errorf("owner (project) is required for Azure DevOps")
+	}
+	var project struct {
+		ID string `json:"id"`
+	}
+	if err := a.doRequest(ctx, "GET", "/_apis/projects/"+url.PathEscape(opts.Owner), nil, &project); err != nil {
+		return nil, fmt.Errorf("project %s not found: %w", opts.Owner, err)
+	}
+	body := map[string]interface{}{
+		"name":    opts.Name,
+		"project": map[string]string{"id": project.ID},
+	}
+	var r azureRepo
+	if err := a.doRequest(ctx, "POST", "/"+url.PathEscape(opts.Owner)+"/_apis/git/repositories", body, &r); err != nil {
+		return nil, err
+	}
+	return r.toRepo(), nil
+}
+
+func (a *azureProvider) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*BranchProtection, error) {
+	return nil, unsupportedOp(PlatformAzure, "GetBranchProtection")
+}
+
+func (a *azureProvider) SetBranchProtection(ctx context.Context, owner, repo string, protection BranchProtection) (*BranchProtection, error) {
+	return nil, unsupportedOp(PlatformAzure, "SetBranchProtection")
+}
+
+func (a *azureProvider) getRepo(ctx context.Context, owner, repo string) (*azureRepo, error) {
+	var r azureRepo
+	if err := a.doRequest(ctx, "GET", azureRepoPath(owner, repo, ""), nil, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (a *azureProvider) CreateCR(ctx context.Context, opts CreateCROptions) (*ChangeRequest, error) {
+	body := map[string]interface{}{
+		"title":         opts.Title,
+		"description":   opts.Description,
+		"sourceRefName": "refs/heads/" + opts.SourceBranch,
+		"targetRefName": "refs/heads/" + opts.TargetBranch,
+	}
+	if len(opts.Labels) > 0 {
+		labels := make([]map[string]string, 0, len(opts.Labels))
+		for _, l := range opts.Labels {
+			labels = append(labels, map[string]string{"name": l})
+		}
+		body["labels"] = labels
+	}
+	if opts.RemoveSourceBranch {
+		body["completionOptions"] = map[string]interface{}{"deleteSourceBranch": true}
+	}
+	var pr azurePR
+	if err := a.doRequest(ctx, "POST", azureRepoPath(opts.Owner, opts.Repo, "/pullrequests"), body, &pr); err != nil {
+		return nil, err
+	}
+	return pr.toCR(a.baseURL), nil
+}
+
+func (a *azureProvider) GetCR(ctx context.Context, owner, repo string, number int) (*ChangeRequest, error) {
+	pr, err := a.getPR(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return pr.toCR(a.baseURL), nil
+}
+
+func (a *azureProvider) getPR(ctx context.Context, owner, repo string, number int) (*azurePR, error) {
+	var pr azurePR
+	if err := a.doRequest(ctx, "GET", azureRepoPath(owner, repo, fmt.Sprintf("/pullrequests/%d", number)), nil, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+func (a *azureProvider) ListCRs(ctx context.Context, opts ListCROptions) ([]*ChangeRequest, int, error) {
+	if opts.Page == 0 {
+		opts.Page = 1
+	}
+	if opts.PerPage == 0 {
+		opts.PerPage = 20
+	}
+	q := url.Values{}
+	q.Set("$skip", fmt.Sprint((opts.Page-1)*opts.PerPage))
+	q.Set("$top", fmt.Sprint(opts.PerPage))
+	q.Set("searchCriteria.status", mapCRStateToAzure(opts.State))
+	if opts.SourceBranch != "" {
+		q.Set("searchCriteria.sourceRefName", "refs/heads/"+opts.SourceBranch)
+	}
+	if opts.TargetBranch != "" {
+		q.Set("searchCriteria.targetRefName", "refs/heads/"+opts.TargetBranch)
+	}
+	var list struct {
+		Value []azurePR `json:"value"`
+	}
+	if err := a.doRequest(ctx, "GET", azureRepoPath(opts.Owner, opts.Repo, "/pullrequests?"+q.Encode()), nil, &list); err != nil {
+		return nil, 0, err
+	}
+	crs := make([]*ChangeRequest, 0, len(list.Value))
+	for i := range list.Value {
+		crs = append(crs, list.Value[i].toCR(a.baseURL))
+	}
+	return crs, len(crs), nil
+}
+
+// MergeCR 完成 PR；需携带当前 lastMergeSourceCommit，合并由服务端异步执行
+func (a *azureProvider) MergeCR(ctx context.Context, owner, repo string, number int, opts MergeCROptions) (*ChangeRequest, error) {
+	pr, err := a.getPR(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	completion := map[string]interface{}{
+		"mergeStrategy":      "noFastForward",
+		"deleteSourceBranch": opts.RemoveSourceBranch,
+	}
+	if opts.Squash {
+		completion["mergeStrategy"] = "squash"
+	}
+	if opts.MergeCommitMessage != "" {
+		completion["mergeCommitMessage"] = opts.MergeCommitMessage
+	}
+	body := map[string]interface{}{
+		"status":                "completed",
+		"lastMergeSourceCommit": map[string]string{"commitId": pr.LastMergeSourceCommit.CommitID},
+		"completionOptions":     completion,
+	}
+	var completed azurePR
+	if err := a.doRequest(ctx, "PATCH", azureRepoPath(owner, repo, fmt.Sprintf("/pullrequests/%d", number)), body, &completed); err != nil {
+		return nil, err
+	}
+	return completed.toCR(a.baseURL), nil
+}
+
+func (a *azureProvider) CloseCR(ctx context.Context, owner, repo string, number int) (*ChangeRequest, error) {
+	var abandoned azurePR
+	body := map[string]interface{}{"status": "abandoned"}
+	if err := a.doRequest(ctx, "PATCH", azureRepoPath(owner, repo, fmt.Sprintf("/pullrequests/%d", number)), body, &abandoned); err != nil {
+		return nil, err
+	}
+	return abandoned.toCR(a.baseURL), nil
+}
+
+func (a *azureProvider) UpdateCR(ctx context.Context, owner, repo string, number int, opts UpdateCROptions) (*ChangeRequest, error) {
+	body := map[string]interface{}{}
+	if opts.Title != "" {
+		body["title"] = opts.Title
+	}
+	if opts.Description != "" {
+		body["description"] = opts.Description
+	}
+	var updated azurePR
+	if err := a.doRequest(ctx, "PATCH", azureRepoPath(owner, repo, fmt.Sprintf("/pullrequests/%d", number)), body, &updated); err != nil {
+		return nil, err
+	}
+	return updated.toCR(a.baseURL), nil
+}
+
+// CreateWebhook 为每种事件创建一个服务挂钩订阅（Web Hooks 消费者），密钥作为 Basic 认证密码；
+// 同一仓库、同一回调地址的订阅视为一个 webhook，ID 由回调地址计算
+// ListCRComments 列出各评论线程中的评论，忽略系统生成的评论
+func (a *azureProvider) ListCRComments(ctx context.Context, owner, repo string, number int) ([]*CRComment, error) {
+	var list struct {
+		Value []azureThread `json:"value"`
+	}
+	if err := a.doRequest(ctx, "GET", azureRepoPath(owner, repo, fmt.Sprintf("/pullRequests/%d/threads", number)), nil, &list); err != nil {
+		return nil, err
+	}
+	var result []*CRComment
+	for i := range list.Value {
+		result = append(result, list.Value[i].toComments()...)
+	}
+	sortComments(result)
+	return result, nil
+}
+
+// AddCRComment 每条评论新建一个线程，行内评论带 threadContext
+func (a *azureProvider) AddCRComment(ctx context.Context, owner, repo string, number int, opts AddCRCommentOptions) (*CRComment, error) {
+	body := map[string]interface{}{
+		"comments": []map[string]interface{}{{"parentCommentId": 0, "content": opts.Body, "commentType": 1}},
+		"status":   1, // active
+	}
+	if opts.inline() {
+		path := opts.Path
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		pos := map[string]int{"line": opts.Line, "offset": 1}
+		body["threadContext"] = map[string]interface{}{"filePath": path, "rightFileStart": pos, "rightFileEnd": pos}
+	}
+	var thread azureThread
+	if err := a.doRequest(ctx, "POST", azureRepoPath(owner, repo, fmt.Sprintf("/pullRequests/%d/threads", number)), body, &thread); err != nil {
+		return nil, err
+	}
+	if comments := thread.toComments(); len(comments) > 0 {
+		return comments[0], nil
+	}
+	return &CRComment{Body: opts.Body, Path: opts.Path, Line: opts.Line}, nil
+}
+
+// ReviewCR 以当前用户身份投票：批准 10，要求修改为"等待作者" -5
+func (a *azureProvider) ReviewCR(ctx context.Context, owner, repo string, number int, opts ReviewCROptions) error {
+	var data struct {
+		AuthenticatedUser struct {
+			ID string `json:"id"`
+		} `json:"authenticatedUser"`
+	}
+	if err := a.doRequest(ctx, "GET", "/_apis/connectionData?api-version="+azureAPIVersion+"-preview", nil, &data); err != nil {
+		return err
+	}
+	vote := 10
+	if opts.Action == ReviewRequestChanges {
+		vote = -5
+	}
+	path := azureRepoPath(owner, repo, fmt.Sprintf("/pullRequests/%d/reviewers/%s", number, url.PathEscape(data.AuthenticatedUser.ID)))
+	if err := a.doRequest(ctx, "PUT", path, map[string]interface{}{"vote": vote}, nil); err != nil {
+		return err
+	}
+	if opts.Body == "" {
+		return nil
+	}
+	_, err := a.AddCRComment(ctx, owner, repo, number, AddCRCommentOptions{Body: opts.Body})
+	return err
+}
+
+func (a *azureProvider) GetCommitStatuses(ctx context.Context, owner, repo, sha string) ([]*CommitStatus, error) {
+	var list struct {
+		Value []struct {
+			State       string `json:"state"` // notSet | pending | succeeded | failed | error | notApplicable
+			Description string `json:"description"`
+			TargetURL   string `json:"targetUrl"`
+			Context     struct {
+				Name  string `json:"name"`
+				Genre string `json:"genre"`
+			} `json:"context"`
+		} `json:"value"`
+	}
+	path := azureRepoPath(owner, repo, "/commits/"+url.PathEscape(sha)+"/statuses?latestOnly=true")
+	if err := a.doRequest(ctx, "GET", path, nil, &list); err != nil {
+		return nil, err
+	}
+	result := make([]*CommitStatus, 0, len(list.Value))
+	for _, st := range list.Value {
+		state := CheckStatePending
+		switch st.State {
+		case "succeeded", "notApplicable":
+			state = CheckStateSuccess
+		case "failed", "error":
+			state = CheckStateFailure
+		}
+		name := st.Context.Name
+		if st.Context.Genre != "" {
+			name = st.Context.Genre + "/" + name
+		}
+		result = append(result, &CommitStatus{Name: name, State: state, Description: st.Description, TargetURL: st.TargetURL})
+	}
+	return result, nil
+}
+
+func (a *azureProvider) CreateRelease(ctx context.Context, opts CreateReleaseOptions) (*Release, error) {
+	return nil, unsupportedOp(PlatformAzure, "CreateRelease")
+}
+
+func (a *azureProvider) UpdateRelease(ctx context.Context, owner, repo, tag string, opts UpdateReleaseOptions) (*Release, error) {
+	return nil, unsupportedOp(PlatformAzure, "UpdateRelease")
+}
+
+func (a *azureProvider) ListReleases(ctx context.Context, owner, repo string) ([]*Release, error) {
+	return nil, unsupportedOp(PlatformAzure, "ListReleases")
+}
+
+func (a *azureProvider) UploadReleaseAsset(ctx context.Context, owner, repo, tag string, asset ReleaseAssetUpload) (*ReleaseAsset, error) {
+	return nil, unsupportedOp(PlatformAzure, "UploadReleaseAsset")
+}
+
+func (a *azureProvider) CreateWebhook(ctx context.Context, opts CreateWebhookOptions) (*PlatformWebhook, error) {
+	repo, err := a.getRepo(ctx, opts.Owner, opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+	consumerInputs := map[string]string{
+		"url":                    opts.URL,
+		"resourceDetailsToSend":  "all",
+		"messagesToSend":         "none",
+		"detailedMessagesToSend": "none",
+	}
+	if opts.Secret != "" {
+		consumerInputs["basicAuthUsername"] = azureHookUsername
+		consumerInputs["basicAuthPassword"] = opts.Secret
+	}
+	events := azureWebhookEvents(opts.Events)
+	var created []string
+	for _, eventType := range events {
+		body := map[string]interface{}{
+			"publisherId":      "tfs",
+			"eventType":        eventType,
+			"resourceVersion":  "1.0",
+			"consumerId":       "webHooks",
+			"consumerActionId": "httpRequest",
+			"publisherInputs":  map[string]string{"projectId": repo.Project.ID, "repository": repo.ID},
+			"consumerInputs":   consumerInputs,
+		}
+		var sub azureSubscription
+		if err := a.doRequest(ctx, "POST", "/_apis/hooks/subscriptions", body, &sub); err != nil {
+			// 回滚已创建的订阅，避免残留半套 webhook
+			for _, id := range created {
+				_ = a.doRequest(ctx, "DELETE", "/_apis/hooks/subscriptions/"+id, nil, nil)
+			}
+			return nil, err
+		}
+		created = append(created, sub.ID)
+	}
+	return &PlatformWebhook{ID: azureHookID(opts.URL), URL: opts.URL, Events: events}, nil
+}
+
+func (a *azureProvider) DeleteWebhook(ctx context.Context, owner, repo string, webhookID int64) error {
+	subs, err := a.listSubscriptions(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, sub := range subs {
+		if azureHookID(sub.ConsumerInputs.URL) != webhookID {
+			continue
+		}
+		found = true
+		if err := a.doRequest(ctx, "DELETE", "/_apis/hooks/subscriptions/"+sub.ID, nil, nil); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return fmt.Errorf("webhook %d not found", webhookID)
+	}
+	return nil
+}
+
+func (a *azureProvider) ListWebhooks(ctx context.Context, owner, repo string) ([]*PlatformWebhook, error) {
+	subs, err := a.listSubscriptions(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	byURL := map[string]*PlatformWebhook{}
+	result := make([]*PlatformWebhook, 0)
+	for _, sub := range subs {
+		wh, ok := byURL[sub.ConsumerInputs.URL]
+		if !ok {
+			wh = &PlatformWebhook{ID: azureHookID(sub.ConsumerInputs.URL), URL: sub.ConsumerInputs.URL}
+			byURL[wh.URL] = wh
+			result = append(result, wh)
+		}
+		wh.Events = append(wh.Events, sub.EventType)
+	}
+	return result, nil
+}
+
+// listSubscriptions 返回指向该仓库的 Web Hooks 订阅
+func (a *azureProvider) listSubscriptions(ctx context.Context, owner, repo string) ([]azureSubscription, error) {
+	r, err := a.getRepo(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Value []azureSubscription `json:"value"`
+	}
+	if err := a.doRequest(ctx, "GET", "/_apis/hooks/subscriptions?publisherId=tfs&consumerId=webHooks", nil, &list); err != nil {
+		return nil, err
+	}
+	var result []azureSubscription
+	for _, sub := range list.Value {
+		if strings.EqualFold(sub.PublisherInputs.Repository, r.ID) {
+			result = append(result, sub)
+		}
+	}
+	return result, nil
+}
+
+// azureHookID 服务挂钩订阅 ID 为 GUID，按回调地址计算稳定的数字 ID
+func azureHookID(callbackURL string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(callbackURL))
+	return int64(h.Sum64() & math.MaxInt64)
+}
+
+// azureWebhookEvents 将通用事件名（push / cr / tag）转换为服务挂钩事件类型
+func azureWebhookEvents(events []string) []string {
+	if len(events) == 0 {
+		events = []string{"push", "cr"}
+	}
+	seen := map[string]bool{}
+	var result []string
+	add := func(types ...string) {
+		for _, t := range types {
+			if !seen[t] {
+				seen[t] = true
+				result = append(result, t)
+			}
+		}
+	}
+	for _, e := range events {
+		switch e {
+		case "push", "tag":
+			add("git.push")
+		case "cr", "pull_request", "merge_request":
+			add("git.pullrequest.created", "git.pullrequest.updated", "git.pullrequest.merged")
+		default:
+			add(e)
+		}
+	}
+	return result
+}
+
+func (a *azureProvider) ParseWebhookEvent(r *http.Request, secret string) (*NormalizedEvent, error) {
+	if !signatureCheckSkipped(r) {
+		if err := a.ValidateWebhookSignature(r, secret); err != nil {
+			return nil, err
+		}
+	}
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return ParseAzureEvent(body)
+}
+
+// ValidateWebhookSignature 服务挂钩不签名，配置了密钥时校验 Basic 认证密码
+func (a *azureProvider) ValidateWebhookSignature(r *http.Request, secret string) error {
+	if secret == "" {
+		return nil
+	}
+	_, password, ok := r.BasicAuth()
+	if !ok || subtle.ConstantTimeCompare([]byte(password), []byte(secret)) != 1 {
+		return fmt.Errorf("invalid Azure DevOps service hook credentials")
+	}
+	return nil
+}
+
+// ParseAzureEvent 将服务挂钩推送的 JSON 事件转换为 NormalizedEvent
+func ParseAzureEvent(data []byte) (*NormalizedEvent, error) {
+	var pl struct {
+		ID          string          `json:"id"`
+		EventType   string          `json:"eventType"`
+		CreatedDate time.Time       `json:"createdDate"`
+		Resource    json.RawMessage `json:"resource"`
+	}
+	if err := json.Unmarshal(data, &pl); err != nil {
+		return nil, err
+	}
+	if pl.EventType == "" {
+		return nil, fmt.Errorf("missing eventType in Azure DevOps payload")
+	}
+	id := pl.ID
+	if id == "" {
+		id = fmt.Sprint(time.Now().UnixNano())
+	}
+	ts := pl.CreatedDate
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	event := &NormalizedEvent{ID: "azure-" + id, Source: PlatformAzure, Timestamp: ts}
+
+	switch {
+	case pl.EventType == "git.push":
+		var push struct {
+			RefUpdates []struct {
+				Name        string `json:"name"`
+				OldObjectID string `json:"oldObjectId"`
+				NewObjectID string `json:"newObjectId"`
+			} `json:"refUpdates"`
+			Repository azureRepo     `json:"repository"`
+			PushedBy   azureIdentity `json:"pushedBy"`
+		}
+		if err := json.Unmarshal(pl.Resource, &push); err != nil {
+			return nil, err
+		}
+		event.Type = "push"
+		event.Actor = push.PushedBy.toUser()
+		event.Repo = push.Repository.toEventRepo()
+		if len(push.RefUpdates) > 0 {
+			ref := push.RefUpdates[0]
+			created, deleted := ref.OldObjectID == azureZeroObjectID, ref.NewObjectID == azureZeroObjectID
+			if strings.HasPrefix(ref.Name, "refs/tags/") {
+				event.Tag = strings.TrimPrefix(ref.Name, "refs/tags/")
+				event.Type = "tag.created"
+				if deleted {
+					event.Type = "tag.deleted"
+				}
+			} else {
+				event.Branch = strings.TrimPrefix(ref.Name, "refs/heads/")
+				switch {
+				case created:
+					event.Type = "branch.created"
+				case deleted:
+					event.Type = "branch.deleted"
+				}
+			}
+		}
+	case strings.HasPrefix(pl.EventType, "git.pullrequest."):
+		var pr azurePR
+		if err := json.Unmarshal(pl.Resource, &pr); err != nil {
+			return nil, err
+		}
+		// updated / merged 事件按 PR 当前状态细分；merged 仅表示合并尝试，未完成时视为更新
+		switch {
+		case pr.Status == "completed":
+			event.Type = "cr.merged"
+		case pr.Status == "abandoned":
+			event.Type = "cr.closed"
+		case pl.EventType == "git.pullrequest.created":
+			event.Type = "cr.opened"
+		default:
+			event.Type = "cr.updated"
+		}
+		event.CR = pr.toCR(pr.Repository.baseURL())
+		event.Repo = pr.Repository.toEventRepo()
+		event.Actor = pr.ClosedBy.toUser()
+		if event.Actor == nil {
+			event.Actor = event.CR.Author
+		}
+	default:
+		event.Type = pl.EventType
+	}
+	return event, nil
+}
+
+func (a *azureProvider) doRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	if !strings.Contains(path, "api-version=") {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		path += sep + "api-version=" + azureAPIVersion
+	}
+	resp, err := a.api.do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	// PAT 无效时服务端可能 203 重定向到登录页而不是返回 401
+	if resp.StatusCode == http.StatusNonAuthoritativeInfo {
+		apiErr := a.api.newError(method, path, resp)
+		apiErr.kind = ErrUnauthorized
+		return apiErr
+	}
+	return resp.decode(result)
+}
+
+func azureRepoPath(owner, repo, suffix string) string {
+	return fmt.Sprintf("/%s/_apis/git/repositories/%s%s", url.PathEscape(owner), url.PathEscape(repo), suffix)
+}
+
+type azureIdentity struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+	ImageURL    string `json:"imageUrl"`
+}
+
+func (i *azureIdentity) toUser() *CRUser {
+	if i.UniqueName == "" && i.DisplayName == "" {
+		return nil
+	}
+	return &CRUser{Username: i.UniqueName, Name: i.DisplayName, AvatarURL: i.ImageURL}
+}
+
+type azureRepo struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Project struct {
+		ID         string `json:"id"`
+		Name       string `json:"name"`
+		Visibility string `json:"visibility"`
+	} `json:"project"`
+	DefaultBranch string `json:"defaultBranch"`
+	IsDisabled    bool   `json:"isDisabled"`
+	RemoteURL     string `json:"remoteUrl"`
+	SSHURL        string `json:"sshUrl"`
+	WebURL        string `json:"webUrl"`
+}
+
+func (r *azureRepo) toRepo() *PlatformRepo {
+	return &PlatformRepo{
+		FullName: r.Project.Name + "/" + r.Name, Name: r.Name, Owner: r.Project.Name,
+		CloneURL: r.RemoteURL, SSHURL: r.SSHURL,
+		DefaultBranch: strings.TrimPrefix(r.DefaultBranch, "refs/heads/"),
+		Private:       r.Project.Visibility != "public", Platform: PlatformAzure,
+		Visibility: r.Project.Visibility, Archived: r.IsDisabled,
+	}
+}
+
+func (r *azureRepo) toEventRepo() *EventRepo {
+	if r.Name == "" {
+		return nil
+	}
+	return &EventRepo{FullName: r.Project.Name + "/" + r.Name, Owner: r.Project.Name, Name: r.Name}
+}
+
+// baseURL 从仓库网页地址 <org>/<project>/_git/<repo> 还原组织地址，供事件中拼接 PR 链接
+func (r *azureRepo) baseURL() string {
+	if i := strings.Index(r.WebURL, "/_git/"); i > 0 {
+		if j := strings.LastIndex(r.WebURL[:i], "/"); j > 0 {
+			return r.WebURL[:j]
+		}
+	}
+	return ""
+}
+
+type azureSubscription struct {
+	ID              string `json:"id"`
+	EventType       string `json:"eventType"`
+	PublisherInputs struct {
+		ProjectID  string `json:"projectId"`
+		Repository string `json:"repository"`
+	} `json:"publisherInputs"`
+	ConsumerInputs struct {
+		URL string `json:"url"`
+	} `json:"consumerInputs"`
+}
+
+type azureThread struct {
+	ID            int64 `json:"id"`
+	IsDeleted     bool  `json:"isDeleted"`
+	ThreadContext *struct {
+		FilePath       string `json:"filePath"`
+		RightFileStart *struct {
+			Line int `json:"line"`
+		} `json:"rightFileStart"`
+	} `json:"threadContext"`
+	Comments []struct {
+		ID            int64         `json:"id"`
+		Content       string        `json:"content"`
+		CommentType   string        `json:"commentType"` // text | system | codeChange
+		Author        azureIdentity `json:"author"`
+		PublishedDate time.Time     `json:"publishedDate"`
+		IsDeleted     bool          `json:"isDeleted"`
+	} `json:"comments"`
+}
+
+func (t *azureThread) toComments() []*CRComment {
+	if t.IsDeleted {
+		return nil
+	}
+	path, line := "", 0
+	if t.ThreadContext != nil {
+		path = strings.TrimPrefix(t.ThreadContext.FilePath, "/")
+		if t.ThreadContext.RightFileStart != nil {
+			line = t.ThreadContext.RightFileStart.Line
+		}
+	}
+	var result []*CRComment
+	for i, c := range t.Comments {
+		if c.IsDeleted || c.CommentType == "system" {
+			continue
+		}
+		result = append(result, &CRComment{
+			ID: c.ID, Body: c.Content, Path: path, Line: line,
+			Author: t.Comments[i].Author.toUser(), CreatedAt: c.PublishedDate,
+		})
+	}
+	return result
+}
+
+type azurePR struct {
+	PullRequestID int           `json:"pullRequestId"`
+	Status        string        `json:"status"` // active | completed | abandoned
+	Title         string        `json:"title"`
+	Description   string        `json:"description"`
+	SourceRefName string        `json:"sourceRefName"`
+	TargetRefName string        `json:"targetRefName"`
+	MergeStatus   string        `json:"mergeStatus"` // notSet | queued | conflicts | succeeded | rejectedByPolicy | failure
+	CreatedBy     azureIdentity `json:"createdBy"`
+	ClosedBy      azureIdentity `json:"closedBy"`
+	Reviewers     []struct {
+		azureIdentity
+		Vote int `json:"vote"`
+	} `json:"reviewers"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Repository            azureRepo `json:"repository"`
+	LastMergeSourceCommit struct {
+		CommitID string `json:"commitId"`
+	} `json:"lastMergeSourceCommit"`
+	CreationDate time.Time `json:"creationDate"`
+	ClosedDate   time.Time `json:"closedDate"`
+}
+
+func (pr *azurePR) toCR(baseURL string) *ChangeRequest {
+	cr := &ChangeRequest{
+		ID: int64(pr.PullRequestID), Number: pr.PullRequestID, Title: pr.Title, Description: pr.Description,
+		State:        mapAzureStatus(pr.Status),
+		SourceBranch: strings.TrimPrefix(pr.SourceRefName, "refs/heads/"),
+		TargetBranch: strings.TrimPrefix(pr.TargetRefName, "refs/heads/"),
+		Author:       pr.CreatedBy.toUser(),
+		MergeStatus:  "unknown",
+		HeadSHA:      pr.LastMergeSourceCommit.CommitID,
+		CreatedAt:    pr.CreationDate, UpdatedAt: pr.CreationDate,
+	}
+	if !pr.ClosedDate.IsZero() {
+		cr.UpdatedAt = pr.ClosedDate
+	}
+	switch pr.MergeStatus {
+	case "succeeded":
+		cr.MergeStatus = "mergeable"
+	case "conflicts":
+		cr.MergeStatus = "conflicting"
+	}
+	for i := range pr.Reviewers {
+		if u := pr.Reviewers[i].toUser(); u != nil {
+			cr.Reviewers = append(cr.Reviewers, u)
+		}
+	}
+	for _, l := range pr.Labels {
+		cr.Labels = append(cr.Labels, l.Name)
+	}
+	if baseURL != "" && pr.Repository.Name != "" {
+		cr.WebURL = fmt.Sprintf("%s/%s/_git/%s/pullrequest/%d", baseURL,
+			url.PathEscape(pr.Repository.Project.Name), url.PathEscape(pr.Repository.Name), pr.PullRequestID)
+	}
+	return cr
+}
+
+func mapAzureStatus(status string) CRState {
+	switch status {
+	case "completed":
+		return CRStateMerged
+	case "abandoned":
+		return CRStateClosed
+	}
+	return CRStateOpened
+}
+
+func mapCRStateToAzure(state CRState) string {
+	switch state {
+	case CRStateMerged:
+		return "completed"
+	case CRStateClosed:
+		return "abandoned"
+	case CRStateOpened:
+		return "active"
+	}
+	return "all"
+}
@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDetectAzure(t *testing.T) {
+	cases := []struct {
+		url, owner, repo, base string
+	}{
+		{"https://dev.azure.com/acme/Platform/_git/tools", "Platform", "tools", "https://dev.azure.com/acme"},
+		{"https://acme@dev.azure.com/acme/My%20Project/_git/tools", "My Project", "tools", "https://dev.azure.com/acme"},
+		{"https://dev.azure.com/acme/_git/tools", "tools", "tools", "https://dev.azure.com/acme"},
+		{"git@ssh.dev.azure.com:v3/acme/Platform/tools", "Platform", "tools", "https://dev.azure.com/acme"},
+		{"https://acme.visualstudio.com/DefaultCollection/Platform/_git/tools", "Platform", "tools", "https://acme.visualstudio.com"},
+		{"acme@vs-ssh.visualstudio.com:v3/acme/Platform/tools", "Platform", "tools", "https://acme.visualstudio.com"},
+	}
+	for _, tc := range cases {
+		r, err := DetectPlatform(tc.url)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.url, err)
+		}
+		if r.Platform != PlatformAzure || r.Owner != tc.owner || r.Repo != tc.repo || r.BaseURL != tc.base {
+			t.Errorf("%s: got %+v", tc.url, r)
+		}
+	}
+	if r, _ := DetectPlatform("git@github.com:acme/tools.git"); r.Platform != PlatformGitHub {
+		t.Errorf("github URL detected as %s", r.Platform)
+	}
+}
+
+func TestAzureMergeCRSendsLastMergeSourceCommit(t *testing.T) {
+	var patched map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, p, ok := r.BasicAuth(); !ok || p != "pat" || r.URL.Query().Get("api-version") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/Platform/_apis/git/repositories/tools/pullrequests/7" {
+			http.NotFound(w, r)
+			return
+		}
+		pr := `{"pullRequestId":7,"status":"active","sourceRefName":"refs/heads/feat","targetRefName":"refs/heads/main",
+			"lastMergeSourceCommit":{"commitId":"abc123"},"repository":{"name":"tools","project":{"name":"Platform"}}}`
+		if r.Method == "PATCH" {
+			json.NewDecoder(r.Body).Decode(&patched)
+			pr = `{"pullRequestId":7,"status":"completed","repository":{"name":"tools","project":{"name":"Platform"}}}`
+		}
+		w.Write([]byte(pr))
+	}))
+	defer srv.Close()
+
+	cr, err := NewAzureProvider(srv.URL, "", "pat").MergeCR(context.Background(), "Platform", "tools", 7, MergeCROptions{Squash: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cr.State != CRStateMerged || cr.WebURL != srv.URL+"/Platform/_git/tools/pullrequest/7" {
+		t.Errorf("unexpected CR: %+v", cr)
+	}
+	commit, _ := patched["lastMergeSourceCommit"].(map[string]interface{})
+	completion, _ := patched["completionOptions"].(map[string]interface{})
+	if patched["status"] != "completed" || commit["commitId"] != "abc123" || completion["mergeStrategy"] != "squash" {
+		t.Errorf("unexpected PATCH body: %v", patched)
+	}
+}
+
+func TestParseAzureEvent(t *testing.T) {
+	ev, err := ParseAzureEvent([]byte(`{"id":"n-1","eventType":"git.push","createdDate":"2024-05-01T08:00:00.1234567Z",
+		"resource":{"refUpdates":[{"name":"refs/heads/feat","oldObjectId":"0000000000000000000000000000000000000000","newObjectId":"abc"}],
+		"repository":{"name":"tools","project":{"name":"Platform"}},"pushedBy":{"displayName":"Alice","uniqueName":"alice@acme.com"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Type != "branch.created" || ev.Branch != "feat" || ev.Repo.FullName != "Platform/tools" || ev.Actor.Username != "alice@acme.com" {
+		t.Errorf("unexpected push event: %+v", ev)
+	}
+
+	ev, err = ParseAzureEvent([]byte(`{"id":"n-2","eventType":"git.pullrequest.updated",
+		"resource":{"pullRequestId":7,"status":"abandoned","sourceRefName":"refs/heads/feat","targetRefName":"refs/heads/main",
+		"createdBy":{"uniqueName":"alice@acme.com"},"closedBy":{"uniqueName":"bob@acme.com"},
+		"repository":{"name":"tools","project":{"name":"Platform"},"webUrl":"https://dev.azure.com/acme/Platform/_git/tools"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Type != "cr.closed" || ev.CR.Number != 7 || ev.Actor.Username != "bob@acme.com" ||
+		ev.CR.WebURL != "https://dev.azure.com/acme/Platform/_git/tools/pullrequest/7" {
+		t.Errorf("unexpected PR event: %+v %+v", ev, ev.CR)
+	}
+}
@@ -0,0 +1,766 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// bitbucketProvider Bitbucket Server / Data Center，baseURL 为服务根地址（不含 /rest/api/1.0）
+type bitbucketProvider struct {
+	baseURL string
+	token   string
+	api     *apiClient
+}
+
+func NewBitbucketProvider(baseURL, token string) *bitbucketProvider {
+	b := &bitbucketProvider{
+		baseURL: strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/rest/api/1.0"),
+		token:   token,
+	}
+	b.api = newAPIClient("Bitbucket", b.baseURL, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	})
+	return b
+}
+
+func (b *bitbucketProvider) Platform() Platform { return PlatformBitbucket }
+
+func (b *bitbucketProvider) TestConnection(ctx context.Context) (*TestConnectionResult, error) {
+	// Bitbucket Server 没有 /user 接口，已认证请求的响应头 X-AUSERNAME 即当前用户
+	header, err := b.do(ctx, "GET", "/projects?limit=1", nil, nil)
+	if err != nil {
+		return &TestConnectionResult{Connected: false, Message: err.Error()}, nil
+	}
+	return &TestConnectionResult{Connected: true, Platform: string(b.Platform()), UserName: header.Get("X-AUSERNAME")}, nil
+}
+
+func (b *bitbucketProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([]*PlatformRepo, error) {
+	if opts.Page == 0 {
+		opts.Page = 1
+	}
+	if opts.PerPage == 0 {
+		opts.PerPage = 20
+	}
+	path := "/repos"
+	if opts.Owner != "" {
+		path = fmt.Sprintf("/projects/%s/repos", url.PathEscape(opts.Owner))
+	}
+	start, limit := (opts.Page-1)*opts.PerPage, opts.PerPage
+	if opts.All {
+		start, limit = 0, 100
+	}
+	var result []*PlatformRepo
+	for pages := 0; ; pages++ {
+		if pages == apiMaxPages {
+			return nil, errPageLimit()
+		}
+		var page struct {
+			Values        []bitbucketRepo `json:"values"`
+			IsLastPage    bool            `json:"isLastPage"`
+			NextPageStart int             `json:"nextPageStart"`
+		}
+		if err := b.doRequest(ctx, "GET", fmt.Sprintf("%s?start=%d&limit=%d", path, start, limit), nil, &page); err != nil {
+			return nil, err
+		}
+		for i := range page.Values {
+			result = append(result, page.Values[i].toRepo())
+		}
+		if !opts.All || page.IsLastPage {
+			break
+		}
+		start = page.NextPageStart
+	}
+	return result, nil
+}
+
+func (b *bitbucketProvider) GetRepo(ctx context.Context, owner, repo string) (*PlatformRepo, error) {
+	var r bitbucketRepo
+	if err := b.doRequest(ctx, "GET", repoPath(owner, repo, ""), nil, &r); err != nil {
+		return nil, err
+	}
+	result := r.toRepo()
+	var branch struct {
+		DisplayID string `json:"displayId"`
+	}
+	// 空仓库没有默认分支，忽略错误
+	if err := b.doRequest(ctx, "GET", repoPath(owner, repo, "/branches/default"), nil, &branch); err == nil {
+		result.DefaultBranch = branch.DisplayID
+	}
+	return result, nil
+}
+
+// CreateRepo 在项目 Owner 下创建仓库（个人项目为 ~username），Bitbucket Server 无 internal，按 private 处理
+func (b *bitbucketProvider) CreateRepo(ctx context.Context, opts CreateRepoOptions) (*PlatformRepo, error) {
+	if opts.Owner == "" {
+		return nil, fmt.Errorf("owner (project key) is required for Bitbucket")
+	}
+	body := map[string]interface{}{
+		"name": opts.Name, "scmId": "git", "description": opts.Description,
+		"public": opts.visibility() == VisibilityPublic,
+	}
+	if opts.DefaultBranch != "" {
+		body["defaultBranch"] = opts.DefaultBranch
+	}
+	var r bitbucketRepo
+	if err := b.doRequest(ctx, "POST", fmt.Sprintf("/projects/%s/repos", url.PathEscape(opts.Owner)), body, &r); err != nil {
+		return nil, err
+	}
+	result := r.toRepo()
+	result.DefaultBranch = opts.DefaultBranch
+	return result, nil
+}
+
+func (b *bitbucketProvider) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*BranchProtection, error) {
+	return nil, unsupportedOp(PlatformBitbucket, "GetBranchProtection")
+}
+
+func (b *bitbucketProvider) SetBranchProtection(ctx context.Context, owner, repo string, protection BranchProtection) (*BranchProtection, error) {
+	return nil, unsupportedOp(PlatformBitbucket, "SetBranchProtection")
+}
+
+func (b *bitbucketProvider) CreateCR(ctx context.Context, opts CreateCROptions) (*ChangeRequest, error) {
+	repoRef := map[string]interface{}{
+		"slug":    opts.Repo,
+		"project": map[string]interface{}{"key": opts.Owner},
+	}
+	body := map[string]interface{}{
+		"title":       opts.Title,
+		"description": opts.Description,
+		"fromRef":     map[string]interface{}{"id": "refs/heads/" + opts.SourceBranch, "repository": repoRef},
+		"toRef":       map[string]interface{}{"id": "refs/heads/" + opts.TargetBranch, "repository": repoRef},
+	}
+	var pr bitbucketPR
+	if err := b.doRequest(ctx, "POST", repoPath(opts.Owner, opts.Repo, "/pull-requests"), body, &pr); err != nil {
+		return nil, err
+	}
+	return pr.toCR(), nil
+}
+
+func (b *bitbucketProvider) GetCR(ctx context.Context, owner, repo string, number int) (*ChangeRequest, error) {
+	pr, err := b.getPR(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return pr.toCR(), nil
+}
+
+func (b *bitbucketProvider) getPR(ctx context.Context, owner, repo string, number int) (*bitbucketPR, error) {
+	var pr bitbucketPR
+	if err := b.doRequest(ctx, "GET", repoPath(owner, repo, fmt.Sprintf("/pull-requests/%d", number)), nil, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+func (b *bitbucketProvider) ListCRs(ctx context.Context, opts ListCROptions) ([]*ChangeRequest, int, error) {
+	if opts.Page == 0 {
+		opts.Page = 1
+	}
+	if opts.PerPage == 0 {
+		opts.PerPage = 20
+	}
+	q := url.Values{}
+	q.Set("start", fmt.Sprint((opts.Page-1)*opts.PerPage))
+	q.Set("limit", fmt.Sprint(opts.PerPage))
+	if opts.State != "" {
+		q.Set("state", mapCRStateToBitbucket(opts.State))
+	}
+	if opts.TargetBranch != "" {
+		q.Set("at", "refs/heads/"+opts.TargetBranch)
+		q.Set("direction", "INCOMING")
+	}
+	var page struct {
+		Values []bitbucketPR `json:"values"`
+	}
+	if err := b.doRequest(ctx, "GET", repoPath(opts.Owner, opts.Repo, "/pull-requests?"+q.Encode()), nil, &page); err != nil {
+		return nil, 0, err
+	}
+	crs := make([]*ChangeRequest, 0, len(page.Values))
+	for i := range page.Values {
+		if opts.SourceBranch != "" && page.Values[i].FromRef.DisplayID != opts.SourceBranch {
+			continue
+		}
+		crs = append(crs, page.Values[i].toCR())
+	}
+	return crs, len(crs), nil
+}
+
+func (b *bitbucketProvider) MergeCR(ctx context.Context, owner, repo string, number int, opts MergeCROptions) (*ChangeRequest, error) {
+	// 合并与拒绝都需要携带 PR 当前版本号（乐观锁）
+	pr, err := b.getPR(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{}
+	if opts.MergeCommitMessage != "" {
+		body["message"] = opts.MergeCommitMessage
+	}
+	if opts.Squash {
+		body["strategyId"] = "squash"
+	}
+	var merged bitbucketPR
+	path := repoPath(owner, repo, fmt.Sprintf("/pull-requests/%d/merge?version=%d", number, pr.Version))
+	if err := b.doRequest(ctx, "POST", path, body, &merged); err != nil {
+		return nil, err
+	}
+	if opts.RemoveSourceBranch {
+		b.deleteBranch(ctx, owner, repo, pr.FromRef.ID)
+	}
+	return merged.toCR(), nil
+}
+
+// deleteBranch 合并后删除源分支，失败不影响合并结果
+func (b *bitbucketProvider) deleteBranch(ctx context.Context, owner, repo, ref string) {
+	path := fmt.Sprintf("/rest/branch-utils/1.0/projects/%s/repos/%s/branches", url.PathEscape(owner), url.PathEscape(repo))
+	_ = b.doRequest(ctx, "DELETE", path, map[string]interface{}{"name": ref, "dryRun": false}, nil)
+}
+
+func (b *bitbucketProvider) CloseCR(ctx context.Context, owner, repo string, number int) (*ChangeRequest, error) {
+	pr, err := b.getPR(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	var declined bitbucketPR
+	path := repoPath(owner, repo, fmt.Sprintf("/pull-requests/%d/decline?version=%d", number, pr.Version))
+	if err := b.doRequest(ctx, "POST", path, map[string]interface{}{}, &declined); err != nil {
+		return nil, err
+	}
+	return declined.toCR(), nil
+}
+
+// UpdateCR PUT 需带当前 version 与完整的 reviewers 列表，否则已有评审人会被移除
+func (b *bitbucketProvider) UpdateCR(ctx context.Context, owner, repo string, number int, opts UpdateCROptions) (*ChangeRequest, error) {
+	pr, err := b.getPR(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{
+		"version":     pr.Version,
+		"title":       pr.Title,
+		"description": pr.Description,
+		"reviewers":   pr.Reviewers,
+	}
+	if opts.Title != "" {
+		body["title"] = opts.Title
+	}
+	if opts.Description != "" {
+		body["description"] = opts.Description
+	}
+	var updated bitbucketPR
+	if err := b.doRequest(ctx, "PUT", repoPath(owner, repo, fmt.Sprintf("/pull-requests/%d", number)), body, &updated); err != nil {
+		return nil, err
+	}
+	return updated.toCR(), nil
+}
+
+// ListCRComments 从 PR 活动流中提取评论（不含回复）
+func (b *bitbucketProvider) ListCRComments(ctx context.Context, owner, repo string, number int) ([]*CRComment, error) {
+	var result []*CRComment
+	start := 0
+	for pages := 0; ; pages++ {
+		if pages == apiMaxPages {
+			return nil, errPageLimit()
+		}
+		var page struct {
+			Values []struct {
+				Action        string            `json:"action"`
+				Comment       *bitbucketComment `json:"comment"`
+				CommentAnchor *struct {
+					Path string `json:"path"`
+					Line int    `json:"line"`
+				} `json:"commentAnchor"`
+			} `json:"values"`
+			IsLastPage    bool `json:"isLastPage"`
+			NextPageStart int  `json:"nextPageStart"`
+		}
+		path := repoPath(owner, repo, fmt.Sprintf("/pull-requests/%d/activities?start=%d&limit=100", number, start))
+		if err := b.doRequest(ctx, "GET", path, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, act := range page.Values {
+			if act.Action != "COMMENTED" || act.Comment == nil {
+				continue
+			}
+			c := act.Comment.toComment()
+			if act.CommentAnchor != nil {
+				c.Path, c.Line = act.CommentAnchor.Path, act.CommentAnchor.Line
+			}
+			result = append(result, c)
+		}
+		if page.IsLastPage {
+			break
+		}
+		start = page.NextPageStart
+	}
+	sortComments(result)
+	return result, nil
+}
+
+func (b *bitbucketProvider) AddCRComment(ctx context.Context, owner, repo string, number int, opts AddCRCommentOptions) (*CRComment, error) {
+	body := map[string]interface{}{"text": opts.Body}
+	if opts.inline() {
+		body["anchor"] = map[string]interface{}{
+			"path": opts.Path, "line": opts.Line, "lineType": "ADDED",
+			"fileType": "TO", "diffType": "EFFECTIVE",
+		}
+	}
+	var c bitbucketComment
+	if err := b.doRequest(ctx, "POST", repoPath(owner, repo, fmt.Sprintf("/pull-requests/%d/comments", number)), body, &c); err != nil {
+		return nil, err
+	}
+	comment := c.toComment()
+	if opts.inline() {
+		comment.Path, comment.Line = opts.Path, opts.Line
+	}
+	return comment, nil
+}
+
+// ReviewCR 以当前用户身份设置参与者状态 APPROVED / NEEDS_WORK，有正文时另发评论
+func (b *bitbucketProvider) ReviewCR(ctx context.Context, owner, repo string, number int, opts ReviewCROptions) error {
+	slug, err := b.currentUserSlug(ctx)
+	if err != nil {
+		return err
+	}
+	status := "APPROVED"
+	if opts.Action == ReviewRequestChanges {
+		status = "NEEDS_WORK"
+	}
+	path := repoPath(owner, repo, fmt.Sprintf("/pull-requests/%d/participants/%s", number, url.PathEscape(slug)))
+	if err := b.doRequest(ctx, "PUT", path, map[string]interface{}{"status": status}, nil); err != nil {
+		return err
+	}
+	if opts.Body == "" {
+		return nil
+	}
+	_, err = b.AddCRComment(ctx, owner, repo, number, AddCRCommentOptions{Body: opts.Body})
+	return err
+}
+
+func (b *bitbucketProvider) currentUserSlug(ctx context.Context) (string, error) {
+	header, err := b.do(ctx, "GET", "/projects?limit=1", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	name := header.Get("X-AUSERNAME")
+	if name == "" {
+		return "", fmt.Errorf("bitbucket: cannot determine current user")
+	}
+	var users struct {
+		Values []bitbucketUser `json:"values"`
+	}
+	if err := b.doRequest(ctx, "GET", "/users?filter="+url.QueryEscape(name), nil, &users); err == nil {
+		for _, u := range users.Values {
+			if strings.EqualFold(u.Name, name) && u.Slug != "" {
+				return u.Slug, nil
+			}
+		}
+	}
+	return strings.ToLower(name), nil
+}
+
+// GetCommitStatuses 读取 Build Status API 上报的构建结果
+func (b *bitbucketProvider) GetCommitStatuses(ctx context.Context, owner, repo, sha string) ([]*CommitStatus, error) {
+	var page struct {
+		Values []struct {
+			State       string `json:"state"` // SUCCESSFUL | FAILED | INPROGRESS
+			Key         string `json:"key"`
+			Name        string `json:"name"`
+			URL         string `json:"url"`
+			Description string `json:"description"`
+		} `json:"values"`
+	}
+	if err := b.doRequest(ctx, "GET", "/rest/build-status/1.0/commits/"+url.PathEscape(sha)+"?limit=100", nil, &page); err != nil {
+		return nil, err
+	}
+	result := make([]*CommitStatus, 0, len(page.Values))
+	for _, v := range page.Values {
+		state := CheckStatePending
+		switch v.State {
+		case "SUCCESSFUL":
+			state = CheckStateSuccess
+		case "FAILED":
+			state = CheckStateFailure
+		}
+		name := v.Name
+		if name == "" {
+			name = v.Key
+		}
+		result = append(result, &CommitStatus{Name: name, State: state, Description: v.Description, TargetURL: v.URL})
+	}
+	return result, nil
+}
+
+func (b *bitbucketProvider) CreateRelease(ctx context.Context, opts CreateReleaseOptions) (*Release, error) {
+	return nil, unsupportedOp(PlatformBitbucket, "CreateRelease")
+}
+
+func (b *bitbucketProvider) UpdateRelease(ctx context.Context, owner, repo, tag string, opts UpdateReleaseOptions) (*Release, error) {
+	return nil, unsupportedOp(PlatformBitbucket, "UpdateRelease")
+}
+
+func (b *bitbucketProvider) ListReleases(ctx context.Context, owner, repo string) ([]*Release, error) {
+	return nil, unsupportedOp(PlatformBitbucket, "ListReleases")
+}
+
+func (b *bitbucketProvider) UploadReleaseAsset(ctx context.Context, owner, repo, tag string, asset ReleaseAssetUpload) (*ReleaseAsset, error) {
+	return nil, unsupportedOp(PlatformBitbucket, "UploadReleaseAsset")
+}
+
+func (b *bitbucketProvider) CreateWebhook(ctx context.Context, opts CreateWebhookOptions) (*PlatformWebhook, error) {
+	body := map[string]interface{}{
+		"name":   "git-manage-service",
+		"url":    opts.URL,
+		"active": true,
+		"events": bitbucketWebhookEvents(opts.Events),
+	}
+	if opts.Secret != "" {
+		body["configuration"] = map[string]interface{}{"secret": opts.Secret}
+	}
+	var wh bitbucketWebhook
+	if err := b.doRequest(ctx, "POST", repoPath(opts.Owner, opts.Repo, "/webhooks"), body, &wh); err != nil {
+		return nil, err
+	}
+	return &PlatformWebhook{ID: wh.ID, URL: wh.URL, Events: wh.Events}, nil
+}
+
+func (b *bitbucketProvider) DeleteWebhook(ctx context.Context, owner, repo string, webhookID int64) error {
+	return b.doRequest(ctx, "DELETE", repoPath(owner, repo, fmt.Sprintf("/webhooks/%d", webhookID)), nil, nil)
+}
+
+func (b *bitbucketProvider) ListWebhooks(ctx context.Context, owner, repo string) ([]*PlatformWebhook, error) {
+	var page struct {
+		Values []bitbucketWebhook `json:"values"`
+	}
+	if err := b.doRequest(ctx, "GET", repoPath(owner, repo, "/webhooks"), nil, &page); err != nil {
+		return nil, err
+	}
+	result := make([]*PlatformWebhook, 0, len(page.Values))
+	for _, wh := range page.Values {
+		result = append(result, &PlatformWebhook{ID: wh.ID, URL: wh.URL, Events: wh.Events})
+	}
+	return result, nil
+}
+
+// bitbucketWebhookEvents 将通用事件名（push / cr / tag）转换为 Bitbucket 事件键
+func bitbucketWebhookEvents(events []string) []string {
+	if len(events) == 0 {
+		events = []string{"push", "cr"}
+	}
+	seen := map[string]bool{}
+	var result []string
+	add := func(keys ...string) {
+		for _, k := range keys {
+			if !seen[k] {
+				seen[k] = true
+				result = append(result, k)
+			}
+		}
+	}
+	for _, e := range events {
+		switch e {
+		case "push", "tag":
+			add("repo:refs_changed")
+		case "cr", "pull_request", "merge_request":
+			add("pr:opened", "pr:from_ref_updated", "pr:modified", "pr:merged", "pr:declined", "pr:deleted")
+		default:
+			add(e)
+		}
+	}
+	return result
+}
+
+func (b *bitbucketProvider) ParseWebhookEvent(r *http.Request, secret string) (*NormalizedEvent, error) {
+	if !signatureCheckSkipped(r) {
+		if err := b.ValidateWebhookSignature(r, secret); err != nil {
+			return nil, err
+		}
+	}
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	eventKey := r.Header.Get("X-Event-Key")
+	var pl struct {
+		EventKey   string        `json:"eventKey"`
+		Date       string        `json:"date"`
+		Actor      bitbucketUser `json:"actor"`
+		Repository bitbucketRepo `json:"repository"`
+		Changes    []struct {
+			Ref struct {
+				ID        string `json:"id"`
+				DisplayID string `json:"displayId"`
+				Type      string `json:"type"` // BRANCH | TAG
+			} `json:"ref"`
+			Type string `json:"type"` // ADD | UPDATE | DELETE
+		} `json:"changes"`
+		PullRequest *bitbucketPR `json:"pullRequest"`
+	}
+	if err := json.Unmarshal(body, &pl); err != nil {
+		return nil, err
+	}
+	if eventKey == "" {
+		eventKey = pl.EventKey
+	}
+
+	id := r.Header.Get("X-Request-Id")
+	if id == "" {
+		id = fmt.Sprint(time.Now().UnixNano())
+	}
+	ts, err := time.Parse("2006-01-02T15:04:05-0700", pl.Date)
+	if err != nil {
+		ts = time.Now()
+	}
+	event := &NormalizedEvent{
+		ID: "bb-" + id, Source: b.Platform(), Timestamp: ts,
+		Actor: pl.Actor.toUser(),
+	}
+
+	repo := pl.Repository
+	if pl.PullRequest != nil && repo.Slug == "" {
+		repo = pl.PullRequest.ToRef.Repository
+	}
+	if repo.Slug != "" {
+		event.Repo = &EventRepo{FullName: repo.Project.Key + "/" + repo.Slug, Owner: repo.Project.Key, Name: repo.Slug}
+	}
+
+	switch {
+	case eventKey == "repo:refs_changed":
+		event.Type = "push"
+		if len(pl.Changes) > 0 {
+			ch := pl.Changes[0]
+			if ch.Ref.Type == "TAG" {
+				event.Tag = ch.Ref.DisplayID
+				event.Type = "tag.created"
+				if ch.Type == "DELETE" {
+					event.Type = "tag.deleted"
+				}
+			} else {
+				event.Branch = ch.Ref.DisplayID
+				switch ch.Type {
+				case "ADD":
+					event.Type = "branch.created"
+				case "DELETE":
+					event.Type = "branch.deleted"
+				}
+			}
+		}
+	case strings.HasPrefix(eventKey, "pr:"):
+		action := strings.TrimPrefix(eventKey, "pr:")
+		switch action {
+		case "declined", "deleted":
+			action = "closed"
+		case "from_ref_updated":
+			action = "updated"
+		}
+		event.Type = "cr." + action
+		if pl.PullRequest != nil {
+			event.CR = pl.PullRequest.toCR()
+		}
+	case eventKey == "diagnostics:ping":
+		event.Type = "ping"
+	default:
+		event.Type = eventKey
+	}
+	return event, nil
+}
+
+// ValidateWebhookSignature 要求 X-Event-Key 请求头；配置了密钥时校验 X-Hub-Signature（sha256=<hex>）
+func (b *bitbucketProvider) ValidateWebhookSignature(r *http.Request, secret string) error {
+	if r.Header.Get("X-Event-Key") == "" {
+		return fmt.Errorf("missing X-Event-Key header")
+	}
+	if secret == "" {
+		return nil
+	}
+	sig := r.Header.Get("X-Hub-Signature")
+	if sig == "" {
+		return fmt.Errorf("missing X-Hub-Signature header")
+	}
+	return verifyHMAC(r, sha256.New, secret, strings.TrimPrefix(sig, "sha256="))
+}
+
+func (b *bitbucketProvider) doRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	_, err := b.do(ctx, method, path, body, result)
+	return err
+}
+
+// do 发送请求并返回响应头；path 以 /rest/ 开头时按服务根路径拼接，否则相对 /rest/api/1.0
+func (b *bitbucketProvider) do(ctx context.Context, method, path string, body interface{}, result interface{}) (http.Header, error) {
+	if !strings.HasPrefix(path, "/rest/") {
+		path = "/rest/api/1.0" + path
+	}
+	resp, err := b.api.do(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Header, resp.decode(result)
+}
+
+func repoPath(owner, repo, suffix string) string {
+	return fmt.Sprintf("/projects/%s/repos/%s%s", url.PathEscape(owner), url.PathEscape(repo), suffix)
+}
+
+type bitbucketUser struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+func (u *bitbucketUser) toUser() *CRUser {
+	if u.Name == "" && u.Slug == "" {
+		return nil
+	}
+	username := u.Slug
+	if username == "" {
+		username = u.Name
+	}
+	return &CRUser{ID: u.ID, Username: username, Name: u.DisplayName}
+}
+
+type bitbucketComment struct {
+	ID          int64         `json:"id"`
+	Text        string        `json:"text"`
+	Author      bitbucketUser `json:"author"`
+	CreatedDate int64         `json:"createdDate"`
+}
+
+func (c *bitbucketComment) toComment() *CRComment {
+	return &CRComment{ID: c.ID, Body: c.Text, Author: c.Author.toUser(), CreatedAt: time.UnixMilli(c.CreatedDate)}
+}
+
+type bitbucketRepo struct {
+	ID          int64  `json:"id"`
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Public      bool   `json:"public"`
+	Archived    bool   `json:"archived"` // 8.0 起支持
+	Project     struct {
+		Key string `json:"key"`
+	} `json:"project"`
+	Links struct {
+		Clone []struct {
+			Href string `json:"href"`
+			Name string `json:"name"` // http | ssh
+		} `json:"clone"`
+	} `json:"links"`
+}
+
+func (r *bitbucketRepo) toRepo() *PlatformRepo {
+	repo := &PlatformRepo{
+		ID: r.ID, FullName: r.Project.Key + "/" + r.Slug, Name: r.Slug, Owner: r.Project.Key,
+		Description: r.Description, Private: !r.Public, Platform: PlatformBitbucket,
+		Visibility: VisibilityPrivate, Archived: r.Archived,
+	}
+	if r.Public {
+		repo.Visibility = VisibilityPublic
+	}
+	for _, l := range r.Links.Clone {
+		switch l.Name {
+		case "http", "https":
+			repo.CloneURL = l.Href
+		case "ssh":
+			repo.SSHURL = l.Href
+		}
+	}
+	return repo
+}
+
+type bitbucketWebhook struct {
+	ID     int64    `json:"id"`
+	Name   string   `json:"name"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+type bitbucketRef struct {
+	ID           string        `json:"id"`
+	DisplayID    string        `json:"displayId"`
+	LatestCommit string        `json:"latestCommit"`
+	Repository   bitbucketRepo `json:"repository"`
+}
+
+type bitbucketPR struct {
+	ID          int          `json:"id"`
+	Version     int          `json:"version"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	State       string       `json:"state"` // OPEN | MERGED | DECLINED
+	FromRef     bitbucketRef `json:"fromRef"`
+	ToRef       bitbucketRef `json:"toRef"`
+	Author      struct {
+		User bitbucketUser `json:"user"`
+	} `json:"author"`
+	Reviewers []struct {
+		User bitbucketUser `json:"user"`
+	} `json:"reviewers"`
+	Properties struct {
+		MergeResult struct {
+			Outcome string `json:"outcome"` // CLEAN | CONFLICTED
+		} `json:"mergeResult"`
+	} `json:"properties"`
+	Links struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+	CreatedDate int64 `json:"createdDate"`
+	UpdatedDate int64 `json:"updatedDate"`
+}
+
+func (pr *bitbucketPR) toCR() *ChangeRequest {
+	cr := &ChangeRequest{
+		ID: int64(pr.ID), Number: pr.ID, Title: pr.Title, Description: pr.Description,
+		State: mapBitbucketState(pr.State), SourceBranch: pr.FromRef.DisplayID, TargetBranch: pr.ToRef.DisplayID,
+		Author:      pr.Author.User.toUser(),
+		MergeStatus: "unknown",
+		HeadSHA:     pr.FromRef.LatestCommit,
+		CreatedAt:   time.UnixMilli(pr.CreatedDate), UpdatedAt: time.UnixMilli(pr.UpdatedDate),
+	}
+	switch pr.Properties.MergeResult.Outcome {
+	case "CLEAN":
+		cr.MergeStatus = "mergeable"
+	case "CONFLICTED":
+		cr.MergeStatus = "conflicting"
+	}
+	for _, r := range pr.Reviewers {
+		if u := r.User.toUser(); u != nil {
+			cr.Reviewers = append(cr.Reviewers, u)
+		}
+	}
+	if len(pr.Links.Self) > 0 {
+		cr.WebURL = pr.Links.Self[0].Href
+	}
+	return cr
+}
+
+func mapBitbucketState(state string) CRState {
+	switch state {
+	case "MERGED":
+		return CRStateMerged
+	case "DECLINED":
+		return CRStateClosed
+	}
+	return CRStateOpened
+}
+
+func mapCRStateToBitbucket(state CRState) string {
+	switch state {
+	case CRStateMerged:
+		return "MERGED"
+	case CRStateClosed:
+		return "DECLINED"
+	case CRStateOpened:
+		return "OPEN"
+	}
+	return "ALL"
+}
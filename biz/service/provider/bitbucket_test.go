@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sqlite "github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+func TestBitbucketProviderAPI(t *testing.T) {
+	var mergedVersion string
+	var updateBody map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/1.0/projects/PROJ/repos/app", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":7,"slug":"app","project":{"key":"PROJ"},
+			"links":{"clone":[{"href":"https://bb.local/scm/proj/app.git","name":"http"},{"href":"ssh://git@bb.local:7999/proj/app.git","name":"ssh"}]}}`))
+	})
+	mux.HandleFunc("/rest/api/1.0/projects/PROJ/repos/app/branches/default", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"refs/heads/master","displayId":"master"}`))
+	})
+	mux.HandleFunc("/rest/api/1.0/projects/PROJ/repos/app/pull-requests/5", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			json.NewDecoder(r.Body).Decode(&updateBody)
+			w.Write([]byte(`{"id":5,"version":4,"title":"Old","description":"changelog","state":"OPEN","fromRef":{"displayId":"feat"},"toRef":{"displayId":"master"}}`))
+			return
+		}
+		w.Write([]byte(`{"id":5,"version":3,"title":"Old","state":"OPEN","fromRef":{"id":"refs/heads/feat","displayId":"feat"},"toRef":{"displayId":"master"},
+			"reviewers":[{"user":{"name":"bob","slug":"bob"}}]}`))
+	})
+	mux.HandleFunc("/rest/api/1.0/projects/PROJ/repos/app/pull-requests/5/merge", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mergedVersion = r.URL.Query().Get("version")
+		w.Write([]byte(`{"id":5,"version":4,"state":"MERGED","fromRef":{"displayId":"feat"},"toRef":{"displayId":"master"}}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p := NewBitbucketProvider(srv.URL, "tok")
+	ctx := context.Background()
+
+	repo, err := p.GetRepo(ctx, "PROJ", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.FullName != "PROJ/app" || repo.DefaultBranch != "master" || repo.SSHURL == "" {
+		t.Errorf("unexpected repo: %+v", repo)
+	}
+
+	cr, err := p.UpdateCR(ctx, "PROJ", "app", 5, UpdateCROptions{Description: "changelog"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviewers, _ := updateBody["reviewers"].([]interface{})
+	if cr.Description != "changelog" || updateBody["version"] != float64(3) || updateBody["title"] != "Old" || len(reviewers) != 1 {
+		t.Errorf("update: cr=%+v body=%v", cr, updateBody)
+	}
+
+	cr, err = p.MergeCR(ctx, "PROJ", "app", 5, MergeCROptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cr.State != CRStateMerged || mergedVersion != "3" {
+		t.Errorf("merge: state=%s version=%s", cr.State, mergedVersion)
+	}
+}
+
+func TestBitbucketParseWebhookEvent(t *testing.T) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"eventKey":   "repo:refs_changed",
+		"date":       "2024-05-01T10:00:00+0800",
+		"actor":      map[string]interface{}{"name": "alice", "slug": "alice", "displayName": "Alice"},
+		"repository": map[string]interface{}{"slug": "app", "project": map[string]interface{}{"key": "PROJ"}},
+		"changes": []interface{}{map[string]interface{}{
+			"ref":  map[string]interface{}{"id": "refs/heads/main", "displayId": "main", "type": "BRANCH"},
+			"type": "UPDATE",
+		}},
+	})
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+
+	newReq := func(sig string) *http.Request {
+		r := httptest.NewRequest("POST", "/api/webhooks/receive", strings.NewReader(string(body)))
+		r.Header.Set("X-Event-Key", "repo:refs_changed")
+		r.Header.Set("X-Request-Id", "req-1")
+		r.Header.Set("X-Hub-Signature", sig)
+		return r
+	}
+
+	p := NewBitbucketProvider("https://bb.local", "")
+	event, err := p.ParseWebhookEvent(newReq("sha256="+hex.EncodeToString(mac.Sum(nil))), "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != "push" || event.Branch != "main" || event.Repo.FullName != "PROJ/app" || event.Actor.Username != "alice" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if _, err := p.ParseWebhookEvent(newReq("sha256=deadbeef"), "s3cret"); err == nil {
+		t.Error("expected signature mismatch")
+	}
+}
+
+func TestDetectBitbucket(t *testing.T) {
+	cases := map[string]string{
+		"https://git.corp.local/scm/PROJ/app.git":                  "https://git.corp.local",
+		"https://git.corp.local/bitbucket/projects/PROJ/repos/app": "https://git.corp.local/bitbucket",
+		"ssh://git@git.corp.local:7999/proj/app.git":               "https://git.corp.local",
+		"git@bitbucket.corp.local:PROJ/app.git":                    "https://bitbucket.corp.local",
+	}
+	for raw, base := range cases {
+		r, err := DetectPlatform(raw)
+		if err != nil {
+			t.Fatalf("%s: %v", raw, err)
+		}
+		if r.Platform != PlatformBitbucket || r.BaseURL != base || !strings.EqualFold(r.Owner, "PROJ") || r.Repo != "app" {
+			t.Errorf("%s: %+v", raw, r)
+		}
+	}
+}
+
+func TestDetectBitbucketPathSkipsKnownHosts(t *testing.T) {
+	r, err := DetectPlatform("https://gitlab.com/scm/group/repo.git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Platform != PlatformGitLab || r.BaseURL != "https://gitlab.com/api/v4" || r.Owner != "scm" || r.Repo != "group/repo" {
+		t.Errorf("unexpected result: %+v", r)
+	}
+
+	conn, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.AutoMigrate(&po.ProviderConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Create(&po.ProviderConfig{Name: "corp", Platform: "gitea", BaseURL: "https://git.corp.local/api/v1"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	old := db.DB
+	db.DB = conn
+	GetManager().Invalidate(0)
+	defer func() {
+		db.DB = old
+		GetManager().Invalidate(0)
+	}()
+
+	r, err = DetectPlatform("https://git.corp.local/scm/PROJ/app.git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Platform != PlatformGitea || r.BaseURL != "https://git.corp.local/api/v1" || r.Owner != "scm" {
+		t.Errorf("configured host: %+v", r)
+	}
+}
@@ -2,8 +2,11 @@ package provider
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"strings"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
 )
 
 type DetectResult struct {
@@ -17,6 +20,9 @@ func DetectPlatform(remoteURL string) (*DetectResult, error) {
 	if remoteURL == "" {
 		return nil, fmt.Errorf("empty remote URL")
 	}
+	if result, err := detectAzure(remoteURL); result != nil || err != nil {
+		return result, err
+	}
 
 	if strings.HasPrefix(remoteURL, "git@") {
 		return detectSSH(remoteURL)
@@ -64,6 +70,13 @@ func detectSSHProtocol(raw string) (*DetectResult, error) {
 		return nil, fmt.Errorf("invalid SSH path: %s", path)
 	}
 	platform, baseURL := classifyHost(host)
+	// Bitbucket Server 与 Gerrit 的默认 SSH 端口
+	switch u.Port() {
+	case "7999":
+		platform, baseURL = PlatformBitbucket, "https://"+u.Hostname()
+	case "29418":
+		platform, baseURL = PlatformGerrit, "https://"+u.Hostname()
+	}
 	return &DetectResult{
 		Platform: platform,
 		Owner:    pathParts[0],
@@ -80,11 +93,23 @@ func detectHTTP(raw string) (*DetectResult, error) {
 	host := u.Host
 	path := strings.TrimSuffix(u.Path, ".git")
 	path = strings.TrimPrefix(path, "/")
+	// 路径特征只用于未识别的主机，避免 gitlab.com/scm/... 之类的仓库被误判为 Bitbucket
+	platform, baseURL, known := knownHost(host)
+	if !known || platform == PlatformBitbucket {
+		if result := detectBitbucketPath(u.Scheme, host, path); result != nil {
+			if known {
+				result.BaseURL = baseURL
+			}
+			return result, nil
+		}
+	}
 	pathParts := strings.SplitN(path, "/", 2)
 	if len(pathParts) != 2 {
 		return nil, fmt.Errorf("invalid HTTP path: %s", path)
 	}
-	platform, baseURL := classifyHost(host)
+	if !known {
+		platform, baseURL = classifyHost(host)
+	}
 	return &DetectResult{
 		Platform: platform,
 		Owner:    pathParts[0],
@@ -93,9 +118,173 @@ func detectHTTP(raw string) (*DetectResult, error) {
 	}, nil
 }
 
+// SameRepo 判断检测结果是否指向 provider 配置 cfg 下名为 fullName（owner/repo）的仓库
+func (r *DetectResult) SameRepo(cfg *po.ProviderConfig, fullName string) bool {
+	if Platform(cfg.Platform) != r.Platform {
+		return false
+	}
+	base := cfg.BaseURL
+	if base == "" {
+		switch r.Platform {
+		case PlatformGitHub:
+			base = "https://api.github.com"
+		case PlatformGitLab:
+			base = "https://gitlab.com/api/v4"
+		case PlatformGitea:
+			base = "https://gitea.com/api/v1"
+		}
+	}
+	cu, err1 := url.Parse(base)
+	ru, err2 := url.Parse(r.BaseURL)
+	if err1 != nil || err2 != nil || !strings.EqualFold(cu.Hostname(), ru.Hostname()) {
+		return false
+	}
+	name := r.Owner + "/" + r.Repo
+	if r.Platform == PlatformGerrit {
+		// Gerrit 认证访问的 HTTP 地址带 /a/ 前缀
+		name = strings.TrimPrefix(name, "a/")
+	}
+	return strings.EqualFold(name, fullName)
+}
+
+// detectBitbucketPath 识别 Bitbucket Server 的克隆地址 [ctx/]scm/PROJ/repo 与浏览地址 [ctx/]projects/PROJ/repos/repo
+func detectBitbucketPath(scheme, host, path string) *DetectResult {
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		var owner, repo string
+		switch {
+		case seg == "scm" && len(segs) == i+3:
+			owner, repo = segs[i+1], segs[i+2]
+		case seg == "projects" && len(segs) >= i+4 && segs[i+2] == "repos":
+			owner, repo = segs[i+1], segs[i+3]
+		default:
+			continue
+		}
+		base := scheme + "://" + host
+		if i > 0 {
+			base += "/" + strings.Join(segs[:i], "/")
+		}
+		return &DetectResult{Platform: PlatformBitbucket, Owner: owner, Repo: repo, BaseURL: base}
+	}
+	return nil
+}
+
+// detectAzure 识别 Azure DevOps 地址，非 Azure 地址返回 nil, nil：
+//
+//	https://[org@]dev.azure.com/org/project/_git/repo
+//	https://org.visualstudio.com/[DefaultCollection/]project/_git/repo
+//	git@ssh.dev.azure.com:v3/org/project/repo
+//	org@vs-ssh.visualstudio.com:v3/org/project/repo
+func detectAzure(raw string) (*DetectResult, error) {
+	var host, path string
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, nil
+		}
+		host, path = u.Hostname(), u.Path
+	} else if at := strings.Index(raw, "@"); at >= 0 {
+		parts := strings.SplitN(raw[at+1:], ":", 2)
+		if len(parts) != 2 {
+			return nil, nil
+		}
+		host, path = parts[0], parts[1]
+		if p, err := url.PathUnescape(path); err == nil {
+			path = p
+		}
+	} else {
+		return nil, nil
+	}
+	host = strings.ToLower(host)
+	segs := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+
+	var org, project, repo string
+	vs := strings.HasSuffix(host, ".visualstudio.com")
+	switch {
+	case host == "ssh.dev.azure.com" || host == "vs-ssh.visualstudio.com":
+		if len(segs) != 4 || segs[0] != "v3" {
+			return nil, fmt.Errorf("invalid Azure DevOps SSH path: %s", path)
+		}
+		org, project, repo = segs[1], segs[2], segs[3]
+		vs = host == "vs-ssh.visualstudio.com"
+	case host == "dev.azure.com" || vs:
+		i := indexOf(segs, "_git")
+		if i < 0 || i+1 >= len(segs) {
+			return nil, fmt.Errorf("invalid Azure DevOps path: %s", path)
+		}
+		repo = segs[i+1]
+		prefix := segs[:i]
+		if vs {
+			org = strings.TrimSuffix(host, ".visualstudio.com")
+			if len(prefix) > 0 && strings.EqualFold(prefix[0], "DefaultCollection") {
+				prefix = prefix[1:]
+			}
+		} else {
+			if len(prefix) == 0 {
+				return nil, fmt.Errorf("invalid Azure DevOps path: %s", path)
+			}
+			org, prefix = prefix[0], prefix[1:]
+		}
+		// 与项目同名的默认仓库地址省略项目段
+		project = repo
+		if len(prefix) > 0 {
+			project = prefix[len(prefix)-1]
+		}
+	default:
+		return nil, nil
+	}
+
+	baseURL := "https://dev.azure.com/" + org
+	if vs {
+		baseURL = "https://" + org + ".visualstudio.com"
+	}
+	return &DetectResult{Platform: PlatformAzure, Owner: project, Repo: repo, BaseURL: baseURL}, nil
+}
+
+func indexOf(segs []string, target string) int {
+	for i, s := range segs {
+		if s == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// knownHost 识别公共平台主机与已配置 provider 的主机，未识别时返回 false
+func knownHost(host string) (Platform, string, bool) {
+	hostname := strings.ToLower(host)
+	if h, _, err := net.SplitHostPort(hostname); err == nil {
+		hostname = h
+	}
+	switch hostname {
+	case "github.com", "www.github.com":
+		return PlatformGitHub, "https://api.github.com", true
+	case "gitlab.com", "www.gitlab.com":
+		return PlatformGitLab, "https://gitlab.com/api/v4", true
+	case "gitea.com", "www.gitea.com":
+		return PlatformGitea, "https://gitea.com/api/v1", true
+	}
+	return GetManager().lookupHost(hostname)
+}
+
 func classifyHost(host string) (Platform, string) {
+	if platform, baseURL, ok := knownHost(host); ok {
+		return platform, baseURL
+	}
 	lower := strings.ToLower(host)
 	switch {
+	case strings.Contains(lower, "bitbucket") && !strings.Contains(lower, "bitbucket.org"):
+		hostname := host
+		if i := strings.LastIndex(hostname, ":"); i > 0 {
+			hostname = hostname[:i]
+		}
+		return PlatformBitbucket, "https://" + hostname
+	case strings.Contains(lower, "gerrit"):
+		hostname := host
+		if i := strings.LastIndex(hostname, ":"); i > 0 {
+			hostname = hostname[:i]
+		}
+		return PlatformGerrit, "https://" + hostname
 	case strings.Contains(lower, "github.com"):
 		return PlatformGitHub, "https://api.github.com"
 	case strings.Contains(lower, "gitlab.com"):
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+func TestDetectResultSameRepo(t *testing.T) {
+	github := &po.ProviderConfig{Platform: "github"}
+	gitlab := &po.ProviderConfig{Platform: "gitlab", BaseURL: "https://gitlab.com/api/v4"}
+	cases := []struct {
+		url      string
+		cfg      *po.ProviderConfig
+		fullName string
+		want     bool
+	}{
+		{"git@github.com:acme/app.git", github, "acme/app", true},
+		{"https://github.com/Acme/App.git", github, "acme/app", true},
+		{"https://github.com/acme/other.git", github, "acme/app", false},
+		// 镜像场景：目标 remote 与绑定仓库同名但在不同平台
+		{"https://github.com/acme/app.git", gitlab, "acme/app", false},
+		{"https://gitlab.com/acme/sub/app.git", gitlab, "acme/sub/app", true},
+	}
+	for _, tc := range cases {
+		r, err := DetectPlatform(tc.url)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.url, err)
+		}
+		if got := r.SameRepo(tc.cfg, tc.fullName); got != tc.want {
+			t.Errorf("%s vs %s/%s: got %v", tc.url, tc.cfg.Platform, tc.fullName, got)
+		}
+	}
+}
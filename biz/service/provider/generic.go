@@ -0,0 +1,417 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/pkg/jsonpath"
+)
+
+// GenericOptions generic 平台的签名与字段映射配置，保存在 ProviderConfig.Options 中
+type GenericOptions struct {
+	SignatureHeader    string `json:"signature_header"`    // 签名请求头，默认 X-Signature
+	SignatureAlgorithm string `json:"signature_algorithm"` // sha256（默认）| sha1 | sha512 | token（请求头直接携带密钥）
+	SignaturePrefix    string `json:"signature_prefix"`    // 签名值前缀，如 "sha256="
+	// Mapping NormalizedEvent 字段到取值表达式：以 $ 开头为 JSONPath，header:<name> 取请求头，其余视为常量
+	Mapping map[string]string `json:"mapping"`
+}
+
+// 可映射的字段
+var genericFields = map[string]bool{
+	"id": true, "type": true, "timestamp": true,
+	"actor": true, "actor_name": true,
+	"repo": true, "repo_owner": true, "repo_name": true,
+	"ref": true, "branch": true, "tag": true,
+	"cr_number": true, "cr_title": true, "cr_description": true, "cr_state": true,
+	"cr_source_branch": true, "cr_target_branch": true, "cr_url": true,
+	"cr_author": true, "cr_labels": true,
+}
+
+const defaultGenericSignatureHeader = "X-Signature"
+
+// ParseGenericOptions 从 ProviderConfig.Options 解析并校验 generic 配置
+func ParseGenericOptions(options map[string]interface{}) (*GenericOptions, error) {
+	opts := &GenericOptions{}
+	if options != nil {
+		b, err := json.Marshal(options)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, opts); err != nil {
+			return nil, fmt.Errorf("invalid generic options: %w", err)
+		}
+	}
+	switch strings.ToLower(opts.SignatureAlgorithm) {
+	case "", "sha256", "sha1", "sha512", "token":
+	default:
+		return nil, fmt.Errorf("unsupported signature_algorithm: %s", opts.SignatureAlgorithm)
+	}
+	if len(opts.Mapping) == 0 {
+		return nil, fmt.Errorf("mapping is required for generic provider")
+	}
+	for field, src := range opts.Mapping {
+		if !genericFields[field] {
+			return nil, fmt.Errorf("unknown mapping field: %s", field)
+		}
+		if strings.HasPrefix(src, "$") {
+			if _, err := jsonpath.Compile(src); err != nil {
+				return nil, fmt.Errorf("mapping %s: %w", field, err)
+			}
+		}
+	}
+	return opts, nil
+}
+
+type genericProvider struct {
+	cfgID   uint
+	baseURL string
+	opts    *GenericOptions
+	paths   map[string]*jsonpath.Path
+}
+
+// NewGenericProvider 创建 generic provider，cfgID 用于隔离不同配置下的事件 ID
+func NewGenericProvider(cfgID uint, baseURL string, options map[string]interface{}) (*genericProvider, error) {
+	opts, err := ParseGenericOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	g := &genericProvider{
+		cfgID:   cfgID,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		opts:    opts,
+		paths:   make(map[string]*jsonpath.Path),
+	}
+	for field, src := range opts.Mapping {
+		if strings.HasPrefix(src, "$") {
+			g.paths[field] = jsonpath.MustCompile(src)
+		}
+	}
+	return g, nil
+}
+
+func (g *genericProvider) Platform() Platform { return PlatformGeneric }
+
+func (g *genericProvider) TestConnection(ctx context.Context) (*TestConnectionResult, error) {
+	return &TestConnectionResult{Connected: true, Platform: string(g.Platform()), Message: "generic provider only receives webhooks"}, nil
+}
+
+func (g *genericProvider) unsupported(op string) error {
+	return unsupportedOp(PlatformGeneric, op)
+}
+
+func (g *genericProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([]*PlatformRepo, error) {
+	return nil, g.unsupported("ListRepos")
+}
+
+func (g *genericProvider) GetRepo(ctx context.Context, owner, repo string) (*PlatformRepo, error) {
+	return nil, g.unsupported("GetRepo")
+}
+
+func (g *genericProvider) CreateRepo(ctx context.Context, opts CreateRepoOptions) (*PlatformRepo, error) {
+	return nil, g.unsupported("CreateRepo")
+}
+
+func (g *genericProvider) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*BranchProtection, error) {
+	return nil, g.unsupported("GetBranchProtection")
+}
+
+func (g *genericProvider) SetBranchProtection(ctx context.Context, owner, repo string, protection BranchProtection) (*BranchProtection, error) {
+	return nil, g.unsupported("SetBranchProtection")
+}
+
+func (g *genericProvider) CreateCR(ctx context.Context, opts CreateCROptions) (*ChangeRequest, error) {
+	return nil, g.unsupported("CreateCR")
+}
+
+func (g *genericProvider) GetCR(ctx context.Context, owner, repo string, number int) (*ChangeRequest, error) {
+	return nil, g.unsupported("GetCR")
+}
+
+func (g *genericProvider) ListCRs(ctx context.Context, opts ListCROptions) ([]*ChangeRequest, int, error) {
+	return nil, 0, g.unsupported("ListCRs")
+}
+
+func (g *genericProvider) MergeCR(ctx context.Context, owner, repo string, number int, opts MergeCROptions) (*ChangeRequest, error) {
+	return nil, g.unsupported("MergeCR")
+}
+
+func (g *genericProvider) CloseCR(ctx context.Context, owner, repo string, number int) (*ChangeRequest, error) {
+	return nil, g.unsupported("CloseCR")
+}
+
+func (g *genericProvider) UpdateCR(ctx context.Context, owner, repo string, number int, opts UpdateCROptions) (*ChangeRequest, error) {
+	return nil, g.unsupported("UpdateCR")
+}
+
+func (g *genericProvider) ListCRComments(ctx context.Context, owner, repo string, number int) ([]*CRComment, error) {
+	return nil, g.unsupported("ListCRComments")
+}
+
+func (g *genericProvider) AddCRComment(ctx context.Context, owner, repo string, number int, opts AddCRCommentOptions) (*CRComment, error) {
+	return nil, g.unsupported("AddCRComment")
+}
+
+func (g *genericProvider) ReviewCR(ctx context.Context, owner, repo string, number int, opts ReviewCROptions) error {
+	return g.unsupported("ReviewCR")
+}
+
+func (g *genericProvider) GetCommitStatuses(ctx context.Context, owner, repo, sha string) ([]*CommitStatus, error) {
+	return nil, g.unsupported("GetCommitStatuses")
+}
+
+func (g *genericProvider) CreateRelease(ctx context.Context, opts CreateReleaseOptions) (*Release, error) {
+	return nil, g.unsupported("CreateRelease")
+}
+
+func (g *genericProvider) UpdateRelease(ctx context.Context, owner, repo, tag string, opts UpdateReleaseOptions) (*Release, error) {
+	return nil, g.unsupported("UpdateRelease")
+}
+
+func (g *genericProvider) ListReleases(ctx context.Context, owner, repo string) ([]*Release, error) {
+	return nil, g.unsupported("ListReleases")
+}
+
+func (g *genericProvider) UploadReleaseAsset(ctx context.Context, owner, repo, tag string, asset ReleaseAssetUpload) (*ReleaseAsset, error) {
+	return nil, g.unsupported("UploadReleaseAsset")
+}
+
+func (g *genericProvider) CreateWebhook(ctx context.Context, opts CreateWebhookOptions) (*PlatformWebhook, error) {
+	return nil, g.unsupported("CreateWebhook")
+}
+
+func (g *genericProvider) DeleteWebhook(ctx context.Context, owner, repo string, webhookID int64) error {
+	return g.unsupported("DeleteWebhook")
+}
+
+func (g *genericProvider) ListWebhooks(ctx context.Context, owner, repo string) ([]*PlatformWebhook, error) {
+	return nil, g.unsupported("ListWebhooks")
+}
+
+// ValidateWebhookSignature 按配置的请求头、算法与前缀校验 HMAC 签名
+func (g *genericProvider) ValidateWebhookSignature(r *http.Request, secret string) error {
+	if secret == "" {
+		return nil
+	}
+	header := g.opts.SignatureHeader
+	if header == "" {
+		header = defaultGenericSignatureHeader
+	}
+	sig := r.Header.Get(header)
+	if sig == "" {
+		return fmt.Errorf("missing %s header", header)
+	}
+	sig = strings.TrimPrefix(strings.TrimSpace(sig), g.opts.SignaturePrefix)
+
+	algo := strings.ToLower(g.opts.SignatureAlgorithm)
+	if algo == "token" {
+		if subtle.ConstantTimeCompare([]byte(sig), []byte(secret)) != 1 {
+			return fmt.Errorf("invalid webhook token")
+		}
+		return nil
+	}
+
+	var newHash func() hash.Hash
+	switch algo {
+	case "sha1":
+		newHash = sha1.New
+	case "sha512":
+		newHash = sha512.New
+	default:
+		newHash = sha256.New
+	}
+	return verifyHMAC(r, newHash, secret, sig)
+}
+
+// verifyHMAC 校验请求体的十六进制 HMAC 签名，读取后恢复 r.Body
+func verifyHMAC(r *http.Request, newHash func() hash.Hash, secret, sig string) error {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(strings.ToLower(sig)), []byte(expected)) {
+		return fmt.Errorf("invalid webhook signature")
+	}
+	return nil
+}
+
+// ParseWebhookEvent 按字段映射将任意 JSON 报文转换为 NormalizedEvent
+func (g *genericProvider) ParseWebhookEvent(r *http.Request, secret string) (*NormalizedEvent, error) {
+	if !signatureCheckSkipped(r) {
+		if err := g.ValidateWebhookSignature(r, secret); err != nil {
+			return nil, err
+		}
+	}
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("payload is not valid JSON: %w", err)
+	}
+	str := func(field string) string { return toString(g.lookup(field, doc, r)) }
+
+	event := &NormalizedEvent{
+		Type:       str("type"),
+		Source:     g.Platform(),
+		Timestamp:  toTime(g.lookup("timestamp", doc, r)),
+		Branch:     str("branch"),
+		Tag:        str("tag"),
+		RawPayload: body,
+	}
+	if event.Type == "" {
+		event.Type = "generic"
+	}
+	// 事件 ID 按配置隔离，避免不同来源的相同 id 被当作重复事件丢弃
+	if id := str("id"); id != "" {
+		event.ID = fmt.Sprintf("gen-%d-%s", g.cfgID, id)
+	} else {
+		event.ID = fmt.Sprintf("gen-%d-%d", g.cfgID, time.Now().UnixNano())
+	}
+	if ref := str("ref"); ref != "" {
+		switch {
+		case strings.HasPrefix(ref, "refs/tags/"):
+			event.Tag = strings.TrimPrefix(ref, "refs/tags/")
+		case event.Branch == "":
+			event.Branch = strings.TrimPrefix(ref, "refs/heads/")
+		}
+	}
+
+	if actor := str("actor"); actor != "" {
+		event.Actor = &CRUser{Username: actor, Name: str("actor_name")}
+	}
+
+	owner, name, fullName := str("repo_owner"), str("repo_name"), str("repo")
+	if fullName == "" && owner != "" && name != "" {
+		fullName = owner + "/" + name
+	}
+	if fullName != "" {
+		if owner == "" || name == "" {
+			if i := strings.LastIndex(fullName, "/"); i > 0 {
+				owner, name = fullName[:i], fullName[i+1:]
+			}
+		}
+		event.Repo = &EventRepo{FullName: fullName, Owner: owner, Name: name}
+	}
+
+	if number, _ := strconv.Atoi(str("cr_number")); number > 0 {
+		event.CR = &ChangeRequest{
+			ID: int64(number), Number: number,
+			Title: str("cr_title"), Description: str("cr_description"),
+			State:        mapGenericState(str("cr_state")),
+			SourceBranch: str("cr_source_branch"), TargetBranch: str("cr_target_branch"),
+			WebURL: str("cr_url"),
+			Labels: toStrings(g.lookup("cr_labels", doc, r)),
+		}
+		if author := str("cr_author"); author != "" {
+			event.CR.Author = &CRUser{Username: author}
+		}
+	}
+	return event, nil
+}
+
+// lookup 按映射取字段值：JSONPath、请求头或常量
+func (g *genericProvider) lookup(field string, doc interface{}, r *http.Request) interface{} {
+	src, ok := g.opts.Mapping[field]
+	if !ok || src == "" {
+		return nil
+	}
+	if p, ok := g.paths[field]; ok {
+		v, _ := p.Get(doc)
+		return v
+	}
+	if strings.HasPrefix(src, "header:") {
+		return r.Header.Get(strings.TrimSpace(strings.TrimPrefix(src, "header:")))
+	}
+	return src
+}
+
+func toString(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case []interface{}:
+		if len(x) > 0 {
+			return toString(x[0])
+		}
+		return ""
+	default:
+		b, _ := json.Marshal(x)
+		return string(b)
+	}
+}
+
+func toStrings(v interface{}) []string {
+	switch x := v.(type) {
+	case []interface{}:
+		out := make([]string, 0, len(x))
+		for _, item := range x {
+			if s := toString(item); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	case string:
+		var out []string
+		for _, s := range strings.Split(x, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	case nil:
+		return nil
+	}
+	return []string{toString(v)}
+}
+
+// toTime 支持 RFC3339 字符串与 Unix 时间戳（秒或毫秒），无法解析时取当前时间
+func toTime(v interface{}) time.Time {
+	switch x := v.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339, x); err == nil {
+			return t
+		}
+		if n, err := strconv.ParseFloat(x, 64); err == nil {
+			return toTime(n)
+		}
+	case float64:
+		if x > 1e12 {
+			return time.UnixMilli(int64(x))
+		}
+		return time.Unix(int64(x), 0)
+	}
+	return time.Now()
+}
+
+func mapGenericState(s string) CRState {
+	switch strings.ToLower(s) {
+	case "merged":
+		return CRStateMerged
+	case "closed", "declined", "abandoned", "rejected":
+		return CRStateClosed
+	case "":
+		return ""
+	}
+	return CRStateOpened
+}
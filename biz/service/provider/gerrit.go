@@ -0,0 +1,978 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// Gerrit REST 响应的 XSSI 防护前缀
+const gerritXSSIPrefix = ")]}'"
+
+const gerritTimeLayout = "2006-01-02 15:04:05.000000000"
+
+// gerritWebhookPrefix 由本服务创建的 webhooks 插件 remote 名前缀，后接数字作为 webhook ID
+const gerritWebhookPrefix = "gms-"
+
+var (
+	changeIDRe     = regexp.MustCompile(`(?m)^Change-Id:\s*(I[0-9a-f]{40})\s*$`)
+	bareChangeIDRe = regexp.MustCompile(`^I[0-9a-f]{40}$`)
+	trailerLineRe  = regexp.MustCompile(`^[A-Za-z0-9-]+:\s`)
+	// 评审消息与上传新 patch set 时 Gerrit 自动生成的首行
+	gerritMessageHeaderRe = regexp.MustCompile(`^(Patch Set \d+:.*|Uploaded patch set \d+\.?.*)$`)
+)
+
+// gerritProvider Gerrit Code Review。CR 对应 change，项目名为 owner/repo；
+// REST 使用 HTTP 密码做 Basic 认证，CreateCR 通过推送 refs/for/<branch> 创建 change。
+type gerritProvider struct {
+	baseURL  string
+	username string
+	token    string
+	api      *apiClient
+}
+
+func NewGerritProvider(baseURL, username, token string) *gerritProvider {
+	g := &gerritProvider{
+		baseURL:  strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/a"),
+		username: username,
+		token:    token,
+	}
+	g.api = newAPIClient("Gerrit", g.baseURL, func(req *http.Request) {
+		req.SetBasicAuth(username, token)
+	})
+	return g
+}
+
+func (g *gerritProvider) Platform() Platform { return PlatformGerrit }
+
+func (g *gerritProvider) TestConnection(ctx context.Context) (*TestConnectionResult, error) {
+	var account gerritAccount
+	if err := g.doRequest(ctx, "GET", "/accounts/self", nil, &account); err != nil {
+		return &TestConnectionResult{Connected: false, Message: err.Error()}, nil
+	}
+	return &TestConnectionResult{Connected: true, Platform: string(g.Platform()), UserName: account.Username}, nil
+}
+
+func (g *gerritProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([]*PlatformRepo, error) {
+	if opts.Page == 0 {
+		opts.Page = 1
+	}
+	if opts.PerPage == 0 {
+		opts.PerPage = 20
+	}
+	start, limit := (opts.Page-1)*opts.PerPage, opts.PerPage
+	if opts.All {
+		start, limit = 0, 100
+	}
+	projects := map[string]gerritProject{}
+	for pages := 0; ; pages++ {
+		if pages == apiMaxPages {
+			return nil, errPageLimit()
+		}
+		q := url.Values{}
+		q.Set("d", "")
+		q.Set("n", strconv.Itoa(limit))
+		q.Set("S", strconv.Itoa(start))
+		if opts.Owner != "" {
+			q.Set("p", opts.Owner+"/")
+		}
+		var page map[string]gerritProject
+		if err := g.doRequest(ctx, "GET", "/projects/?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		for name, p := range page {
+			projects[name] = p
+		}
+		// 项目列表没有翻页标记，不足一页即为最后一页
+		if !opts.All || len(page) < limit {
+			break
+		}
+		start += limit
+	}
+	names := make([]string, 0, len(projects))
+	for name := range projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]*PlatformRepo, 0, len(names))
+	for _, name := range names {
+		p := projects[name]
+		result = append(result, g.toRepo(name, &p))
+	}
+	return result, nil
+}
+
+func (g *gerritProvider) GetRepo(ctx context.Context, owner, repo string) (*PlatformRepo, error) {
+	project := gerritProjectName(owner, repo)
+	var p gerritProject
+	if err := g.doRequest(ctx, "GET", "/projects/"+url.PathEscape(project), nil, &p); err != nil {
+		return nil, err
+	}
+	result := g.toRepo(project, &p)
+	var head string
+	if err := g.doRequest(ctx, "GET", "/projects/"+url.PathEscape(project)+"/HEAD", nil, &head); err == nil {
+		result.DefaultBranch = strings.TrimPrefix(head, "refs/heads/")
+	}
+	return result, nil
+}
+
+// CreateRepo 创建项目 owner/name，DefaultBranch 作为 HEAD；可见性由父项目权限决定，此处不处理
+func (g *gerritProvider) CreateRepo(ctx context.Context, opts CreateRepoOptions) (*PlatformRepo, error) {
+	project := gerritProjectName(opts.Owner, opts.Name)
+	body := map[string]interface{}{"description": opts.Description}
+	if opts.DefaultBranch != "" {
+		body["branches"] = []string{opts.DefaultBranch}
+	}
+	var p gerritProject
+	if err := g.doRequest(ctx, "PUT", "/projects/"+url.PathEscape(project), body, &p); err != nil {
+		return nil, err
+	}
+	result := g.toRepo(project, &p)
+	result.DefaultBranch = opts.DefaultBranch
+	return result, nil
+}
+
+func (g *gerritProvider) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*BranchProtection, error) {
+	return nil, unsupportedOp(PlatformGerrit, "GetBranchProtection")
+}
+
+func (g *gerritProvider) SetBranchProtection(ctx context.Context, owner, repo string, protection BranchProtection) (*BranchProtection, error) {
+	return nil, unsupportedOp(PlatformGerrit, "SetBranchProtection")
+}
+
+func (g *gerritProvider) toRepo(name string, p *gerritProject) *PlatformRepo {
+	owner, repo := "", name
+	if i := strings.LastIndex(name, "/"); i > 0 {
+		owner, repo = name[:i], name[i+1:]
+	}
+	return &PlatformRepo{
+		FullName: name, Name: repo, Owner: owner, Description: p.Description,
+		CloneURL: g.baseURL + "/" + name, Platform: PlatformGerrit,
+		Private: p.State == "HIDDEN", Archived: p.State == "READ_ONLY",
+	}
+}
+
+// CreateCR 将本地源分支推送到 refs/for/<target> 创建 change。
+// 目标分支之后缺少 Change-Id 的提交会在临时引用上改写后推送，本地源分支保持不变；
+// Change-Id 由原提交确定性生成，重复推送同一批提交会落到相同的 change 上。
+// change 标题取提交说明首行，Description 作为 patch set 消息，Labels 作为 hashtag。
+func (g *gerritProvider) CreateCR(ctx context.Context, opts CreateCROptions) (*ChangeRequest, error) {
+	if opts.RepoPath == "" {
+		return nil, fmt.Errorf("gerrit changes are created by pushing: local repo path is required")
+	}
+	r, err := git.PlainOpen(opts.RepoPath)
+	if err != nil {
+		return nil, fmt.Errorf("open repo: %w", err)
+	}
+	pushRef, changeID, err := prepareChangeRef(r, opts.SourceBranch, opts.TargetBranch)
+	if err != nil {
+		return nil, err
+	}
+	if pushRef != plumbing.NewBranchReferenceName(opts.SourceBranch) {
+		defer r.Storer.RemoveReference(pushRef)
+	}
+
+	project := gerritProjectName(opts.Owner, opts.Repo)
+	remote := git.NewRemote(r.Storer, &config.RemoteConfig{
+		Name: "gerrit",
+		URLs: []string{g.baseURL + "/a/" + project},
+	})
+	pushOpts := map[string]string{"topic": opts.SourceBranch}
+	if opts.Description != "" {
+		pushOpts["m"] = opts.Description
+	}
+	if len(opts.Labels) > 0 {
+		pushOpts["hashtag"] = strings.Join(opts.Labels, ",")
+	}
+	err = remote.PushContext(ctx, &git.PushOptions{
+		RemoteName: "gerrit",
+		RefSpecs:   []config.RefSpec{config.RefSpec(pushRef.String() + ":refs/for/" + opts.TargetBranch)},
+		Auth:       &githttp.BasicAuth{Username: g.username, Password: g.token},
+		Options:    pushOpts,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, fmt.Errorf("push to refs/for/%s: %w", opts.TargetBranch, err)
+	}
+
+	q := fmt.Sprintf("change:%s project:%s branch:%s", changeID, project, opts.TargetBranch)
+	var changes []gerritChange
+	if err := g.doRequest(ctx, "GET", "/changes/?o=DETAILED_ACCOUNTS&q="+url.QueryEscape(q), nil, &changes); err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("change %s not found after push", changeID)
+	}
+	return changes[0].toCR(g.baseURL), nil
+}
+
+const gerritPushRefPrefix = "refs/git-manage/gerrit/"
+
+// prepareChangeRef 返回待推送的引用与最新提交的 Change-Id。
+// 源分支上目标分支之后的提交都带 Change-Id 时直接推送源分支，否则改写到临时引用，调用方推送后负责删除
+func prepareChangeRef(r *git.Repository, source, target string) (plumbing.ReferenceName, string, error) {
+	refName := plumbing.NewBranchReferenceName(source)
+	ref, err := r.Reference(refName, true)
+	if err != nil {
+		return "", "", fmt.Errorf("branch %s not found: %w", source, err)
+	}
+	base, err := targetAncestors(r, target)
+	if err != nil {
+		return "", "", err
+	}
+
+	rewritten := map[plumbing.Hash]plumbing.Hash{}
+	var rewrite func(h plumbing.Hash) (plumbing.Hash, error)
+	rewrite = func(h plumbing.Hash) (plumbing.Hash, error) {
+		if base[h] {
+			return h, nil
+		}
+		if nh, ok := rewritten[h]; ok {
+			return nh, nil
+		}
+		commit, err := r.CommitObject(h)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		changed := false
+		parents := make([]plumbing.Hash, 0, len(commit.ParentHashes))
+		for _, p := range commit.ParentHashes {
+			np, err := rewrite(p)
+			if err != nil {
+				return plumbing.ZeroHash, err
+			}
+			changed = changed || np != p
+			parents = append(parents, np)
+		}
+		message := commit.Message
+		if !changeIDRe.MatchString(message) {
+			message = appendTrailer(message, "Change-Id: "+deriveChangeID(commit))
+			changed = true
+		}
+		if !changed {
+			rewritten[h] = h
+			return h, nil
+		}
+		amended := &object.Commit{
+			Author:       commit.Author,
+			Committer:    commit.Committer,
+			Message:      message,
+			TreeHash:     commit.TreeHash,
+			ParentHashes: parents,
+		}
+		obj := r.Storer.NewEncodedObject()
+		if err := amended.Encode(obj); err != nil {
+			return plumbing.ZeroHash, err
+		}
+		nh, err := r.Storer.SetEncodedObject(obj)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		rewritten[h] = nh
+		return nh, nil
+	}
+
+	tip, err := rewrite(ref.Hash())
+	if err != nil {
+		return "", "", fmt.Errorf("add Change-Id: %w", err)
+	}
+	commit, err := r.CommitObject(tip)
+	if err != nil {
+		return "", "", err
+	}
+	m := changeIDRe.FindStringSubmatch(commit.Message)
+	if m == nil {
+		return "", "", fmt.Errorf("branch %s has no commits ahead of %s", source, target)
+	}
+	if tip == ref.Hash() {
+		return refName, m[1], nil
+	}
+	pushRef := plumbing.ReferenceName(gerritPushRefPrefix + source)
+	if err := r.Storer.SetReference(plumbing.NewHashReference(pushRef, tip)); err != nil {
+		return "", "", err
+	}
+	return pushRef, m[1], nil
+}
+
+// targetAncestors 收集本地目标分支（或同名远程跟踪分支）可达的提交，这些提交已在 Gerrit 上，不需要 Change-Id
+func targetAncestors(r *git.Repository, target string) (map[plumbing.Hash]bool, error) {
+	var tips []plumbing.Hash
+	if ref, err := r.Reference(plumbing.NewBranchReferenceName(target), true); err == nil {
+		tips = append(tips, ref.Hash())
+	}
+	remotes, err := r.Remotes()
+	if err != nil {
+		return nil, err
+	}
+	for _, rm := range remotes {
+		if ref, err := r.Reference(plumbing.NewRemoteReferenceName(rm.Config().Name, target), true); err == nil {
+			tips = append(tips, ref.Hash())
+		}
+	}
+	if len(tips) == 0 {
+		return nil, fmt.Errorf("target branch %s not found locally: fetch it before creating a change", target)
+	}
+
+	base := map[plumbing.Hash]bool{}
+	for _, tip := range tips {
+		if base[tip] {
+			continue
+		}
+		iter, err := r.Log(&git.LogOptions{From: tip})
+		if err != nil {
+			return nil, err
+		}
+		err = iter.ForEach(func(c *object.Commit) error {
+			base[c.Hash] = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return base, nil
+}
+
+// deriveChangeID 由原提交生成 Change-Id，同一提交多次推送得到相同的 change
+func deriveChangeID(commit *object.Commit) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s\n%s\n%s", commit.TreeHash, commit.Hash, commit.Message)))
+	return "I" + hex.EncodeToString(sum[:])
+}
+
+// gerritMessageBody 去掉 Gerrit 为消息自动加的首行（"Patch Set 2:"、"Uploaded patch set 2."）
+func gerritMessageBody(msg string) string {
+	msg = strings.TrimSpace(msg)
+	first, rest, _ := strings.Cut(msg, "\n")
+	if gerritMessageHeaderRe.MatchString(first) {
+		return strings.TrimSpace(rest)
+	}
+	return msg
+}
+
+// appendTrailer 追加 trailer：说明末段已是 trailer（Key: value）时直接续行，否则空一行
+func appendTrailer(message, trailer string) string {
+	msg := strings.TrimRight(message, "\n")
+	paragraphs := strings.Split(msg, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	isTrailer := len(paragraphs) > 1
+	for _, line := range strings.Split(last, "\n") {
+		if !trailerLineRe.MatchString(line) {
+			isTrailer = false
+			break
+		}
+	}
+	if isTrailer {
+		return msg + "\n" + trailer + "\n"
+	}
+	return msg + "\n\n" + trailer + "\n"
+}
+
+func (g *gerritProvider) GetCR(ctx context.Context, owner, repo string, number int) (*ChangeRequest, error) {
+	var c gerritChange
+	if err := g.doRequest(ctx, "GET", changePath(owner, repo, number, "?o=DETAILED_ACCOUNTS&o=DETAILED_LABELS&o=CURRENT_REVISION"), nil, &c); err != nil {
+		return nil, err
+	}
+	return c.toCR(g.baseURL), nil
+}
+
+func (g *gerritProvider) ListCRs(ctx context.Context, opts ListCROptions) ([]*ChangeRequest, int, error) {
+	if opts.Page == 0 {
+		opts.Page = 1
+	}
+	if opts.PerPage == 0 {
+		opts.PerPage = 20
+	}
+	terms := []string{"project:" + gerritProjectName(opts.Owner, opts.Repo)}
+	switch opts.State {
+	case CRStateOpened:
+		terms = append(terms, "status:open")
+	case CRStateMerged:
+		terms = append(terms, "status:merged")
+	case CRStateClosed:
+		terms = append(terms, "status:abandoned")
+	}
+	if opts.TargetBranch != "" {
+		terms = append(terms, "branch:"+opts.TargetBranch)
+	}
+	if opts.SourceBranch != "" {
+		terms = append(terms, "topic:"+opts.SourceBranch)
+	}
+	q := url.Values{}
+	q.Set("q", strings.Join(terms, " "))
+	q.Set("n", strconv.Itoa(opts.PerPage))
+	q.Set("S", strconv.Itoa((opts.Page-1)*opts.PerPage))
+	q["o"] = []string{"DETAILED_ACCOUNTS", "CURRENT_REVISION"}
+	var changes []gerritChange
+	if err := g.doRequest(ctx, "GET", "/changes/?"+q.Encode(), nil, &changes); err != nil {
+		return nil, 0, err
+	}
+	crs := make([]*ChangeRequest, 0, len(changes))
+	for i := range changes {
+		crs = append(crs, changes[i].toCR(g.baseURL))
+	}
+	return crs, len(crs), nil
+}
+
+// MergeCR 提交（submit）change；Gerrit 的合并策略由项目配置决定，Squash 等选项不生效
+func (g *gerritProvider) MergeCR(ctx context.Context, owner, repo string, number int, opts MergeCROptions) (*ChangeRequest, error) {
+	var c gerritChange
+	if err := g.doRequest(ctx, "POST", changePath(owner, repo, number, "/submit"), map[string]interface{}{}, &c); err != nil {
+		return nil, err
+	}
+	return c.toCR(g.baseURL), nil
+}
+
+// CloseCR 放弃（abandon）change
+func (g *gerritProvider) CloseCR(ctx context.Context, owner, repo string, number int) (*ChangeRequest, error) {
+	var c gerritChange
+	if err := g.doRequest(ctx, "POST", changePath(owner, repo, number, "/abandon"), map[string]interface{}{}, &c); err != nil {
+		return nil, err
+	}
+	return c.toCR(g.baseURL), nil
+}
+
+// UpdateCR Gerrit 的标题来自提交说明，修改需要新的补丁集；这里仅把描述作为评审消息发布，
+// 与 CreateCR 通过推送选项 m 设置描述的方式一致；内容与最近一条消息相同时不重复发布
+func (g *gerritProvider) UpdateCR(ctx context.Context, owner, repo string, number int, opts UpdateCROptions) (*ChangeRequest, error) {
+	if opts.Description != "" {
+		var messages []struct {
+			Message string `json:"message"`
+		}
+		if err := g.doRequest(ctx, "GET", changePath(owner, repo, number, "/messages"), nil, &messages); err != nil {
+			return nil, err
+		}
+		if len(messages) > 0 && gerritMessageBody(messages[len(messages)-1].Message) == strings.TrimSpace(opts.Description) {
+			return g.GetCR(ctx, owner, repo, number)
+		}
+		if err := g.postReview(ctx, owner, repo, number, "", map[string]interface{}{"message": opts.Description}); err != nil {
+			return nil, err
+		}
+	}
+	return g.GetCR(ctx, owner, repo, number)
+}
+
+// ListCRComments 合并 change 消息（不含系统自动生成的）与各文件上的行内评论
+func (g *gerritProvider) ListCRComments(ctx context.Context, owner, repo string, number int) ([]*CRComment, error) {
+	var messages []struct {
+		ID      string        `json:"id"`
+		Author  gerritAccount `json:"author"`
+		Message string        `json:"message"`
+		Date    string        `json:"date"`
+		Tag     string        `json:"tag"`
+	}
+	if err := g.doRequest(ctx, "GET", changePath(owner, repo, number, "/messages"), nil, &messages); err != nil {
+		return nil, err
+	}
+	var inline map[string][]struct {
+		ID      string        `json:"id"`
+		Line    int           `json:"line"`
+		Message string        `json:"message"`
+		Author  gerritAccount `json:"author"`
+		Updated string        `json:"updated"`
+	}
+	if err := g.doRequest(ctx, "GET", changePath(owner, repo, number, "/comments"), nil, &inline); err != nil {
+		return nil, err
+	}
+	var result []*CRComment
+	for i, m := range messages {
+		if strings.HasPrefix(m.Tag, "autogenerated:") {
+			continue
+		}
+		c := &CRComment{ID: int64(i + 1), Body: m.Message, Author: messages[i].Author.toUser()}
+		c.CreatedAt, _ = time.Parse(gerritTimeLayout, m.Date)
+		result = append(result, c)
+	}
+	for path, comments := range inline {
+		for i, ic := range comments {
+			c := &CRComment{Body: ic.Message, Path: path, Line: ic.Line, Author: comments[i].Author.toUser()}
+			c.CreatedAt, _ = time.Parse(gerritTimeLayout, ic.Updated)
+			result = append(result, c)
+		}
+	}
+	sortComments(result)
+	return result, nil
+}
+
+// AddCRComment 通过 review 接口在指定 revision（默认当前 patch set）上发表评论
+func (g *gerritProvider) AddCRComment(ctx context.Context, owner, repo string, number int, opts AddCRCommentOptions) (*CRComment, error) {
+	body := map[string]interface{}{}
+	if opts.inline() {
+		body["comments"] = map[string]interface{}{
+			opts.Path: []map[string]interface{}{{"line": opts.Line, "message": opts.Body}},
+		}
+	} else {
+		body["message"] = opts.Body
+	}
+	if err := g.postReview(ctx, owner, repo, number, opts.CommitSHA, body); err != nil {
+		return nil, err
+	}
+	c := &CRComment{Body: opts.Body, CreatedAt: time.Now()}
+	if opts.inline() {
+		c.Path, c.Line = opts.Path, opts.Line
+	}
+	return c, nil
+}
+
+// ReviewCR 批准为 Code-Review +2，要求修改为 Code-Review -1
+func (g *gerritProvider) ReviewCR(ctx context.Context, owner, repo string, number int, opts ReviewCROptions) error {
+	vote := 2
+	if opts.Action == ReviewRequestChanges {
+		vote = -1
+	}
+	body := map[string]interface{}{"labels": map[string]int{"Code-Review": vote}}
+	if opts.Body != "" {
+		body["message"] = opts.Body
+	}
+	return g.postReview(ctx, owner, repo, number, "", body)
+}
+
+func (g *gerritProvider) postReview(ctx context.Context, owner, repo string, number int, revision string, body map[string]interface{}) error {
+	if revision == "" {
+		revision = "current"
+	}
+	return g.doRequest(ctx, "POST", changePath(owner, repo, number, "/revisions/"+url.PathEscape(revision)+"/review"), body, nil)
+}
+
+// GetCommitStatuses Gerrit 没有 commit status，CI 结果以包含该提交的 change 上的 Verified 标签表示
+func (g *gerritProvider) GetCommitStatuses(ctx context.Context, owner, repo, sha string) ([]*CommitStatus, error) {
+	q := url.Values{}
+	q.Set("q", "commit:"+sha+" project:"+gerritProjectName(owner, repo))
+	q.Set("o", "LABELS")
+	var changes []gerritChange
+	if err := g.doRequest(ctx, "GET", "/changes/?"+q.Encode(), nil, &changes); err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	label, ok := changes[0].LabelInfo["Verified"]
+	if !ok {
+		return nil, nil
+	}
+	state := CheckStatePending
+	switch {
+	case label.Rejected != nil || label.Disliked != nil:
+		state = CheckStateFailure
+	case label.Approved != nil || label.Recommended != nil:
+		state = CheckStateSuccess
+	}
+	return []*CommitStatus{{Name: "Verified", State: state}}, nil
+}
+
+func (g *gerritProvider) CreateRelease(ctx context.Context, opts CreateReleaseOptions) (*Release, error) {
+	return nil, unsupportedOp(PlatformGerrit, "CreateRelease")
+}
+
+func (g *gerritProvider) UpdateRelease(ctx context.Context, owner, repo, tag string, opts UpdateReleaseOptions) (*Release, error) {
+	return nil, unsupportedOp(PlatformGerrit, "UpdateRelease")
+}
+
+func (g *gerritProvider) ListReleases(ctx context.Context, owner, repo string) ([]*Release, error) {
+	return nil, unsupportedOp(PlatformGerrit, "ListReleases")
+}
+
+func (g *gerritProvider) UploadReleaseAsset(ctx context.Context, owner, repo, tag string, asset ReleaseAssetUpload) (*ReleaseAsset, error) {
+	return nil, unsupportedOp(PlatformGerrit, "UploadReleaseAsset")
+}
+
+// CreateWebhook 通过 webhooks 插件的项目级 remote 配置回调，remote 名为 gms-<ID>
+func (g *gerritProvider) CreateWebhook(ctx context.Context, opts CreateWebhookOptions) (*PlatformWebhook, error) {
+	existing, err := g.ListWebhooks(ctx, opts.Owner, opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+	var id int64 = 1
+	for _, wh := range existing {
+		if wh.ID >= id {
+			id = wh.ID + 1
+		}
+	}
+	events := gerritWebhookEvents(opts.Events)
+	hookURL := opts.URL
+	if opts.Secret != "" {
+		// webhooks 插件不支持签名，密钥以 token 参数附在回调地址上
+		sep := "?"
+		if strings.Contains(hookURL, "?") {
+			sep = "&"
+		}
+		hookURL += sep + "token=" + url.QueryEscape(opts.Secret)
+	}
+	body := map[string]interface{}{"url": hookURL, "events": events}
+	if err := g.doRequest(ctx, "PUT", webhookPath(opts.Owner, opts.Repo, id), body, nil); err != nil {
+		return nil, err
+	}
+	return &PlatformWebhook{ID: id, URL: opts.URL, Events: events}, nil
+}
+
+func (g *gerritProvider) DeleteWebhook(ctx context.Context, owner, repo string, webhookID int64) error {
+	return g.doRequest(ctx, "DELETE", webhookPath(owner, repo, webhookID), nil, nil)
+}
+
+func (g *gerritProvider) ListWebhooks(ctx context.Context, owner, repo string) ([]*PlatformWebhook, error) {
+	path := fmt.Sprintf("/config/server/webhooks~projects/%s/remotes/", url.PathEscape(gerritProjectName(owner, repo)))
+	var remotes map[string]struct {
+		URL    string   `json:"url"`
+		Events []string `json:"events"`
+	}
+	if err := g.doRequest(ctx, "GET", path, nil, &remotes); err != nil {
+		return nil, err
+	}
+	result := make([]*PlatformWebhook, 0, len(remotes))
+	for name, rm := range remotes {
+		id, err := strconv.ParseInt(strings.TrimPrefix(name, gerritWebhookPrefix), 10, 64)
+		if err != nil || !strings.HasPrefix(name, gerritWebhookPrefix) {
+			continue
+		}
+		result = append(result, &PlatformWebhook{ID: id, URL: rm.URL, Events: rm.Events})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func webhookPath(owner, repo string, id int64) string {
+	return fmt.Sprintf("/config/server/webhooks~projects/%s/remotes/%s%d", url.PathEscape(gerritProjectName(owner, repo)), gerritWebhookPrefix, id)
+}
+
+// gerritWebhookEvents 将通用事件名（push / cr / tag）转换为 Gerrit 事件类型
+func gerritWebhookEvents(events []string) []string {
+	if len(events) == 0 {
+		events = []string{"push", "cr"}
+	}
+	seen := map[string]bool{}
+	var result []string
+	for _, e := range events {
+		var keys []string
+		switch e {
+		case "push", "tag":
+			keys = []string{"ref-updated"}
+		case "cr", "pull_request", "merge_request":
+			keys = []string{"patchset-created", "change-merged", "change-abandoned", "change-restored", "comment-added"}
+		default:
+			keys = []string{e}
+		}
+		for _, k := range keys {
+			if !seen[k] {
+				seen[k] = true
+				result = append(result, k)
+			}
+		}
+	}
+	return result
+}
+
+// ParseWebhookEvent 解析 webhooks 插件推送的事件，格式与 stream-events 相同
+func (g *gerritProvider) ParseWebhookEvent(r *http.Request, secret string) (*NormalizedEvent, error) {
+	if !signatureCheckSkipped(r) {
+		if err := g.ValidateWebhookSignature(r, secret); err != nil {
+			return nil, err
+		}
+	}
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return ParseGerritEvent(body)
+}
+
+// ValidateWebhookSignature webhooks 插件不签名，配置了密钥时校验回调地址中的 token 参数或 X-Gerrit-Token 请求头
+func (g *gerritProvider) ValidateWebhookSignature(r *http.Request, secret string) error {
+	if secret == "" {
+		return nil
+	}
+	token := r.Header.Get("X-Gerrit-Token")
+	if token == "" && r.URL != nil {
+		token = r.URL.Query().Get("token")
+	}
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return fmt.Errorf("invalid Gerrit webhook token")
+	}
+	return nil
+}
+
+// ParseGerritEvent 将一条 stream-events（或 webhooks 插件）JSON 事件转换为 NormalizedEvent
+func ParseGerritEvent(data []byte) (*NormalizedEvent, error) {
+	var ev struct {
+		Type           string        `json:"type"`
+		EventCreatedOn int64         `json:"eventCreatedOn"`
+		Change         *gerritChange `json:"change"`
+		PatchSet       *struct {
+			Number   gerritInt     `json:"number"`
+			Revision string        `json:"revision"`
+			Ref      string        `json:"ref"`
+			Uploader gerritAccount `json:"uploader"`
+		} `json:"patchSet"`
+		RefUpdate *struct {
+			OldRev  string `json:"oldRev"`
+			NewRev  string `json:"newRev"`
+			RefName string `json:"refName"`
+			Project string `json:"project"`
+		} `json:"refUpdate"`
+		Submitter *gerritAccount `json:"submitter"`
+		Abandoner *gerritAccount `json:"abandoner"`
+		Restorer  *gerritAccount `json:"restorer"`
+		Author    *gerritAccount `json:"author"`
+		Uploader  *gerritAccount `json:"uploader"`
+		Changer   *gerritAccount `json:"changer"`
+	}
+	if err := json.Unmarshal(bytes.TrimPrefix(bytes.TrimSpace(data), []byte(gerritXSSIPrefix)), &ev); err != nil {
+		return nil, err
+	}
+	if ev.Type == "" {
+		return nil, fmt.Errorf("missing gerrit event type")
+	}
+
+	ts := time.Now()
+	if ev.EventCreatedOn > 0 {
+		ts = time.Unix(ev.EventCreatedOn, 0)
+	}
+	var number, patchSet int
+	if ev.Change != nil {
+		number = int(ev.Change.Number)
+	}
+	if ev.PatchSet != nil {
+		patchSet = int(ev.PatchSet.Number)
+	}
+	event := &NormalizedEvent{
+		// 以事件内容生成稳定 ID，插件重投时可去重
+		ID:         fmt.Sprintf("gerrit-%s-%d-%d-%d", ev.Type, number, patchSet, ev.EventCreatedOn),
+		Source:     PlatformGerrit,
+		Timestamp:  ts,
+		RawPayload: data,
+	}
+	for _, a := range []*gerritAccount{ev.Submitter, ev.Abandoner, ev.Restorer, ev.Author, ev.Uploader, ev.Changer} {
+		if a != nil {
+			event.Actor = a.toUser()
+			break
+		}
+	}
+
+	project := ""
+	if ev.Change != nil {
+		project = ev.Change.Project
+	} else if ev.RefUpdate != nil {
+		project = ev.RefUpdate.Project
+	}
+	if project != "" {
+		er := &EventRepo{FullName: project, Name: project}
+		if i := strings.LastIndex(project, "/"); i > 0 {
+			er.Owner, er.Name = project[:i], project[i+1:]
+		}
+		event.Repo = er
+	}
+
+	switch ev.Type {
+	case "ref-updated":
+		event.Type = "push"
+		if ev.RefUpdate != nil {
+			ref := ev.RefUpdate.RefName
+			created := strings.Trim(ev.RefUpdate.OldRev, "0") == ""
+			deleted := strings.Trim(ev.RefUpdate.NewRev, "0") == ""
+			if strings.HasPrefix(ref, "refs/tags/") {
+				event.Tag = strings.TrimPrefix(ref, "refs/tags/")
+				event.Type = "tag.created"
+				if deleted {
+					event.Type = "tag.deleted"
+				}
+			} else {
+				event.Branch = strings.TrimPrefix(ref, "refs/heads/")
+				if created {
+					event.Type = "branch.created"
+				} else if deleted {
+					event.Type = "branch.deleted"
+				}
+			}
+		}
+	case "patchset-created":
+		event.Type = "cr.updated"
+		if patchSet <= 1 {
+			event.Type = "cr.opened"
+		}
+	case "change-merged":
+		event.Type = "cr.merged"
+	case "change-abandoned", "change-deleted":
+		event.Type = "cr.closed"
+	case "change-restored":
+		event.Type = "cr.reopened"
+	case "comment-added":
+		event.Type = "cr.commented"
+	default:
+		event.Type = "gerrit." + ev.Type
+	}
+
+	if ev.Change != nil && strings.HasPrefix(event.Type, "cr.") {
+		event.CR = ev.Change.toCR("")
+		if event.Actor == nil {
+			event.Actor = event.CR.Author
+		}
+	}
+	return event, nil
+}
+
+func (g *gerritProvider) doRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	// /a/ 前缀表示需要认证的 REST 接口
+	resp, err := g.api.do(ctx, method, "/a"+path, body)
+	if err != nil {
+		return err
+	}
+	resp.Body = bytes.TrimPrefix(resp.Body, []byte(gerritXSSIPrefix))
+	return resp.decode(result)
+}
+
+// gerritProjectName owner/repo 还原为 Gerrit 项目名（项目名本身可以多级）
+func gerritProjectName(owner, repo string) string {
+	if owner == "" {
+		return repo
+	}
+	return owner + "/" + repo
+}
+
+func changePath(owner, repo string, number int, suffix string) string {
+	return fmt.Sprintf("/changes/%s~%d%s", url.PathEscape(gerritProjectName(owner, repo)), number, suffix)
+}
+
+// gerritInt 兼容旧版 stream-events 中以字符串表示的数字
+type gerritInt int
+
+func (n *gerritInt) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	*n = gerritInt(v)
+	return nil
+}
+
+type gerritAccount struct {
+	AccountID int64  `json:"_account_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Username  string `json:"username"`
+}
+
+func (a *gerritAccount) toUser() *CRUser {
+	if a == nil || (a.Username == "" && a.Name == "" && a.Email == "") {
+		return nil
+	}
+	username := a.Username
+	if username == "" {
+		username = a.Email
+	}
+	return &CRUser{ID: a.AccountID, Username: username, Name: a.Name}
+}
+
+// gerritLabel o=LABELS 返回的标签汇总，各字段为给出对应投票的账号
+type gerritLabel struct {
+	Approved    *gerritAccount `json:"approved"`
+	Rejected    *gerritAccount `json:"rejected"`
+	Recommended *gerritAccount `json:"recommended"`
+	Disliked    *gerritAccount `json:"disliked"`
+}
+
+type gerritProject struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+}
+
+// gerritChange 同时兼容 REST ChangeInfo 与 stream-events 中的 change 属性
+type gerritChange struct {
+	ID        string        `json:"id"`
+	Project   string        `json:"project"`
+	Branch    string        `json:"branch"`
+	Topic     string        `json:"topic"`
+	ChangeID  string        `json:"change_id"`
+	Subject   string        `json:"subject"`
+	Status    string        `json:"status"` // NEW | MERGED | ABANDONED
+	Number    gerritInt     `json:"_number"`
+	Owner     gerritAccount `json:"owner"`
+	Created   string        `json:"created"`
+	Updated   string        `json:"updated"`
+	Mergeable *bool         `json:"mergeable"`
+	Hashtags  []string      `json:"hashtags"`
+	URL       string        `json:"url"`
+	// CurrentRevision 需 o=CURRENT_REVISION
+	CurrentRevision string                 `json:"current_revision"`
+	LabelInfo       map[string]gerritLabel `json:"labels"`
+	// stream-events 字段
+	StreamNumber  gerritInt `json:"number"`
+	CommitMessage string    `json:"commitMessage"`
+	Open          *bool     `json:"open"`
+}
+
+func (c *gerritChange) UnmarshalJSON(b []byte) error {
+	type raw gerritChange
+	if err := json.Unmarshal(b, (*raw)(c)); err != nil {
+		return err
+	}
+	if c.Number == 0 {
+		c.Number = c.StreamNumber
+	}
+	// stream-events 中 id 为 Change-Id
+	if c.ChangeID == "" && bareChangeIDRe.MatchString(c.ID) {
+		c.ChangeID = c.ID
+	}
+	return nil
+}
+
+func (c *gerritChange) toCR(baseURL string) *ChangeRequest {
+	cr := &ChangeRequest{
+		ID: int64(c.Number), Number: int(c.Number), Title: c.Subject,
+		State:        mapGerritStatus(c.Status),
+		SourceBranch: c.Topic, TargetBranch: c.Branch,
+		Author:      c.Owner.toUser(),
+		Labels:      c.Hashtags,
+		MergeStatus: "unknown",
+		WebURL:      c.URL,
+		HeadSHA:     c.CurrentRevision,
+	}
+	if c.CommitMessage != "" {
+		cr.Description = c.CommitMessage
+	}
+	if c.Mergeable != nil {
+		cr.MergeStatus = "conflicting"
+		if *c.Mergeable {
+			cr.MergeStatus = "mergeable"
+		}
+	}
+	if cr.WebURL == "" && baseURL != "" && c.Number > 0 {
+		cr.WebURL = fmt.Sprintf("%s/c/%s/+/%d", baseURL, c.Project, c.Number)
+	}
+	if t, err := time.Parse(gerritTimeLayout, c.Created); err == nil {
+		cr.CreatedAt = t
+	}
+	if t, err := time.Parse(gerritTimeLayout, c.Updated); err == nil {
+		cr.UpdatedAt = t
+	}
+	return cr
+}
+
+func mapGerritStatus(status string) CRState {
+	switch strings.ToUpper(status) {
+	case "MERGED":
+		return CRStateMerged
+	case "ABANDONED":
+		return CRStateClosed
+	}
+	return CRStateOpened
+}
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestGerritGetCRStripsXSSIPrefix(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/a/changes/platform%2Ftools~42" {
+			http.NotFound(w, r)
+			return
+		}
+		if u, p, ok := r.BasicAuth(); !ok || u != "bot" || p != "pw" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(")]}'\n" + `{"project":"platform/tools","branch":"main","topic":"feat-x","subject":"Add x",
+			"status":"MERGED","_number":42,"owner":{"_account_id":7,"username":"alice"},
+			"created":"2024-05-01 08:00:00.000000000"}`))
+	}))
+	defer srv.Close()
+
+	cr, err := NewGerritProvider(srv.URL, "bot", "pw").GetCR(context.Background(), "platform", "tools", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cr.Number != 42 || cr.State != CRStateMerged || cr.SourceBranch != "feat-x" || cr.Author.Username != "alice" {
+		t.Errorf("unexpected CR: %+v", cr)
+	}
+}
+
+func TestParseGerritEvent(t *testing.T) {
+	ev, err := ParseGerritEvent([]byte(`{"type":"patchset-created","eventCreatedOn":1714550400,
+		"change":{"project":"platform/tools","branch":"main","id":"I0123456789abcdef0123456789abcdef01234567","number":"42","subject":"Add x","status":"NEW","owner":{"username":"alice"}},
+		"patchSet":{"number":"2","ref":"refs/changes/42/42/2"},
+		"uploader":{"name":"Bob","username":"bob"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Type != "cr.updated" || ev.CR.Number != 42 || ev.Repo.Owner != "platform" || ev.Actor.Username != "bob" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+
+	ev, err = ParseGerritEvent([]byte(`{"type":"ref-updated","refUpdate":{"oldRev":"0000000000000000000000000000000000000000","newRev":"abc","refName":"refs/heads/release","project":"tools"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Type != "branch.created" || ev.Branch != "release" || ev.Repo.FullName != "tools" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+}
+
+func TestAppendTrailer(t *testing.T) {
+	cases := map[string]string{
+		"Fix bug":                   "Fix bug\n\nChange-Id: Iabc\n",
+		"Fix bug\n\nDetails here\n": "Fix bug\n\nDetails here\n\nChange-Id: Iabc\n",
+		"Fix bug\n\nSigned-off-by: A <a@x.com>\n": "Fix bug\n\nSigned-off-by: A <a@x.com>\nChange-Id: Iabc\n",
+	}
+	for in, want := range cases {
+		if got := appendTrailer(in, "Change-Id: Iabc"); got != want {
+			t.Errorf("appendTrailer(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPrepareChangeRefKeepsSourceBranch(t *testing.T) {
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := func(msg string) plumbing.Hash {
+		if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte(msg), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Add("f.txt"); err != nil {
+			t.Fatal(err)
+		}
+		h, err := w.Commit(msg, &git.CommitOptions{Author: &object.Signature{Name: "T", Email: "t@x.com", When: time.Now()}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	base := commit("base")
+	if err := r.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), base)); err != nil {
+		t.Fatal(err)
+	}
+	commit("first")
+	tip := commit("second")
+	if err := r.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("feature"), tip)); err != nil {
+		t.Fatal(err)
+	}
+
+	pushRef, changeID, err := prepareChangeRef(r, "feature", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pushRef == plumbing.NewBranchReferenceName("feature") {
+		t.Fatalf("expected temporary ref, got %s", pushRef)
+	}
+	if ref, _ := r.Reference(plumbing.NewBranchReferenceName("feature"), true); ref.Hash() != tip {
+		t.Errorf("source branch rewritten: %s", ref.Hash())
+	}
+	ref, err := r.Reference(pushRef, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := r.CommitObject(ref.Hash())
+	first, _ := second.Parent(0)
+	if !strings.Contains(second.Message, "Change-Id: "+changeID) || !changeIDRe.MatchString(first.Message) {
+		t.Errorf("missing Change-Id: %q / %q", first.Message, second.Message)
+	}
+	if first.ParentHashes[0] != base {
+		t.Errorf("rewrite went past target branch: parent %s", first.ParentHashes[0])
+	}
+
+	again, againID, err := prepareChangeRef(r, "feature", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref2, _ := r.Reference(again, true); ref2.Hash() != ref.Hash() || againID != changeID {
+		t.Errorf("rewrite not deterministic: %s %s", ref2.Hash(), againID)
+	}
+}
+
+func TestGerritUpdateCRSkipsUnchangedMessage(t *testing.T) {
+	posted := 0
+	last := "Uploaded patch set 1.\n\nChangelog v1"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a/changes/platform%2Ftools~42/messages", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(")]}'\n" + fmt.Sprintf(`[{"message":"Created"},{"message":%q}]`, last)))
+	})
+	mux.HandleFunc("/a/changes/platform%2Ftools~42/revisions/current/review", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		posted++
+		last = "Patch Set 1:\n\n" + body.Message
+		w.Write([]byte(")]}'\n{}"))
+	})
+	mux.HandleFunc("/a/changes/platform%2Ftools~42", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(")]}'\n" + `{"project":"platform/tools","branch":"main","_number":42,"status":"NEW"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p := NewGerritProvider(srv.URL, "bot", "pw")
+	ctx := context.Background()
+	for _, desc := range []string{"Changelog v1", "Changelog v2", "Changelog v2"} {
+		if _, err := p.UpdateCR(ctx, "platform", "tools", 42, UpdateCROptions{Description: desc}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if posted != 1 {
+		t.Errorf("expected only the changed description to be posted, got %d posts", posted)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -14,18 +16,21 @@ import (
 type giteaProvider struct {
 	baseURL string
 	token   string
-	client  *http.Client
+	api     *apiClient
 }
 
 func NewGiteaProvider(baseURL, token string) *giteaProvider {
 	if baseURL == "" {
 		baseURL = "https://gitea.com/api/v1"
 	}
-	return &giteaProvider{
+	g := &giteaProvider{
 		baseURL: strings.TrimRight(baseURL, "/"),
 		token:   token,
-		client:  &http.Client{Timeout: 30 * time.Second},
 	}
+	g.api = newAPIClient("Gitea", g.baseURL, func(req *http.Request) {
+		req.Header.Set("Authorization", "token "+token)
+	})
+	return g
 }
 
 func (g *giteaProvider) Platform() Platform { return PlatformGitea }
@@ -41,31 +46,66 @@ func (g *giteaProvider) TestConnection(ctx context.Context) (*TestConnectionResu
 }
 
 func (g *giteaProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([]*PlatformRepo, error) {
-	path := "/repos/search"
 	if opts.Page == 0 {
 		opts.Page = 1
 	}
 	if opts.PerPage == 0 {
 		opts.PerPage = 20
 	}
-	path = fmt.Sprintf("%s?page=%d&limit=%d", path, opts.Page, opts.PerPage)
-	var result struct {
-		Data []struct {
-			ID            int    `json:"id"`
-			FullName      string `json:"full_name"`
-			Name          string `json:"name"`
-			Description   string `json:"description"`
-			CloneURL      string `json:"clone_url"`
-			SSHURL        string `json:"ssh_url"`
-			DefaultBranch string `json:"default_branch"`
-			Private       bool   `json:"private"`
-		} `json:"data"`
-	}
-	if err := g.doRequest(ctx, "GET", path, nil, &result); err != nil {
+	type giteaRepo struct {
+		ID            int    `json:"id"`
+		FullName      string `json:"full_name"`
+		Name          string `json:"name"`
+		Description   string `json:"description"`
+		CloneURL      string `json:"clone_url"`
+		SSHURL        string `json:"ssh_url"`
+		DefaultBranch string `json:"default_branch"`
+		Private       bool   `json:"private"`
+		Internal      bool   `json:"internal"`
+		Archived      bool   `json:"archived"`
+	}
+	// 单页上限由服务端 MAX_RESPONSE_ITEMS 决定（默认 50）
+	list := func(path string) ([]giteaRepo, error) {
+		if opts.All {
+			return collectPages[giteaRepo](ctx, g.api.pages(path+"?limit=50"))
+		}
+		var repos []giteaRepo
+		err := g.doRequest(ctx, "GET", fmt.Sprintf("%s?page=%d&limit=%d", path, opts.Page, opts.PerPage), nil, &repos)
+		return repos, err
+	}
+	var data []giteaRepo
+	var err error
+	if opts.Owner == "" {
+		var result struct {
+			Data []giteaRepo `json:"data"`
+		}
+		if opts.All {
+			it := g.api.pages("/repos/search?limit=50")
+			for it.More() {
+				result.Data = nil
+				if err = it.Next(ctx, &result); err != nil {
+					break
+				}
+				data = append(data, result.Data...)
+			}
+			if err == nil {
+				err = it.Err()
+			}
+		} else {
+			err = g.doRequest(ctx, "GET", fmt.Sprintf("/repos/search?page=%d&limit=%d", opts.Page, opts.PerPage), nil, &result)
+			data = result.Data
+		}
+	} else {
+		data, err = list(fmt.Sprintf("/orgs/%s/repos", opts.Owner))
+		if errors.Is(err, ErrNotFound) {
+			data, err = list(fmt.Sprintf("/users/%s/repos", opts.Owner))
+		}
+	}
+	if err != nil {
 		return nil, err
 	}
-	repos := make([]*PlatformRepo, 0, len(result.Data))
-	for _, r := range result.Data {
+	repos := make([]*PlatformRepo, 0, len(data))
+	for _, r := range data {
 		parts := strings.SplitN(r.FullName, "/", 2)
 		owner := ""
 		if len(parts) == 2 {
@@ -75,6 +115,7 @@ func (g *giteaProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([]
 			ID: int64(r.ID), FullName: r.FullName, Name: r.Name, Owner: owner,
 			Description: r.Description, CloneURL: r.CloneURL, SSHURL: r.SSHURL,
 			DefaultBranch: r.DefaultBranch, Private: r.Private, Platform: g.Platform(),
+			Visibility: giteaVisibility(r.Private, r.Internal), Archived: r.Archived,
 		})
 	}
 	return repos, nil
@@ -90,6 +131,8 @@ func (g *giteaProvider) GetRepo(ctx context.Context, owner, repo string) (*Platf
 		SSHURL        string `json:"ssh_url"`
 		DefaultBranch string `json:"default_branch"`
 		Private       bool   `json:"private"`
+		Internal      bool   `json:"internal"`
+		Archived      bool   `json:"archived"`
 	}
 	if err := g.doRequest(ctx, "GET", fmt.Sprintf("/repos/%s/%s", owner, repo), nil, &r); err != nil {
 		return nil, err
@@ -103,9 +146,103 @@ func (g *giteaProvider) GetRepo(ctx context.Context, owner, repo string) (*Platf
 		ID: int64(r.ID), FullName: r.FullName, Name: r.Name, Owner: ownerR,
 		Description: r.Description, CloneURL: r.CloneURL, SSHURL: r.SSHURL,
 		DefaultBranch: r.DefaultBranch, Private: r.Private, Platform: g.Platform(),
+		Visibility: giteaVisibility(r.Private, r.Internal), Archived: r.Archived,
+	}, nil
+}
+
+// CreateRepo Owner 为空或为当前用户时建在用户名下，否则建在组织下；Gitea 没有 internal，按 private 处理
+func (g *giteaProvider) CreateRepo(ctx context.Context, opts CreateRepoOptions) (*PlatformRepo, error) {
+	body := map[string]interface{}{
+		"name": opts.Name, "description": opts.Description,
+		"private": opts.visibility() != VisibilityPublic,
+	}
+	if opts.DefaultBranch != "" {
+		body["default_branch"] = opts.DefaultBranch
+	}
+	path := "/user/repos"
+	if opts.Owner != "" {
+		var user struct {
+			Login string `json:"login"`
+		}
+		if err := g.doRequest(ctx, "GET", "/user", nil, &user); err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(user.Login, opts.Owner) {
+			path = fmt.Sprintf("/orgs/%s/repos", opts.Owner)
+		}
+	}
+	var r struct {
+		ID            int    `json:"id"`
+		FullName      string `json:"full_name"`
+		Name          string `json:"name"`
+		Description   string `json:"description"`
+		CloneURL      string `json:"clone_url"`
+		SSHURL        string `json:"ssh_url"`
+		DefaultBranch string `json:"default_branch"`
+		Private       bool   `json:"private"`
+		Internal      bool   `json:"internal"`
+		Archived      bool   `json:"archived"`
+	}
+	if err := g.doRequest(ctx, "POST", path, body, &r); err != nil {
+		return nil, err
+	}
+	owner := ""
+	if parts := strings.SplitN(r.FullName, "/", 2); len(parts) == 2 {
+		owner = parts[0]
+	}
+	return &PlatformRepo{
+		ID: int64(r.ID), FullName: r.FullName, Name: r.Name, Owner: owner,
+		Description: r.Description, CloneURL: r.CloneURL, SSHURL: r.SSHURL,
+		DefaultBranch: r.DefaultBranch, Private: r.Private, Platform: g.Platform(),
+		Visibility: giteaVisibility(r.Private, r.Internal), Archived: r.Archived,
 	}, nil
 }
 
+func (g *giteaProvider) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*BranchProtection, error) {
+	var bp giteaBranchProtection
+	err := g.doRequest(ctx, "GET", fmt.Sprintf("/repos/%s/%s/branch_protections/%s", owner, repo, url.PathEscape(branch)), nil, &bp)
+	if errors.Is(err, ErrNotFound) {
+		return &BranchProtection{Branch: branch}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bp.toProtection(branch), nil
+}
+
+// SetBranchProtection 新建规则时保留推送权限（enable_push），否则同步任务将无法推送
+func (g *giteaProvider) SetBranchProtection(ctx context.Context, owner, repo string, protection BranchProtection) (*BranchProtection, error) {
+	rulePath := fmt.Sprintf("/repos/%s/%s/branch_protections/%s", owner, repo, url.PathEscape(protection.Branch))
+	if !protection.Protected {
+		if err := g.doRequest(ctx, "DELETE", rulePath, nil, nil); err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		return &BranchProtection{Branch: protection.Branch}, nil
+	}
+
+	checks := protection.RequiredChecks
+	if checks == nil {
+		checks = []string{}
+	}
+	body := map[string]interface{}{
+		"required_approvals":    protection.RequiredReviews,
+		"enable_status_check":   protection.RequireChecks,
+		"status_check_contexts": checks,
+		"enable_force_push":     protection.AllowForcePush,
+	}
+	var bp giteaBranchProtection
+	err := g.doRequest(ctx, "PATCH", rulePath, body, &bp)
+	if errors.Is(err, ErrNotFound) {
+		body["rule_name"] = protection.Branch
+		body["enable_push"] = true
+		err = g.doRequest(ctx, "POST", fmt.Sprintf("/repos/%s/%s/branch_protections", owner, repo), body, &bp)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bp.toProtection(protection.Branch), nil
+}
+
 func (g *giteaProvider) CreateCR(ctx context.Context, opts CreateCROptions) (*ChangeRequest, error) {
 	body := map[string]interface{}{
 		"title": opts.Title, "body": opts.Description,
@@ -173,6 +310,194 @@ func (g *giteaProvider) CloseCR(ctx context.Context, owner, repo string, number
 	return pr.toCR(), nil
 }
 
+func (g *giteaProvider) UpdateCR(ctx context.Context, owner, repo string, number int, opts UpdateCROptions) (*ChangeRequest, error) {
+	body := map[string]interface{}{}
+	if opts.Title != "" {
+		body["title"] = opts.Title
+	}
+	if opts.Description != "" {
+		body["body"] = opts.Description
+	}
+	var pr giteaPR
+	if err := g.doRequest(ctx, "PATCH", fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repo, number), body, &pr); err != nil {
+		return nil, err
+	}
+	return pr.toCR(), nil
+}
+
+// ListCRComments 合并 PR 对话评论与各次评审中的行内评论
+func (g *giteaProvider) ListCRComments(ctx context.Context, owner, repo string, number int) ([]*CRComment, error) {
+	comments, err := collectPages[giteaComment](ctx, g.api.pages(fmt.Sprintf("/repos/%s/%s/issues/%d/comments?limit=50", owner, repo, number)))
+	if err != nil {
+		return nil, err
+	}
+	type giteaReview struct {
+		ID            int64 `json:"id"`
+		CommentsCount int   `json:"comments_count"`
+	}
+	reviews, err := collectPages[giteaReview](ctx, g.api.pages(fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews?limit=50", owner, repo, number)))
+	if err != nil {
+		return nil, err
+	}
+	for _, rv := range reviews {
+		if rv.CommentsCount == 0 {
+			continue
+		}
+		var reviewComments []giteaComment
+		if err := g.doRequest(ctx, "GET", fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews/%d/comments", owner, repo, number, rv.ID), nil, &reviewComments); err != nil {
+			return nil, err
+		}
+		comments = append(comments, reviewComments...)
+	}
+	result := make([]*CRComment, 0, len(comments))
+	for i := range comments {
+		result = append(result, comments[i].toComment())
+	}
+	sortComments(result)
+	return result, nil
+}
+
+// AddCRComment 行内评论以只含该评论的 COMMENT 评审提交
+func (g *giteaProvider) AddCRComment(ctx context.Context, owner, repo string, number int, opts AddCRCommentOptions) (*CRComment, error) {
+	if !opts.inline() {
+		var c giteaComment
+		path := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", owner, repo, number)
+		if err := g.doRequest(ctx, "POST", path, map[string]interface{}{"body": opts.Body}, &c); err != nil {
+			return nil, err
+		}
+		return c.toComment(), nil
+	}
+	body := map[string]interface{}{
+		"event": "COMMENT", "commit_id": opts.CommitSHA,
+		"comments": []map[string]interface{}{{"path": opts.Path, "body": opts.Body, "new_position": opts.Line}},
+	}
+	var review struct {
+		ID          int64     `json:"id"`
+		SubmittedAt time.Time `json:"submitted_at"`
+		User        struct {
+			ID    int    `json:"id"`
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if err := g.doRequest(ctx, "POST", fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews", owner, repo, number), body, &review); err != nil {
+		return nil, err
+	}
+	return &CRComment{
+		ID: review.ID, Body: opts.Body, Path: opts.Path, Line: opts.Line, CreatedAt: review.SubmittedAt,
+		Author: &CRUser{ID: int64(review.User.ID), Username: review.User.Login},
+	}, nil
+}
+
+func (g *giteaProvider) ReviewCR(ctx context.Context, owner, repo string, number int, opts ReviewCROptions) error {
+	event := "APPROVED"
+	if opts.Action == ReviewRequestChanges {
+		event = "REQUEST_CHANGES"
+	}
+	body := map[string]interface{}{"event": event, "body": opts.Body}
+	return g.doRequest(ctx, "POST", fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews", owner, repo, number), body, nil)
+}
+
+func (g *giteaProvider) GetCommitStatuses(ctx context.Context, owner, repo, sha string) ([]*CommitStatus, error) {
+	var combined struct {
+		Statuses []struct {
+			Context     string `json:"context"`
+			Status      string `json:"status"`
+			Description string `json:"description"`
+			TargetURL   string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := g.doRequest(ctx, "GET", fmt.Sprintf("/repos/%s/%s/commits/%s/status", owner, repo, sha), nil, &combined); err != nil {
+		return nil, err
+	}
+	result := make([]*CommitStatus, 0, len(combined.Statuses))
+	for _, st := range combined.Statuses {
+		state := CheckStateFailure
+		switch st.Status {
+		case "success", "warning":
+			state = CheckStateSuccess
+		case "pending", "":
+			state = CheckStatePending
+		}
+		result = append(result, &CommitStatus{Name: st.Context, State: state, Description: st.Description, TargetURL: st.TargetURL})
+	}
+	return result, nil
+}
+
+func (g *giteaProvider) CreateRelease(ctx context.Context, opts CreateReleaseOptions) (*Release, error) {
+	body := map[string]interface{}{
+		"tag_name": opts.TagName, "name": opts.Name, "body": opts.Body,
+		"draft": opts.Draft, "prerelease": opts.Prerelease,
+	}
+	if opts.Target != "" {
+		body["target_commitish"] = opts.Target
+	}
+	var rel giteaRelease
+	if err := g.doRequest(ctx, "POST", fmt.Sprintf("/repos/%s/%s/releases", opts.Owner, opts.Repo), body, &rel); err != nil {
+		return nil, err
+	}
+	return rel.toRelease(), nil
+}
+
+func (g *giteaProvider) UpdateRelease(ctx context.Context, owner, repo, tag string, opts UpdateReleaseOptions) (*Release, error) {
+	rel, err := g.releaseByTag(ctx, owner, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	var updated giteaRelease
+	if err := g.doRequest(ctx, "PATCH", fmt.Sprintf("/repos/%s/%s/releases/%d", owner, repo, rel.ID), releaseUpdateBody(opts), &updated); err != nil {
+		return nil, err
+	}
+	return updated.toRelease(), nil
+}
+
+func (g *giteaProvider) ListReleases(ctx context.Context, owner, repo string) ([]*Release, error) {
+	rels, err := collectPages[giteaRelease](ctx, g.api.pages(fmt.Sprintf("/repos/%s/%s/releases?limit=50", owner, repo)))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Release, 0, len(rels))
+	for i := range rels {
+		result = append(result, rels[i].toRelease())
+	}
+	return result, nil
+}
+
+func (g *giteaProvider) UploadReleaseAsset(ctx context.Context, owner, repo, tag string, asset ReleaseAssetUpload) (*ReleaseAsset, error) {
+	rel, err := g.releaseByTag(ctx, owner, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	body, contentType := multipartBody("attachment", asset.Name, asset.Content)
+	path := fmt.Sprintf("/repos/%s/%s/releases/%d/assets?name=%s", owner, repo, rel.ID, url.QueryEscape(asset.Name))
+	var a giteaReleaseAsset
+	if err := g.api.upload(ctx, "POST", path, contentType, body, -1, &a); err != nil {
+		return nil, err
+	}
+	return a.toAsset(), nil
+}
+
+// releaseByTag 草稿发布不能按 tag 查询，查不到时再从列表中匹配
+func (g *giteaProvider) releaseByTag(ctx context.Context, owner, repo, tag string) (*giteaRelease, error) {
+	var rel giteaRelease
+	err := g.doRequest(ctx, "GET", fmt.Sprintf("/repos/%s/%s/releases/tags/%s", owner, repo, url.PathEscape(tag)), nil, &rel)
+	if err == nil {
+		return &rel, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	rels, err := collectPages[giteaRelease](ctx, g.api.pages(fmt.Sprintf("/repos/%s/%s/releases?limit=50", owner, repo)))
+	if err != nil {
+		return nil, err
+	}
+	for i := range rels {
+		if rels[i].TagName == tag {
+			return &rels[i], nil
+		}
+	}
+	return nil, fmt.Errorf("release for tag %s: %w", tag, ErrNotFound)
+}
+
 func (g *giteaProvider) CreateWebhook(ctx context.Context, opts CreateWebhookOptions) (*PlatformWebhook, error) {
 	events := opts.Events
 	if len(events) == 0 {
@@ -197,11 +522,12 @@ func (g *giteaProvider) DeleteWebhook(ctx context.Context, owner, repo string, w
 }
 
 func (g *giteaProvider) ListWebhooks(ctx context.Context, owner, repo string) ([]*PlatformWebhook, error) {
-	var whs []struct {
+	type giteaHook struct {
 		ID  int    `json:"id"`
 		URL string `json:"url"`
 	}
-	if err := g.doRequest(ctx, "GET", fmt.Sprintf("/repos/%s/%s/hooks", owner, repo), nil, &whs); err != nil {
+	whs, err := collectPages[giteaHook](ctx, g.api.pages(fmt.Sprintf("/repos/%s/%s/hooks?limit=50", owner, repo)))
+	if err != nil {
 		return nil, err
 	}
 	result := make([]*PlatformWebhook, 0, len(whs))
@@ -212,8 +538,10 @@ func (g *giteaProvider) ListWebhooks(ctx context.Context, owner, repo string) ([
 }
 
 func (g *giteaProvider) ParseWebhookEvent(r *http.Request, secret string) (*NormalizedEvent, error) {
-	if err := g.ValidateWebhookSignature(r, secret); err != nil {
-		return nil, err
+	if !signatureCheckSkipped(r) {
+		if err := g.ValidateWebhookSignature(r, secret); err != nil {
+			return nil, err
+		}
 	}
 	body, _ := io.ReadAll(r.Body)
 	r.Body = io.NopCloser(bytes.NewReader(body))
@@ -312,33 +640,7 @@ func (g *giteaProvider) ValidateWebhookSignature(r *http.Request, secret string)
 }
 
 func (g *giteaProvider) doRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
-	var reqBody io.Reader
-	if body != nil {
-		b, err := json.Marshal(body)
-		if err != nil {
-			return err
-		}
-		reqBody = bytes.NewReader(b)
-	}
-	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, reqBody)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Authorization", "token "+g.token)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := g.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	respBody, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("Gitea API %s %s returned %d: %s", method, path, resp.StatusCode, string(respBody))
-	}
-	if result != nil && resp.StatusCode != http.StatusNoContent {
-		return json.Unmarshal(respBody, result)
-	}
-	return nil
+	return g.api.doJSON(ctx, method, path, body, result)
 }
 
 type giteaPR struct {
@@ -349,6 +651,7 @@ type giteaPR struct {
 	State  string `json:"state"`
 	Head   struct {
 		Ref string `json:"ref"`
+		SHA string `json:"sha"`
 	} `json:"head"`
 	Base struct {
 		Ref string `json:"ref"`
@@ -369,7 +672,32 @@ func (pr *giteaPR) toCR() *ChangeRequest {
 		State:        mapGiteaState(pr.State, pr.Merged),
 		SourceBranch: pr.Head.Ref, TargetBranch: pr.Base.Ref,
 		Author: &CRUser{ID: int64(pr.User.ID), Username: pr.User.Login},
-		WebURL: pr.HTMLURL, CreatedAt: pr.CreatedAt, UpdatedAt: pr.UpdatedAt,
+		WebURL: pr.HTMLURL, HeadSHA: pr.Head.SHA, CreatedAt: pr.CreatedAt, UpdatedAt: pr.UpdatedAt,
+	}
+}
+
+// giteaComment issue 评论与评审行内评论共用，行内评论带 path / position
+type giteaComment struct {
+	ID               int64  `json:"id"`
+	Body             string `json:"body"`
+	Path             string `json:"path"`
+	Position         int    `json:"position"`
+	OriginalPosition int    `json:"original_position"`
+	User             struct {
+		ID    int    `json:"id"`
+		Login string `json:"login"`
+	} `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c *giteaComment) toComment() *CRComment {
+	line := c.Position
+	if line == 0 {
+		line = c.OriginalPosition
+	}
+	return &CRComment{
+		ID: c.ID, Body: c.Body, Path: c.Path, Line: line, CreatedAt: c.CreatedAt,
+		Author: &CRUser{ID: int64(c.User.ID), Username: c.User.Login},
 	}
 }
 
@@ -382,3 +710,69 @@ func mapGiteaState(state string, merged bool) CRState {
 	}
 	return CRStateOpened
 }
+
+// giteaVisibility Gitea 以 private / internal 两个布尔值表示可见性
+func giteaVisibility(private, internal bool) string {
+	switch {
+	case internal:
+		return VisibilityInternal
+	case private:
+		return VisibilityPrivate
+	}
+	return VisibilityPublic
+}
+
+type giteaBranchProtection struct {
+	RuleName            string   `json:"rule_name"`
+	RequiredApprovals   int      `json:"required_approvals"`
+	EnableStatusCheck   bool     `json:"enable_status_check"`
+	StatusCheckContexts []string `json:"status_check_contexts"`
+	EnableForcePush     bool     `json:"enable_force_push"`
+}
+
+func (p *giteaBranchProtection) toProtection(branch string) *BranchProtection {
+	bp := &BranchProtection{
+		Branch: branch, Protected: true,
+		RequiredReviews: p.RequiredApprovals, AllowForcePush: p.EnableForcePush,
+		RequireChecks: p.EnableStatusCheck,
+	}
+	if p.EnableStatusCheck {
+		bp.RequiredChecks = p.StatusCheckContexts
+	}
+	return bp
+}
+
+type giteaRelease struct {
+	ID         int64               `json:"id"`
+	TagName    string              `json:"tag_name"`
+	Name       string              `json:"name"`
+	Body       string              `json:"body"`
+	Draft      bool                `json:"draft"`
+	Prerelease bool                `json:"prerelease"`
+	HTMLURL    string              `json:"html_url"`
+	CreatedAt  time.Time           `json:"created_at"`
+	Assets     []giteaReleaseAsset `json:"assets"`
+}
+
+type giteaReleaseAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+func (r *giteaRelease) toRelease() *Release {
+	rel := &Release{
+		ID: r.ID, TagName: r.TagName, Name: r.Name, Body: r.Body,
+		Draft: r.Draft, Prerelease: r.Prerelease, WebURL: r.HTMLURL, CreatedAt: r.CreatedAt,
+		Assets: make([]*ReleaseAsset, 0, len(r.Assets)),
+	}
+	for i := range r.Assets {
+		rel.Assets = append(rel.Assets, r.Assets[i].toAsset())
+	}
+	return rel
+}
+
+func (a *giteaReleaseAsset) toAsset() *ReleaseAsset {
+	return &ReleaseAsset{ID: a.ID, Name: a.Name, Size: a.Size, DownloadURL: a.BrowserDownloadURL}
+}
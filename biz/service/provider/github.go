@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -14,18 +16,26 @@ import (
 type githubProvider struct {
 	baseURL string
 	token   string
-	client  *http.Client
+	api     *apiClient
 }
 
 func NewGitHubProvider(baseURL, token string) *githubProvider {
 	if baseURL == "" {
 		baseURL = "https://api.github.com"
 	}
-	return &githubProvider{
+	g := &githubProvider{
 		baseURL: strings.TrimRight(baseURL, "/"),
 		token:   token,
-		client:  &http.Client{Timeout: 30 * time.Second},
 	}
+	g.api = newAPIClient("GitHub", g.baseURL, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+	})
+	// github.com 的附件上传地址在 uploads.github.com，GitHub Enterprise 与 API 同主机
+	if u, err := url.Parse(g.baseURL); err == nil && strings.HasPrefix(u.Host, "api.") {
+		g.api.trustedHosts = []string{"uploads." + strings.TrimPrefix(u.Host, "api.")}
+	}
+	return g
 }
 
 func (g *githubProvider) Platform() Platform { return PlatformGitHub }
@@ -41,18 +51,13 @@ func (g *githubProvider) TestConnection(ctx context.Context) (*TestConnectionRes
 }
 
 func (g *githubProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([]*PlatformRepo, error) {
-	path := "/user/repos"
-	if opts.Owner != "" {
-		path = fmt.Sprintf("/users/%s/repos", opts.Owner)
-	}
 	if opts.Page == 0 {
 		opts.Page = 1
 	}
 	if opts.PerPage == 0 {
 		opts.PerPage = 20
 	}
-	path = fmt.Sprintf("%s?page=%d&per_page=%d", path, opts.Page, opts.PerPage)
-	var repos []struct {
+	type githubRepo struct {
 		ID            int    `json:"id"`
 		FullName      string `json:"full_name"`
 		Name          string `json:"name"`
@@ -61,8 +66,29 @@ func (g *githubProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([
 		SSHURL        string `json:"ssh_url"`
 		DefaultBranch string `json:"default_branch"`
 		Private       bool   `json:"private"`
+		Visibility    string `json:"visibility"`
+		Archived      bool   `json:"archived"`
+	}
+	list := func(path string) ([]githubRepo, error) {
+		if opts.All {
+			return collectPages[githubRepo](ctx, g.api.pages(path+"?per_page=100"))
+		}
+		var repos []githubRepo
+		err := g.doRequest(ctx, "GET", fmt.Sprintf("%s?page=%d&per_page=%d", path, opts.Page, opts.PerPage), nil, &repos)
+		return repos, err
+	}
+	var repos []githubRepo
+	var err error
+	if opts.Owner == "" {
+		repos, err = list("/user/repos")
+	} else {
+		// 组织接口包含成员可见的私有仓库，owner 为个人用户时回退到用户接口
+		repos, err = list(fmt.Sprintf("/orgs/%s/repos", opts.Owner))
+		if errors.Is(err, ErrNotFound) {
+			repos, err = list(fmt.Sprintf("/users/%s/repos", opts.Owner))
+		}
 	}
-	if err := g.doRequest(ctx, "GET", path, nil, &repos); err != nil {
+	if err != nil {
 		return nil, err
 	}
 	result := make([]*PlatformRepo, 0, len(repos))
@@ -76,6 +102,7 @@ func (g *githubProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([
 			ID: int64(r.ID), FullName: r.FullName, Name: r.Name, Owner: owner,
 			Description: r.Description, CloneURL: r.CloneURL, SSHURL: r.SSHURL,
 			DefaultBranch: r.DefaultBranch, Private: r.Private, Platform: g.Platform(),
+			Visibility: r.Visibility, Archived: r.Archived,
 		})
 	}
 	return result, nil
@@ -91,6 +118,8 @@ func (g *githubProvider) GetRepo(ctx context.Context, owner, repo string) (*Plat
 		SSHURL        string `json:"ssh_url"`
 		DefaultBranch string `json:"default_branch"`
 		Private       bool   `json:"private"`
+		Visibility    string `json:"visibility"`
+		Archived      bool   `json:"archived"`
 	}
 	if err := g.doRequest(ctx, "GET", fmt.Sprintf("/repos/%s/%s", owner, repo), nil, &r); err != nil {
 		return nil, err
@@ -104,9 +133,123 @@ func (g *githubProvider) GetRepo(ctx context.Context, owner, repo string) (*Plat
 		ID: int64(r.ID), FullName: r.FullName, Name: r.Name, Owner: ownerR,
 		Description: r.Description, CloneURL: r.CloneURL, SSHURL: r.SSHURL,
 		DefaultBranch: r.DefaultBranch, Private: r.Private, Platform: g.Platform(),
+		Visibility: r.Visibility, Archived: r.Archived,
 	}, nil
 }
 
+// CreateRepo Owner 为空或为当前用户时建在用户名下，否则建在组织下。
+// GitHub 空仓库没有分支，默认分支由首次推送决定，DefaultBranch 不生效。
+func (g *githubProvider) CreateRepo(ctx context.Context, opts CreateRepoOptions) (*PlatformRepo, error) {
+	body := map[string]interface{}{
+		"name": opts.Name, "description": opts.Description,
+		"private": opts.visibility() != VisibilityPublic,
+	}
+	path := "/user/repos"
+	if opts.Owner != "" {
+		var user struct {
+			Login string `json:"login"`
+		}
+		if err := g.doRequest(ctx, "GET", "/user", nil, &user); err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(user.Login, opts.Owner) {
+			path = fmt.Sprintf("/orgs/%s/repos", opts.Owner)
+			// internal 仅组织仓库可用
+			body["visibility"] = opts.visibility()
+		}
+	}
+	var r struct {
+		ID            int    `json:"id"`
+		FullName      string `json:"full_name"`
+		Name          string `json:"name"`
+		Description   string `json:"description"`
+		CloneURL      string `json:"clone_url"`
+		SSHURL        string `json:"ssh_url"`
+		DefaultBranch string `json:"default_branch"`
+		Private       bool   `json:"private"`
+		Visibility    string `json:"visibility"`
+		Archived      bool   `json:"archived"`
+	}
+	if err := g.doRequest(ctx, "POST", path, body, &r); err != nil {
+		return nil, err
+	}
+	owner := ""
+	if parts := strings.SplitN(r.FullName, "/", 2); len(parts) == 2 {
+		owner = parts[0]
+	}
+	return &PlatformRepo{
+		ID: int64(r.ID), FullName: r.FullName, Name: r.Name, Owner: owner,
+		Description: r.Description, CloneURL: r.CloneURL, SSHURL: r.SSHURL,
+		DefaultBranch: r.DefaultBranch, Private: r.Private, Platform: g.Platform(),
+		Visibility: r.Visibility, Archived: r.Archived,
+	}, nil
+}
+
+func (g *githubProvider) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*BranchProtection, error) {
+	var p githubProtection
+	err := g.doRequest(ctx, "GET", g.protectionPath(owner, repo, branch), nil, &p)
+	if errors.Is(err, ErrNotFound) {
+		return &BranchProtection{Branch: branch}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.toProtection(branch), nil
+}
+
+// SetBranchProtection PUT 会整体覆盖保护设置，未由 BranchProtection 表达的选项（管理员约束、推送限制、
+// strict、评审附加要求、线性历史等）沿用当前值
+func (g *githubProvider) SetBranchProtection(ctx context.Context, owner, repo string, protection BranchProtection) (*BranchProtection, error) {
+	path := g.protectionPath(owner, repo, protection.Branch)
+	if !protection.Protected {
+		if err := g.doRequest(ctx, "DELETE", path, nil, nil); err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		return &BranchProtection{Branch: protection.Branch}, nil
+	}
+
+	var current githubProtection
+	if err := g.doRequest(ctx, "GET", path, nil, &current); err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	body := map[string]interface{}{
+		"required_status_checks":           nil,
+		"enforce_admins":                   current.EnforceAdmins.Enabled,
+		"required_pull_request_reviews":    nil,
+		"restrictions":                     current.restrictions(),
+		"allow_force_pushes":               protection.AllowForcePush,
+		"required_linear_history":          current.RequiredLinearHistory.Enabled,
+		"allow_deletions":                  current.AllowDeletions.Enabled,
+		"required_conversation_resolution": current.RequiredConversationResolution.Enabled,
+	}
+	if protection.RequireChecks {
+		contexts := protection.RequiredChecks
+		if contexts == nil {
+			contexts = []string{}
+		}
+		strict := current.RequiredStatusChecks != nil && current.RequiredStatusChecks.Strict
+		body["required_status_checks"] = map[string]interface{}{"strict": strict, "contexts": contexts}
+	}
+	if protection.RequiredReviews > 0 {
+		reviews := map[string]interface{}{"required_approving_review_count": protection.RequiredReviews}
+		if r := current.RequiredPullRequestReviews; r != nil {
+			reviews["dismiss_stale_reviews"] = r.DismissStaleReviews
+			reviews["require_code_owner_reviews"] = r.RequireCodeOwnerReviews
+			reviews["require_last_push_approval"] = r.RequireLastPushApproval
+		}
+		body["required_pull_request_reviews"] = reviews
+	}
+	var updated githubProtection
+	if err := g.doRequest(ctx, "PUT", path, body, &updated); err != nil {
+		return nil, err
+	}
+	return updated.toProtection(protection.Branch), nil
+}
+
+func (g *githubProvider) protectionPath(owner, repo, branch string) string {
+	return fmt.Sprintf("/repos/%s/%s/branches/%s/protection", owner, repo, url.PathEscape(branch))
+}
+
 func (g *githubProvider) CreateCR(ctx context.Context, opts CreateCROptions) (*ChangeRequest, error) {
 	body := map[string]interface{}{
 		"title": opts.Title, "body": opts.Description,
@@ -175,6 +318,196 @@ func (g *githubProvider) CloseCR(ctx context.Context, owner, repo string, number
 	return pr.toCR(), nil
 }
 
+func (g *githubProvider) UpdateCR(ctx context.Context, owner, repo string, number int, opts UpdateCROptions) (*ChangeRequest, error) {
+	body := map[string]interface{}{}
+	if opts.Title != "" {
+		body["title"] = opts.Title
+	}
+	if opts.Description != "" {
+		body["body"] = opts.Description
+	}
+	var pr githubPR
+	if err := g.doRequest(ctx, "PATCH", fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repo, number), body, &pr); err != nil {
+		return nil, err
+	}
+	return pr.toCR(), nil
+}
+
+// ListCRComments 合并 PR 对话评论（issue comments）与行内评审评论
+func (g *githubProvider) ListCRComments(ctx context.Context, owner, repo string, number int) ([]*CRComment, error) {
+	issueComments, err := collectPages[githubComment](ctx, g.api.pages(fmt.Sprintf("/repos/%s/%s/issues/%d/comments?per_page=100", owner, repo, number)))
+	if err != nil {
+		return nil, err
+	}
+	reviewComments, err := collectPages[githubComment](ctx, g.api.pages(fmt.Sprintf("/repos/%s/%s/pulls/%d/comments?per_page=100", owner, repo, number)))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*CRComment, 0, len(issueComments)+len(reviewComments))
+	for i := range issueComments {
+		result = append(result, issueComments[i].toComment())
+	}
+	for i := range reviewComments {
+		result = append(result, reviewComments[i].toComment())
+	}
+	sortComments(result)
+	return result, nil
+}
+
+func (g *githubProvider) AddCRComment(ctx context.Context, owner, repo string, number int, opts AddCRCommentOptions) (*CRComment, error) {
+	var c githubComment
+	if !opts.inline() {
+		path := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", owner, repo, number)
+		if err := g.doRequest(ctx, "POST", path, map[string]interface{}{"body": opts.Body}, &c); err != nil {
+			return nil, err
+		}
+		return c.toComment(), nil
+	}
+	if opts.CommitSHA == "" {
+		cr, err := g.GetCR(ctx, owner, repo, number)
+		if err != nil {
+			return nil, err
+		}
+		opts.CommitSHA = cr.HeadSHA
+	}
+	body := map[string]interface{}{
+		"body": opts.Body, "commit_id": opts.CommitSHA,
+		"path": opts.Path, "line": opts.Line, "side": "RIGHT",
+	}
+	if err := g.doRequest(ctx, "POST", fmt.Sprintf("/repos/%s/%s/pulls/%d/comments", owner, repo, number), body, &c); err != nil {
+		return nil, err
+	}
+	return c.toComment(), nil
+}
+
+func (g *githubProvider) ReviewCR(ctx context.Context, owner, repo string, number int, opts ReviewCROptions) error {
+	event := "APPROVE"
+	if opts.Action == ReviewRequestChanges {
+		event = "REQUEST_CHANGES"
+	}
+	body := map[string]interface{}{"event": event, "body": opts.Body}
+	return g.doRequest(ctx, "POST", fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews", owner, repo, number), body, nil)
+}
+
+// GetCommitStatuses 合并 commit status（外部 CI）与 check run（GitHub Actions 等）
+func (g *githubProvider) GetCommitStatuses(ctx context.Context, owner, repo, sha string) ([]*CommitStatus, error) {
+	var combined struct {
+		Statuses []struct {
+			Context     string `json:"context"`
+			State       string `json:"state"`
+			Description string `json:"description"`
+			TargetURL   string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := g.doRequest(ctx, "GET", fmt.Sprintf("/repos/%s/%s/commits/%s/status?per_page=100", owner, repo, sha), nil, &combined); err != nil {
+		return nil, err
+	}
+	var runs struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+		} `json:"check_runs"`
+	}
+	if err := g.doRequest(ctx, "GET", fmt.Sprintf("/repos/%s/%s/commits/%s/check-runs?per_page=100", owner, repo, sha), nil, &runs); err != nil {
+		return nil, err
+	}
+	result := make([]*CommitStatus, 0, len(combined.Statuses)+len(runs.CheckRuns))
+	for _, st := range combined.Statuses {
+		result = append(result, &CommitStatus{
+			Name: st.Context, State: mapGHStatusState(st.State),
+			Description: st.Description, TargetURL: st.TargetURL,
+		})
+	}
+	for _, run := range runs.CheckRuns {
+		state := CheckStatePending
+		if run.Status == "completed" {
+			state = mapGHStatusState(run.Conclusion)
+		}
+		result = append(result, &CommitStatus{Name: run.Name, State: state, Description: run.Conclusion, TargetURL: run.HTMLURL})
+	}
+	return result, nil
+}
+
+func (g *githubProvider) CreateRelease(ctx context.Context, opts CreateReleaseOptions) (*Release, error) {
+	body := map[string]interface{}{
+		"tag_name": opts.TagName, "name": opts.Name, "body": opts.Body,
+		"draft": opts.Draft, "prerelease": opts.Prerelease,
+	}
+	if opts.Target != "" {
+		body["target_commitish"] = opts.Target
+	}
+	var rel githubRelease
+	if err := g.doRequest(ctx, "POST", fmt.Sprintf("/repos/%s/%s/releases", opts.Owner, opts.Repo), body, &rel); err != nil {
+		return nil, err
+	}
+	return rel.toRelease(), nil
+}
+
+func (g *githubProvider) UpdateRelease(ctx context.Context, owner, repo, tag string, opts UpdateReleaseOptions) (*Release, error) {
+	rel, err := g.releaseByTag(ctx, owner, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	var updated githubRelease
+	if err := g.doRequest(ctx, "PATCH", fmt.Sprintf("/repos/%s/%s/releases/%d", owner, repo, rel.ID), releaseUpdateBody(opts), &updated); err != nil {
+		return nil, err
+	}
+	return updated.toRelease(), nil
+}
+
+func (g *githubProvider) ListReleases(ctx context.Context, owner, repo string) ([]*Release, error) {
+	rels, err := collectPages[githubRelease](ctx, g.api.pages(fmt.Sprintf("/repos/%s/%s/releases?per_page=100", owner, repo)))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Release, 0, len(rels))
+	for i := range rels {
+		result = append(result, rels[i].toRelease())
+	}
+	return result, nil
+}
+
+// UploadReleaseAsset 上传地址取自发布的 upload_url（RFC 6570 模板），要求已知长度
+func (g *githubProvider) UploadReleaseAsset(ctx context.Context, owner, repo, tag string, asset ReleaseAssetUpload) (*ReleaseAsset, error) {
+	if asset.Size < 0 {
+		return nil, fmt.Errorf("GitHub requires the asset size to be known: %s", asset.Name)
+	}
+	rel, err := g.releaseByTag(ctx, owner, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	uploadURL := strings.SplitN(rel.UploadURL, "{", 2)[0] + "?name=" + url.QueryEscape(asset.Name)
+	var a githubReleaseAsset
+	if err := g.api.upload(ctx, "POST", uploadURL, asset.ContentType, asset.Content, asset.Size, &a); err != nil {
+		return nil, err
+	}
+	return a.toAsset(), nil
+}
+
+// releaseByTag 草稿发布没有关联 tag，tags 接口查不到时再从列表中匹配
+func (g *githubProvider) releaseByTag(ctx context.Context, owner, repo, tag string) (*githubRelease, error) {
+	var rel githubRelease
+	err := g.doRequest(ctx, "GET", fmt.Sprintf("/repos/%s/%s/releases/tags/%s", owner, repo, url.PathEscape(tag)), nil, &rel)
+	if err == nil {
+		return &rel, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	rels, err := collectPages[githubRelease](ctx, g.api.pages(fmt.Sprintf("/repos/%s/%s/releases?per_page=100", owner, repo)))
+	if err != nil {
+		return nil, err
+	}
+	for i := range rels {
+		if rels[i].TagName == tag {
+			return &rels[i], nil
+		}
+	}
+	return nil, fmt.Errorf("release for tag %s: %w", tag, ErrNotFound)
+}
+
 func (g *githubProvider) CreateWebhook(ctx context.Context, opts CreateWebhookOptions) (*PlatformWebhook, error) {
 	events := opts.Events
 	if len(events) == 0 {
@@ -199,11 +532,12 @@ func (g *githubProvider) DeleteWebhook(ctx context.Context, owner, repo string,
 }
 
 func (g *githubProvider) ListWebhooks(ctx context.Context, owner, repo string) ([]*PlatformWebhook, error) {
-	var whs []struct {
+	type githubHook struct {
 		ID  int    `json:"id"`
 		URL string `json:"url"`
 	}
-	if err := g.doRequest(ctx, "GET", fmt.Sprintf("/repos/%s/%s/hooks", owner, repo), nil, &whs); err != nil {
+	whs, err := collectPages[githubHook](ctx, g.api.pages(fmt.Sprintf("/repos/%s/%s/hooks?per_page=100", owner, repo)))
+	if err != nil {
 		return nil, err
 	}
 	result := make([]*PlatformWebhook, 0, len(whs))
@@ -214,8 +548,10 @@ func (g *githubProvider) ListWebhooks(ctx context.Context, owner, repo string) (
 }
 
 func (g *githubProvider) ParseWebhookEvent(r *http.Request, secret string) (*NormalizedEvent, error) {
-	if err := g.ValidateWebhookSignature(r, secret); err != nil {
-		return nil, err
+	if !signatureCheckSkipped(r) {
+		if err := g.ValidateWebhookSignature(r, secret); err != nil {
+			return nil, err
+		}
 	}
 	body, _ := io.ReadAll(r.Body)
 	r.Body = io.NopCloser(bytes.NewReader(body))
@@ -324,34 +660,7 @@ func (g *githubProvider) ValidateWebhookSignature(r *http.Request, secret string
 }
 
 func (g *githubProvider) doRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
-	var reqBody io.Reader
-	if body != nil {
-		b, err := json.Marshal(body)
-		if err != nil {
-			return err
-		}
-		reqBody = bytes.NewReader(b)
-	}
-	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, reqBody)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Authorization", "Bearer "+g.token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := g.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	respBody, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("GitHub API %s %s returned %d: %s", method, path, resp.StatusCode, string(respBody))
-	}
-	if result != nil && resp.StatusCode != http.StatusNoContent {
-		return json.Unmarshal(respBody, result)
-	}
-	return nil
+	return g.api.doJSON(ctx, method, path, body, result)
 }
 
 type githubPR struct {
@@ -362,6 +671,7 @@ type githubPR struct {
 	State  string `json:"state"`
 	Head   struct {
 		Ref string `json:"ref"`
+		SHA string `json:"sha"`
 	} `json:"head"`
 	Base struct {
 		Ref string `json:"ref"`
@@ -393,11 +703,42 @@ func (pr *githubPR) toCR() *ChangeRequest {
 		ID: int64(pr.Number), Number: pr.Number, Title: pr.Title, Description: pr.Body,
 		State: state, SourceBranch: pr.Head.Ref, TargetBranch: pr.Base.Ref,
 		Author:      &CRUser{ID: int64(pr.User.ID), Username: pr.User.Login},
-		MergeStatus: mergeStatus, WebURL: pr.HTMLURL,
+		MergeStatus: mergeStatus, WebURL: pr.HTMLURL, HeadSHA: pr.Head.SHA,
 		CreatedAt: pr.CreatedAt, UpdatedAt: pr.UpdatedAt,
 	}
 }
 
+type githubComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	User struct {
+		ID    int    `json:"id"`
+		Login string `json:"login"`
+	} `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c *githubComment) toComment() *CRComment {
+	return &CRComment{
+		ID: c.ID, Body: c.Body, Path: c.Path, Line: c.Line, CreatedAt: c.CreatedAt,
+		Author: &CRUser{ID: int64(c.User.ID), Username: c.User.Login},
+	}
+}
+
+// mapGHStatusState commit status 的 state 与 check run 的 conclusion
+func mapGHStatusState(state string) CheckState {
+	switch state {
+	case "success", "neutral", "skipped":
+		return CheckStateSuccess
+	case "pending", "":
+		return CheckStatePending
+	default: // failure, error, cancelled, timed_out, action_required
+		return CheckStateFailure
+	}
+}
+
 func mapGHState(state string, merged bool) CRState {
 	if state == "closed" && merged {
 		return CRStateMerged
@@ -420,3 +761,103 @@ func mapGHStateToGitHub(state CRState) string {
 		return "all"
 	}
 }
+
+type githubEnabled struct {
+	Enabled bool `json:"enabled"`
+}
+
+type githubProtection struct {
+	RequiredStatusChecks *struct {
+		Strict   bool     `json:"strict"`
+		Contexts []string `json:"contexts"`
+	} `json:"required_status_checks"`
+	RequiredPullRequestReviews *struct {
+		DismissStaleReviews          bool `json:"dismiss_stale_reviews"`
+		RequireCodeOwnerReviews      bool `json:"require_code_owner_reviews"`
+		RequireLastPushApproval      bool `json:"require_last_push_approval"`
+		RequiredApprovingReviewCount int  `json:"required_approving_review_count"`
+	} `json:"required_pull_request_reviews"`
+	AllowForcePushes               githubEnabled `json:"allow_force_pushes"`
+	EnforceAdmins                  githubEnabled `json:"enforce_admins"`
+	RequiredLinearHistory          githubEnabled `json:"required_linear_history"`
+	AllowDeletions                 githubEnabled `json:"allow_deletions"`
+	RequiredConversationResolution githubEnabled `json:"required_conversation_resolution"`
+
+	Restrictions *struct {
+		Users []struct {
+			Login string `json:"login"`
+		} `json:"users"`
+		Teams []struct {
+			Slug string `json:"slug"`
+		} `json:"teams"`
+		Apps []struct {
+			Slug string `json:"slug"`
+		} `json:"apps"`
+	} `json:"restrictions"`
+}
+
+func (p *githubProtection) toProtection(branch string) *BranchProtection {
+	bp := &BranchProtection{Branch: branch, Protected: true, AllowForcePush: p.AllowForcePushes.Enabled}
+	if p.RequiredStatusChecks != nil {
+		bp.RequireChecks = true
+		bp.RequiredChecks = p.RequiredStatusChecks.Contexts
+	}
+	if p.RequiredPullRequestReviews != nil {
+		bp.RequiredReviews = p.RequiredPullRequestReviews.RequiredApprovingReviewCount
+	}
+	return bp
+}
+
+// restrictions 转换为 PUT 请求格式；未限制推送时为 null
+func (p *githubProtection) restrictions() interface{} {
+	if p.Restrictions == nil {
+		return nil
+	}
+	users, teams, apps := []string{}, []string{}, []string{}
+	for _, u := range p.Restrictions.Users {
+		users = append(users, u.Login)
+	}
+	for _, t := range p.Restrictions.Teams {
+		teams = append(teams, t.Slug)
+	}
+	for _, a := range p.Restrictions.Apps {
+		apps = append(apps, a.Slug)
+	}
+	return map[string]interface{}{"users": users, "teams": teams, "apps": apps}
+}
+
+type githubRelease struct {
+	ID         int64                `json:"id"`
+	TagName    string               `json:"tag_name"`
+	Name       string               `json:"name"`
+	Body       string               `json:"body"`
+	Draft      bool                 `json:"draft"`
+	Prerelease bool                 `json:"prerelease"`
+	HTMLURL    string               `json:"html_url"`
+	UploadURL  string               `json:"upload_url"`
+	CreatedAt  time.Time            `json:"created_at"`
+	Assets     []githubReleaseAsset `json:"assets"`
+}
+
+type githubReleaseAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+func (r *githubRelease) toRelease() *Release {
+	rel := &Release{
+		ID: r.ID, TagName: r.TagName, Name: r.Name, Body: r.Body,
+		Draft: r.Draft, Prerelease: r.Prerelease, WebURL: r.HTMLURL, CreatedAt: r.CreatedAt,
+		Assets: make([]*ReleaseAsset, 0, len(r.Assets)),
+	}
+	for i := range r.Assets {
+		rel.Assets = append(rel.Assets, r.Assets[i].toAsset())
+	}
+	return rel
+}
+
+func (a *githubReleaseAsset) toAsset() *ReleaseAsset {
+	return &ReleaseAsset{ID: a.ID, Name: a.Name, Size: a.Size, DownloadURL: a.BrowserDownloadURL}
+}
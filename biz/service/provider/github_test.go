@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGitHubCommitStatusesAndComments(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/acme/app/commits/abc/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"statuses":[{"context":"ci/jenkins","state":"success"}]}`))
+	})
+	mux.HandleFunc("/repos/acme/app/commits/abc/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"check_runs":[{"name":"lint","status":"completed","conclusion":"success"},{"name":"test","status":"in_progress"}]}`))
+	})
+	mux.HandleFunc("/repos/acme/app/issues/3/comments", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":2,"body":"LGTM","user":{"login":"bob"},"created_at":"2024-01-02T00:00:00Z"}]`))
+	})
+	mux.HandleFunc("/repos/acme/app/pulls/3/comments", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":1,"body":"nit","path":"main.go","line":12,"user":{"login":"alice"},"created_at":"2024-01-01T00:00:00Z"}]`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p := NewGitHubProvider(srv.URL, "tok")
+	ctx := context.Background()
+
+	statuses, err := p.GetCommitStatuses(ctx, "acme", "app", "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 3 || AggregateCheckState(statuses) != CheckStatePending {
+		t.Errorf("unexpected statuses: %+v", statuses)
+	}
+	statuses[2].State = CheckStateFailure
+	if AggregateCheckState(statuses) != CheckStateFailure || AggregateCheckState(nil) != CheckStateNone {
+		t.Error("unexpected aggregate state")
+	}
+
+	comments, err := p.ListCRComments(ctx, "acme", "app", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 || comments[0].Path != "main.go" || comments[0].Line != 12 || comments[1].Author.Username != "bob" {
+		t.Errorf("unexpected comments: %+v", comments)
+	}
+}
+
+func TestGitHubBranchProtection(t *testing.T) {
+	var putBody map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/acme/app/branches/main/protection", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			json.NewDecoder(r.Body).Decode(&putBody)
+			w.Write([]byte(`{"required_status_checks":{"contexts":["ci"]},"required_pull_request_reviews":{"required_approving_review_count":2},"allow_force_pushes":{"enabled":false},"enforce_admins":{"enabled":true}}`))
+			return
+		}
+		w.Write([]byte(`{"allow_force_pushes":{"enabled":true},"enforce_admins":{"enabled":true}}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p := NewGitHubProvider(srv.URL, "tok")
+	ctx := context.Background()
+
+	bp, err := p.GetBranchProtection(ctx, "acme", "app", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bp.Protected || !bp.AllowForcePush || bp.RequireChecks || bp.RequiredReviews != 0 {
+		t.Errorf("unexpected protection: %+v", bp)
+	}
+	unprotected, err := p.GetBranchProtection(ctx, "acme", "app", "dev")
+	if err != nil || unprotected.Protected {
+		t.Errorf("dev: %+v %v", unprotected, err)
+	}
+
+	bp, err = p.SetBranchProtection(ctx, "acme", "app", BranchProtection{
+		Branch: "main", Protected: true, RequiredReviews: 2, RequireChecks: true, RequiredChecks: []string{"ci"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if putBody["enforce_admins"] != true || putBody["allow_force_pushes"] != false || putBody["restrictions"] != nil {
+		t.Errorf("unexpected PUT body: %v", putBody)
+	}
+	if bp.RequiredReviews != 2 || len(bp.RequiredChecks) != 1 || bp.AllowForcePush {
+		t.Errorf("unexpected result: %+v", bp)
+	}
+}
+
+func TestGitHubReleaseAndAssetUpload(t *testing.T) {
+	var srvURL, uploaded string
+	var createBody map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/acme/app/releases", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			json.NewDecoder(r.Body).Decode(&createBody)
+			w.Write([]byte(`{"id":7,"tag_name":"v1.0.0","name":"v1.0.0","html_url":"https://example.com/r/7"}`))
+			return
+		}
+		w.Write([]byte(`[{"id":7,"tag_name":"v1.0.0","assets":[{"id":1,"name":"app.tar.gz","size":5}]}]`))
+	})
+	mux.HandleFunc("/repos/acme/app/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":7,"tag_name":"v1.0.0","upload_url":"` + srvURL + `/uploads/7/assets{?name,label}"}`))
+	})
+	mux.HandleFunc("/uploads/7/assets", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		uploaded = r.URL.Query().Get("name") + ":" + r.Header.Get("Content-Type") + ":" + string(data)
+		if r.ContentLength != int64(len(data)) {
+			w.WriteHeader(http.StatusLengthRequired)
+			return
+		}
+		w.Write([]byte(`{"id":1,"name":"app.tar.gz","size":5,"browser_download_url":"https://example.com/d/1"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	srvURL = srv.URL
+
+	p := NewGitHubProvider(srv.URL, "tok")
+	ctx := context.Background()
+
+	rel, err := p.CreateRelease(ctx, CreateReleaseOptions{Owner: "acme", Repo: "app", TagName: "v1.0.0", Name: "v1.0.0", Body: "notes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.WebURL != "https://example.com/r/7" || createBody["body"] != "notes" || createBody["target_commitish"] != nil {
+		t.Errorf("unexpected release %+v, body %v", rel, createBody)
+	}
+
+	asset, err := p.UploadReleaseAsset(ctx, "acme", "app", "v1.0.0", ReleaseAssetUpload{
+		Name: "app.tar.gz", ContentType: "application/gzip", Size: 5, Content: strings.NewReader("hello"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploaded != "app.tar.gz:application/gzip:hello" || asset.DownloadURL != "https://example.com/d/1" {
+		t.Errorf("unexpected upload %q -> %+v", uploaded, asset)
+	}
+
+	rels, err := p.ListReleases(ctx, "acme", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels) != 1 || len(rels[0].Assets) != 1 || rels[0].Assets[0].Size != 5 {
+		t.Errorf("unexpected releases: %+v", rels)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -14,22 +16,30 @@ import (
 type gitlabProvider struct {
 	baseURL string
 	token   string
-	client  *http.Client
+	api     *apiClient
 }
 
 func NewGitLabProvider(baseURL, token string) *gitlabProvider {
 	if baseURL == "" {
 		baseURL = "https://gitlab.com/api/v4"
 	}
-	return &gitlabProvider{
+	g := &gitlabProvider{
 		baseURL: strings.TrimRight(baseURL, "/"),
 		token:   token,
-		client:  &http.Client{Timeout: 30 * time.Second},
 	}
+	g.api = newAPIClient("GitLab", g.baseURL, func(req *http.Request) {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	})
+	return g
 }
 
 func (g *gitlabProvider) Platform() Platform { return PlatformGitLab }
 
+// gitlabProjectPath 项目路径的 URL 编码形式，owner 可含子组（如 grp/sub）
+func gitlabProjectPath(owner, repo string) string {
+	return url.PathEscape(owner + "/" + repo)
+}
+
 func (g *gitlabProvider) TestConnection(ctx context.Context) (*TestConnectionResult, error) {
 	var user struct {
 		Username string `json:"username"`
@@ -41,9 +51,10 @@ func (g *gitlabProvider) TestConnection(ctx context.Context) (*TestConnectionRes
 }
 
 func (g *gitlabProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([]*PlatformRepo, error) {
-	path := "/projects"
+	path := "/projects?"
 	if opts.Owner != "" {
-		path = fmt.Sprintf("/groups/%s/projects", opts.Owner)
+		// 子组需要编码为 grp%2Fsub；include_subgroups 才会返回子组下的项目
+		path = fmt.Sprintf("/groups/%s/projects?include_subgroups=true&", url.PathEscape(opts.Owner))
 	}
 	if opts.Page == 0 {
 		opts.Page = 1
@@ -51,8 +62,7 @@ func (g *gitlabProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([
 	if opts.PerPage == 0 {
 		opts.PerPage = 20
 	}
-	path = fmt.Sprintf("%s?page=%d&per_page=%d", path, opts.Page, opts.PerPage)
-	var projects []struct {
+	type gitlabProject struct {
 		ID            int    `json:"id"`
 		Name          string `json:"name"`
 		PathWithNS    string `json:"path_with_namespace"`
@@ -61,8 +71,16 @@ func (g *gitlabProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([
 		SSHURL        string `json:"ssh_url_to_repo"`
 		DefaultBranch string `json:"default_branch"`
 		Visibility    string `json:"visibility"`
+		Archived      bool   `json:"archived"`
+	}
+	var projects []gitlabProject
+	var err error
+	if opts.All {
+		projects, err = collectPages[gitlabProject](ctx, g.api.pages(path+"per_page=100"))
+	} else {
+		err = g.doRequest(ctx, "GET", fmt.Sprintf("%spage=%d&per_page=%d", path, opts.Page, opts.PerPage), nil, &projects)
 	}
-	if err := g.doRequest(ctx, "GET", path, nil, &projects); err != nil {
+	if err != nil {
 		return nil, err
 	}
 	repos := make([]*PlatformRepo, 0, len(projects))
@@ -76,13 +94,14 @@ func (g *gitlabProvider) ListRepos(ctx context.Context, opts ListRepoOptions) ([
 			ID: int64(p.ID), FullName: p.PathWithNS, Name: p.Name, Owner: owner,
 			Description: p.Description, CloneURL: p.HTTPURL, SSHURL: p.SSHURL,
 			DefaultBranch: p.DefaultBranch, Private: p.Visibility != "public", Platform: g.Platform(),
+			Visibility: p.Visibility, Archived: p.Archived,
 		})
 	}
 	return repos, nil
 }
 
 func (g *gitlabProvider) GetRepo(ctx context.Context, owner, repo string) (*PlatformRepo, error) {
-	encoded := fmt.Sprintf("%s%%2F%s", owner, repo)
+	encoded := gitlabProjectPath(owner, repo)
 	var p struct {
 		ID            int    `json:"id"`
 		Name          string `json:"name"`
@@ -92,6 +111,7 @@ func (g *gitlabProvider) GetRepo(ctx context.Context, owner, repo string) (*Plat
 		SSHURL        string `json:"ssh_url_to_repo"`
 		DefaultBranch string `json:"default_branch"`
 		Visibility    string `json:"visibility"`
+		Archived      bool   `json:"archived"`
 	}
 	if err := g.doRequest(ctx, "GET", "/projects/"+encoded, nil, &p); err != nil {
 		return nil, err
@@ -105,11 +125,126 @@ func (g *gitlabProvider) GetRepo(ctx context.Context, owner, repo string) (*Plat
 		ID: int64(p.ID), FullName: p.PathWithNS, Name: p.Name, Owner: ownerR,
 		Description: p.Description, CloneURL: p.HTTPURL, SSHURL: p.SSHURL,
 		DefaultBranch: p.DefaultBranch, Private: p.Visibility != "public", Platform: g.Platform(),
+		Visibility: p.Visibility, Archived: p.Archived,
 	}, nil
 }
 
+// CreateRepo 在 Owner 对应的组（可为子组路径）或当前用户下创建项目
+func (g *gitlabProvider) CreateRepo(ctx context.Context, opts CreateRepoOptions) (*PlatformRepo, error) {
+	body := map[string]interface{}{
+		"name": opts.Name, "path": opts.Name, "description": opts.Description,
+		"visibility": opts.visibility(),
+	}
+	if opts.DefaultBranch != "" {
+		body["default_branch"] = opts.DefaultBranch
+	}
+	if opts.Owner != "" {
+		var ns struct {
+			ID int `json:"id"`
+		}
+		if err := g.doRequest(ctx, "GET", "/namespaces/"+url.PathEscape(opts.Owner), nil, &ns); err != nil {
+			return nil, fmt.Errorf("namespace %s not found: %w", opts.Owner, err)
+		}
+		body["namespace_id"] = ns.ID
+	}
+	var p struct {
+		ID            int    `json:"id"`
+		Name          string `json:"name"`
+		PathWithNS    string `json:"path_with_namespace"`
+		Description   string `json:"description"`
+		HTTPURL       string `json:"http_url_to_repo"`
+		SSHURL        string `json:"ssh_url_to_repo"`
+		DefaultBranch string `json:"default_branch"`
+		Visibility    string `json:"visibility"`
+		Archived      bool   `json:"archived"`
+	}
+	if err := g.doRequest(ctx, "POST", "/projects", body, &p); err != nil {
+		return nil, err
+	}
+	owner := ""
+	if i := strings.LastIndex(p.PathWithNS, "/"); i > 0 {
+		owner = p.PathWithNS[:i]
+	}
+	return &PlatformRepo{
+		ID: int64(p.ID), FullName: p.PathWithNS, Name: p.Name, Owner: owner,
+		Description: p.Description, CloneURL: p.HTTPURL, SSHURL: p.SSHURL,
+		DefaultBranch: p.DefaultBranch, Private: p.Visibility != "public", Platform: g.Platform(),
+		Visibility: p.Visibility, Archived: p.Archived,
+	}, nil
+}
+
+// GetBranchProtection 分支保护与推送规则在 protected_branches，
+// 批准数与“流水线成功才能合并”是项目级设置
+func (g *gitlabProvider) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*BranchProtection, error) {
+	encoded := gitlabProjectPath(owner, repo)
+	result := &BranchProtection{Branch: branch}
+	var pb gitlabProtectedBranch
+	err := g.doRequest(ctx, "GET", fmt.Sprintf("/projects/%s/protected_branches/%s", encoded, url.PathEscape(branch)), nil, &pb)
+	if errors.Is(err, ErrNotFound) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Protected = true
+	result.AllowForcePush = pb.AllowForcePush
+
+	var project struct {
+		OnlyAllowMergeIfPipelineSucceeds bool `json:"only_allow_merge_if_pipeline_succeeds"`
+	}
+	if err := g.doRequest(ctx, "GET", "/projects/"+encoded, nil, &project); err != nil {
+		return nil, err
+	}
+	result.RequireChecks = project.OnlyAllowMergeIfPipelineSucceeds
+
+	// 批准规则为付费功能，社区版返回 404 / 403 时视为不要求
+	var approvals struct {
+		ApprovalsBeforeMerge int `json:"approvals_before_merge"`
+	}
+	if err := g.doRequest(ctx, "GET", fmt.Sprintf("/projects/%s/approvals", encoded), nil, &approvals); err == nil {
+		result.RequiredReviews = approvals.ApprovalsBeforeMerge
+	} else if !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrUnauthorized) {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (g *gitlabProvider) SetBranchProtection(ctx context.Context, owner, repo string, protection BranchProtection) (*BranchProtection, error) {
+	encoded := gitlabProjectPath(owner, repo)
+	branchPath := fmt.Sprintf("/projects/%s/protected_branches/%s", encoded, url.PathEscape(protection.Branch))
+	if !protection.Protected {
+		if err := g.doRequest(ctx, "DELETE", branchPath, nil, nil); err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		return &BranchProtection{Branch: protection.Branch}, nil
+	}
+
+	err := g.doRequest(ctx, "GET", branchPath, nil, nil)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		body := map[string]interface{}{"name": protection.Branch, "allow_force_push": protection.AllowForcePush}
+		err = g.doRequest(ctx, "POST", fmt.Sprintf("/projects/%s/protected_branches", encoded), body, nil)
+	case err == nil:
+		err = g.doRequest(ctx, "PATCH", branchPath, map[string]interface{}{"allow_force_push": protection.AllowForcePush}, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := g.doRequest(ctx, "PUT", "/projects/"+encoded, map[string]interface{}{
+		"only_allow_merge_if_pipeline_succeeds": protection.RequireChecks,
+	}, nil); err != nil {
+		return nil, err
+	}
+	if err := g.doRequest(ctx, "POST", fmt.Sprintf("/projects/%s/approvals", encoded), map[string]interface{}{
+		"approvals_before_merge": protection.RequiredReviews,
+	}, nil); err != nil && protection.RequiredReviews > 0 {
+		return nil, fmt.Errorf("set required approvals: %w", err)
+	}
+	return g.GetBranchProtection(ctx, owner, repo, protection.Branch)
+}
+
 func (g *gitlabProvider) CreateCR(ctx context.Context, opts CreateCROptions) (*ChangeRequest, error) {
-	encoded := fmt.Sprintf("%s%%2F%s", opts.Owner, opts.Repo)
+	encoded := gitlabProjectPath(opts.Owner, opts.Repo)
 	body := map[string]interface{}{
 		"source_branch": opts.SourceBranch, "target_branch": opts.TargetBranch,
 		"title": opts.Title, "description": opts.Description,
@@ -126,7 +261,7 @@ func (g *gitlabProvider) CreateCR(ctx context.Context, opts CreateCROptions) (*C
 }
 
 func (g *gitlabProvider) GetCR(ctx context.Context, owner, repo string, number int) (*ChangeRequest, error) {
-	encoded := fmt.Sprintf("%s%%2F%s", owner, repo)
+	encoded := gitlabProjectPath(owner, repo)
 	var mr gitlabMR
 	if err := g.doRequest(ctx, "GET", fmt.Sprintf("/projects/%s/merge_requests/%d", encoded, number), nil, &mr); err != nil {
 		return nil, err
@@ -135,7 +270,7 @@ func (g *gitlabProvider) GetCR(ctx context.Context, owner, repo string, number i
 }
 
 func (g *gitlabProvider) ListCRs(ctx context.Context, opts ListCROptions) ([]*ChangeRequest, int, error) {
-	encoded := fmt.Sprintf("%s%%2F%s", opts.Owner, opts.Repo)
+	encoded := gitlabProjectPath(opts.Owner, opts.Repo)
 	if opts.Page == 0 {
 		opts.Page = 1
 	}
@@ -164,7 +299,7 @@ func (g *gitlabProvider) ListCRs(ctx context.Context, opts ListCROptions) ([]*Ch
 }
 
 func (g *gitlabProvider) MergeCR(ctx context.Context, owner, repo string, number int, opts MergeCROptions) (*ChangeRequest, error) {
-	encoded := fmt.Sprintf("%s%%2F%s", owner, repo)
+	encoded := gitlabProjectPath(owner, repo)
 	body := map[string]interface{}{}
 	if opts.MergeCommitMessage != "" {
 		body["merge_commit_message"] = opts.MergeCommitMessage
@@ -183,7 +318,7 @@ func (g *gitlabProvider) MergeCR(ctx context.Context, owner, repo string, number
 }
 
 func (g *gitlabProvider) CloseCR(ctx context.Context, owner, repo string, number int) (*ChangeRequest, error) {
-	encoded := fmt.Sprintf("%s%%2F%s", owner, repo)
+	encoded := gitlabProjectPath(owner, repo)
 	body := map[string]interface{}{"state_event": "close"}
 	var mr gitlabMR
 	if err := g.doRequest(ctx, "PUT", fmt.Sprintf("/projects/%s/merge_requests/%d", encoded, number), body, &mr); err != nil {
@@ -192,8 +327,220 @@ func (g *gitlabProvider) CloseCR(ctx context.Context, owner, repo string, number
 	return mr.toCR(), nil
 }
 
+func (g *gitlabProvider) UpdateCR(ctx context.Context, owner, repo string, number int, opts UpdateCROptions) (*ChangeRequest, error) {
+	encoded := gitlabProjectPath(owner, repo)
+	body := map[string]interface{}{}
+	if opts.Title != "" {
+		body["title"] = opts.Title
+	}
+	if opts.Description != "" {
+		body["description"] = opts.Description
+	}
+	var mr gitlabMR
+	if err := g.doRequest(ctx, "PUT", fmt.Sprintf("/projects/%s/merge_requests/%d", encoded, number), body, &mr); err != nil {
+		return nil, err
+	}
+	return mr.toCR(), nil
+}
+
+// ListCRComments 列出 MR 的评论，忽略系统生成的 note
+func (g *gitlabProvider) ListCRComments(ctx context.Context, owner, repo string, number int) ([]*CRComment, error) {
+	encoded := gitlabProjectPath(owner, repo)
+	notes, err := collectPages[gitlabNote](ctx, g.api.pages(fmt.Sprintf("/projects/%s/merge_requests/%d/notes?sort=asc&order_by=created_at&per_page=100", encoded, number)))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*CRComment, 0, len(notes))
+	for i := range notes {
+		if notes[i].System {
+			continue
+		}
+		result = append(result, notes[i].toComment())
+	}
+	return result, nil
+}
+
+// AddCRComment 行内评论通过 discussion 发表，position 需要 MR 的 diff_refs
+func (g *gitlabProvider) AddCRComment(ctx context.Context, owner, repo string, number int, opts AddCRCommentOptions) (*CRComment, error) {
+	encoded := gitlabProjectPath(owner, repo)
+	if !opts.inline() {
+		var note gitlabNote
+		path := fmt.Sprintf("/projects/%s/merge_requests/%d/notes", encoded, number)
+		if err := g.doRequest(ctx, "POST", path, map[string]interface{}{"body": opts.Body}, &note); err != nil {
+			return nil, err
+		}
+		return note.toComment(), nil
+	}
+	var mr gitlabMR
+	if err := g.doRequest(ctx, "GET", fmt.Sprintf("/projects/%s/merge_requests/%d", encoded, number), nil, &mr); err != nil {
+		return nil, err
+	}
+	headSHA := mr.DiffRefs.HeadSHA
+	if opts.CommitSHA != "" {
+		headSHA = opts.CommitSHA
+	}
+	body := map[string]interface{}{
+		"body": opts.Body,
+		"position": map[string]interface{}{
+			"position_type": "text",
+			"base_sha":      mr.DiffRefs.BaseSHA,
+			"start_sha":     mr.DiffRefs.StartSHA,
+			"head_sha":      headSHA,
+			"new_path":      opts.Path,
+			"new_line":      opts.Line,
+		},
+	}
+	var discussion struct {
+		Notes []gitlabNote `json:"notes"`
+	}
+	if err := g.doRequest(ctx, "POST", fmt.Sprintf("/projects/%s/merge_requests/%d/discussions", encoded, number), body, &discussion); err != nil {
+		return nil, err
+	}
+	if len(discussion.Notes) == 0 {
+		return &CRComment{Body: opts.Body, Path: opts.Path, Line: opts.Line}, nil
+	}
+	return discussion.Notes[0].toComment(), nil
+}
+
+// ReviewCR GitLab 没有"要求修改"的审批状态，以撤销本人批准并发表评论代替
+func (g *gitlabProvider) ReviewCR(ctx context.Context, owner, repo string, number int, opts ReviewCROptions) error {
+	encoded := gitlabProjectPath(owner, repo)
+	mrPath := fmt.Sprintf("/projects/%s/merge_requests/%d", encoded, number)
+	if opts.Action == ReviewApprove {
+		if err := g.doRequest(ctx, "POST", mrPath+"/approve", nil, nil); err != nil {
+			return err
+		}
+	} else {
+		// 尚未批准时返回 404，忽略
+		if err := g.doRequest(ctx, "POST", mrPath+"/unapprove", nil, nil); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if opts.Body == "" {
+			opts.Body = "Changes requested"
+		}
+	}
+	if opts.Body == "" {
+		return nil
+	}
+	return g.doRequest(ctx, "POST", mrPath+"/notes", map[string]interface{}{"body": opts.Body}, nil)
+}
+
+func (g *gitlabProvider) GetCommitStatuses(ctx context.Context, owner, repo, sha string) ([]*CommitStatus, error) {
+	encoded := gitlabProjectPath(owner, repo)
+	type gitlabStatus struct {
+		Name         string `json:"name"`
+		Status       string `json:"status"`
+		Description  string `json:"description"`
+		TargetURL    string `json:"target_url"`
+		AllowFailure bool   `json:"allow_failure"`
+	}
+	statuses, err := collectPages[gitlabStatus](ctx, g.api.pages(fmt.Sprintf("/projects/%s/repository/commits/%s/statuses?per_page=100", encoded, sha)))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*CommitStatus, 0, len(statuses))
+	for _, st := range statuses {
+		state := CheckStateFailure
+		switch st.Status {
+		case "success", "skipped":
+			state = CheckStateSuccess
+		case "pending", "running", "created", "waiting_for_resource", "preparing", "scheduled", "manual":
+			state = CheckStatePending
+		}
+		if state == CheckStateFailure && st.AllowFailure {
+			state = CheckStateSuccess
+		}
+		result = append(result, &CommitStatus{Name: st.Name, State: state, Description: st.Description, TargetURL: st.TargetURL})
+	}
+	return result, nil
+}
+
+// CreateRelease GitLab 没有草稿 / 预发布概念，设置 Draft 或 Prerelease 时返回 ErrUnsupported
+func (g *gitlabProvider) CreateRelease(ctx context.Context, opts CreateReleaseOptions) (*Release, error) {
+	if opts.Draft {
+		return nil, unsupportedOp(PlatformGitLab, "draft releases")
+	}
+	if opts.Prerelease {
+		return nil, unsupportedOp(PlatformGitLab, "prereleases")
+	}
+	encoded := gitlabProjectPath(opts.Owner, opts.Repo)
+	body := map[string]interface{}{"tag_name": opts.TagName, "name": opts.Name, "description": opts.Body}
+	if opts.Target != "" {
+		body["ref"] = opts.Target
+	}
+	var rel gitlabRelease
+	if err := g.doRequest(ctx, "POST", fmt.Sprintf("/projects/%s/releases", encoded), body, &rel); err != nil {
+		return nil, err
+	}
+	return rel.toRelease(), nil
+}
+
+func (g *gitlabProvider) UpdateRelease(ctx context.Context, owner, repo, tag string, opts UpdateReleaseOptions) (*Release, error) {
+	if opts.Draft != nil && *opts.Draft {
+		return nil, unsupportedOp(PlatformGitLab, "draft releases")
+	}
+	if opts.Prerelease != nil && *opts.Prerelease {
+		return nil, unsupportedOp(PlatformGitLab, "prereleases")
+	}
+	encoded := gitlabProjectPath(owner, repo)
+	body := map[string]interface{}{}
+	if opts.Name != nil {
+		body["name"] = *opts.Name
+	}
+	if opts.Body != nil {
+		body["description"] = *opts.Body
+	}
+	var rel gitlabRelease
+	if err := g.doRequest(ctx, "PUT", fmt.Sprintf("/projects/%s/releases/%s", encoded, url.PathEscape(tag)), body, &rel); err != nil {
+		return nil, err
+	}
+	return rel.toRelease(), nil
+}
+
+func (g *gitlabProvider) ListReleases(ctx context.Context, owner, repo string) ([]*Release, error) {
+	encoded := gitlabProjectPath(owner, repo)
+	rels, err := collectPages[gitlabRelease](ctx, g.api.pages(fmt.Sprintf("/projects/%s/releases?per_page=100", encoded)))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Release, 0, len(rels))
+	for i := range rels {
+		result = append(result, rels[i].toRelease())
+	}
+	return result, nil
+}
+
+// UploadReleaseAsset 先上传为项目附件，再作为链接挂到发布上
+func (g *gitlabProvider) UploadReleaseAsset(ctx context.Context, owner, repo, tag string, asset ReleaseAssetUpload) (*ReleaseAsset, error) {
+	encoded := gitlabProjectPath(owner, repo)
+	body, contentType := multipartBody("file", asset.Name, asset.Content)
+	var uploaded struct {
+		URL      string `json:"url"`
+		FullPath string `json:"full_path"`
+	}
+	if err := g.api.upload(ctx, "POST", fmt.Sprintf("/projects/%s/uploads", encoded), contentType, body, -1, &uploaded); err != nil {
+		return nil, err
+	}
+	webBase := strings.TrimSuffix(g.baseURL, "/api/v4")
+	fileURL := webBase + uploaded.FullPath
+	if uploaded.FullPath == "" {
+		fileURL = fmt.Sprintf("%s/%s/%s%s", webBase, owner, repo, uploaded.URL)
+	}
+
+	var link struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	}
+	linkBody := map[string]interface{}{"name": asset.Name, "url": fileURL}
+	if err := g.doRequest(ctx, "POST", fmt.Sprintf("/projects/%s/releases/%s/assets/links", encoded, url.PathEscape(tag)), linkBody, &link); err != nil {
+		return nil, err
+	}
+	return &ReleaseAsset{ID: link.ID, Name: link.Name, Size: asset.Size, DownloadURL: link.URL}, nil
+}
+
 func (g *gitlabProvider) CreateWebhook(ctx context.Context, opts CreateWebhookOptions) (*PlatformWebhook, error) {
-	encoded := fmt.Sprintf("%s%%2F%s", opts.Owner, opts.Repo)
+	encoded := gitlabProjectPath(opts.Owner, opts.Repo)
 	body := map[string]interface{}{"url": opts.URL, "token": opts.Secret}
 	if len(opts.Events) > 0 {
 		em := map[string]bool{}
@@ -215,17 +562,18 @@ func (g *gitlabProvider) CreateWebhook(ctx context.Context, opts CreateWebhookOp
 }
 
 func (g *gitlabProvider) DeleteWebhook(ctx context.Context, owner, repo string, webhookID int64) error {
-	encoded := fmt.Sprintf("%s%%2F%s", owner, repo)
+	encoded := gitlabProjectPath(owner, repo)
 	return g.doRequest(ctx, "DELETE", fmt.Sprintf("/projects/%s/hooks/%d", encoded, webhookID), nil, nil)
 }
 
 func (g *gitlabProvider) ListWebhooks(ctx context.Context, owner, repo string) ([]*PlatformWebhook, error) {
-	encoded := fmt.Sprintf("%s%%2F%s", owner, repo)
-	var whs []struct {
+	encoded := gitlabProjectPath(owner, repo)
+	type gitlabHook struct {
 		ID  int    `json:"id"`
 		URL string `json:"url"`
 	}
-	if err := g.doRequest(ctx, "GET", "/projects/"+encoded+"/hooks", nil, &whs); err != nil {
+	whs, err := collectPages[gitlabHook](ctx, g.api.pages("/projects/"+encoded+"/hooks?per_page=100"))
+	if err != nil {
 		return nil, err
 	}
 	result := make([]*PlatformWebhook, 0, len(whs))
@@ -236,8 +584,10 @@ func (g *gitlabProvider) ListWebhooks(ctx context.Context, owner, repo string) (
 }
 
 func (g *gitlabProvider) ParseWebhookEvent(r *http.Request, secret string) (*NormalizedEvent, error) {
-	if err := g.ValidateWebhookSignature(r, secret); err != nil {
-		return nil, err
+	if !signatureCheckSkipped(r) {
+		if err := g.ValidateWebhookSignature(r, secret); err != nil {
+			return nil, err
+		}
 	}
 	body, _ := io.ReadAll(r.Body)
 	r.Body = io.NopCloser(bytes.NewReader(body))
@@ -319,33 +669,7 @@ func (g *gitlabProvider) ValidateWebhookSignature(r *http.Request, secret string
 }
 
 func (g *gitlabProvider) doRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
-	var reqBody io.Reader
-	if body != nil {
-		b, err := json.Marshal(body)
-		if err != nil {
-			return err
-		}
-		reqBody = bytes.NewReader(b)
-	}
-	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, reqBody)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("PRIVATE-TOKEN", g.token)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := g.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	respBody, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("GitLab API %s %s returned %d: %s", method, path, resp.StatusCode, string(respBody))
-	}
-	if result != nil && resp.StatusCode != http.StatusNoContent {
-		return json.Unmarshal(respBody, result)
-	}
-	return nil
+	return g.api.doJSON(ctx, method, path, body, result)
 }
 
 type gitlabMR struct {
@@ -360,11 +684,17 @@ type gitlabMR struct {
 		Username string `json:"username"`
 		Name     string `json:"name"`
 	} `json:"author"`
-	Labels      []string  `json:"labels"`
-	MergeStatus string    `json:"merge_status"`
-	WebURL      string    `json:"web_url"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Labels      []string `json:"labels"`
+	MergeStatus string   `json:"merge_status"`
+	WebURL      string   `json:"web_url"`
+	SHA         string   `json:"sha"`
+	DiffRefs    struct {
+		BaseSHA  string `json:"base_sha"`
+		HeadSHA  string `json:"head_sha"`
+		StartSHA string `json:"start_sha"`
+	} `json:"diff_refs"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 func (mr *gitlabMR) toCR() *ChangeRequest {
@@ -372,11 +702,38 @@ func (mr *gitlabMR) toCR() *ChangeRequest {
 		ID: int64(mr.IID), Number: mr.IID, Title: mr.Title, Description: mr.Description,
 		State: mapGLState(mr.State), SourceBranch: mr.SourceBranch, TargetBranch: mr.TargetBranch,
 		Author: &CRUser{ID: int64(mr.Author.ID), Username: mr.Author.Username, Name: mr.Author.Name},
-		Labels: mr.Labels, MergeStatus: mr.MergeStatus, WebURL: mr.WebURL,
+		Labels: mr.Labels, MergeStatus: mr.MergeStatus, WebURL: mr.WebURL, HeadSHA: mr.SHA,
 		CreatedAt: mr.CreatedAt, UpdatedAt: mr.UpdatedAt,
 	}
 }
 
+type gitlabNote struct {
+	ID     int64  `json:"id"`
+	Body   string `json:"body"`
+	System bool   `json:"system"`
+	Author struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+		Name     string `json:"name"`
+	} `json:"author"`
+	Position *struct {
+		NewPath string `json:"new_path"`
+		NewLine int    `json:"new_line"`
+	} `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (n *gitlabNote) toComment() *CRComment {
+	c := &CRComment{
+		ID: n.ID, Body: n.Body, CreatedAt: n.CreatedAt,
+		Author: &CRUser{ID: int64(n.Author.ID), Username: n.Author.Username, Name: n.Author.Name},
+	}
+	if n.Position != nil {
+		c.Path, c.Line = n.Position.NewPath, n.Position.NewLine
+	}
+	return c
+}
+
 func mapGLState(state string) CRState {
 	switch state {
 	case "merged":
@@ -387,3 +744,42 @@ func mapGLState(state string) CRState {
 		return CRStateOpened
 	}
 }
+
+type gitlabProtectedBranch struct {
+	Name           string `json:"name"`
+	AllowForcePush bool   `json:"allow_force_push"`
+}
+
+type gitlabRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	Links       struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+	Assets struct {
+		Links []struct {
+			ID             int64  `json:"id"`
+			Name           string `json:"name"`
+			URL            string `json:"url"`
+			DirectAssetURL string `json:"direct_asset_url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+func (r *gitlabRelease) toRelease() *Release {
+	rel := &Release{
+		TagName: r.TagName, Name: r.Name, Body: r.Description,
+		WebURL: r.Links.Self, CreatedAt: r.CreatedAt,
+		Assets: make([]*ReleaseAsset, 0, len(r.Assets.Links)),
+	}
+	for _, l := range r.Assets.Links {
+		downloadURL := l.DirectAssetURL
+		if downloadURL == "" {
+			downloadURL = l.URL
+		}
+		rel.Assets = append(rel.Assets, &ReleaseAsset{ID: l.ID, Name: l.Name, DownloadURL: downloadURL})
+	}
+	return rel
+}
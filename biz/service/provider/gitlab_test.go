@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGitLabEncodesSubgroupProjectPath(t *testing.T) {
+	var uri string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uri = r.RequestURI
+		w.Write([]byte(`{"id":1,"path":"app","path_with_namespace":"grp/sub/app","namespace":{"full_path":"grp/sub"}}`))
+	}))
+	defer srv.Close()
+
+	g := NewGitLabProvider(srv.URL, "tok")
+	if _, err := g.GetRepo(context.Background(), "grp/sub", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if uri != "/projects/grp%2Fsub%2Fapp" {
+		t.Errorf("unexpected request URI %s", uri)
+	}
+}
+
+func TestGitLabReleaseRejectsDraftAndPrerelease(t *testing.T) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.Write([]byte(`{"tag_name":"v1.0.0"}`))
+	}))
+	defer srv.Close()
+
+	g := NewGitLabProvider(srv.URL, "tok")
+	ctx := context.Background()
+	if _, err := g.CreateRelease(ctx, CreateReleaseOptions{Owner: "acme", Repo: "app", TagName: "v1.0.0", Draft: true}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("draft create: expected ErrUnsupported, got %v", err)
+	}
+	prerelease := true
+	if _, err := g.UpdateRelease(ctx, "acme", "app", "v1.0.0", UpdateReleaseOptions{Prerelease: &prerelease}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("prerelease update: expected ErrUnsupported, got %v", err)
+	}
+	if called {
+		t.Error("unsupported release options must not reach the API")
+	}
+
+	draft := false
+	if _, err := g.UpdateRelease(ctx, "acme", "app", "v1.0.0", UpdateReleaseOptions{Draft: &draft}); err != nil {
+		t.Errorf("draft=false should be accepted: %v", err)
+	}
+}
@@ -0,0 +1,531 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 平台 API 错误分类，可用 errors.Is 判断
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+	// ErrPageLimit 自动翻页达到 apiMaxPages 仍有下一页，列表不完整
+	ErrPageLimit = errors.New("page limit reached")
+)
+
+const (
+	// apiMaxRetries 限流或网关错误时的最大重试次数
+	apiMaxRetries = 3
+	// apiMaxWait 单次等待上限，限流需要更久时直接返回 ErrRateLimited
+	apiMaxWait = time.Minute
+	// apiETagCacheSize 每个客户端缓存的 GET 响应数
+	apiETagCacheSize = 256
+	// apiMaxPages 自动翻页的页数上限
+	apiMaxPages = 200
+)
+
+// APIError 平台 API 返回的错误响应
+type APIError struct {
+	Platform   string
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+	// RetryAfter 限流时服务端要求的等待时间
+	RetryAfter time.Duration
+
+	kind error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API %s %s returned %d: %s", e.Platform, e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+func (e *APIError) Unwrap() error { return e.kind }
+
+// apiClient 各平台共用的 HTTP 层：JSON 编解码、限流退避、GET 的 ETag 条件请求与错误分类
+type apiClient struct {
+	platform string // 错误信息中的平台名
+	baseURL  string
+	client   *http.Client
+	// auth 设置认证及平台特有的请求头
+	auth func(req *http.Request)
+
+	// trustedHosts 除 baseURL 外允许携带凭证访问的主机，如 GitHub 的 uploads 主机
+	trustedHosts []string
+
+	mu           sync.Mutex
+	blockedUntil time.Time
+	etags        map[string]etagEntry
+}
+
+type etagEntry struct {
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+// apiResponse 已读取完毕的响应
+type apiResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func newAPIClient(platform, baseURL string, auth func(req *http.Request)) *apiClient {
+	return &apiClient{
+		platform: platform,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		client:   &http.Client{Timeout: 30 * time.Second},
+		auth:     auth,
+		etags:    map[string]etagEntry{},
+	}
+}
+
+// doJSON 发送请求并将响应解析到 result，result 为 nil 或响应为空时忽略响应体
+func (c *apiClient) doJSON(ctx context.Context, method, path string, body, result interface{}) error {
+	resp, err := c.do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	return resp.decode(result)
+}
+
+func (r *apiResponse) decode(result interface{}) error {
+	if result == nil || r.StatusCode == http.StatusNoContent || len(bytes.TrimSpace(r.Body)) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Body, result)
+}
+
+// do 发送请求；path 为相对 baseURL 的路径，也可以是翻页返回的同源绝对地址。
+// 限流（429，或配额耗尽的 403）按 Retry-After / X-RateLimit-Reset 等待后重试，
+// GET 遇到 502/503/504 按指数退避重试；状态码 >= 400 返回 *APIError。
+func (c *apiClient) do(ctx context.Context, method, path string, body interface{}) (*apiResponse, error) {
+	endpoint, err := c.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	var payload []byte
+	if body != nil {
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if wait := c.rateLimitWait(); wait > 0 {
+			if wait > apiMaxWait {
+				return nil, c.rateLimitError(method, path, wait)
+			}
+			if err := sleepCtx(ctx, wait); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := c.send(ctx, method, endpoint, payload)
+		if err != nil {
+			return nil, err
+		}
+		c.updateRateLimit(resp.Header)
+
+		if resp.StatusCode == http.StatusNotModified && method == http.MethodGet {
+			if cached, ok := c.cached(endpoint); ok {
+				return &apiResponse{StatusCode: http.StatusOK, Header: cached.header, Body: cached.body}, nil
+			}
+		}
+
+		if isRateLimited(resp) {
+			wait, ok := retryAfter(resp.Header)
+			if !ok {
+				wait = backoff(attempt)
+			}
+			if attempt >= apiMaxRetries || wait > apiMaxWait {
+				apiErr := c.newError(method, path, resp)
+				apiErr.RetryAfter = wait
+				return nil, apiErr
+			}
+			if err := sleepCtx(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if method == http.MethodGet && attempt < apiMaxRetries && isTransient(resp.StatusCode) {
+			if err := sleepCtx(ctx, backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode >= 400 {
+			return nil, c.newError(method, path, resp)
+		}
+		if method == http.MethodGet && resp.StatusCode == http.StatusOK {
+			c.store(endpoint, resp)
+		}
+		return resp, nil
+	}
+}
+
+func (c *apiClient) resolve(path string) (string, error) {
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		return c.baseURL + path, nil
+	}
+	// 翻页地址来自响应头，只允许与 baseURL 同源，避免凭证发往其他主机
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == base.Scheme {
+		for _, host := range c.trustedHosts {
+			if u.Host == host {
+				return path, nil
+			}
+		}
+	}
+	if u.Scheme != base.Scheme || u.Host != base.Host {
+		return "", fmt.Errorf("%s API: refusing to follow cross-origin URL %s", c.platform, path)
+	}
+	return path, nil
+}
+
+func (c *apiClient) send(ctx context.Context, method, endpoint string, payload []byte) (*apiResponse, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.auth != nil {
+		c.auth(req)
+	}
+	if method == http.MethodGet {
+		if cached, ok := c.cached(endpoint); ok {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &apiResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: respBody}, nil
+}
+
+// upload 以原始请求体发送（上传发布附件等）。请求体只能读取一次，因此不做重试，也不设整体超时，
+// 由 ctx 控制；size < 0 表示长度未知，按分块传输发送
+func (c *apiClient) upload(ctx context.Context, method, path, contentType string, body io.Reader, size int64, result interface{}) error {
+	// 未发出请求就返回时关闭请求体，避免 multipartBody 的写协程阻塞
+	abort := func(err error) error {
+		if rc, ok := body.(io.Closer); ok {
+			rc.Close()
+		}
+		return err
+	}
+	endpoint, err := c.resolve(path)
+	if err != nil {
+		return abort(err)
+	}
+	if wait := c.rateLimitWait(); wait > 0 {
+		if wait > apiMaxWait {
+			return abort(c.rateLimitError(method, path, wait))
+		}
+		if err := sleepCtx(ctx, wait); err != nil {
+			return abort(err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return abort(err)
+	}
+	if size >= 0 {
+		req.ContentLength = size
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.auth != nil {
+		c.auth(req)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	httpResp, err := (&http.Client{Transport: c.client.Transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	resp := &apiResponse{StatusCode: httpResp.StatusCode, Header: httpResp.Header, Body: respBody}
+	c.updateRateLimit(resp.Header)
+	if resp.StatusCode >= 400 {
+		return c.newError(method, path, resp)
+	}
+	return resp.decode(result)
+}
+
+// multipartBody 把 content 作为单个文件字段流式编码为 multipart/form-data，返回请求体与 Content-Type
+func multipartBody(field, filename string, content io.Reader) (io.Reader, string) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile(field, filename)
+		if err == nil {
+			_, err = io.Copy(part, content)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, mw.FormDataContentType()
+}
+
+func (c *apiClient) newError(method, path string, resp *apiResponse) *APIError {
+	e := &APIError{
+		Platform: c.platform, Method: method, Path: path,
+		StatusCode: resp.StatusCode, Body: string(resp.Body),
+	}
+	switch {
+	case isRateLimited(resp):
+		e.kind = ErrRateLimited
+		e.RetryAfter, _ = retryAfter(resp.Header)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		e.kind = ErrUnauthorized
+	case resp.StatusCode == http.StatusNotFound:
+		e.kind = ErrNotFound
+	}
+	return e
+}
+
+func (c *apiClient) rateLimitError(method, path string, wait time.Duration) *APIError {
+	return &APIError{
+		Platform: c.platform, Method: method, Path: path,
+		StatusCode: http.StatusTooManyRequests,
+		Body:       fmt.Sprintf("rate limit exhausted, resets in %s", wait.Round(time.Second)),
+		RetryAfter: wait, kind: ErrRateLimited,
+	}
+}
+
+// updateRateLimit 配额耗尽时记录重置时间，后续请求在此之前先等待
+func (c *apiClient) updateRateLimit(h http.Header) {
+	remaining := headerAny(h, "X-RateLimit-Remaining", "RateLimit-Remaining")
+	if remaining != "0" {
+		return
+	}
+	reset := rateLimitReset(h)
+	if reset.IsZero() {
+		return
+	}
+	c.mu.Lock()
+	if reset.After(c.blockedUntil) {
+		c.blockedUntil = reset
+	}
+	c.mu.Unlock()
+}
+
+func (c *apiClient) rateLimitWait() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Until(c.blockedUntil)
+}
+
+func (c *apiClient) cached(endpoint string) (etagEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.etags[endpoint]
+	return e, ok
+}
+
+func (c *apiClient) store(endpoint string, resp *apiResponse) {
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.etags[endpoint]; !ok && len(c.etags) >= apiETagCacheSize {
+		// 缓存满时随机淘汰一项
+		for k := range c.etags {
+			delete(c.etags, k)
+			break
+		}
+	}
+	c.etags[endpoint] = etagEntry{etag: etag, header: resp.Header, body: resp.Body}
+}
+
+// pageIterator 按 Link rel="next"（GitHub / Gitea / GitLab）或 X-Next-Page（GitLab）逐页读取列表接口
+type pageIterator struct {
+	api   *apiClient
+	next  string
+	pages int
+}
+
+func (c *apiClient) pages(path string) *pageIterator {
+	return &pageIterator{api: c, next: path}
+}
+
+// More 是否还有下一页
+func (it *pageIterator) More() bool {
+	return it.next != "" && it.pages < apiMaxPages
+}
+
+// Err 达到页数上限但仍有下一页时返回 ErrPageLimit
+func (it *pageIterator) Err() error {
+	if it.next != "" && it.pages >= apiMaxPages {
+		return errPageLimit()
+	}
+	return nil
+}
+
+// Next 读取下一页并解析到 result
+func (it *pageIterator) Next(ctx context.Context, result interface{}) error {
+	if !it.More() {
+		if err := it.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	current := it.next
+	resp, err := it.api.do(ctx, http.MethodGet, current, nil)
+	if err != nil {
+		return err
+	}
+	it.pages++
+	it.next = nextPageURL(current, resp.Header)
+	return resp.decode(result)
+}
+
+// collectPages 读取全部分页并拼接；超过页数上限时返回 ErrPageLimit，不返回截断的列表
+func collectPages[T any](ctx context.Context, it *pageIterator) ([]T, error) {
+	var all []T
+	for it.More() {
+		var page []T
+		if err := it.Next(ctx, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+func errPageLimit() error {
+	return fmt.Errorf("%w: more than %d pages", ErrPageLimit, apiMaxPages)
+}
+
+var linkNextRe = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+func nextPageURL(current string, h http.Header) string {
+	if m := linkNextRe.FindStringSubmatch(h.Get("Link")); m != nil {
+		return m[1]
+	}
+	next := h.Get("X-Next-Page")
+	if next == "" {
+		return ""
+	}
+	u, err := url.Parse(current)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	q.Set("page", next)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func isRateLimited(resp *apiResponse) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	// GitHub 主/次级限流均返回 403，并带 Retry-After 或剩余配额为 0
+	return resp.StatusCode == http.StatusForbidden &&
+		(resp.Header.Get("Retry-After") != "" || headerAny(resp.Header, "X-RateLimit-Remaining", "RateLimit-Remaining") == "0")
+}
+
+func isTransient(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+// retryAfter 解析 Retry-After（秒数或 HTTP 日期），没有时取配额重置时间；ok 表示服务端给出了等待时间
+func retryAfter(h http.Header) (wait time.Duration, ok bool) {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second, true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return time.Until(t), true
+		}
+	}
+	if reset := rateLimitReset(h); !reset.IsZero() {
+		return time.Until(reset), true
+	}
+	return 0, false
+}
+
+// rateLimitReset X-RateLimit-Reset / RateLimit-Reset 为 Unix 时间戳
+func rateLimitReset(h http.Header) time.Time {
+	v := headerAny(h, "X-RateLimit-Reset", "RateLimit-Reset")
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || secs <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0)
+}
+
+func headerAny(h http.Header, keys ...string) string {
+	for _, k := range keys {
+		if v := h.Get(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func backoff(attempt int) time.Duration {
+	return time.Second << attempt
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGitHubListReposFollowsLinkPagination(t *testing.T) {
+	var srvURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/acme/repos", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/users/acme/repos", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/users/acme/repos?per_page=100&page=2>; rel="next", <%s/users/acme/repos?per_page=100&page=2>; rel="last"`, srvURL, srvURL))
+			w.Write([]byte(`[{"id":1,"full_name":"acme/a","name":"a"}]`))
+			return
+		}
+		w.Write([]byte(`[{"id":2,"full_name":"acme/b","name":"b","archived":true}]`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	srvURL = srv.URL
+
+	repos, err := NewGitHubProvider(srv.URL, "tok").ListRepos(context.Background(), ListRepoOptions{Owner: "acme", All: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 2 || repos[1].FullName != "acme/b" || !repos[1].Archived {
+		t.Errorf("unexpected repos: %+v", repos)
+	}
+}
+
+func TestCollectPagesReportsPageLimit(t *testing.T) {
+	var srvURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 每页都声明还有下一页
+		page := 1
+		fmt.Sscan(r.URL.Query().Get("page"), &page)
+		w.Header().Set("Link", fmt.Sprintf(`<%s/users/acme/repos?page=%d>; rel="next"`, srvURL, page+1))
+		w.Write([]byte(`[{"id":1,"full_name":"acme/a","name":"a"}]`))
+	}))
+	defer srv.Close()
+	srvURL = srv.URL
+
+	repos, err := NewGitHubProvider(srv.URL, "tok").ListRepos(context.Background(), ListRepoOptions{Owner: "acme", All: true})
+	if !errors.Is(err, ErrPageLimit) || repos != nil {
+		t.Fatalf("expected ErrPageLimit without partial list, got %d repos, err %v", len(repos), err)
+	}
+}
+
+func TestAPIClientRetriesRateLimit(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"login":"bot"}`))
+	}))
+	defer srv.Close()
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := newAPIClient("Test", srv.URL, nil).doJSON(context.Background(), "GET", "/user", nil, &user); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || user.Login != "bot" {
+		t.Errorf("calls=%d login=%q", calls, user.Login)
+	}
+}
+
+func TestAPIClientETagAndTypedErrors(t *testing.T) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repo", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"name":"app"}`))
+	})
+	mux.HandleFunc("/private", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/limited", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusForbidden)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newAPIClient("Test", srv.URL, nil)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		var repo struct {
+			Name string `json:"name"`
+		}
+		if err := c.doJSON(ctx, "GET", "/repo", nil, &repo); err != nil {
+			t.Fatal(err)
+		}
+		if repo.Name != "app" {
+			t.Errorf("request %d: name=%q", i, repo.Name)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("calls=%d", calls)
+	}
+
+	if err := c.doJSON(ctx, "GET", "/missing", nil, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing: %v", err)
+	}
+	if err := c.doJSON(ctx, "GET", "/private", nil, nil); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("private: %v", err)
+	}
+	err := c.doJSON(ctx, "GET", "/limited", nil, nil)
+	var apiErr *APIError
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &apiErr) || apiErr.RetryAfter.Hours() != 1 {
+		t.Errorf("limited: %v", err)
+	}
+	if _, err := c.do(ctx, "GET", "https://evil.example.com/x", nil); err == nil {
+		t.Error("expected cross-origin URL to be refused")
+	}
+}
@@ -2,6 +2,8 @@ package provider
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 	"sync"
 
 	"github.com/yi-nology/git-manage-service/biz/dal/db"
@@ -16,6 +18,12 @@ var (
 type ProviderManager struct {
 	mu    sync.RWMutex
 	cache map[uint]Provider
+	hosts map[string]configuredHost // 已配置 provider 的主机名，nil 表示尚未加载
+}
+
+type configuredHost struct {
+	platform Platform
+	baseURL  string
 }
 
 func GetManager() *ProviderManager {
@@ -44,9 +52,12 @@ func (m *ProviderManager) GetProvider(configID uint) (Provider, error) {
 		return nil, fmt.Errorf("provider config not found: %w", err)
 	}
 
-	cred, err := resolveCredential(cfg.CredentialID)
-	if err != nil {
-		return nil, fmt.Errorf("credential not found: %w", err)
+	// generic 平台只接收 webhook，凭证可选
+	var cred *po.Credential
+	if Platform(cfg.Platform) != PlatformGeneric || cfg.CredentialID > 0 {
+		if cred, err = resolveCredential(cfg.CredentialID); err != nil {
+			return nil, fmt.Errorf("credential not found: %w", err)
+		}
 	}
 
 	p, err := newProvider(cfg, cred)
@@ -57,10 +68,55 @@ func (m *ProviderManager) GetProvider(configID uint) (Provider, error) {
 	return p, nil
 }
 
+// Invalidate 清除指定配置的 provider 缓存，并在下次识别主机时重新加载配置列表
 func (m *ProviderManager) Invalidate(configID uint) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	delete(m.cache, configID)
+	m.hosts = nil
+}
+
+// lookupHost 按主机名查找已配置的 provider，配置列表在首次查找时加载并缓存
+func (m *ProviderManager) lookupHost(hostname string) (Platform, string, bool) {
+	m.mu.RLock()
+	hosts := m.hosts
+	m.mu.RUnlock()
+	if hosts == nil {
+		var err error
+		if hosts, err = loadConfiguredHosts(); err != nil {
+			return "", "", false
+		}
+		m.mu.Lock()
+		m.hosts = hosts
+		m.mu.Unlock()
+	}
+	h, ok := hosts[hostname]
+	return h.platform, h.baseURL, ok
+}
+
+func loadConfiguredHosts() (map[string]configuredHost, error) {
+	if db.DB == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	configs, err := db.NewProviderConfigDAO().FindAll()
+	if err != nil {
+		return nil, err
+	}
+	hosts := make(map[string]configuredHost, len(configs))
+	for _, cfg := range configs {
+		if Platform(cfg.Platform) == PlatformGeneric || cfg.BaseURL == "" {
+			continue
+		}
+		u, err := url.Parse(cfg.BaseURL)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		hostname := strings.ToLower(u.Hostname())
+		if _, dup := hosts[hostname]; !dup {
+			hosts[hostname] = configuredHost{platform: Platform(cfg.Platform), baseURL: strings.TrimRight(cfg.BaseURL, "/")}
+		}
+	}
+	return hosts, nil
 }
 
 func (m *ProviderManager) DetectAndCreate(remoteURL string, credentialID uint) (Provider, *DetectResult, error) {
@@ -87,6 +143,30 @@ func (m *ProviderManager) DetectAndCreate(remoteURL string, credentialID uint) (
 	return p, result, nil
 }
 
+// LinkedRepo 已关联平台仓库的本地仓库及其 provider
+type LinkedRepo struct {
+	Repo     *po.Repo
+	Provider Provider
+	Owner    string // 平台 owner
+	Name     string // 平台仓库名
+}
+
+// ResolveLinkedRepo 按 Key 查找本地仓库，要求其已关联 provider 配置与平台仓库
+func ResolveLinkedRepo(repoKey string) (*LinkedRepo, error) {
+	repo, err := db.NewRepoDAO().FindByKey(repoKey)
+	if err != nil {
+		return nil, fmt.Errorf("repo not found: %w", err)
+	}
+	if repo.ProviderConfigID == 0 || repo.PlatformOwner == "" || repo.PlatformRepo == "" {
+		return nil, fmt.Errorf("repo %s is not linked to a provider repo", repoKey)
+	}
+	p, err := GetManager().GetProvider(repo.ProviderConfigID)
+	if err != nil {
+		return nil, err
+	}
+	return &LinkedRepo{Repo: repo, Provider: p, Owner: repo.PlatformOwner, Name: repo.PlatformRepo}, nil
+}
+
 func resolveCredential(credentialID uint) (*po.Credential, error) {
 	if credentialID == 0 {
 		return nil, fmt.Errorf("credential ID is 0")
@@ -100,7 +180,10 @@ func resolveCredential(credentialID uint) (*po.Credential, error) {
 }
 
 func newProvider(cfg *po.ProviderConfig, cred *po.Credential) (Provider, error) {
-	token := cred.Secret
+	token := ""
+	if cred != nil {
+		token = cred.Secret
+	}
 	switch Platform(cfg.Platform) {
 	case PlatformGitLab:
 		return NewGitLabProvider(cfg.BaseURL, token), nil
@@ -108,6 +191,14 @@ func newProvider(cfg *po.ProviderConfig, cred *po.Credential) (Provider, error)
 		return NewGitHubProvider(cfg.BaseURL, token), nil
 	case PlatformGitea:
 		return NewGiteaProvider(cfg.BaseURL, token), nil
+	case PlatformBitbucket:
+		return NewBitbucketProvider(cfg.BaseURL, token), nil
+	case PlatformGerrit:
+		return NewGerritProvider(cfg.BaseURL, cred.Username, token), nil
+	case PlatformAzure:
+		return NewAzureProvider(cfg.BaseURL, cred.Username, token), nil
+	case PlatformGeneric:
+		return NewGenericProvider(cfg.ID, cfg.BaseURL, cfg.Options)
 	default:
 		return nil, fmt.Errorf("unsupported platform: %s", cfg.Platform)
 	}
@@ -3,7 +3,11 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"sort"
 	"time"
 )
 
@@ -13,17 +17,60 @@ const (
 	PlatformGitLab Platform = "gitlab"
 	PlatformGitHub Platform = "github"
 	PlatformGitea  Platform = "gitea"
+	// PlatformBitbucket Bitbucket Server / Data Center（不含 bitbucket.org 云服务）
+	PlatformBitbucket Platform = "bitbucket"
+	// PlatformGerrit Gerrit Code Review，CR 对应 change
+	PlatformGerrit Platform = "gerrit"
+	// PlatformAzure Azure DevOps Services / Server，owner 对应项目
+	PlatformAzure Platform = "azure"
+	// PlatformGeneric 任意系统的入站 webhook，按 JSONPath 映射字段
+	PlatformGeneric Platform = "generic"
 )
 
+// NeedsDedicatedEndpoint 报文无法按请求头识别来源的平台，需通过 /api/webhooks/receive/:id 接收
+func NeedsDedicatedEndpoint(p Platform) bool {
+	return p == PlatformGeneric || p == PlatformGerrit || p == PlatformAzure
+}
+
+// ErrUnsupported 平台不支持该操作，可用 errors.Is 判断
+var ErrUnsupported = errors.New("operation not supported by platform")
+
+type unsupportedError struct {
+	platform Platform
+	op       string
+}
+
+func (e *unsupportedError) Error() string {
+	return fmt.Sprintf("%s provider does not support %s", e.platform, e.op)
+}
+
+func (e *unsupportedError) Is(target error) bool { return target == ErrUnsupported }
+
+func unsupportedOp(platform Platform, op string) error {
+	return &unsupportedError{platform: platform, op: op}
+}
+
 type Provider interface {
 	Platform() Platform
 	ListRepos(ctx context.Context, opts ListRepoOptions) ([]*PlatformRepo, error)
 	GetRepo(ctx context.Context, owner, repo string) (*PlatformRepo, error)
+	CreateRepo(ctx context.Context, opts CreateRepoOptions) (*PlatformRepo, error)
+	GetBranchProtection(ctx context.Context, owner, repo, branch string) (*BranchProtection, error)
+	SetBranchProtection(ctx context.Context, owner, repo string, protection BranchProtection) (*BranchProtection, error)
 	CreateCR(ctx context.Context, opts CreateCROptions) (*ChangeRequest, error)
 	GetCR(ctx context.Context, owner, repo string, number int) (*ChangeRequest, error)
 	ListCRs(ctx context.Context, opts ListCROptions) ([]*ChangeRequest, int, error)
 	MergeCR(ctx context.Context, owner, repo string, number int, opts MergeCROptions) (*ChangeRequest, error)
 	CloseCR(ctx context.Context, owner, repo string, number int) (*ChangeRequest, error)
+	UpdateCR(ctx context.Context, owner, repo string, number int, opts UpdateCROptions) (*ChangeRequest, error)
+	ListCRComments(ctx context.Context, owner, repo string, number int) ([]*CRComment, error)
+	AddCRComment(ctx context.Context, owner, repo string, number int, opts AddCRCommentOptions) (*CRComment, error)
+	ReviewCR(ctx context.Context, owner, repo string, number int, opts ReviewCROptions) error
+	GetCommitStatuses(ctx context.Context, owner, repo, sha string) ([]*CommitStatus, error)
+	CreateRelease(ctx context.Context, opts CreateReleaseOptions) (*Release, error)
+	UpdateRelease(ctx context.Context, owner, repo, tag string, opts UpdateReleaseOptions) (*Release, error)
+	ListReleases(ctx context.Context, owner, repo string) ([]*Release, error)
+	UploadReleaseAsset(ctx context.Context, owner, repo, tag string, asset ReleaseAssetUpload) (*ReleaseAsset, error)
 	CreateWebhook(ctx context.Context, opts CreateWebhookOptions) (*PlatformWebhook, error)
 	DeleteWebhook(ctx context.Context, owner, repo string, webhookID int64) error
 	ListWebhooks(ctx context.Context, owner, repo string) ([]*PlatformWebhook, error)
@@ -42,9 +89,19 @@ type PlatformRepo struct {
 	SSHURL        string   `json:"ssh_url"`
 	DefaultBranch string   `json:"default_branch"`
 	Private       bool     `json:"private"`
+	Visibility    string   `json:"visibility,omitempty"` // private | internal | public
+	Archived      bool     `json:"archived"`
 	Platform      Platform `json:"platform"`
 }
 
+// RemoteURL 凭证为 SSH 密钥时优先使用 SSH 地址，否则优先使用 HTTP 地址
+func (r *PlatformRepo) RemoteURL(useSSH bool) string {
+	if (useSSH && r.SSHURL != "") || r.CloneURL == "" {
+		return r.SSHURL
+	}
+	return r.CloneURL
+}
+
 type CRState string
 
 const (
@@ -66,6 +123,7 @@ type ChangeRequest struct {
 	Labels       []string  `json:"labels"`
 	MergeStatus  string    `json:"merge_status"`
 	WebURL       string    `json:"web_url"`
+	HeadSHA      string    `json:"head_sha"` // 源分支最新提交，用于查询 CI 状态
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -81,6 +139,43 @@ type ListRepoOptions struct {
 	Owner   string `json:"owner"`
 	Page    int    `json:"page"`
 	PerPage int    `json:"per_page"`
+	// All 自动翻页返回全部仓库，忽略 Page / PerPage
+	All bool `json:"all"`
+}
+
+// BranchProtection 分支保护设置；Protected 为 false 时其余字段无意义，写入时表示解除保护
+type BranchProtection struct {
+	Branch          string `json:"branch"`
+	Protected       bool   `json:"protected"`
+	RequiredReviews int    `json:"required_reviews"` // 合并前所需的批准数，0 表示不要求
+	AllowForcePush  bool   `json:"allow_force_push"`
+	RequireChecks   bool   `json:"require_checks"` // 合并前 CI 必须通过
+	// RequiredChecks 必须通过的检查名；GitLab 只支持流水线整体通过，始终为空
+	RequiredChecks []string `json:"required_checks,omitempty"`
+}
+
+// CreateRepoOptions 在平台上新建空仓库；Owner 为空时建在当前用户名下
+type CreateRepoOptions struct {
+	Owner         string `json:"owner"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Visibility    string `json:"visibility"` // private | internal | public，默认 private
+	DefaultBranch string `json:"default_branch"`
+}
+
+// 仓库可见性
+const (
+	VisibilityPrivate  = "private"
+	VisibilityInternal = "internal"
+	VisibilityPublic   = "public"
+)
+
+func (o CreateRepoOptions) visibility() string {
+	switch o.Visibility {
+	case VisibilityPublic, VisibilityInternal:
+		return o.Visibility
+	}
+	return VisibilityPrivate
 }
 
 type CreateCROptions struct {
@@ -92,6 +187,8 @@ type CreateCROptions struct {
 	TargetBranch       string   `json:"target_branch"`
 	Labels             []string `json:"labels"`
 	RemoveSourceBranch bool     `json:"remove_source_branch"`
+	// RepoPath 本地仓库路径，供通过推送创建 CR 的平台（Gerrit refs/for/<branch>）使用
+	RepoPath string `json:"-"`
 }
 
 type ListCROptions struct {
@@ -110,6 +207,136 @@ type MergeCROptions struct {
 	RemoveSourceBranch bool   `json:"remove_source_branch"`
 }
 
+// Release 平台发布，统一按 tag 定位（GitLab 的发布没有数字 ID）
+type Release struct {
+	ID         int64           `json:"id,omitempty"`
+	TagName    string          `json:"tag_name"`
+	Name       string          `json:"name"`
+	Body       string          `json:"body"`
+	Draft      bool            `json:"draft"`
+	Prerelease bool            `json:"prerelease"`
+	WebURL     string          `json:"web_url"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Assets     []*ReleaseAsset `json:"assets"`
+}
+
+type ReleaseAsset struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	DownloadURL string `json:"download_url"`
+}
+
+// CreateReleaseOptions Target 为 tag 不存在时用于创建 tag 的提交 / 分支；
+// GitLab 不支持 Draft 与 Prerelease，会被忽略
+type CreateReleaseOptions struct {
+	Owner      string `json:"owner"`
+	Repo       string `json:"repo"`
+	TagName    string `json:"tag_name"`
+	Target     string `json:"target"`
+	Name       string `json:"name"`
+	Body       string `json:"body"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+}
+
+// UpdateReleaseOptions nil 字段保持不变
+type UpdateReleaseOptions struct {
+	Name       *string `json:"name"`
+	Body       *string `json:"body"`
+	Draft      *bool   `json:"draft"`
+	Prerelease *bool   `json:"prerelease"`
+}
+
+// ReleaseAssetUpload 上传的附件内容；Size < 0 表示长度未知（GitHub 要求已知长度）
+type ReleaseAssetUpload struct {
+	Name        string
+	ContentType string
+	Size        int64
+	Content     io.Reader
+}
+
+// UpdateCROptions 为空的字段保持不变
+type UpdateCROptions struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// CRComment CR 评论；Path 不为空时为针对文件某一行的行内评论
+type CRComment struct {
+	ID        int64     `json:"id"`
+	Author    *CRUser   `json:"author"`
+	Body      string    `json:"body"`
+	Path      string    `json:"path,omitempty"`
+	Line      int       `json:"line,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddCRCommentOptions Path + Line 指定时发表行内评论（新版本文件的行号）；
+// CommitSHA 为空时使用 CR 当前的 HeadSHA
+type AddCRCommentOptions struct {
+	Body      string `json:"body"`
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	CommitSHA string `json:"commit_sha"`
+}
+
+func (o AddCRCommentOptions) inline() bool { return o.Path != "" && o.Line > 0 }
+
+type ReviewAction string
+
+const (
+	ReviewApprove        ReviewAction = "approve"
+	ReviewRequestChanges ReviewAction = "request_changes"
+)
+
+type ReviewCROptions struct {
+	Action ReviewAction `json:"action"`
+	Body   string       `json:"body"`
+}
+
+// CheckState CI 检查状态
+type CheckState string
+
+const (
+	CheckStateNone    CheckState = "none" // 没有任何检查
+	CheckStatePending CheckState = "pending"
+	CheckStateSuccess CheckState = "success"
+	CheckStateFailure CheckState = "failure"
+)
+
+// CommitStatus 提交上的一项 CI 检查（commit status / check run / pipeline）
+type CommitStatus struct {
+	Name        string     `json:"name"`
+	State       CheckState `json:"state"`
+	Description string     `json:"description,omitempty"`
+	TargetURL   string     `json:"target_url,omitempty"`
+}
+
+// AggregateCheckState 任一失败即失败，其次任一进行中即进行中，全部成功才成功
+func AggregateCheckState(statuses []*CommitStatus) CheckState {
+	if len(statuses) == 0 {
+		return CheckStateNone
+	}
+	state := CheckStateSuccess
+	for _, s := range statuses {
+		switch s.State {
+		case CheckStateFailure:
+			return CheckStateFailure
+		case CheckStatePending:
+			state = CheckStatePending
+		}
+	}
+	return state
+}
+
+// sortComments 按创建时间排序，合并多个来源的评论后使用
+func sortComments(comments []*CRComment) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+}
+
 type CreateWebhookOptions struct {
 	Owner  string   `json:"owner"`
 	Repo   string   `json:"repo"`
@@ -149,3 +376,21 @@ type TestConnectionResult struct {
 	UserName  string `json:"user_name"`
 	Message   string `json:"message,omitempty"`
 }
+
+// releaseUpdateBody GitHub / Gitea 发布 PATCH 请求体，只包含需要修改的字段
+func releaseUpdateBody(opts UpdateReleaseOptions) map[string]interface{} {
+	body := map[string]interface{}{}
+	if opts.Name != nil {
+		body["name"] = *opts.Name
+	}
+	if opts.Body != nil {
+		body["body"] = *opts.Body
+	}
+	if opts.Draft != nil {
+		body["draft"] = *opts.Draft
+	}
+	if opts.Prerelease != nil {
+		body["prerelease"] = *opts.Prerelease
+	}
+	return body
+}
@@ -0,0 +1,19 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+)
+
+type skipSignatureKey struct{}
+
+// SkipSignatureCheck 标记请求跳过签名校验，用于重放已存储（且已脱敏）的 webhook 报文
+func SkipSignatureCheck(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), skipSignatureKey{}, true))
+}
+
+// signatureCheckSkipped 判断请求是否由 SkipSignatureCheck 标记
+func signatureCheckSkipped(r *http.Request) bool {
+	skip, _ := r.Context().Value(skipSignatureKey{}).(bool)
+	return skip
+}
@@ -0,0 +1,359 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/service"
+	"github.com/yi-nology/git-manage-service/biz/service/auth"
+	"github.com/yi-nology/git-manage-service/biz/service/git"
+	"github.com/yi-nology/git-manage-service/biz/service/provider"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+)
+
+// notesMaxCommits 生成的发布说明中最多列出的提交数
+const notesMaxCommits = 500
+
+func List(ctx context.Context, repoKey string) ([]*api.ReleaseDTO, error) {
+	t, err := resolveTarget(repoKey)
+	if err != nil {
+		return nil, err
+	}
+	releases, err := t.Provider.ListReleases(ctx, t.Owner, t.Name)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*api.ReleaseDTO, 0, len(releases))
+	for _, r := range releases {
+		result = append(result, toDTO(repoKey, r))
+	}
+	return result, nil
+}
+
+// Create 发布 Release 后逐个上传附件，附件失败只记录在 asset_errors
+func Create(ctx context.Context, req *api.CreateReleaseReq) (*api.ReleaseDTO, error) {
+	t, err := resolveTarget(req.RepoKey)
+	if err != nil {
+		return nil, err
+	}
+	name := req.Name
+	if name == "" {
+		name = req.TagName
+	}
+	r, err := t.Provider.CreateRelease(ctx, provider.CreateReleaseOptions{
+		Owner: t.Owner, Repo: t.Name, TagName: req.TagName, Target: req.Target,
+		Name: name, Body: req.Body, Draft: req.Draft, Prerelease: req.Prerelease,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t.withAssets(ctx, r, req.Assets), nil
+}
+
+func Update(ctx context.Context, req *api.UpdateReleaseReq) (*api.ReleaseDTO, error) {
+	t, err := resolveTarget(req.RepoKey)
+	if err != nil {
+		return nil, err
+	}
+	r, err := t.Provider.UpdateRelease(ctx, t.Owner, t.Name, req.TagName, provider.UpdateReleaseOptions{
+		Name: req.Name, Body: req.Body, Draft: req.Draft, Prerelease: req.Prerelease,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t.withAssets(ctx, r, req.Assets), nil
+}
+
+// TagRelease 在本地创建 tag 并推送，然后以生成的发布说明创建 Release；
+// 推送失败时删除本地 tag，便于修正后重试
+func TagRelease(ctx context.Context, req *api.TagReleaseReq) (*api.TagReleaseResultDTO, error) {
+	t, err := resolveTarget(req.RepoKey)
+	if err != nil {
+		return nil, err
+	}
+	gitSvc := git.NewGitService()
+	path := t.Repo.Path
+	ref := req.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	remote := req.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+
+	tagName := req.TagName
+	if tagName == "auto" {
+		tagName = "v0.1.0"
+		if latest, err := gitSvc.GetLatestVersion(path); err == nil && latest != "" {
+			next, err := gitSvc.GetNextVersions(path)
+			if err != nil {
+				return nil, err
+			}
+			tagName = next.NextPatch
+		}
+	}
+	previous := req.PreviousTag
+	if previous == "" {
+		// 新 tag 尚未创建，ref 上最近的 tag 即上一个版本
+		previous, _ = gitSvc.RunCommand(path, "describe", "--tags", "--abbrev=0", ref)
+		previous = strings.TrimSpace(previous)
+	}
+
+	message := req.Message
+	if message == "" {
+		message = "Release " + tagName
+	}
+	authorName, authorEmail, _ := gitSvc.GetGlobalGitUser()
+	if err := gitSvc.CreateTag(path, tagName, ref, message, authorName, authorEmail); err != nil {
+		return nil, fmt.Errorf("create tag: %w", err)
+	}
+	pushAuth, err := resolvePushAuth(gitSvc, t.Repo, remote)
+	if err == nil {
+		err = gitSvc.PushTagWithAuth(path, remote, tagName, pushAuth)
+	}
+	if err != nil {
+		gitSvc.DeleteTag(path, tagName)
+		return nil, fmt.Errorf("push tag %s to %s: %w", tagName, remote, err)
+	}
+
+	body := req.Body
+	if body == "" {
+		if body, err = GenerateNotes(path, previous, tagName); err != nil {
+			return nil, err
+		}
+	}
+	name := req.Name
+	if name == "" {
+		name = tagName
+	}
+	r, err := t.Provider.CreateRelease(ctx, provider.CreateReleaseOptions{
+		Owner: t.Owner, Repo: t.Name, TagName: tagName,
+		Name: name, Body: body, Draft: req.Draft, Prerelease: req.Prerelease,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tag %s pushed but release failed: %w", tagName, err)
+	}
+	return &api.TagReleaseResultDTO{
+		TagName: tagName, PreviousTag: previous,
+		Release: t.withAssets(ctx, r, req.Assets),
+	}, nil
+}
+
+var conventionalRe = regexp.MustCompile(`^(\w+)(\([^)]*\))?!?:\s*(.+)$`)
+
+// GenerateNotes 按 Conventional Commits 前缀对 previousTag..tagName 的提交分组；
+// previousTag 为空时列出 tagName 之前的全部提交
+func GenerateNotes(path, previousTag, tagName string) (string, error) {
+	logRange := tagName
+	if previousTag != "" {
+		logRange = previousTag + ".." + tagName
+	}
+	out, err := git.NewGitService().RunCommand(path, "log", "--no-merges", "--pretty=format:%h%x09%an%x09%s", logRange)
+	if err != nil {
+		return "", fmt.Errorf("git log %s: %w", logRange, err)
+	}
+
+	sections := []string{"Features", "Bug Fixes", "Performance", "Other Changes"}
+	grouped := map[string][]string{}
+	count := 0
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		count++
+		if count > notesMaxCommits {
+			continue
+		}
+		section, subject := "Other Changes", parts[2]
+		if m := conventionalRe.FindStringSubmatch(subject); m != nil {
+			switch strings.ToLower(m[1]) {
+			case "feat":
+				section, subject = "Features", m[3]
+			case "fix":
+				section, subject = "Bug Fixes", m[3]
+			case "perf":
+				section, subject = "Performance", m[3]
+			}
+		}
+		grouped[section] = append(grouped[section], fmt.Sprintf("- %s (%s, %s)", subject, parts[0], parts[1]))
+	}
+
+	var b strings.Builder
+	b.WriteString("## What's Changed\n")
+	for _, section := range sections {
+		if len(grouped[section]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n%s\n", section, strings.Join(grouped[section], "\n"))
+	}
+	if count == 0 {
+		b.WriteString("\nNo changes.\n")
+	} else if count > notesMaxCommits {
+		fmt.Fprintf(&b, "\n... and %d more commits\n", count-notesMaxCommits)
+	}
+	if previousTag != "" {
+		fmt.Fprintf(&b, "\n**Full Changelog**: %s...%s\n", previousTag, tagName)
+	}
+	return b.String(), nil
+}
+
+// resolvePushAuth 按仓库为该远程配置的凭证解析推送认证，数据库 SSH 密钥需要读取密钥内容
+func resolvePushAuth(gitSvc *git.GitService, repo *po.Repo, remote string) (transport.AuthMethod, error) {
+	authSvc := auth.NewAuthService()
+	method, isDBKey, err := authSvc.ResolveCredentialForRemote(
+		repo.RemoteCredentials, repo.DefaultCredentialID, repo.RemoteAuths, remote,
+		repo.AuthType, repo.AuthKey, repo.AuthSecret,
+	)
+	if !isDBKey {
+		return method, err
+	}
+	var privateKey, passphrase string
+	if credID := auth.GetCredentialIDForRemote(repo.RemoteCredentials, repo.DefaultCredentialID, remote); credID > 0 {
+		privateKey, passphrase, err = authSvc.GetCredentialKeyContent(credID)
+	} else {
+		authInfo := auth.GetAuthInfoForRemote(repo.RemoteAuths, remote, repo.AuthType, repo.AuthKey, repo.AuthSecret)
+		privateKey, passphrase, err = authSvc.GetDBSSHKeyContent(authInfo.SSHKeyID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load SSH key: %w", err)
+	}
+	return gitSvc.GetAuthFromDBKey(privateKey, passphrase)
+}
+
+// target 发布操作的目标仓库
+type target struct {
+	*provider.LinkedRepo
+}
+
+func resolveTarget(repoKey string) (*target, error) {
+	lr, err := provider.ResolveLinkedRepo(repoKey)
+	if err != nil {
+		return nil, err
+	}
+	return &target{lr}, nil
+}
+
+// withAssets 上传附件并把结果合并进 Release
+func (t *target) withAssets(ctx context.Context, r *provider.Release, sources []api.ReleaseAssetSource) *api.ReleaseDTO {
+	var errs []string
+	for _, src := range sources {
+		asset, err := t.uploadAsset(ctx, r.TagName, src)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", assetLabel(src), err))
+			continue
+		}
+		r.Assets = append(r.Assets, asset)
+	}
+	dto := toDTO(t.Repo.Key, r)
+	dto.AssetErrors = errs
+	return dto
+}
+
+func (t *target) uploadAsset(ctx context.Context, tag string, src api.ReleaseAssetSource) (*provider.ReleaseAsset, error) {
+	upload := provider.ReleaseAssetUpload{Name: src.Name, ContentType: src.ContentType}
+	switch {
+	case src.Path != "" && src.Key != "":
+		return nil, fmt.Errorf("path and key are mutually exclusive")
+	case src.Path != "":
+		full, err := repoFile(t.Repo.Path, src.Path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.Open(full)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("%s is a directory", src.Path)
+		}
+		upload.Content, upload.Size = f, info.Size()
+	case src.Key != "":
+		store := service.Storage()
+		if store == nil {
+			return nil, fmt.Errorf("object storage is not configured")
+		}
+		bucket := src.Bucket
+		if bucket == "" {
+			bucket = configs.GlobalConfig.Storage.RepoBucket
+		}
+		info, err := store.StatObject(ctx, bucket, src.Key)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := store.GetObject(ctx, bucket, src.Key)
+		if err != nil {
+			return nil, err
+		}
+		defer obj.Close()
+		upload.Content, upload.Size = obj, info.Size
+	default:
+		return nil, fmt.Errorf("path or key is required")
+	}
+
+	if upload.Name == "" {
+		upload.Name = filepath.Base(assetLabel(src))
+	}
+	if upload.ContentType == "" {
+		upload.ContentType = mime.TypeByExtension(filepath.Ext(upload.Name))
+	}
+	if upload.ContentType == "" {
+		upload.ContentType = "application/octet-stream"
+	}
+	return t.Provider.UploadReleaseAsset(ctx, t.Owner, t.Name, tag, upload)
+}
+
+// repoFile 把附件路径限制在仓库工作目录内（包括符号链接指向）
+func repoFile(repoPath, rel string) (string, error) {
+	if filepath.IsAbs(rel) {
+		return "", fmt.Errorf("path must be relative to the repo")
+	}
+	root, err := filepath.EvalSymlinks(repoPath)
+	if err != nil {
+		return "", err
+	}
+	full, err := filepath.EvalSymlinks(filepath.Join(root, rel))
+	if err != nil {
+		return "", err
+	}
+	if r, err := filepath.Rel(root, full); err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s is outside the repo", rel)
+	}
+	return full, nil
+}
+
+func assetLabel(src api.ReleaseAssetSource) string {
+	switch {
+	case src.Name != "":
+		return src.Name
+	case src.Path != "":
+		return src.Path
+	}
+	return src.Key
+}
+
+func toDTO(repoKey string, r *provider.Release) *api.ReleaseDTO {
+	dto := &api.ReleaseDTO{
+		RepoKey: repoKey, TagName: r.TagName, Name: r.Name, Body: r.Body,
+		Draft: r.Draft, Prerelease: r.Prerelease, WebURL: r.WebURL, CreatedAt: r.CreatedAt,
+		Assets: make([]*api.ReleaseAssetDTO, 0, len(r.Assets)),
+	}
+	for _, a := range r.Assets {
+		dto.Assets = append(dto.Assets, &api.ReleaseAssetDTO{Name: a.Name, Size: a.Size, DownloadURL: a.DownloadURL})
+	}
+	return dto
+}
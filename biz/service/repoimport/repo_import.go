@@ -0,0 +1,489 @@
+package repoimport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/google/uuid"
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/service/git"
+	"github.com/yi-nology/git-manage-service/biz/service/provider"
+	"github.com/yi-nology/git-manage-service/biz/service/repotask"
+	syncSvc "github.com/yi-nology/git-manage-service/biz/service/sync"
+	"github.com/yi-nology/git-manage-service/pkg/strutil"
+)
+
+// defaultMirrorRemote 镜像模板未指定 target_remote 时使用的 remote 名
+const defaultMirrorRemote = "mirror"
+
+// templateData 路径模板与目标地址模板可用的字段
+type templateData struct {
+	Platform string
+	Owner    string
+	Name     string
+	FullName string
+}
+
+// Start 校验参数并创建导入任务，列举、筛选与规划在异步任务中进行：
+// 为每个仓库生成导入记录并提交克隆任务，dry run 只生成导入计划。进度与最终结果通过 Get 查看。
+func Start(ctx context.Context, providerConfigID uint, req *api.RepoImportReq) (*api.RepoImportDTO, error) {
+	pathTmpl, err := parseTemplate("path_template", req.PathTemplate)
+	if err != nil {
+		return nil, err
+	}
+	if mt := req.MirrorTemplate; mt != nil {
+		if mt.CreateTargetRepo == nil && mt.TargetURLTemplate == "" {
+			return nil, fmt.Errorf("mirror_template requires create_target_repo or target_url_template")
+		}
+		if mt.TargetURLTemplate != "" {
+			if _, err := parseTemplate("target_url_template", mt.TargetURLTemplate); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, v := range req.Filter.Visibility {
+		if v != provider.VisibilityPrivate && v != provider.VisibilityInternal && v != provider.VisibilityPublic {
+			return nil, fmt.Errorf("invalid visibility filter: %s", v)
+		}
+	}
+
+	cfg, err := db.NewProviderConfigDAO().FindByID(providerConfigID)
+	if err != nil {
+		return nil, fmt.Errorf("provider config not found: %w", err)
+	}
+	p, err := provider.GetManager().GetProvider(cfg.ID)
+	if err != nil {
+		return nil, err
+	}
+	credentialID := req.CredentialID
+	if credentialID == 0 {
+		credentialID = cfg.CredentialID
+	}
+	useSSH := false
+	if credentialID > 0 {
+		cred, err := db.NewCredentialDAO().FindByID(credentialID)
+		if err != nil {
+			return nil, fmt.Errorf("credential not found: %w", err)
+		}
+		useSSH = cred.Type == "ssh_key"
+	}
+
+	dao := db.NewRepoImportDAO()
+	job := &po.RepoImport{
+		ProviderConfigID: cfg.ID, Owner: req.Owner, Filter: req.Filter,
+		PathTemplate: req.PathTemplate, CredentialID: credentialID,
+		MirrorTemplate: req.MirrorTemplate, DryRun: req.DryRun,
+		Status: po.RepoImportStatusRunning,
+	}
+	if err := dao.Create(job); err != nil {
+		return nil, err
+	}
+
+	target := fmt.Sprintf("provider:%d/%s", cfg.ID, req.Owner)
+	task, err := git.GlobalTaskManager.Submit(po.AsyncTaskTypeImport, target, func(ctx context.Context, taskID string) error {
+		if err := plan(ctx, taskID, cfg, p, job, &req.Filter, pathTmpl, useSSH); err != nil {
+			dao.UpdateStatus(job.ID, po.RepoImportStatusFailed, truncate(err.Error()))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		dao.UpdateStatus(job.ID, po.RepoImportStatusFailed, truncate("submit import: "+err.Error()))
+		return nil, err
+	}
+	job.PlanTaskID = task.ID
+	if err := dao.UpdatePlanTaskID(job.ID, task.ID); err != nil {
+		return nil, err
+	}
+	dto := api.NewRepoImportDTO(job, nil, true)
+	return &dto, nil
+}
+
+// plan 列举并筛选仓库，写入导入记录后提交克隆任务
+func plan(ctx context.Context, taskID string, cfg *po.ProviderConfig, p provider.Provider, job *po.RepoImport, filter *po.RepoImportFilter, pathTmpl *template.Template, useSSH bool) error {
+	dao := db.NewRepoImportDAO()
+	repos, err := p.ListRepos(ctx, provider.ListRepoOptions{Owner: job.Owner, All: true})
+	if err != nil {
+		return fmt.Errorf("failed to list repos: %w", err)
+	}
+	git.GlobalTaskManager.AppendLog(taskID, fmt.Sprintf("listed %d repos", len(repos)))
+
+	// 先写入全部导入记录再提交克隆，避免先完成的任务误判整个导入已结束
+	items := make([]po.RepoImportItem, 0, len(repos))
+	seenPaths := map[string]bool{}
+	seenNames := map[string]bool{}
+	for _, r := range repos {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !matchFilter(r, filter) {
+			continue
+		}
+		item := planItem(cfg, job, r, pathTmpl, useSSH, seenPaths, seenNames)
+		if err := dao.CreateItem(&item); err != nil {
+			return fmt.Errorf("failed to save item %s: %w", r.FullName, err)
+		}
+		items = append(items, item)
+	}
+	git.GlobalTaskManager.AppendLog(taskID, fmt.Sprintf("planned %d repos", len(items)))
+
+	if !job.DryRun {
+		for i := range items {
+			if items[i].Status != po.RepoImportItemCloning {
+				continue
+			}
+			submitClone(job, &items[i], repoOf(repos, items[i].FullName))
+		}
+	}
+	finishIfDone(job.ID)
+	return nil
+}
+
+// Get 返回导入报告；克隆任务已失败或被取消（含服务重启中断）但未回调的记录在此修正
+func Get(id uint) (*api.RepoImportDTO, error) {
+	dao := db.NewRepoImportDAO()
+	job, err := dao.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := dao.FindItems(id)
+	if err != nil {
+		return nil, err
+	}
+	// 规划任务失败或被取消（含服务重启中断）时任务未能自行标记失败
+	if job.Status == po.RepoImportStatusRunning && job.PlanTaskID != "" {
+		if task, ok := git.GlobalTaskManager.GetTask(job.PlanTaskID); ok &&
+			(task.Status == po.AsyncTaskStatusFailed || task.Status == po.AsyncTaskStatusCancelled) {
+			dao.UpdateStatus(id, po.RepoImportStatusFailed, truncate("import "+task.Status+": "+task.Error))
+			if job, err = dao.FindByID(id); err != nil {
+				return nil, err
+			}
+		}
+	}
+	changed := false
+	for i := range items {
+		it := &items[i]
+		if it.Status != po.RepoImportItemCloning || it.CloneTaskID == "" {
+			continue
+		}
+		task, ok := git.GlobalTaskManager.GetTask(it.CloneTaskID)
+		if !ok || (task.Status != po.AsyncTaskStatusFailed && task.Status != po.AsyncTaskStatusCancelled) {
+			continue
+		}
+		it.Status = po.RepoImportItemFailed
+		it.Reason = truncate("clone " + task.Status + ": " + task.Error)
+		dao.SaveItem(it)
+		changed = true
+	}
+	if changed {
+		finishIfDone(id)
+		if job, err = dao.FindByID(id); err != nil {
+			return nil, err
+		}
+	}
+	dto := api.NewRepoImportDTO(job, items, true)
+	return &dto, nil
+}
+
+// List 分页列出导入任务（不含明细）
+func List(page, pageSize int) ([]api.RepoImportDTO, int64, error) {
+	dao := db.NewRepoImportDAO()
+	jobs, total, err := dao.List(page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	result := make([]api.RepoImportDTO, 0, len(jobs))
+	for i := range jobs {
+		items, _ := dao.FindItems(jobs[i].ID)
+		result = append(result, api.NewRepoImportDTO(&jobs[i], items, false))
+	}
+	return result, total, nil
+}
+
+// matchFilter 名称 glob 可匹配仓库名或 full_name
+func matchFilter(r *provider.PlatformRepo, f *po.RepoImportFilter) bool {
+	if r.Archived && !f.IncludeArchived {
+		return false
+	}
+	if f.NamePattern != "" {
+		nameOK, _ := filepath.Match(f.NamePattern, repoName(r))
+		fullOK, _ := filepath.Match(f.NamePattern, r.FullName)
+		if !nameOK && !fullOK {
+			return false
+		}
+	}
+	if len(f.Visibility) > 0 {
+		vis := visibilityOf(r)
+		for _, v := range f.Visibility {
+			if v == vis {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func visibilityOf(r *provider.PlatformRepo) string {
+	if r.Visibility != "" {
+		return r.Visibility
+	}
+	if r.Private {
+		return provider.VisibilityPrivate
+	}
+	return provider.VisibilityPublic
+}
+
+// repoName 仓库路径名（不含 owner），与 Repo.PlatformRepo 一致
+func repoName(r *provider.PlatformRepo) string {
+	if r.Owner != "" && strings.HasPrefix(r.FullName, r.Owner+"/") {
+		return strings.TrimPrefix(r.FullName, r.Owner+"/")
+	}
+	return r.Name
+}
+
+// planItem 渲染本地路径、预留仓库名并检查是否可导入；可导入的记录状态为 cloning（dry run 为 planned）
+func planItem(cfg *po.ProviderConfig, job *po.RepoImport, r *provider.PlatformRepo, pathTmpl *template.Template, useSSH bool, seenPaths, seenNames map[string]bool) po.RepoImportItem {
+	item := po.RepoImportItem{
+		ImportID: job.ID, FullName: r.FullName, RemoteURL: r.RemoteURL(useSSH),
+		Status: po.RepoImportItemCloning,
+	}
+	if job.DryRun {
+		item.Status = po.RepoImportItemPlanned
+	}
+	skip := func(reason string) po.RepoImportItem {
+		item.Status = po.RepoImportItemSkipped
+		item.Reason = reason
+		return item
+	}
+
+	localPath, err := render(pathTmpl, dataOf(cfg, r))
+	if err != nil {
+		item.Status = po.RepoImportItemFailed
+		item.Reason = truncate("render path: " + err.Error())
+		return item
+	}
+	item.LocalPath = filepath.Clean(localPath)
+
+	if existing, err := db.NewRepoDAO().FindByPlatform(cfg.ID, r.Owner, repoName(r)); err == nil {
+		return skip("already registered as " + existing.Name)
+	}
+	if item.RemoteURL == "" {
+		return skip("no clone URL")
+	}
+	if seenPaths[item.LocalPath] {
+		return skip("duplicate local path in this import")
+	}
+	seenPaths[item.LocalPath] = true
+	if _, err := os.Stat(item.LocalPath); err == nil {
+		return skip("local path already exists")
+	}
+	// 同一次导入的仓库在克隆完成后才注册，仓库名需在规划阶段一并预留
+	name, err := uniqueRepoName(item.LocalPath, r, seenNames)
+	if err != nil {
+		item.Status = po.RepoImportItemFailed
+		item.Reason = truncate(err.Error())
+		return item
+	}
+	seenNames[name] = true
+	item.RepoName = name
+	return item
+}
+
+func submitClone(job *po.RepoImport, item *po.RepoImportItem, r *provider.PlatformRepo) {
+	dao := db.NewRepoImportDAO()
+	it := *item
+	platformRepoID := ""
+	if r.ID > 0 {
+		platformRepoID = fmt.Sprint(r.ID)
+	}
+	task, err := repotask.SubmitClone(repotask.CloneOptions{
+		RemoteURL:        item.RemoteURL,
+		LocalPath:        item.LocalPath,
+		CredentialID:     job.CredentialID,
+		Name:             item.RepoName,
+		ProviderConfigID: job.ProviderConfigID,
+		PlatformRepoID:   platformRepoID,
+		PlatformOwner:    r.Owner,
+		PlatformRepo:     repoName(r),
+		OnFinish: func(taskID string, repo *po.Repo, err error) {
+			onCloneFinished(job, it, r, taskID, repo, err)
+		},
+	})
+	if err != nil {
+		item.Status = po.RepoImportItemFailed
+		item.Reason = truncate("submit clone: " + err.Error())
+		dao.SaveItem(item)
+		return
+	}
+	item.CloneTaskID = task.ID
+	dao.UpdateItemTaskID(item.ID, task.ID)
+}
+
+// uniqueRepoName 仓库名默认取目录名，与已注册仓库或本次导入已预留的名称重复时改用 owner-repo 形式
+func uniqueRepoName(localPath string, r *provider.PlatformRepo, reserved map[string]bool) (string, error) {
+	repoDAO := db.NewRepoDAO()
+	for _, name := range []string{filepath.Base(localPath), strings.ReplaceAll(r.FullName, "/", "-")} {
+		if reserved[name] {
+			continue
+		}
+		exists, err := repoDAO.ExistsByName(name)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("repo name for %s already taken", r.FullName)
+}
+
+func onCloneFinished(job *po.RepoImport, item po.RepoImportItem, r *provider.PlatformRepo, taskID string, repo *po.Repo, cloneErr error) {
+	dao := db.NewRepoImportDAO()
+	item.CloneTaskID = taskID
+	if cloneErr != nil {
+		item.Status = po.RepoImportItemFailed
+		item.Reason = truncate(cloneErr.Error())
+	} else {
+		item.Status = po.RepoImportItemImported
+		item.RepoKey = repo.Key
+		if job.MirrorTemplate != nil {
+			cfg, _ := db.NewProviderConfigDAO().FindByID(job.ProviderConfigID)
+			key, err := createMirrorTask(job.MirrorTemplate, repo, dataOf(cfg, r))
+			if err != nil {
+				item.Reason = truncate("mirror task: " + err.Error())
+			}
+			item.SyncTaskKey = key
+		}
+	}
+	if err := dao.SaveItem(&item); err != nil {
+		log.Printf("repo import %d: failed to save item %s: %v", job.ID, item.FullName, err)
+	}
+	finishIfDone(job.ID)
+}
+
+// createMirrorTask 按模板为导入的仓库添加目标 remote 并创建同步任务
+func createMirrorTask(mt *po.RepoImportMirrorTemplate, repo *po.Repo, data templateData) (string, error) {
+	remote := mt.TargetRemote
+	if remote == "" {
+		remote = defaultMirrorRemote
+	}
+	if mt.CreateTargetRepo != nil {
+		result, err := syncSvc.NewSyncService().ProvisionTargetRepo(context.Background(), repo.Key, remote, &api.CreateTargetRepoReq{
+			ProviderConfigID: mt.CreateTargetRepo.ProviderConfigID,
+			Owner:            mt.CreateTargetRepo.Owner,
+			Name:             data.Name,
+			Visibility:       mt.CreateTargetRepo.Visibility,
+			RemoteName:       remote,
+		})
+		if err != nil {
+			return "", err
+		}
+		remote = result.RemoteName
+	} else {
+		tmpl, err := parseTemplate("target_url_template", mt.TargetURLTemplate)
+		if err != nil {
+			return "", err
+		}
+		targetURL, err := render(tmpl, data)
+		if err != nil {
+			return "", err
+		}
+		if err := git.NewGitService().AddRemote(repo.Path, remote, targetURL, false); err != nil {
+			return "", err
+		}
+		if mt.TargetCredentialID > 0 {
+			if repo.RemoteCredentials == nil {
+				repo.RemoteCredentials = map[string]uint{}
+			}
+			repo.RemoteCredentials[remote] = mt.TargetCredentialID
+			if err := db.NewRepoDAO().Save(repo); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	syncMode := mt.SyncMode
+	if syncMode != "all-branch" {
+		syncMode = "single"
+	}
+	task := po.SyncTask{
+		Key:           uuid.New().String(),
+		SourceRepoKey: repo.Key,
+		SourceRemote:  "origin",
+		SourceBranch:  mt.SourceBranch,
+		TargetRepoKey: repo.Key,
+		TargetRemote:  remote,
+		TargetBranch:  mt.TargetBranch,
+		PushOptions:   mt.PushOptions,
+		Cron:          mt.Cron,
+		Enabled:       mt.Enabled,
+		SyncMode:      syncMode,
+		GitTags:       mt.GitTags,
+		GitForce:      mt.GitForce,
+		GitPrune:      mt.GitPrune,
+	}
+	if err := db.NewSyncTaskDAO().Create(&task); err != nil {
+		return "", err
+	}
+	syncSvc.CronSvc.UpdateTask(task)
+	return task.Key, nil
+}
+
+// finishIfDone 没有仍在克隆的仓库时将导入任务标记为完成，已失败的任务保持失败
+func finishIfDone(importID uint) {
+	dao := db.NewRepoImportDAO()
+	pending, err := dao.CountItemsByStatus(importID, po.RepoImportItemCloning)
+	if err != nil || pending > 0 {
+		return
+	}
+	dao.FinishRunning(importID, po.RepoImportStatusCompleted, "")
+}
+
+func dataOf(cfg *po.ProviderConfig, r *provider.PlatformRepo) templateData {
+	platform := ""
+	if cfg != nil {
+		platform = cfg.Platform
+	}
+	return templateData{Platform: platform, Owner: r.Owner, Name: repoName(r), FullName: r.FullName}
+}
+
+func repoOf(repos []*provider.PlatformRepo, fullName string) *provider.PlatformRepo {
+	for _, r := range repos {
+		if r.FullName == fullName {
+			return r
+		}
+	}
+	return nil
+}
+
+func parseTemplate(field, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, fmt.Errorf("%s is required", field)
+	}
+	tmpl, err := template.New(field).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", field, err)
+	}
+	return tmpl, nil
+}
+
+func render(tmpl *template.Template, data templateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func truncate(s string) string {
+	return strutil.Truncate(s, 500)
+}
@@ -0,0 +1,209 @@
+// Package repotask 仓库后台任务：克隆、拉取、备份统一通过 git.GlobalTaskManager 提交执行
+package repotask
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/service"
+	"github.com/yi-nology/git-manage-service/biz/service/auth"
+	"github.com/yi-nology/git-manage-service/biz/service/git"
+	"github.com/yi-nology/git-manage-service/biz/service/stats"
+	"github.com/yi-nology/git-manage-service/biz/service/storage"
+)
+
+// CloneOptions 克隆参数
+type CloneOptions struct {
+	RemoteURL    string
+	LocalPath    string
+	AuthType     string
+	AuthKey      string
+	AuthSecret   string
+	SSHKeyID     uint // 数据库SSH密钥ID (deprecated)
+	CredentialID uint // 凭证 ID
+
+	// Name 注册的仓库名，为空时取 LocalPath 的目录名
+	Name string
+	// 平台关联，克隆成功后写入仓库记录
+	ProviderConfigID uint
+	PlatformRepoID   string
+	PlatformOwner    string
+	PlatformRepo     string
+	// OnFinish 任务执行结束时回调，成功时 repo 为注册后的仓库，失败时 repo 为 nil
+	OnFinish func(taskID string, repo *po.Repo, err error)
+}
+
+// SubmitClone 提交克隆任务，成功后自动注册仓库
+func SubmitClone(opts CloneOptions) (*git.Task, error) {
+	return git.GlobalTaskManager.Submit(po.AsyncTaskTypeClone, opts.RemoteURL, func(ctx context.Context, taskID string) error {
+		repo, err := cloneAndRegister(ctx, taskID, opts)
+		if opts.OnFinish != nil {
+			opts.OnFinish(taskID, repo, err)
+		}
+		return err
+	})
+}
+
+func cloneAndRegister(ctx context.Context, taskID string, opts CloneOptions) (*po.Repo, error) {
+	if err := clone(ctx, taskID, opts); err != nil {
+		return nil, err
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = filepath.Base(opts.LocalPath)
+	}
+	repo := po.Repo{
+		Key:                 uuid.New().String(),
+		Name:                name,
+		Path:                opts.LocalPath,
+		RemoteURL:           opts.RemoteURL,
+		AuthType:            opts.AuthType,
+		AuthKey:             opts.AuthKey,
+		AuthSecret:          opts.AuthSecret,
+		DefaultCredentialID: opts.CredentialID,
+		ProviderConfigID:    opts.ProviderConfigID,
+		PlatformRepoID:      opts.PlatformRepoID,
+		PlatformOwner:       opts.PlatformOwner,
+		PlatformRepo:        opts.PlatformRepo,
+	}
+	if err := db.NewRepoDAO().Create(&repo); err != nil {
+		return nil, fmt.Errorf("clone succeeded but failed to register repo: %w", err)
+	}
+	git.GlobalTaskManager.AppendLog(taskID, "Registered repo "+repo.Name)
+
+	go syncStats(repo)
+	return &repo, nil
+}
+
+// clone 执行克隆，进度写入任务日志
+func clone(ctx context.Context, taskID string, opts CloneOptions) error {
+	gitSvc := git.NewGitService()
+	authSvc := auth.NewAuthService()
+
+	progressChan := make(chan string)
+	drained := make(chan struct{})
+	go func() {
+		for msg := range progressChan {
+			git.GlobalTaskManager.AppendLog(taskID, msg)
+		}
+		close(drained)
+	}()
+	defer func() {
+		close(progressChan)
+		<-drained
+	}()
+
+	// 优先使用新凭证系统
+	if opts.CredentialID > 0 {
+		if authSvc.IsCredentialDBKey(opts.CredentialID) {
+			// 数据库 SSH 密钥凭证 — 使用原生 git 命令
+			privateKey, passphrase, err := authSvc.GetCredentialKeyContent(opts.CredentialID)
+			if err != nil {
+				return fmt.Errorf("failed to load credential key: %w", err)
+			}
+			git.GlobalTaskManager.AppendLog(taskID, "Using credential (DB SSH key) for clone...")
+			return gitSvc.CloneWithDBKeyContext(ctx, opts.RemoteURL, opts.LocalPath, privateKey, passphrase, progressChan)
+		}
+		// go-git 方式（HTTP 或本地 SSH 密钥凭证）
+		authMethod, err := authSvc.ResolveCredential(opts.CredentialID)
+		if err != nil {
+			git.GlobalTaskManager.AppendLog(taskID, "Warning: credential resolution failed: "+err.Error())
+		}
+		git.GlobalTaskManager.AppendLog(taskID, "Using credential for clone...")
+		return gitSvc.CloneWithAuthMethodContext(ctx, opts.RemoteURL, opts.LocalPath, authMethod, progressChan)
+	}
+
+	if opts.SSHKeyID > 0 {
+		// 兼容旧接口：数据库SSH密钥
+		privateKey, passphrase, err := authSvc.GetDBSSHKeyContent(opts.SSHKeyID)
+		if err != nil {
+			return fmt.Errorf("failed to load SSH key: %w", err)
+		}
+		git.GlobalTaskManager.AppendLog(taskID, "Using database SSH key for clone...")
+		return gitSvc.CloneWithDBKeyContext(ctx, opts.RemoteURL, opts.LocalPath, privateKey, passphrase, progressChan)
+	}
+
+	// 兼容旧接口：go-git 方式
+	authMethod, err := authSvc.ResolveAuthFromParams(opts.AuthType, opts.AuthKey, opts.AuthSecret, 0)
+	if err != nil {
+		git.GlobalTaskManager.AppendLog(taskID, "Warning: auth resolution failed: "+err.Error())
+	}
+	return gitSvc.CloneWithAuthMethodContext(ctx, opts.RemoteURL, opts.LocalPath, authMethod, progressChan)
+}
+
+// SubmitFetch 提交拉取任务
+func SubmitFetch(repo *po.Repo) (*git.Task, error) {
+	r := *repo
+	return git.GlobalTaskManager.Submit(po.AsyncTaskTypeFetch, r.Key, func(ctx context.Context, taskID string) error {
+		git.GlobalTaskManager.AppendLog(taskID, "Fetching all remotes of "+r.Path)
+		return FetchRepo(ctx, &r)
+	})
+}
+
+// FetchRepo 拉取仓库的所有远程，自动选择数据库 SSH 密钥或 go-git 认证
+func FetchRepo(ctx context.Context, repo *po.Repo) error {
+	gitSvc := git.NewGitService()
+	authSvc := auth.NewAuthService()
+
+	// 检查是否有任何远程使用数据库SSH密钥（新凭证系统 + 旧系统）
+	useDBKey := false
+	var dbPrivateKey, dbPassphrase string
+
+	// 优先检查新凭证系统
+	if repo.DefaultCredentialID > 0 && authSvc.IsCredentialDBKey(repo.DefaultCredentialID) {
+		pk, pp, err := authSvc.GetCredentialKeyContent(repo.DefaultCredentialID)
+		if err != nil {
+			return fmt.Errorf("failed to resolve credential key: %w", err)
+		}
+		dbPrivateKey = pk
+		dbPassphrase = pp
+		useDBKey = true
+	}
+
+	// 如果新系统没有配置，回退到旧系统
+	if !useDBKey && repo.RemoteAuths != nil {
+		for _, authInfo := range repo.RemoteAuths {
+			if authInfo.Type == "ssh" && authInfo.Source == "database" && authInfo.SSHKeyID > 0 {
+				pk, pp, err := authSvc.GetDBSSHKeyContent(authInfo.SSHKeyID)
+				if err != nil {
+					return fmt.Errorf("failed to resolve SSH key: %w", err)
+				}
+				dbPrivateKey = pk
+				dbPassphrase = pp
+				useDBKey = true
+				break
+			}
+		}
+	}
+
+	if useDBKey {
+		return gitSvc.FetchAllWithDBKeyContext(ctx, repo.Path, dbPrivateKey, dbPassphrase)
+	}
+	return gitSvc.FetchAllContext(ctx, repo.Path)
+}
+
+// SubmitBackup 提交备份任务，备份文件写入对象存储
+func SubmitBackup(repo *po.Repo) (*git.Task, error) {
+	repoID := repo.ID
+	return git.GlobalTaskManager.Submit(po.AsyncTaskTypeBackup, repo.Key, func(ctx context.Context, taskID string) error {
+		backupSvc := storage.NewRepoBackupService(service.Storage())
+		record, err := backupSvc.BackupRepo(ctx, repoID)
+		if err != nil {
+			return err
+		}
+		git.GlobalTaskManager.AppendLog(taskID, fmt.Sprintf("Backup stored at %s (%d bytes)", record.StorageKey, record.Size))
+		return nil
+	})
+}
+
+func syncStats(repo po.Repo) {
+	head, err := git.NewGitService().GetHeadBranch(repo.Path)
+	if err == nil && head != "" {
+		stats.StatsSvc.SyncRepoStats(repo.ID, repo.Path, head)
+	}
+}
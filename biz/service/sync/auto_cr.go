@@ -0,0 +1,178 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/service/crservice"
+	"github.com/yi-nology/git-manage-service/biz/service/provider"
+)
+
+const (
+	// changelogMaxCommits 描述中最多列出的提交数，超出部分只给出数量
+	changelogMaxCommits = 200
+	// changelogMaxLen Azure DevOps 描述上限为 4000 字符，统一按此截断
+	changelogMaxLen = 4000
+)
+
+// CheckAutoCRTarget 校验目标 remote 指向的平台仓库就是目标本地仓库绑定的平台仓库；
+// CR 通过目标仓库的绑定创建，两者不一致时会开到错误的仓库上
+func (s *SyncService) CheckAutoCRTarget(task *po.SyncTask) error {
+	repoDAO := db.NewRepoDAO()
+	source, err := repoDAO.FindByKey(task.SourceRepoKey)
+	if err != nil {
+		return fmt.Errorf("source repo not found: %w", err)
+	}
+	target, err := repoDAO.FindByKey(task.TargetRepoKey)
+	if err != nil {
+		return fmt.Errorf("target repo not found: %w", err)
+	}
+	if target.ProviderConfigID == 0 || target.PlatformOwner == "" || target.PlatformRepo == "" {
+		return fmt.Errorf("auto_cr requires target repo %s to be linked to a provider repo", target.Key)
+	}
+	cfg, err := db.NewProviderConfigDAO().FindByID(target.ProviderConfigID)
+	if err != nil {
+		return fmt.Errorf("provider config not found: %w", err)
+	}
+
+	targetRemote := task.TargetRemote
+	if targetRemote == "" {
+		targetRemote = "origin"
+	}
+	targetURL, _ := s.git.GetRemoteURL(source.Path, targetRemote)
+	if targetURL == "" && targetRemote == "origin" {
+		targetURL = target.RemoteURL
+	}
+	if targetURL == "" {
+		return fmt.Errorf("auto_cr: cannot resolve URL of target remote %s", targetRemote)
+	}
+	detected, err := provider.DetectPlatform(targetURL)
+	if err != nil {
+		return fmt.Errorf("auto_cr: %w", err)
+	}
+	linked := target.PlatformOwner + "/" + target.PlatformRepo
+	if !detected.SameRepo(cfg, linked) {
+		return fmt.Errorf("auto_cr: target remote %s (%s) is not the platform repo %s linked to %s", targetRemote, targetURL, linked, target.Key)
+	}
+	return nil
+}
+
+// upsertSyncCR 推送到暂存分支成功后，创建或更新 task.TargetBranch -> task.CRTargetBranch 的 CR，
+// 描述为暂存分支相对合入分支的提交变更日志，并记录本次 SyncRun
+func (s *SyncService) upsertSyncCR(ctx context.Context, task *po.SyncTask, run *po.SyncRun, logf func(string, ...interface{})) error {
+	headHash := run.CommitRange
+	if i := strings.Index(headHash, ".."); i >= 0 {
+		headHash = headHash[i+2:]
+	}
+
+	if err := s.CheckAutoCRTarget(task); err != nil {
+		return err
+	}
+	path := task.SourceRepo.Path
+	changelog, err := s.syncChangelog(path, task, run, headHash, logf)
+	if err != nil {
+		return err
+	}
+	title := task.CRTitle
+	if title == "" {
+		title = fmt.Sprintf("Sync %s into %s", task.TargetBranch, task.CRTargetBranch)
+	}
+
+	existing, err := crservice.FindOpenCR(ctx, task.TargetRepoKey, task.TargetBranch, task.CRTargetBranch)
+	if err != nil {
+		return fmt.Errorf("find open CR: %w", err)
+	}
+	var cr *api.CRDTO
+	if existing != nil {
+		logf("Updating CR #%d (%s -> %s)", existing.CRNumber, task.TargetBranch, task.CRTargetBranch)
+		cr, err = crservice.UpdateCR(ctx, task.TargetRepoKey, existing.CRNumber, title, changelog)
+	} else {
+		logf("Creating CR %s -> %s", task.TargetBranch, task.CRTargetBranch)
+		cr, err = crservice.CreateCR(ctx, &api.CreateCRReq{
+			RepoKey: task.TargetRepoKey, Title: title, Description: changelog,
+			SourceBranch: task.TargetBranch, TargetBranch: task.CRTargetBranch,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	run.CRNumber = cr.CRNumber
+	run.CRWebURL = cr.WebURL
+	if repo, err := db.NewRepoDAO().FindByKey(task.TargetRepoKey); err == nil {
+		crDAO := db.NewChangeRequestDAO()
+		if localCR, err := crDAO.FindByRepoAndNumber(repo.ID, cr.CRNumber); err == nil {
+			localCR.SyncRunID = run.ID
+			crDAO.Save(localCR)
+		}
+	}
+	logf("CR #%d: %s", cr.CRNumber, cr.WebURL)
+	return nil
+}
+
+// syncChangelog 生成 CR 描述；能取到合入分支时列出合入分支..暂存分支的全部提交，
+// 否则只列出本次同步的提交范围
+func (s *SyncService) syncChangelog(path string, task *po.SyncTask, run *po.SyncRun, headHash string, logf func(string, ...interface{})) (string, error) {
+	targetRemote := task.TargetRemote
+	if targetRemote == "" {
+		targetRemote = "origin"
+	}
+	targetURL, _ := s.git.GetRemoteURL(path, targetRemote)
+	if targetURL == "" && targetRemote == "origin" {
+		targetURL = task.TargetRepo.RemoteURL
+	}
+
+	logRange := run.CommitRange
+	refSpec := fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", task.CRTargetBranch, targetRemote, task.CRTargetBranch)
+	if err := s.fetchRemote(path, task.TargetRepo, targetRemote, targetURL, refSpec, &logWriter{logf: logf}, logf); err != nil {
+		logf("Warning: failed to fetch %s/%s, changelog limited to this run: %v", targetRemote, task.CRTargetBranch, err)
+	} else if baseHash, err := s.git.GetCommitHash(path, targetRemote, task.CRTargetBranch); err == nil {
+		logRange = baseHash + ".." + headHash
+	}
+
+	out, err := s.git.RunCommand(path, "log", "--no-merges", "--pretty=format:%h%x09%an%x09%s", logRange)
+	if err != nil {
+		return "", fmt.Errorf("git log %s: %w", logRange, err)
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	var b strings.Builder
+	sourceRemote := task.SourceRemote
+	if sourceRemote == "" {
+		sourceRemote = "origin"
+	}
+	fmt.Fprintf(&b, "Automated sync of `%s/%s` into `%s`.\n\n", sourceRemote, task.SourceBranch, task.TargetBranch)
+	fmt.Fprintf(&b, "- Sync task: `%s`\n", task.Key)
+	fmt.Fprintf(&b, "- Sync run: #%d (%s, %s)\n", run.ID, run.TriggerSource, run.StartTime.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "- Head: `%s`\n\n", headHash)
+	fmt.Fprintf(&b, "### Changelog (%d commits)\n\n", len(lines))
+
+	footer := ""
+	for i, line := range lines {
+		if i == changelogMaxCommits {
+			footer = fmt.Sprintf("- ... and %d more\n", len(lines)-i)
+			break
+		}
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		entry := fmt.Sprintf("- %s %s (%s)\n", parts[0], parts[2], parts[1])
+		if b.Len()+len(entry) > changelogMaxLen-64 {
+			footer = fmt.Sprintf("- ... and %d more\n", len(lines)-i)
+			break
+		}
+		b.WriteString(entry)
+	}
+	b.WriteString(footer)
+	return b.String(), nil
+}
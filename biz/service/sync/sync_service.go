@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"strings"
 	"time"
 
@@ -62,6 +63,12 @@ func (s *SyncService) ExecuteSync(task *po.SyncTask) error {
 }
 
 func (s *SyncService) ExecuteSyncWithTrigger(task *po.SyncTask, triggerSource string) error {
+	_, err := s.ExecuteSyncRun(task, triggerSource)
+	return err
+}
+
+// ExecuteSyncRun 执行同步并返回本次创建的 SyncRun；未能开始执行（如获取锁失败）时 run 为 nil
+func (s *SyncService) ExecuteSyncRun(task *po.SyncTask, triggerSource string) (*po.SyncRun, error) {
 	ctx := context.Background()
 	var err error
 
@@ -69,7 +76,7 @@ func (s *SyncService) ExecuteSyncWithTrigger(task *po.SyncTask, triggerSource st
 	if s.lockSvc != nil {
 		lockKey := fmt.Sprintf("sync:task:%s", task.Key)
 		if err := s.lockSvc.UpWait(ctx, lockKey, 5*time.Minute, 30*time.Second); err != nil {
-			return fmt.Errorf("failed to acquire lock for task %s: %w", task.Key, err)
+			return nil, fmt.Errorf("failed to acquire lock for task %s: %w", task.Key, err)
 		}
 		defer func() {
 			if err := s.lockSvc.Down(ctx, lockKey); err != nil {
@@ -127,6 +134,12 @@ func (s *SyncService) ExecuteSyncWithTrigger(task *po.SyncTask, triggerSource st
 	} else {
 		run.Status = "success"
 		logf("Sync completed successfully")
+		// CR 创建失败不影响同步结果，只在日志中记录
+		if task.AutoCR && syncMode == "single" && commitRange != "" {
+			if crErr := s.upsertSyncCR(ctx, task, &run, logf); crErr != nil {
+				logf("Auto CR failed: %v", crErr)
+			}
+		}
 	}
 	// Save final details
 	run.Details = logs.String()
@@ -138,7 +151,7 @@ func (s *SyncService) ExecuteSyncWithTrigger(task *po.SyncTask, triggerSource st
 	// 发送通知
 	s.sendNotification(task, &run)
 
-	return err
+	return &run, err
 }
 
 // getAuthInfoForRemote 获取指定远程的认证信息（旧系统）
@@ -351,7 +364,7 @@ func (s *SyncService) doSyncSingleBranch(path string, task *po.SyncTask, logf fu
 			if isSourceBehind {
 				return "", fmt.Errorf("source is behind target")
 			}
-			return "", fmt.Errorf("conflict")
+			return commitRange, fmt.Errorf("conflict")
 		}
 		logf("Fast-forward check passed.")
 	}
@@ -671,62 +684,23 @@ func (w *logWriter) Write(p []byte) (n int, err error) {
 
 // sendNotification 根据同步结果发送通知
 func (s *SyncService) sendNotification(task *po.SyncTask, run *po.SyncRun) {
-	var triggerEvent string
-	var status string
-
-	switch run.Status {
-	case "success":
-		triggerEvent = po.TriggerSyncSuccess
-		status = "success"
-	case "failed":
-		triggerEvent = po.TriggerSyncFailure
-		status = "failure"
-	case "conflict":
-		triggerEvent = po.TriggerSyncConflict
-		status = "failure"
-	default:
-		return
-	}
-
-	// 计算耗时
-	duration := ""
-	if !run.EndTime.IsZero() && !run.StartTime.IsZero() {
-		d := run.EndTime.Sub(run.StartTime)
-		duration = d.Round(time.Millisecond).String()
-	}
-
-	data := &notificationSvc.TemplateData{
-		TaskKey:      task.Key,
-		TaskName:     task.Key,
-		Status:       run.Status,
-		EventType:    triggerEvent,
-		SourceRemote: task.SourceRemote,
-		SourceBranch: task.SourceBranch,
-		TargetRemote: task.TargetRemote,
-		TargetBranch: task.TargetBranch,
-		RepoKey:      task.SourceRepoKey,
-		RepoName:     task.SourceRepo.Name,
-		ErrorMessage: run.ErrorMessage,
-		CommitRange:  run.CommitRange,
-		Duration:     duration,
-		SyncMode:     task.SyncMode,
-	}
-	if task.Cron != "" {
-		data.CronExpression = task.Cron
-	}
-
-	// 使用模板渲染的默认标题和内容作为 fallback
-	title, content := notificationSvc.RenderTitleAndContent("", "", data)
-
-	notificationSvc.NotifySvc.Send(&notificationSvc.NotificationMessage{
-		Title:        title,
-		Content:      content,
-		Status:       status,
-		TriggerEvent: triggerEvent,
-		TaskKey:      task.Key,
-		RepoKey:      task.SourceRepoKey,
-		Data:         data,
-	})
+	var authors []string
+	if run.Status == "conflict" {
+		authors = s.conflictAuthors(task, run.CommitRange)
+	}
+	if msg := notificationSvc.SyncRunMessage(task, run, authors); msg != nil {
+		notificationSvc.NotifySvc.Send(msg)
+	}
+}
+
+// conflictAuthors 获取冲突双方分叉提交的作者邮箱，供通知 @ 提及
+func (s *SyncService) conflictAuthors(task *po.SyncTask, commitRange string) []string {
+	authors, err := s.git.GetRangeDivergentAuthors(task.SourceRepo.Path, commitRange, notificationSvc.ConflictAuthorLimit)
+	if err != nil {
+		log.Printf("[Sync] Failed to collect conflict authors for task %s: %v", task.Key, err)
+		return nil
+	}
+	return authors
 }
 
 // GetSyncRecommendations 获取同步建议
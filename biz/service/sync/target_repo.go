@@ -0,0 +1,94 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/service/provider"
+)
+
+// ProvisionTargetRepo 在 provider 平台上新建空仓库，注册为本地仓库 repoKey 的 remote 并记录其凭证；
+// 本地仓库尚未绑定平台仓库时，同时绑定 ProviderConfigID / PlatformOwner / PlatformRepo。
+// defaultRemote 为 req.RemoteName 为空时使用的 remote 名。
+func (s *SyncService) ProvisionTargetRepo(ctx context.Context, repoKey, defaultRemote string, req *api.CreateTargetRepoReq) (*api.TargetRepoDTO, error) {
+	if req.ProviderConfigID == 0 || req.Name == "" {
+		return nil, fmt.Errorf("provider_config_id and name are required")
+	}
+	repoDAO := db.NewRepoDAO()
+	repo, err := repoDAO.FindByKey(repoKey)
+	if err != nil {
+		return nil, fmt.Errorf("target repo not found: %w", err)
+	}
+	cfg, err := db.NewProviderConfigDAO().FindByID(req.ProviderConfigID)
+	if err != nil {
+		return nil, fmt.Errorf("provider config not found: %w", err)
+	}
+
+	remoteName := req.RemoteName
+	if remoteName == "" {
+		remoteName = defaultRemote
+	}
+	if remoteName == "" {
+		remoteName = cfg.Platform
+	}
+	// 先检查 remote 是否冲突，避免平台上留下无人使用的空仓库
+	remotes, err := s.git.GetRemotes(repo.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remotes: %w", err)
+	}
+	for _, r := range remotes {
+		if r == remoteName {
+			return nil, fmt.Errorf("remote %s already exists in repo %s", remoteName, repo.Key)
+		}
+	}
+
+	p, err := provider.GetManager().GetProvider(cfg.ID)
+	if err != nil {
+		return nil, err
+	}
+	created, err := p.CreateRepo(ctx, provider.CreateRepoOptions{
+		Owner: req.Owner, Name: req.Name, Description: req.Description,
+		Visibility: req.Visibility, DefaultBranch: req.DefaultBranch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repo on %s: %w", cfg.Platform, err)
+	}
+
+	useSSH := false
+	if cfg.CredentialID > 0 {
+		if cred, err := db.NewCredentialDAO().FindByID(cfg.CredentialID); err == nil {
+			useSSH = cred.Type == "ssh_key"
+		}
+	}
+	remoteURL := created.RemoteURL(useSSH)
+	if err := s.git.AddRemote(repo.Path, remoteName, remoteURL, false); err != nil {
+		return nil, fmt.Errorf("repo %s created but failed to add remote: %w", created.FullName, err)
+	}
+
+	result := &api.TargetRepoDTO{
+		ProviderConfigID: cfg.ID, Platform: cfg.Platform, FullName: created.FullName,
+		RemoteName: remoteName, RemoteURL: remoteURL,
+	}
+	if cfg.CredentialID > 0 {
+		if repo.RemoteCredentials == nil {
+			repo.RemoteCredentials = map[string]uint{}
+		}
+		repo.RemoteCredentials[remoteName] = cfg.CredentialID
+	}
+	if repo.ProviderConfigID == 0 {
+		repo.ProviderConfigID = cfg.ID
+		repo.PlatformOwner = created.Owner
+		repo.PlatformRepo = strings.TrimPrefix(created.FullName, created.Owner+"/")
+		if created.ID > 0 {
+			repo.PlatformRepoID = fmt.Sprint(created.ID)
+		}
+		result.Linked = true
+	}
+	if err := repoDAO.Save(repo); err != nil {
+		return nil, fmt.Errorf("repo %s created but failed to update local repo: %w", created.FullName, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,299 @@
+package webhookevent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/service/crservice"
+	"github.com/yi-nology/git-manage-service/biz/service/git"
+	"github.com/yi-nology/git-manage-service/biz/service/lint"
+	notificationSvc "github.com/yi-nology/git-manage-service/biz/service/notification"
+	"github.com/yi-nology/git-manage-service/biz/service/stats"
+	"github.com/yi-nology/git-manage-service/biz/service/sync"
+	"github.com/yi-nology/git-manage-service/pkg/strutil"
+)
+
+// actionFunc 规则动作执行函数，返回执行结果摘要
+type actionFunc func(ctx context.Context, rule *po.WebhookRule, event *po.WebhookEvent, repo *po.Repo) (string, error)
+
+var actionHandlers = map[string]actionFunc{
+	po.WebhookActionSync:     runSyncAction,
+	po.WebhookActionNotify:   runNotifyAction,
+	po.WebhookActionCreateCR: runCreateCRAction,
+	po.WebhookActionLint:     runLintAction,
+	po.WebhookActionStats:    runStatsAction,
+}
+
+// executeAction 执行规则动作并记录到 webhook_action_executions
+func executeAction(ctx context.Context, rule *po.WebhookRule, event *po.WebhookEvent, repo *po.Repo) *po.WebhookActionExecution {
+	execDAO := db.NewWebhookActionExecutionDAO()
+	exec := &po.WebhookActionExecution{
+		WebhookEventID: event.ID,
+		RuleID:         rule.ID,
+		RuleName:       rule.Name,
+		Action:         rule.Action,
+		Status:         po.ActionExecStatusRunning,
+		StartedAt:      time.Now(),
+	}
+	_ = execDAO.Create(exec)
+
+	var result string
+	var err error
+	if handler, ok := actionHandlers[rule.Action]; ok {
+		result, err = invokeAction(handler, ctx, rule, event, repo)
+	} else {
+		err = fmt.Errorf("unknown action: %s", rule.Action)
+	}
+
+	now := time.Now()
+	exec.FinishedAt = &now
+	exec.Result = result
+	if err != nil {
+		exec.Status = po.ActionExecStatusFailed
+		exec.ErrorMessage = strutil.Truncate(err.Error(), 500)
+	} else {
+		exec.Status = po.ActionExecStatusSuccess
+	}
+	_ = execDAO.Save(exec)
+	return exec
+}
+
+// invokeAction 调用动作并捕获 panic，避免单个规则影响整个事件处理
+func invokeAction(handler actionFunc, ctx context.Context, rule *po.WebhookRule, event *po.WebhookEvent, repo *po.Repo) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("action panic: %v", r)
+		}
+	}()
+	return handler(ctx, rule, event, repo)
+}
+
+// runSyncAction 执行 action_config.sync_task_key 指定的同步任务
+func runSyncAction(ctx context.Context, rule *po.WebhookRule, event *po.WebhookEvent, repo *po.Repo) (string, error) {
+	taskKey := configString(rule.ActionConfig, "sync_task_key")
+	if taskKey == "" {
+		return "", fmt.Errorf("sync_task_key is required")
+	}
+	task, err := db.NewSyncTaskDAO().FindByKey(taskKey)
+	if err != nil {
+		return "", fmt.Errorf("sync task %s not found", taskKey)
+	}
+
+	run, syncErr := sync.NewSyncService().ExecuteSyncRun(task, po.TriggerSourceWebhook)
+
+	result := "sync task " + taskKey
+	if run != nil && run.ID > 0 {
+		result = fmt.Sprintf("sync task %s, run #%d: %s", taskKey, run.ID, run.Status)
+	}
+	return result, syncErr
+}
+
+// runNotifyAction 通过通知服务发送消息；配置了 title_template/content_template 时使用规则模板，否则使用渠道模板
+func runNotifyAction(ctx context.Context, rule *po.WebhookRule, event *po.WebhookEvent, repo *po.Repo) (string, error) {
+	data := eventTemplateData(event, repo)
+	triggerEvent := configString(rule.ActionConfig, "trigger_event")
+	if triggerEvent == "" {
+		triggerEvent = po.TriggerWebhookReceived
+	}
+	data.EventType = triggerEvent
+
+	titleTmpl := configString(rule.ActionConfig, "title_template")
+	contentTmpl := configString(rule.ActionConfig, "content_template")
+	for _, tmpl := range []string{titleTmpl, contentTmpl} {
+		if err := notificationSvc.ValidateTemplate(tmpl); err != nil {
+			return "", err
+		}
+	}
+	title, content := notificationSvc.RenderTitleAndContent(titleTmpl, contentTmpl, data)
+
+	msg := &notificationSvc.NotificationMessage{
+		Title:        title,
+		Content:      content,
+		Status:       data.Status,
+		TriggerEvent: triggerEvent,
+		RepoKey:      data.RepoKey,
+
+		TriggerSource: po.TriggerSourceWebhook,
+	}
+	// 未配置规则模板时交由渠道模板渲染
+	if titleTmpl == "" && contentTmpl == "" {
+		msg.Data = data
+	}
+	notificationSvc.NotifySvc.Send(msg)
+	return "notification dispatched: " + title, nil
+}
+
+// runCreateCRAction 为事件所在仓库创建 CR，标题与描述支持通知模板变量
+func runCreateCRAction(ctx context.Context, rule *po.WebhookRule, event *po.WebhookEvent, repo *po.Repo) (string, error) {
+	cfg := rule.ActionConfig
+	repoKey := configString(cfg, "repo_key")
+	if repoKey == "" && repo != nil {
+		repoKey = repo.Key
+	}
+	if repoKey == "" {
+		return "", fmt.Errorf("repo_key is required when the event has no linked repo")
+	}
+
+	data := eventTemplateData(event, repo)
+	sourceBranch := configString(cfg, "source_branch")
+	if sourceBranch == "" {
+		sourceBranch = data.SourceBranch
+	}
+	targetBranch := configString(cfg, "target_branch")
+	if sourceBranch == "" || targetBranch == "" {
+		return "", fmt.Errorf("source_branch and target_branch are required")
+	}
+	data.TargetBranch = targetBranch
+
+	titleTmpl := configString(cfg, "title")
+	if titleTmpl == "" {
+		titleTmpl = "Merge {{.SourceBranch}} into {{.TargetBranch}}"
+	}
+	title, err := notificationSvc.RenderTemplate(titleTmpl, data)
+	if err != nil {
+		return "", err
+	}
+	description, err := notificationSvc.RenderTemplate(configString(cfg, "description"), data)
+	if err != nil {
+		return "", err
+	}
+
+	cr, err := crservice.CreateCR(ctx, &api.CreateCRReq{
+		RepoKey:            repoKey,
+		Title:              title,
+		Description:        description,
+		SourceBranch:       sourceBranch,
+		TargetBranch:       targetBranch,
+		Labels:             configStrings(cfg, "labels"),
+		RemoveSourceBranch: configBool(cfg, "remove_source_branch"),
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("created CR #%d %s", cr.CRNumber, cr.WebURL), nil
+}
+
+// runLintAction 对仓库中 action_config.file_path 指定的文件执行 lint，存在 error 级问题时视为失败
+func runLintAction(ctx context.Context, rule *po.WebhookRule, event *po.WebhookEvent, repo *po.Repo) (string, error) {
+	if repo == nil {
+		return "", fmt.Errorf("event has no linked repo")
+	}
+	filePath := configString(rule.ActionConfig, "file_path")
+	if filePath == "" {
+		return "", fmt.Errorf("file_path is required")
+	}
+	fullPath := filepath.Join(repo.Path, filepath.Clean("/"+filePath))
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	result, err := lint.NewLintService().Lint(string(content), configStrings(rule.ActionConfig, "rule_ids"))
+	if err != nil {
+		return "", err
+	}
+	summary, _ := json.Marshal(result.Stats)
+	if result.Stats.ErrorCount > 0 {
+		return string(summary), fmt.Errorf("lint found %d errors in %s", result.Stats.ErrorCount, filePath)
+	}
+	return string(summary), nil
+}
+
+// runStatsAction 刷新仓库统计，分支优先取 action_config.branch，其次事件分支，最后 HEAD
+func runStatsAction(ctx context.Context, rule *po.WebhookRule, event *po.WebhookEvent, repo *po.Repo) (string, error) {
+	if repo == nil {
+		return "", fmt.Errorf("event has no linked repo")
+	}
+	if stats.StatsSvc == nil {
+		return "", fmt.Errorf("stats service not initialized")
+	}
+	branch := configString(rule.ActionConfig, "branch")
+	if branch == "" {
+		branch = payloadString(event, "branch")
+	}
+	if branch == "" {
+		head, err := git.NewGitService().GetHeadBranch(repo.Path)
+		if err != nil {
+			return "", err
+		}
+		branch = head
+	}
+	stats.StatsSvc.SyncRepoStats(repo.ID, repo.Path, branch)
+	return "stats synced for branch " + branch, nil
+}
+
+// eventTemplateData 将 Webhook 事件转换为通知模板数据
+func eventTemplateData(event *po.WebhookEvent, repo *po.Repo) *notificationSvc.TemplateData {
+	data := &notificationSvc.TemplateData{
+		Status:        "success",
+		EventType:     po.TriggerWebhookReceived,
+		WebhookSource: event.Source,
+		SourceBranch:  payloadString(event, "branch"),
+		Timestamp:     event.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if repo != nil {
+		data.RepoKey = repo.Key
+		data.RepoName = repo.Name
+		data.TaskName = repo.Name
+	}
+	if data.TaskName == "" {
+		data.TaskName = event.EventType
+	}
+	return data
+}
+
+func payloadString(event *po.WebhookEvent, key string) string {
+	if event.Payload == nil {
+		return ""
+	}
+	v, _ := event.Payload[key].(string)
+	return v
+}
+
+func configString(cfg map[string]interface{}, key string) string {
+	if cfg == nil {
+		return ""
+	}
+	v, _ := cfg[key].(string)
+	return strings.TrimSpace(v)
+}
+
+func configBool(cfg map[string]interface{}, key string) bool {
+	if cfg == nil {
+		return false
+	}
+	v, _ := cfg[key].(bool)
+	return v
+}
+
+func configStrings(cfg map[string]interface{}, key string) []string {
+	if cfg == nil {
+		return nil
+	}
+	switch v := cfg[key].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	case string:
+		if v == "" {
+			return nil
+		}
+		return strings.Split(v, ",")
+	}
+	return nil
+}
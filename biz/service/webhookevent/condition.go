@@ -0,0 +1,118 @@
+package webhookevent
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/service/provider"
+	"github.com/yi-nology/git-manage-service/pkg/expr"
+)
+
+// EventEnv 构造条件表达式的求值环境。
+// 事件字段同时以 event.<field> 和顶层 <field> 暴露，原始报文以 payload.<path> 访问。
+func EventEnv(ne *provider.NormalizedEvent) map[string]interface{} {
+	fields := map[string]interface{}{
+		"id":     ne.ID,
+		"type":   ne.Type,
+		"source": string(ne.Source),
+		"branch": ne.Branch,
+		"tag":    ne.Tag,
+	}
+	if ne.Actor != nil {
+		fields["actor"] = ne.Actor.Username
+		fields["actor_name"] = ne.Actor.Name
+	}
+	if ne.Repo != nil {
+		fields["repo"] = ne.Repo.FullName
+		fields["repo_owner"] = ne.Repo.Owner
+		fields["repo_name"] = ne.Repo.Name
+	}
+	labels := []interface{}{}
+	if ne.CR != nil {
+		fields["cr_number"] = float64(ne.CR.Number)
+		fields["cr_title"] = ne.CR.Title
+		fields["cr_state"] = string(ne.CR.State)
+		fields["source_branch"] = ne.CR.SourceBranch
+		fields["target_branch"] = ne.CR.TargetBranch
+		if ne.CR.Author != nil {
+			fields["cr_author"] = ne.CR.Author.Username
+		}
+		for _, l := range ne.CR.Labels {
+			labels = append(labels, l)
+		}
+	}
+	fields["labels"] = labels
+
+	env := make(map[string]interface{}, len(fields)+2)
+	for k, v := range fields {
+		env[k] = v
+	}
+	env["event"] = fields
+
+	var payload interface{}
+	if len(ne.RawPayload) > 0 {
+		_ = json.Unmarshal(ne.RawPayload, &payload)
+	}
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	env["payload"] = payload
+	return env
+}
+
+// ValidateCondition 校验条件表达式语法
+func ValidateCondition(condition string) error {
+	if condition == "" {
+		return nil
+	}
+	if err := expr.Validate(condition); err != nil {
+		return fmt.Errorf("invalid condition: %w", err)
+	}
+	return nil
+}
+
+// evalCondition 对事件求值规则条件，空条件视为匹配
+func evalCondition(rule *po.WebhookRule, env map[string]interface{}) (bool, error) {
+	return expr.Eval(rule.Condition, env)
+}
+
+// normalizedFromStored 根据已存储的事件记录还原 NormalizedEvent
+func normalizedFromStored(e *po.WebhookEvent) *provider.NormalizedEvent {
+	ne := &provider.NormalizedEvent{
+		ID:        e.EventID,
+		Type:      e.EventType,
+		Source:    provider.Platform(e.Source),
+		Timestamp: e.CreatedAt,
+		Branch:    payloadString(e, "branch"),
+		Tag:       payloadString(e, "tag"),
+	}
+	if e.ActorUsername != "" || e.ActorName != "" {
+		ne.Actor = &provider.CRUser{Username: e.ActorUsername, Name: e.ActorName}
+	}
+	if fullName := payloadString(e, "repo"); fullName != "" {
+		ne.Repo = &provider.EventRepo{
+			FullName: fullName,
+			Owner:    payloadString(e, "repo_owner"),
+			Name:     payloadString(e, "repo_name"),
+		}
+	}
+	if e.PlatformCRNumber > 0 {
+		ne.CR = &provider.ChangeRequest{
+			Number:       e.PlatformCRNumber,
+			Title:        payloadString(e, "cr_title"),
+			State:        provider.CRState(payloadString(e, "cr_state")),
+			SourceBranch: payloadString(e, "source_branch"),
+			TargetBranch: payloadString(e, "target_branch"),
+			Labels:       payloadStrings(e, "labels"),
+		}
+	}
+	return ne
+}
+
+func payloadStrings(e *po.WebhookEvent, key string) []string {
+	if e.Payload == nil {
+		return nil
+	}
+	return configStrings(e.Payload, key)
+}
@@ -0,0 +1,261 @@
+package webhookevent
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/service/provider"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+)
+
+const redacted = "[REDACTED]"
+
+// 名称包含以下片段的请求头 / JSON 字段会被脱敏
+var sensitiveKeyParts = []string{
+	"secret", "token", "password", "passwd", "signature", "authorization",
+	"cookie", "private_key", "privatekey", "api_key", "apikey", "credential",
+}
+
+// URL 中的 user:password@ 认证信息
+var urlCredentialRe = regexp.MustCompile(`://[^/@\s:]+:[^/@\s]+@`)
+
+func isSensitiveKey(key string) bool {
+	k := strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(k, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeaders 复制请求头并脱敏敏感字段
+func redactHeaders(h http.Header) map[string][]string {
+	out := make(map[string][]string, len(h))
+	for k, vals := range h {
+		if isSensitiveKey(k) {
+			out[k] = []string{redacted}
+			continue
+		}
+		out[k] = append([]string{}, vals...)
+	}
+	return out
+}
+
+// redactBody 对 JSON 请求体逐字段脱敏；非 JSON 内容仅清理 URL 中的认证信息
+func redactBody(body []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return urlCredentialRe.ReplaceAll(body, []byte("://"+redacted+"@"))
+	}
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return body
+	}
+	return b
+}
+
+func redactValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, val := range x {
+			if _, isObj := val.(map[string]interface{}); !isObj && isSensitiveKey(k) && val != nil {
+				x[k] = redacted
+				continue
+			}
+			x[k] = redactValue(val)
+		}
+		return x
+	case []interface{}:
+		for i := range x {
+			x[i] = redactValue(x[i])
+		}
+		return x
+	case string:
+		return urlCredentialRe.ReplaceAllString(x, "://"+redacted+"@")
+	}
+	return v
+}
+
+// storePayload 保存脱敏、压缩后的原始请求
+func storePayload(eventID uint, headers http.Header, body []byte) {
+	if configs.GlobalConfig.Webhook.PayloadRetentionDays <= 0 || (len(body) == 0 && len(headers) == 0) {
+		return
+	}
+	clean := redactBody(body)
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(clean); err != nil {
+		log.Printf("[WARN] Failed to compress webhook payload: %v", err)
+		return
+	}
+	if err := zw.Close(); err != nil {
+		log.Printf("[WARN] Failed to compress webhook payload: %v", err)
+		return
+	}
+	payload := &po.WebhookEventPayload{
+		WebhookEventID: eventID,
+		Headers:        redactHeaders(headers),
+		Body:           buf.Bytes(),
+		Encoding:       "gzip",
+		Size:           len(clean),
+	}
+	if err := db.NewWebhookEventPayloadDAO().Create(payload); err != nil {
+		log.Printf("[WARN] Failed to store webhook payload: %v", err)
+	}
+}
+
+// loadPayload 读取并解压已存储的原始请求
+func loadPayload(eventID uint) (http.Header, []byte, error) {
+	payload, err := db.NewWebhookEventPayloadDAO().FindByEventID(eventID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("raw payload not stored or expired")
+	}
+	body := payload.Body
+	if payload.Encoding == "gzip" {
+		zr, err := gzip.NewReader(bytes.NewReader(payload.Body))
+		if err != nil {
+			return nil, nil, err
+		}
+		defer zr.Close()
+		if body, err = io.ReadAll(zr); err != nil {
+			return nil, nil, err
+		}
+	}
+	return http.Header(payload.Headers), body, nil
+}
+
+// GetPayload 返回事件的原始请求头与请求体（已脱敏）
+func GetPayload(eventID uint) (*api.WebhookEventPayloadDTO, error) {
+	headers, body, err := loadPayload(eventID)
+	if err != nil {
+		return nil, err
+	}
+	dto := &api.WebhookEventPayloadDTO{EventID: eventID, Headers: headers}
+	if json.Valid(body) {
+		dto.Body = json.RawMessage(body)
+	} else {
+		dto.BodyText = string(body)
+	}
+	return dto, nil
+}
+
+// reparse 使用事件所属 provider 重新解析已存储的请求；签名已脱敏，因此跳过校验
+func reparse(event *po.WebhookEvent) (*provider.NormalizedEvent, error) {
+	headers, body, err := loadPayload(event.ID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := provider.GetManager().GetProvider(event.ProviderConfigID)
+	if err != nil {
+		return nil, fmt.Errorf("provider config %d unavailable: %w", event.ProviderConfigID, err)
+	}
+	req, err := http.NewRequest(http.MethodPost, "/api/webhooks/receive", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, vals := range headers {
+		for _, v := range vals {
+			req.Header.Add(k, v)
+		}
+	}
+	ne, err := p.ParseWebhookEvent(provider.SkipSignatureCheck(req), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse stored payload: %w", err)
+	}
+	if len(ne.RawPayload) == 0 && json.Valid(body) {
+		ne.RawPayload = body
+	}
+	return ne, nil
+}
+
+// RuleMatch 重放时单条规则的匹配结果
+type RuleMatch struct {
+	RuleID   uint   `json:"rule_id"`
+	RuleName string `json:"rule_name"`
+	Action   string `json:"action"`
+	*api.WebhookRuleTestResult
+}
+
+// ReplayResult 重放结果：重新解析得到的事件与各规则匹配情况
+type ReplayResult struct {
+	Event    *provider.NormalizedEvent `json:"event"`
+	Rules    []RuleMatch               `json:"rules"`
+	Executed bool                      `json:"executed"`
+}
+
+// Replay 将已存储的事件重新走一遍解析与规则匹配；execute 为 true 时执行匹配规则
+func Replay(eventID uint, execute bool) (*ReplayResult, error) {
+	event, err := db.NewWebhookEventDAO().FindByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found: %w", err)
+	}
+	ne, err := reparse(event)
+	if err != nil {
+		return nil, err
+	}
+	env := EventEnv(ne)
+
+	rules, err := db.NewWebhookRuleDAO().FindByProviderConfigID(event.ProviderConfigID)
+	if err != nil {
+		return nil, err
+	}
+	var repo *po.Repo
+	if event.RepoID > 0 {
+		if r, err := db.NewRepoDAO().FindByID(event.RepoID); err == nil {
+			repo = r
+		}
+	}
+	// 以重新解析得到的类型匹配，便于验证解析逻辑变更
+	probe := *event
+	probe.EventType = ne.Type
+
+	result := &ReplayResult{Event: ne, Rules: make([]RuleMatch, 0, len(rules))}
+	for i := range rules {
+		m := matchRule(&rules[i], &probe, repo, env)
+		m.Env = nil
+		result.Rules = append(result.Rules, RuleMatch{
+			RuleID:                rules[i].ID,
+			RuleName:              rules[i].Name,
+			Action:                rules[i].Action,
+			WebhookRuleTestResult: m,
+		})
+	}
+
+	if execute {
+		if err := Retry(eventID); err != nil {
+			return nil, err
+		}
+		result.Executed = true
+	}
+	return result, nil
+}
+
+// cleanupPayloads 按保留天数清理原始报文
+func cleanupPayloads() {
+	days := configs.GlobalConfig.Webhook.PayloadRetentionDays
+	if days <= 0 {
+		return
+	}
+	n, err := db.NewWebhookEventPayloadDAO().DeleteBefore(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		log.Printf("[WARN] Webhook payload cleanup failed: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("[INFO] Cleaned up %d expired webhook payloads", n)
+	}
+}
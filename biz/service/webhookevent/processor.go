@@ -0,0 +1,206 @@
+package webhookevent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	stdsync "sync"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+	"github.com/yi-nology/git-manage-service/pkg/strutil"
+)
+
+const (
+	retryPollInterval = 15 * time.Second
+	retryBatchSize    = 20
+	maxRetryBackoff   = 6 * time.Hour
+)
+
+var retryWorkerOnce stdsync.Once
+
+// InitWorkers 启动失败事件的自动重试与原始报文清理协程，并恢复上次退出时处理中的事件
+func InitWorkers() {
+	retryWorkerOnce.Do(func() {
+		dao := db.NewWebhookEventDAO()
+		if n, err := dao.ResetProcessing(time.Now()); err != nil {
+			log.Printf("[WARN] Failed to recover processing webhook events: %v", err)
+		} else if n > 0 {
+			log.Printf("[INFO] Rescheduled %d interrupted webhook events", n)
+		}
+		go retryLoop()
+		go cleanupLoop()
+	})
+}
+
+func cleanupLoop() {
+	cleanupPayloads()
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		cleanupPayloads()
+	}
+}
+
+func retryLoop() {
+	ticker := time.NewTicker(retryPollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		events, err := db.NewWebhookEventDAO().FindDueRetries(time.Now(), retryBatchSize)
+		if err != nil {
+			log.Printf("[WARN] Failed to load webhook events for retry: %v", err)
+			continue
+		}
+		for i := range events {
+			e := events[i]
+			go processEvent(&e, storedEventEnv(&e), true)
+		}
+	}
+}
+
+// Retry 手动重放事件：重新执行所有匹配的规则，并重置自动重试计数
+func Retry(eventID uint) error {
+	dao := db.NewWebhookEventDAO()
+	event, err := dao.FindByID(eventID)
+	if err != nil {
+		return fmt.Errorf("event not found: %w", err)
+	}
+	if event.Status == po.WebhookEventStatusProcessing {
+		return fmt.Errorf("event is being processed")
+	}
+	event.Attempts = 0
+	event.NextRetryAt = nil
+	event.ErrorMessage = ""
+	event.Status = po.WebhookEventStatusPending
+	if err := dao.Save(event); err != nil {
+		return err
+	}
+	go processEvent(event, storedEventEnv(event), false)
+	return nil
+}
+
+// processEvent 抢占事件并执行匹配规则；onlyFailed 为 true 时跳过上次已成功的规则，避免自动重试重复执行
+func processEvent(event *po.WebhookEvent, env map[string]interface{}, onlyFailed bool) {
+	dao := db.NewWebhookEventDAO()
+	claimed, err := dao.Claim(event.ID, []string{po.WebhookEventStatusPending, po.WebhookEventStatusFailed})
+	if err != nil || !claimed {
+		return
+	}
+	event.Status = po.WebhookEventStatusProcessing
+
+	failures := applyRules(event, env, onlyFailed)
+
+	now := time.Now()
+	event.Attempts++
+	event.ProcessedAt = &now
+	event.NextRetryAt = nil
+	event.ErrorMessage = ""
+	if len(failures) == 0 {
+		event.Status = po.WebhookEventStatusSucceeded
+	} else {
+		event.ErrorMessage = strutil.Truncate(strings.Join(failures, "; "), 500)
+		if event.Attempts > maxRetries() {
+			event.Status = po.WebhookEventStatusDead
+			log.Printf("[WARN] Webhook event %s moved to dead letter after %d attempts", event.EventID, event.Attempts)
+		} else {
+			event.Status = po.WebhookEventStatusFailed
+			next := now.Add(retryDelay(event.Attempts))
+			event.NextRetryAt = &next
+		}
+	}
+	if err := dao.Save(event); err != nil {
+		log.Printf("[WARN] Failed to save webhook event %s: %v", event.EventID, err)
+	}
+}
+
+// applyRules 执行事件匹配的规则，返回失败描述
+func applyRules(event *po.WebhookEvent, env map[string]interface{}, onlyFailed bool) []string {
+	ruleDAO := db.NewWebhookRuleDAO()
+	rules, err := ruleDAO.FindByProviderConfigID(event.ProviderConfigID)
+	if err != nil {
+		return []string{"failed to load rules: " + err.Error()}
+	}
+
+	var repo *po.Repo
+	if event.RepoID > 0 {
+		if r, err := db.NewRepoDAO().FindByID(event.RepoID); err == nil {
+			repo = r
+		}
+	}
+
+	var succeeded map[uint]bool
+	if onlyFailed {
+		succeeded = succeededRules(event.ID)
+	}
+
+	ctx := context.Background()
+	var failures []string
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Enabled || succeeded[rule.ID] {
+			continue
+		}
+		match := matchRule(rule, event, repo, env)
+		if match.ConditionError != "" {
+			log.Printf("Webhook rule %s: condition error for event %s: %s", rule.Name, event.EventID, match.ConditionError)
+		}
+		if !match.Matched {
+			continue
+		}
+
+		exec := executeAction(ctx, rule, event, repo)
+		if exec.Status == po.ActionExecStatusFailed {
+			log.Printf("Webhook rule %s: %s action failed for event %s: %s", rule.Name, rule.Action, event.EventID, exec.ErrorMessage)
+			failures = append(failures, rule.Name+": "+exec.ErrorMessage)
+		}
+	}
+	return failures
+}
+
+// succeededRules 返回最近一次执行成功的规则 ID
+func succeededRules(eventID uint) map[uint]bool {
+	execs, err := db.NewWebhookActionExecutionDAO().FindByEventID(eventID)
+	if err != nil {
+		return nil
+	}
+	result := make(map[uint]bool)
+	for _, e := range execs {
+		result[e.RuleID] = e.Status == po.ActionExecStatusSuccess
+	}
+	return result
+}
+
+// storedEventEnv 为已存储的事件构造条件求值环境：优先重新解析原始报文，报文不可用时按事件记录还原
+func storedEventEnv(e *po.WebhookEvent) map[string]interface{} {
+	if ne, err := reparse(e); err == nil {
+		return EventEnv(ne)
+	}
+	return EventEnv(normalizedFromStored(e))
+}
+
+func maxRetries() int {
+	n := configs.GlobalConfig.Webhook.MaxRetries
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// retryDelay 指数退避：retry_backoff * 2^(attempts-1)，上限 maxRetryBackoff
+func retryDelay(attempts int) time.Duration {
+	base := time.Duration(configs.GlobalConfig.Webhook.RetryBackoff) * time.Second
+	if base <= 0 {
+		base = 30 * time.Second
+	}
+	delay := base
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	return delay
+}
@@ -0,0 +1,89 @@
+package webhookevent
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+)
+
+func TestRetryDelayAndMaxRetries(t *testing.T) {
+	old := configs.GlobalConfig.Webhook
+	defer func() { configs.GlobalConfig.Webhook = old }()
+
+	cases := []struct {
+		backoff, attempts int
+		want              time.Duration
+	}{
+		{0, 1, 30 * time.Second},
+		{-5, 1, 30 * time.Second},
+		{-5, 3, 2 * time.Minute},
+		{10, 0, 10 * time.Second},
+		{10, 1, 10 * time.Second},
+		{10, 3, 40 * time.Second},
+		{10, 100, maxRetryBackoff},
+	}
+	for _, c := range cases {
+		configs.GlobalConfig.Webhook.RetryBackoff = c.backoff
+		if got := retryDelay(c.attempts); got != c.want {
+			t.Errorf("retryDelay(%d) with backoff %d = %s, want %s", c.attempts, c.backoff, got, c.want)
+		}
+	}
+
+	for cfg, want := range map[int]int{-1: 0, 0: 0, 3: 3} {
+		configs.GlobalConfig.Webhook.MaxRetries = cfg
+		if got := maxRetries(); got != want {
+			t.Errorf("maxRetries() with %d = %d, want %d", cfg, got, want)
+		}
+	}
+}
+
+func TestProcessEventRetriesThenDeadLetters(t *testing.T) {
+	openTestDB(t, &po.WebhookEvent{}, &po.WebhookRule{}, &po.WebhookActionExecution{})
+	old := configs.GlobalConfig.Webhook
+	configs.GlobalConfig.Webhook.MaxRetries = 1
+	configs.GlobalConfig.Webhook.RetryBackoff = 10
+	defer func() { configs.GlobalConfig.Webhook = old }()
+
+	if err := db.DB.Create(&po.WebhookRule{Name: "broken", ProviderConfigID: 1, EventTypePattern: "*", Action: "missing", Enabled: true}).Error; err != nil {
+		t.Fatal(err)
+	}
+	event := &po.WebhookEvent{EventID: "evt-1", ProviderConfigID: 1, EventType: "push", Status: po.WebhookEventStatusPending}
+	if err := db.DB.Create(event).Error; err != nil {
+		t.Fatal(err)
+	}
+	dao := db.NewWebhookEventDAO()
+
+	before := time.Now()
+	processEvent(event, nil, false)
+	stored, _ := dao.FindByID(event.ID)
+	if stored.Status != po.WebhookEventStatusFailed || stored.Attempts != 1 || !strings.Contains(stored.ErrorMessage, "broken") {
+		t.Fatalf("first failure should schedule a retry: %+v", stored)
+	}
+	if stored.NextRetryAt == nil || stored.NextRetryAt.Before(before.Add(10*time.Second)) {
+		t.Errorf("retry should wait for the backoff: %v", stored.NextRetryAt)
+	}
+	if due, _ := dao.FindDueRetries(time.Now(), 10); len(due) != 0 {
+		t.Errorf("retry should not be due yet: %+v", due)
+	}
+
+	// 重试次数用尽后进入死信，不再安排重试
+	processEvent(stored, nil, true)
+	stored, _ = dao.FindByID(event.ID)
+	if stored.Status != po.WebhookEventStatusDead || stored.Attempts != 2 || stored.NextRetryAt != nil {
+		t.Fatalf("exhausted retries should dead-letter: %+v", stored)
+	}
+
+	// 死信事件不可被自动重试抢占
+	processEvent(stored, nil, true)
+	stored, _ = dao.FindByID(event.ID)
+	if stored.Status != po.WebhookEventStatusDead || stored.Attempts != 2 {
+		t.Errorf("dead event must not be reprocessed: %+v", stored)
+	}
+	if claimed, err := dao.Claim(event.ID, []string{po.WebhookEventStatusPending, po.WebhookEventStatusFailed}); err != nil || claimed {
+		t.Errorf("dead event claimed: %v %v", claimed, err)
+	}
+}
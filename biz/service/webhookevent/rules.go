@@ -0,0 +1,139 @@
+package webhookevent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+)
+
+func ListRules(providerConfigID uint) ([]api.WebhookRuleDTO, error) {
+	rules, err := db.NewWebhookRuleDAO().List(providerConfigID)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]api.WebhookRuleDTO, 0, len(rules))
+	for i := range rules {
+		dtos = append(dtos, toRuleDTO(&rules[i]))
+	}
+	return dtos, nil
+}
+
+func CreateRule(req *api.CreateWebhookRuleReq) (*api.WebhookRuleDTO, error) {
+	rule := &po.WebhookRule{}
+	applyRuleReq(rule, req)
+	if err := ValidateRule(rule); err != nil {
+		return nil, err
+	}
+	dao := db.NewWebhookRuleDAO()
+	if err := dao.Create(rule); err != nil {
+		return nil, err
+	}
+	// enabled 列带有 default:true，创建时 false 会被忽略，需要再写一次
+	if !req.Enabled {
+		rule.Enabled = false
+		if err := dao.Save(rule); err != nil {
+			return nil, err
+		}
+	}
+	dto := toRuleDTO(rule)
+	return &dto, nil
+}
+
+func UpdateRule(id uint, req *api.CreateWebhookRuleReq) (*api.WebhookRuleDTO, error) {
+	dao := db.NewWebhookRuleDAO()
+	rule, err := dao.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("rule not found: %w", err)
+	}
+	applyRuleReq(rule, req)
+	if err := ValidateRule(rule); err != nil {
+		return nil, err
+	}
+	if err := dao.Save(rule); err != nil {
+		return nil, err
+	}
+	dto := toRuleDTO(rule)
+	return &dto, nil
+}
+
+func DeleteRule(id uint) error {
+	return db.NewWebhookRuleDAO().Delete(id)
+}
+
+// ValidateRule 保存前校验规则：名称、动作类型与条件表达式
+func ValidateRule(rule *po.WebhookRule) error {
+	if strings.TrimSpace(rule.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if _, ok := actionHandlers[rule.Action]; !ok {
+		return fmt.Errorf("unsupported action: %s", rule.Action)
+	}
+	return ValidateCondition(rule.Condition)
+}
+
+// TestRule 使用已存储的事件对草稿规则求值，不执行动作；求值环境与实际处理、重放一致
+func TestRule(req *api.TestWebhookRuleReq) (*api.WebhookRuleTestResult, error) {
+	event, err := db.NewWebhookEventDAO().FindByID(req.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found: %w", err)
+	}
+	rule := &po.WebhookRule{}
+	applyRuleReq(rule, &req.Rule)
+	if err := ValidateCondition(rule.Condition); err != nil {
+		return nil, err
+	}
+
+	var repo *po.Repo
+	if event.RepoID > 0 {
+		if r, err := db.NewRepoDAO().FindByID(event.RepoID); err == nil {
+			repo = r
+		}
+	}
+	return matchRule(rule, event, repo, storedEventEnv(event)), nil
+}
+
+// matchRule 依次检查事件类型、仓库与条件表达式
+func matchRule(rule *po.WebhookRule, event *po.WebhookEvent, repo *po.Repo, env map[string]interface{}) *api.WebhookRuleTestResult {
+	result := &api.WebhookRuleTestResult{Env: env}
+	result.EventTypeMatched = matchPattern(rule.EventTypePattern, event.EventType)
+	result.RepoMatched = true
+	if rule.RepoPattern != "" && rule.RepoPattern != "*" && repo != nil {
+		result.RepoMatched = matchPattern(rule.RepoPattern, repo.PlatformOwner+"/"+repo.PlatformRepo)
+	}
+	ok, err := evalCondition(rule, env)
+	if err != nil {
+		result.ConditionError = err.Error()
+	}
+	result.ConditionResult = ok
+	result.Matched = result.EventTypeMatched && result.RepoMatched && result.ConditionResult
+	return result
+}
+
+func applyRuleReq(rule *po.WebhookRule, req *api.CreateWebhookRuleReq) {
+	rule.Name = req.Name
+	rule.ProviderConfigID = req.ProviderConfigID
+	rule.EventTypePattern = req.EventTypePattern
+	rule.RepoPattern = req.RepoPattern
+	rule.Condition = strings.TrimSpace(req.Condition)
+	rule.Action = req.Action
+	rule.ActionConfig = req.ActionConfig
+	rule.Enabled = req.Enabled
+}
+
+func toRuleDTO(r *po.WebhookRule) api.WebhookRuleDTO {
+	return api.WebhookRuleDTO{
+		ID:               r.ID,
+		Name:             r.Name,
+		ProviderConfigID: r.ProviderConfigID,
+		EventTypePattern: r.EventTypePattern,
+		RepoPattern:      r.RepoPattern,
+		Condition:        r.Condition,
+		Action:           r.Action,
+		ActionConfig:     r.ActionConfig,
+		Enabled:          r.Enabled,
+		CreatedAt:        r.CreatedAt,
+	}
+}
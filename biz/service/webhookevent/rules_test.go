@@ -0,0 +1,44 @@
+package webhookevent
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+)
+
+func TestTestRuleUsesStoredPayload(t *testing.T) {
+	conn := openTestDB(t, &po.ProviderConfig{}, &po.WebhookEvent{}, &po.WebhookEventPayload{})
+	oldRetention := configs.GlobalConfig.Webhook.PayloadRetentionDays
+	configs.GlobalConfig.Webhook.PayloadRetentionDays = 7
+	defer func() { configs.GlobalConfig.Webhook.PayloadRetentionDays = oldRetention }()
+
+	cfg := &po.ProviderConfig{Name: "ci", Platform: "generic", Options: map[string]interface{}{
+		"mapping": map[string]interface{}{"id": "$.id", "type": "build"},
+	}}
+	if err := conn.Create(cfg).Error; err != nil {
+		t.Fatal(err)
+	}
+	event := &po.WebhookEvent{EventID: "gen-1-7", ProviderConfigID: cfg.ID, EventType: "build", Source: "generic", Status: po.WebhookEventStatusSucceeded}
+	if err := conn.Create(event).Error; err != nil {
+		t.Fatal(err)
+	}
+	storePayload(event.ID, http.Header{"Content-Type": {"application/json"}}, []byte(`{"id":"7","build":{"status":"failed"}}`))
+
+	for cond, want := range map[string]bool{
+		`payload.build.status == "failed"`: true,
+		`payload.build.status == "ok"`:     false,
+	} {
+		result, err := TestRule(&api.TestWebhookRuleReq{EventID: event.ID, Rule: api.CreateWebhookRuleReq{
+			EventTypePattern: "build", Condition: cond, Action: "notify",
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Matched != want || result.ConditionError != "" {
+			t.Errorf("%s: matched=%v error=%q, want %v", cond, result.Matched, result.ConditionError, want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package webhookevent
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	stdsync "sync"
+	"time"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/api"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/service/provider"
+	"github.com/yi-nology/git-manage-service/biz/service/sync"
+	"github.com/yi-nology/git-manage-service/pkg/configs"
+)
+
+// pendingSync 去抖窗口内等待执行的同步任务
+type pendingSync struct {
+	timer    *time.Timer
+	routeIDs []uint
+}
+
+var (
+	pendingMu    stdsync.Mutex
+	pendingSyncs = make(map[string]*pendingSync)
+)
+
+// routePushEvent 将 push 事件路由到源仓库与分支匹配的已启用同步任务，并记录每个决策
+func routePushEvent(whEvent *po.WebhookEvent, event *provider.NormalizedEvent) {
+	if !configs.GlobalConfig.Webhook.AutoSync || event.Type != "push" || event.Branch == "" || event.Repo == nil {
+		return
+	}
+	tasks, err := db.NewSyncTaskDAO().FindEnabledWithRepos()
+	if err != nil {
+		log.Printf("[WARN] Failed to load sync tasks for webhook routing: %v", err)
+		return
+	}
+
+	routeDAO := db.NewWebhookSyncRouteDAO()
+	for i := range tasks {
+		task := &tasks[i]
+		if !sourceRepoMatches(&task.SourceRepo, whEvent.ProviderConfigID, event.Repo.FullName) {
+			continue
+		}
+		route := &po.WebhookSyncRoute{
+			WebhookEventID: whEvent.ID,
+			TaskKey:        task.Key,
+			Branch:         event.Branch,
+		}
+		if reason := branchMismatch(task, event.Branch); reason != "" {
+			route.Decision = po.SyncRouteSkipped
+			route.Reason = reason
+			_ = routeDAO.Create(route)
+			continue
+		}
+		route.Decision = po.SyncRouteQueued
+		if err := routeDAO.Create(route); err != nil {
+			log.Printf("[WARN] Failed to record sync route for task %s: %v", task.Key, err)
+		}
+		if scheduleSync(task.Key, route.ID) {
+			route.Decision = po.SyncRouteDebounced
+			route.Reason = "merged into pending sync"
+			_ = routeDAO.Save(route)
+		}
+	}
+}
+
+// sourceRepoMatches 按 PlatformOwner/PlatformRepo 匹配事件仓库；仓库绑定了 provider 时还需来自同一 provider
+func sourceRepoMatches(repo *po.Repo, providerCfgID uint, fullName string) bool {
+	if repo.PlatformOwner == "" || repo.PlatformRepo == "" {
+		return false
+	}
+	if repo.ProviderConfigID > 0 && repo.ProviderConfigID != providerCfgID {
+		return false
+	}
+	return strings.EqualFold(repo.PlatformOwner+"/"+repo.PlatformRepo, fullName)
+}
+
+// branchMismatch 返回不触发的原因；全分支模式匹配任意分支，单分支模式按 source_branch（支持 *）匹配
+func branchMismatch(task *po.SyncTask, branch string) string {
+	if task.SyncMode == "all-branch" {
+		return ""
+	}
+	if task.SourceBranch == "" {
+		return "task has no source branch"
+	}
+	if !matchPattern(task.SourceBranch, branch) {
+		return fmt.Sprintf("branch %s does not match %s", branch, task.SourceBranch)
+	}
+	return ""
+}
+
+// scheduleSync 在去抖窗口结束后执行任务；任务已在等待时顺延窗口并返回 true
+func scheduleSync(taskKey string, routeID uint) bool {
+	delay := time.Duration(configs.GlobalConfig.Webhook.SyncDebounce) * time.Second
+	if delay < 0 {
+		delay = 0
+	}
+
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+	if p, ok := pendingSyncs[taskKey]; ok {
+		p.timer.Reset(delay)
+		p.routeIDs = append(p.routeIDs, routeID)
+		return true
+	}
+	pendingSyncs[taskKey] = &pendingSync{
+		routeIDs: []uint{routeID},
+		timer:    time.AfterFunc(delay, func() { runRoutedSync(taskKey) }),
+	}
+	return false
+}
+
+func runRoutedSync(taskKey string) {
+	pendingMu.Lock()
+	p := pendingSyncs[taskKey]
+	delete(pendingSyncs, taskKey)
+	pendingMu.Unlock()
+	if p == nil {
+		return
+	}
+
+	routeDAO := db.NewWebhookSyncRouteDAO()
+	task, err := db.NewSyncTaskDAO().FindByKey(taskKey)
+	if err != nil || !task.Enabled {
+		_ = routeDAO.UpdateRunResult(p.routeIDs, 0, "cancelled")
+		return
+	}
+
+	run, syncErr := sync.NewSyncService().ExecuteSyncRun(task, po.TriggerSourceWebhook)
+	if syncErr != nil {
+		log.Printf("Webhook-triggered sync %s failed: %v", taskKey, syncErr)
+	}
+
+	var runID uint
+	status := "failed"
+	if syncErr == nil {
+		status = "success"
+	}
+	if run != nil && run.ID > 0 {
+		runID = run.ID
+		status = run.Status
+	}
+	if err := routeDAO.UpdateRunResult(p.routeIDs, runID, status); err != nil {
+		log.Printf("[WARN] Failed to update sync routes for task %s: %v", taskKey, err)
+	}
+}
+
+// ListSyncRoutes 列出事件的同步任务路由决策
+func ListSyncRoutes(eventID uint) ([]api.WebhookSyncRouteDTO, error) {
+	routes, err := db.NewWebhookSyncRouteDAO().FindByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]api.WebhookSyncRouteDTO, 0, len(routes))
+	for _, r := range routes {
+		dtos = append(dtos, api.WebhookSyncRouteDTO{
+			ID:        r.ID,
+			TaskKey:   r.TaskKey,
+			Branch:    r.Branch,
+			Decision:  r.Decision,
+			Reason:    r.Reason,
+			SyncRunID: r.SyncRunID,
+			RunStatus: r.RunStatus,
+			CreatedAt: r.CreatedAt,
+		})
+	}
+	return dtos, nil
+}
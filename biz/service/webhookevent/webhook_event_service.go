@@ -1,11 +1,10 @@
 package webhookevent
 
 import (
-	"fmt"
-	"log"
+	"encoding/json"
+	"net/http"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/yi-nology/git-manage-service/biz/dal/db"
 	"github.com/yi-nology/git-manage-service/biz/model/api"
@@ -26,18 +25,8 @@ func List(eventType, source, status string, page, pageSize int) ([]api.WebhookEv
 	return dtos, int(total), nil
 }
 
-func Retry(eventID uint) error {
-	dao := db.NewWebhookEventDAO()
-	var event po.WebhookEvent
-	if err := db.DB.First(&event, eventID).Error; err != nil {
-		return fmt.Errorf("event not found: %w", err)
-	}
-	event.Status = "received"
-	event.ErrorMessage = ""
-	return dao.Save(&event)
-}
-
-func ProcessIncomingEvent(event *provider.NormalizedEvent, providerCfgID uint) error {
+// ProcessIncomingEvent 记录事件及其原始请求，并异步执行匹配的规则
+func ProcessIncomingEvent(event *provider.NormalizedEvent, providerCfgID uint, headers http.Header, body []byte) error {
 	dao := db.NewWebhookEventDAO()
 	eventID := event.ID
 
@@ -89,6 +78,18 @@ func ProcessIncomingEvent(event *provider.NormalizedEvent, providerCfgID uint) e
 	if event.Tag != "" {
 		payload["tag"] = event.Tag
 	}
+	if event.Repo != nil {
+		payload["repo"] = event.Repo.FullName
+		payload["repo_owner"] = event.Repo.Owner
+		payload["repo_name"] = event.Repo.Name
+	}
+	if event.CR != nil {
+		payload["cr_title"] = event.CR.Title
+		payload["cr_state"] = string(event.CR.State)
+		payload["source_branch"] = event.CR.SourceBranch
+		payload["target_branch"] = event.CR.TargetBranch
+		payload["labels"] = event.CR.Labels
+	}
 
 	whEvent := &po.WebhookEvent{
 		EventID:          eventID,
@@ -101,62 +102,44 @@ func ProcessIncomingEvent(event *provider.NormalizedEvent, providerCfgID uint) e
 		ActorName:        actorName,
 		ActorUsername:    actorUsername,
 		Payload:          payload,
-		Status:           "received",
+		Status:           po.WebhookEventStatusPending,
 	}
 	if err := dao.Create(whEvent); err != nil {
 		return err
 	}
 
-	go applyRules(whEvent)
-
-	return nil
-}
-
-func applyRules(event *po.WebhookEvent) {
-	ruleDAO := db.NewWebhookRuleDAO()
-	rules, err := ruleDAO.FindByProviderConfigID(event.ProviderConfigID)
-	if err != nil {
-		return
-	}
-
-	for _, rule := range rules {
-		if !rule.Enabled {
-			continue
-		}
-		if !matchPattern(rule.EventTypePattern, event.EventType) {
-			continue
-		}
-		if rule.RepoPattern != "" && rule.RepoPattern != "*" {
-			repoDAO := db.NewRepoDAO()
-			if repo, err := repoDAO.FindByID(event.RepoID); err == nil {
-				fullName := repo.PlatformOwner + "/" + repo.PlatformRepo
-				if !matchPattern(rule.RepoPattern, fullName) {
-					continue
-				}
-			}
-		}
+	storePayload(whEvent.ID, headers, body)
+	routePushEvent(whEvent, event)
 
-		switch rule.Action {
-		case "sync":
-			triggerSync(rule.ActionConfig)
-		case "notify":
-			log.Printf("Webhook rule %s: notify action triggered for event %s", rule.Name, event.EventID)
-		}
+	if len(event.RawPayload) == 0 && json.Valid(body) {
+		event.RawPayload = body
 	}
+	go processEvent(whEvent, EventEnv(event), false)
 
-	now := time.Now()
-	event.Status = "processed"
-	event.ProcessedAt = &now
-	eventDAO := db.NewWebhookEventDAO()
-	eventDAO.Save(event)
+	return nil
 }
 
-func triggerSync(config map[string]interface{}) {
-	taskKey, _ := config["sync_task_key"].(string)
-	if taskKey == "" {
-		return
-	}
-	log.Printf("Webhook rule triggered sync for task: %s", taskKey)
+// ListExecutions 列出事件的规则动作执行记录
+func ListExecutions(eventID uint) ([]api.WebhookActionExecutionDTO, error) {
+	execs, err := db.NewWebhookActionExecutionDAO().FindByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]api.WebhookActionExecutionDTO, 0, len(execs))
+	for _, e := range execs {
+		dtos = append(dtos, api.WebhookActionExecutionDTO{
+			ID:           e.ID,
+			RuleID:       e.RuleID,
+			RuleName:     e.RuleName,
+			Action:       e.Action,
+			Status:       e.Status,
+			Result:       e.Result,
+			ErrorMessage: e.ErrorMessage,
+			StartedAt:    e.StartedAt,
+			FinishedAt:   e.FinishedAt,
+		})
+	}
+	return dtos, nil
 }
 
 func matchPattern(pattern, value string) bool {
@@ -183,6 +166,8 @@ func toEventDTO(e *po.WebhookEvent) api.WebhookEventDTO {
 		ActorName:        e.ActorName,
 		ActorUsername:    e.ActorUsername,
 		Status:           e.Status,
+		Attempts:         e.Attempts,
+		NextRetryAt:      e.NextRetryAt,
 		ProcessedAt:      e.ProcessedAt,
 		CreatedAt:        e.CreatedAt,
 		ErrorMessage:     e.ErrorMessage,
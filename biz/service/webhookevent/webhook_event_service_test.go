@@ -0,0 +1,75 @@
+package webhookevent
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sqlite "github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/yi-nology/git-manage-service/biz/dal/db"
+	"github.com/yi-nology/git-manage-service/biz/model/po"
+	"github.com/yi-nology/git-manage-service/biz/service/provider"
+)
+
+func TestProcessIncomingEventGenericSameIDAcrossConfigs(t *testing.T) {
+	openTestDB(t, &po.WebhookEvent{}, &po.WebhookEventPayload{}, &po.WebhookRule{}, &po.WebhookActionExecution{})
+
+	options := map[string]interface{}{"mapping": map[string]interface{}{"id": "$.delivery", "type": "push"}}
+	body := `{"delivery":"42"}`
+	ids := map[string]bool{}
+	for _, cfgID := range []uint{1, 2} {
+		p, err := provider.NewGenericProvider(cfgID, "", options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest("POST", "/api/webhooks/receive/1", strings.NewReader(body))
+		event, err := p.ParseWebhookEvent(req, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ProcessIncomingEvent(event, cfgID, req.Header, []byte(body)); err != nil {
+			t.Fatal(err)
+		}
+		ids[event.ID] = true
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected distinct event IDs per config, got %v", ids)
+	}
+	for id := range ids {
+		// 等待异步处理结束，避免测试结束后协程仍访问数据库
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			stored, err := db.NewWebhookEventDAO().FindByEventID(id)
+			if err != nil {
+				t.Fatalf("event %s not stored: %v", id, err)
+			}
+			if stored.Status == po.WebhookEventStatusSucceeded {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("event %s not processed, status %s", id, stored.Status)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+// openTestDB 使用临时 sqlite 数据库替换 db.DB，测试结束后恢复
+func openTestDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+	old := db.DB
+	db.DB = conn
+	t.Cleanup(func() { db.DB = old })
+	return conn
+}
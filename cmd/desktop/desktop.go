@@ -14,8 +14,11 @@ import (
 	"github.com/yi-nology/git-manage-service/biz/dal/db"
 	"github.com/yi-nology/git-manage-service/biz/router"
 	"github.com/yi-nology/git-manage-service/biz/service/audit"
+	"github.com/yi-nology/git-manage-service/biz/service/git"
+	"github.com/yi-nology/git-manage-service/biz/service/notification"
 	"github.com/yi-nology/git-manage-service/biz/service/stats"
 	"github.com/yi-nology/git-manage-service/biz/service/sync"
+	"github.com/yi-nology/git-manage-service/biz/service/webhookevent"
 	"github.com/yi-nology/git-manage-service/biz/utils"
 	"github.com/yi-nology/git-manage-service/pkg/appinfo"
 	"github.com/yi-nology/git-manage-service/pkg/configs"
@@ -118,6 +121,9 @@ func (a *App) startBackend() {
 	sync.InitCronService()
 	stats.InitStatsService()
 	audit.InitAuditService()
+	git.GlobalTaskManager.Init()
+	webhookevent.InitWorkers()
+	notification.InitWorkers()
 
 	// 设置嵌入的文件系统（供 API 路由使用）
 	router.SetEmbedFS(embed.GetPublicFS(), embed.GetDocsFS())
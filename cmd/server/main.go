@@ -20,8 +20,11 @@ import (
 	"github.com/yi-nology/git-manage-service/biz/router"
 	"github.com/yi-nology/git-manage-service/biz/rpc_handler"
 	"github.com/yi-nology/git-manage-service/biz/service/audit"
+	"github.com/yi-nology/git-manage-service/biz/service/git"
+	"github.com/yi-nology/git-manage-service/biz/service/notification"
 	"github.com/yi-nology/git-manage-service/biz/service/stats"
 	"github.com/yi-nology/git-manage-service/biz/service/sync"
+	"github.com/yi-nology/git-manage-service/biz/service/webhookevent"
 	"github.com/yi-nology/git-manage-service/biz/utils"
 	"github.com/yi-nology/git-manage-service/pkg/appinfo"
 	"github.com/yi-nology/git-manage-service/pkg/configs"
@@ -131,6 +134,9 @@ func initResources() {
 	sync.InitCronService()
 	stats.InitStatsService()
 	audit.InitAuditService()
+	git.GlobalTaskManager.Init()
+	webhookevent.InitWorkers()
+	notification.InitWorkers()
 
 	log.Println("Resources initialized successfully")
 }
@@ -25,6 +25,11 @@ func LoadConfig(configPaths []string, configName string, configType string) (Con
 	v.SetDefault("webhook.secret", "my-secret-key")
 	v.SetDefault("webhook.rate_limit", 100)
 	v.SetDefault("webhook.ip_whitelist", []string{})
+	v.SetDefault("webhook.max_retries", 5)
+	v.SetDefault("webhook.retry_backoff", 30)
+	v.SetDefault("webhook.payload_retention_days", 30)
+	v.SetDefault("webhook.auto_sync", true)
+	v.SetDefault("webhook.sync_debounce", 10)
 
 	// Storage defaults (本地存储优先)
 	v.SetDefault("storage.type", "local")
@@ -39,6 +44,19 @@ func LoadConfig(configPaths []string, configName string, configType string) (Con
 	v.SetDefault("lock.type", "memory")
 	v.SetDefault("lock.redis_db", 0)
 
+	// Task executor defaults
+	v.SetDefault("task.max_concurrent", 4)
+	v.SetDefault("task.queue_size", 1000)
+	v.SetDefault("task.retention_days", 7)
+
+	// Notification delivery defaults
+	v.SetDefault("notification.max_retries", 3)
+	v.SetDefault("notification.retry_backoff", 30)
+	v.SetDefault("notification.retention_days", 30)
+	v.SetDefault("notification.alert_dedup", true)
+	v.SetDefault("notification.alert_reminder_minutes", 60)
+	v.SetDefault("notification.escalate_after", 3)
+
 	// Environment variables override
 	// 支持环境变量覆盖，如 STORAGE_TYPE, LOCK_REDIS_ADDR 等
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
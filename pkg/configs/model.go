@@ -8,6 +8,9 @@ type Config struct {
 	Storage  StorageConfig  `mapstructure:"storage"`
 	Lock     LockConfig     `mapstructure:"lock"`
 	Lint     LintConfig     `mapstructure:"lint"`
+	Task     TaskConfig     `mapstructure:"task"`
+
+	Notification NotificationConfig `mapstructure:"notification"`
 }
 
 type ServerConfig struct {
@@ -30,9 +33,16 @@ type DatabaseConfig struct {
 }
 
 type WebhookConfig struct {
-	Secret      string   `mapstructure:"secret"`
-	RateLimit   int      `mapstructure:"rate_limit"`
-	IPWhitelist []string `mapstructure:"ip_whitelist"`
+	Secret       string   `mapstructure:"secret"`
+	RateLimit    int      `mapstructure:"rate_limit"`
+	IPWhitelist  []string `mapstructure:"ip_whitelist"`
+	MaxRetries   int      `mapstructure:"max_retries"`   // 事件处理失败后的自动重试次数，超过后进入死信
+	RetryBackoff int      `mapstructure:"retry_backoff"` // 首次重试间隔（秒），之后按指数增长
+	// 原始报文（已脱敏、压缩）保留天数，0 表示不保存
+	PayloadRetentionDays int `mapstructure:"payload_retention_days"`
+	// push 事件自动触发源仓库与分支匹配的同步任务
+	AutoSync     bool `mapstructure:"auto_sync"`
+	SyncDebounce int  `mapstructure:"sync_debounce"` // 同一任务的去抖窗口（秒），窗口内的多次 push 只执行一次
 }
 
 // StorageConfig 对象存储配置
@@ -60,3 +70,21 @@ type LockConfig struct {
 type LintConfig struct {
 	EnableRpmlint bool `mapstructure:"enable_rpmlint"` // 是否启用 rpmlint（仅当系统安装时生效）
 }
+
+// TaskConfig 异步任务执行器配置（克隆、拉取、备份）
+type TaskConfig struct {
+	MaxConcurrent int `mapstructure:"max_concurrent"` // 最大并发执行数
+	QueueSize     int `mapstructure:"queue_size"`     // 排队任务上限
+	RetentionDays int `mapstructure:"retention_days"` // 已结束任务的保留天数
+}
+
+// NotificationConfig 通知投递重试与告警配置
+type NotificationConfig struct {
+	MaxRetries    int `mapstructure:"max_retries"`    // 发送失败后的自动重试次数，超过后标记为 dead
+	RetryBackoff  int `mapstructure:"retry_backoff"`  // 首次重试间隔（秒），之后按指数增长
+	RetentionDays int `mapstructure:"retention_days"` // 投递记录与已恢复告警的保留天数，0 表示不清理
+
+	AlertDedup           bool `mapstructure:"alert_dedup"`            // 同一任务同一失败事件只在首次发送，之后按提醒间隔发送
+	AlertReminderMinutes int  `mapstructure:"alert_reminder_minutes"` // 持续失败的提醒间隔（分钟），0 表示不提醒
+	EscalateAfter        int  `mapstructure:"escalate_after"`         // 连续失败达到次数后升级到升级渠道，0 表示不升级
+}
@@ -0,0 +1,346 @@
+package expr
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type node interface {
+	eval(env map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+type identNode struct {
+	path []string
+}
+
+func newIdentNode(name string) *identNode {
+	return &identNode{path: strings.Split(name, ".")}
+}
+
+func (n *identNode) eval(env map[string]interface{}) (interface{}, error) {
+	var cur interface{} = env
+	for _, key := range n.path {
+		cur = lookup(cur, key)
+		if cur == nil {
+			return nil, nil
+		}
+	}
+	return normalize(cur), nil
+}
+
+type listNode struct {
+	items []node
+}
+
+func (n *listNode) eval(env map[string]interface{}) (interface{}, error) {
+	out := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		v, err := item.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(env map[string]interface{}) (interface{}, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return !truthy(v), nil
+}
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+func (n *logicalNode) eval(env map[string]interface{}) (interface{}, error) {
+	l, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	// 短路求值
+	if n.op == "&&" && !truthy(l) {
+		return false, nil
+	}
+	if n.op == "||" && truthy(l) {
+		return true, nil
+	}
+	r, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return truthy(r), nil
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+	re          *regexp.Regexp // 右侧为字面量时预编译的 matches / =~ 正则
+}
+
+// precompile 右侧为字面量时提前编译正则，保存规则时即可发现错误
+func (n *compareNode) precompile() error {
+	lit, ok := n.right.(*literalNode)
+	if !ok {
+		return nil
+	}
+	s, isStr := lit.value.(string)
+	switch n.op {
+	case "matches":
+		if !isStr {
+			return fmt.Errorf("matches requires a string pattern")
+		}
+		n.re = globToRegexp(s)
+	case "=~":
+		if !isStr {
+			return fmt.Errorf("=~ requires a string pattern")
+		}
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return fmt.Errorf("invalid regexp: %w", err)
+		}
+		n.re = re
+	}
+	return nil
+}
+
+func (n *compareNode) eval(env map[string]interface{}) (interface{}, error) {
+	l, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	case "<", "<=", ">", ">=":
+		return ordered(n.op, l, r)
+	case "matches", "=~":
+		if l == nil {
+			return false, nil
+		}
+		re := n.re
+		if re == nil {
+			pattern, ok := r.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s requires a string pattern", n.op)
+			}
+			if n.op == "matches" {
+				re = globToRegexp(pattern)
+			} else if re, err = regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("invalid regexp: %w", err)
+			}
+		}
+		return re.MatchString(toString(l)), nil
+	case "contains":
+		return contains(l, r), nil
+	case "in":
+		return contains(r, l), nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", n.op)
+}
+
+// lookup 在 map 或切片中按 key 取值
+func lookup(v interface{}, key string) interface{} {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m[key]
+	case map[string]string:
+		if s, ok := m[key]; ok {
+			return s
+		}
+		return nil
+	case []interface{}:
+		if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(m) {
+			return m[i]
+		}
+	case []string:
+		if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(m) {
+			return m[i]
+		}
+	}
+	return nil
+}
+
+// normalize 将数值统一为 float64、字符串切片统一为 []interface{}
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case int:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case uint:
+		return float64(x)
+	case float32:
+		return float64(x)
+	case []string:
+		out := make([]interface{}, len(x))
+		for i, s := range x {
+			out[i] = s
+		}
+		return out
+	}
+	return v
+}
+
+func truthy(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case string:
+		return x != ""
+	case float64:
+		return x != 0
+	case []interface{}:
+		return len(x) > 0
+	case map[string]interface{}:
+		return len(x) > 0
+	}
+	return true
+}
+
+func equal(l, r interface{}) bool {
+	if l == nil || r == nil {
+		return l == nil && r == nil
+	}
+	if lf, ok := l.(float64); ok {
+		if rf, ok := toNumber(r); ok {
+			return lf == rf
+		}
+	}
+	if rf, ok := r.(float64); ok {
+		if lf, ok := toNumber(l); ok {
+			return lf == rf
+		}
+	}
+	if reflect.TypeOf(l) == reflect.TypeOf(r) {
+		switch l.(type) {
+		case string, bool:
+			return l == r
+		}
+	}
+	return reflect.DeepEqual(l, r)
+}
+
+func ordered(op string, l, r interface{}) (interface{}, error) {
+	lf, lok := toNumber(l)
+	rf, rok := toNumber(r)
+	if lok && rok {
+		switch op {
+		case "<":
+			return lf < rf, nil
+		case "<=":
+			return lf <= rf, nil
+		case ">":
+			return lf > rf, nil
+		default:
+			return lf >= rf, nil
+		}
+	}
+	ls, lIsStr := l.(string)
+	rs, rIsStr := r.(string)
+	if !lIsStr || !rIsStr {
+		return false, nil
+	}
+	switch op {
+	case "<":
+		return ls < rs, nil
+	case "<=":
+		return ls <= rs, nil
+	case ">":
+		return ls > rs, nil
+	default:
+		return ls >= rs, nil
+	}
+}
+
+// contains 字符串子串判断或列表成员判断
+func contains(container, item interface{}) bool {
+	switch c := container.(type) {
+	case string:
+		if item == nil {
+			return false
+		}
+		return strings.Contains(c, toString(item))
+	case []interface{}:
+		for _, v := range c {
+			if equal(normalize(v), item) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		if s, ok := item.(string); ok {
+			_, exists := c[s]
+			return exists
+		}
+	}
+	return false
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case string:
+		f, err := strconv.ParseFloat(x, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toString(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// globToRegexp 将 glob 转换为正则：* 匹配任意字符，? 匹配单个字符
+func globToRegexp(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, c := range pattern {
+		switch c {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
@@ -0,0 +1,237 @@
+// Package expr 一个小型的沙箱条件表达式语言，用于规则匹配
+//
+// 语法示例:
+//
+//	event.branch matches "release/*" && actor != "bot"
+//	"hotfix" in labels || payload.object_attributes.action == "merge"
+//	!(tag =~ "^v[0-9]+\\.[0-9]+$")
+//
+// 支持的运算符: && (and), || (or), ! (not), ==, !=, <, <=, >, >=,
+// matches (glob, * 与 ?), =~ (正则), contains, in。
+// 标识符以 . 访问嵌套字段，不存在的字段求值为 null。表达式不支持函数调用，也不会修改环境。
+package expr
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	maxSourceLength = 4096
+	maxDepth        = 64
+)
+
+// Expr 编译后的表达式
+type Expr struct {
+	src  string
+	root node
+}
+
+// Compile 解析表达式，语法错误时返回带位置信息的错误
+func Compile(src string) (*Expr, error) {
+	if len(src) > maxSourceLength {
+		return nil, fmt.Errorf("expression too long (max %d characters)", maxSourceLength)
+	}
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("position %d: unexpected %q", t.pos, t.text)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// Validate 仅检查表达式语法
+func Validate(src string) error {
+	_, err := Compile(src)
+	return err
+}
+
+// String 返回表达式源码
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval 在给定环境中求值，结果按真值规则转换为 bool
+func (e *Expr) Eval(env map[string]interface{}) (bool, error) {
+	v, err := e.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	return truthy(v), nil
+}
+
+// Eval 编译并求值；空表达式视为 true
+func Eval(src string, env map[string]interface{}) (bool, error) {
+	if src == "" {
+		return true, nil
+	}
+	e, err := Compile(src)
+	if err != nil {
+		return false, err
+	}
+	return e.Eval(env)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isOp(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseOr(depth int) (node, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("expression nested too deeply")
+	}
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||", "or") {
+		p.next()
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (node, error) {
+	left, err := p.parseNot(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&", "and") {
+		p.next()
+		right, err := p.parseNot(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot(depth int) (node, error) {
+	if p.isOp("!", "not") {
+		p.next()
+		if depth > maxDepth {
+			return nil, fmt.Errorf("expression nested too deeply")
+		}
+		operand, err := p.parseNot(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseCompare(depth)
+}
+
+func (p *parser) parseCompare(depth int) (node, error) {
+	left, err := p.parsePrimary(depth)
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp("==", "!=", "<", "<=", ">", ">=", "=~", "matches", "contains", "in") {
+		opTok := p.next()
+		right, err := p.parsePrimary(depth)
+		if err != nil {
+			return nil, err
+		}
+		n := &compareNode{op: opTok.text, left: left, right: right}
+		if err := n.precompile(); err != nil {
+			return nil, fmt.Errorf("position %d: %w", opTok.pos, err)
+		}
+		return n, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parsePrimary(depth int) (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return &literalNode{value: t.text}, nil
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("position %d: invalid number %q", t.pos, t.text)
+		}
+		return &literalNode{value: f}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null", "nil":
+			return &literalNode{value: nil}, nil
+		}
+		return newIdentNode(t.text), nil
+	case tokLParen:
+		inner, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, fmt.Errorf("position %d: expected ')'", closing.pos)
+		}
+		return inner, nil
+	case tokLBracket:
+		list := &listNode{}
+		if p.peek().kind == tokRBracket {
+			p.next()
+			return list, nil
+		}
+		for {
+			item, err := p.parsePrimary(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			list.items = append(list.items, item)
+			sep := p.next()
+			if sep.kind == tokRBracket {
+				return list, nil
+			}
+			if sep.kind != tokComma {
+				return nil, fmt.Errorf("position %d: expected ',' or ']'", sep.pos)
+			}
+		}
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("position %d: unexpected %q", t.pos, t.text)
+	}
+}
@@ -0,0 +1,53 @@
+package expr
+
+import "testing"
+
+func TestEval(t *testing.T) {
+	env := map[string]interface{}{
+		"event": map[string]interface{}{
+			"branch": "release/1.2",
+			"labels": []string{"hotfix", "backend"},
+		},
+		"actor": "alice",
+		"payload": map[string]interface{}{
+			"object_attributes": map[string]interface{}{"action": "merge", "iid": float64(42)},
+		},
+	}
+
+	cases := []struct {
+		src  string
+		want bool
+	}{
+		{`event.branch matches "release/*" && actor != "bot"`, true},
+		{`event.branch matches "main"`, false},
+		{`"hotfix" in event.labels`, true},
+		{`event.labels contains "frontend"`, false},
+		{`payload.object_attributes.action == "merge" and payload.object_attributes.iid >= 40`, true},
+		{`!(actor =~ "^ali") || missing.field == null`, true},
+		{`actor in ["bob", 'carol']`, false},
+		{`missing`, false},
+	}
+	for _, c := range cases {
+		got, err := Eval(c.src, env)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.src, err)
+		}
+		if got != c.want {
+			t.Errorf("%s: got %v, want %v", c.src, got, c.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, src := range []string{
+		`actor ==`,
+		`(actor == "a"`,
+		`actor =~ "["`,
+		`actor == "unterminated`,
+		`actor @ "x"`,
+	} {
+		if _, err := Compile(src); err == nil {
+			t.Errorf("%s: expected compile error", src)
+		}
+	}
+}
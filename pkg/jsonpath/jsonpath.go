@@ -0,0 +1,184 @@
+// Package jsonpath 实现 JSONPath 的常用子集，用于从任意 JSON 报文中提取字段。
+//
+// 支持：根 $、成员 .name / ['name']、下标 [0] / [-1]、通配 .* / [*]、递归 ..name。
+// 包含通配或递归的路径返回所有匹配值组成的切片。
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepWildcard
+	stepRecursive
+)
+
+type step struct {
+	kind  stepKind
+	name  string
+	index int
+}
+
+// Path 已编译的 JSONPath 表达式
+type Path struct {
+	src   string
+	steps []step
+	multi bool
+}
+
+// Compile 编译 JSONPath 表达式
+func Compile(src string) (*Path, error) {
+	s := strings.TrimSpace(src)
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("jsonpath %q must start with $", src)
+	}
+	p := &Path{src: src}
+	i := 1
+	for i < len(s) {
+		switch s[i] {
+		case '.':
+			recursive := i+1 < len(s) && s[i+1] == '.'
+			if recursive {
+				i++
+			}
+			i++
+			start := i
+			for i < len(s) && s[i] != '.' && s[i] != '[' {
+				i++
+			}
+			name := s[start:i]
+			if name == "" {
+				return nil, fmt.Errorf("jsonpath %q: empty member name at %d", src, start)
+			}
+			if recursive {
+				p.steps = append(p.steps, step{kind: stepRecursive, name: name})
+				p.multi = true
+			} else if name == "*" {
+				p.steps = append(p.steps, step{kind: stepWildcard})
+				p.multi = true
+			} else {
+				p.steps = append(p.steps, step{kind: stepField, name: name})
+			}
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath %q: unclosed [", src)
+			}
+			inner := strings.TrimSpace(s[i+1 : i+end])
+			i += end + 1
+			switch {
+			case inner == "*":
+				p.steps = append(p.steps, step{kind: stepWildcard})
+				p.multi = true
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				p.steps = append(p.steps, step{kind: stepField, name: inner[1 : len(inner)-1]})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("jsonpath %q: invalid subscript [%s]", src, inner)
+				}
+				p.steps = append(p.steps, step{kind: stepIndex, index: n})
+			}
+		default:
+			return nil, fmt.Errorf("jsonpath %q: unexpected %q at %d", src, s[i], i)
+		}
+	}
+	return p, nil
+}
+
+// MustCompile 编译失败时 panic
+func MustCompile(src string) *Path {
+	p, err := Compile(src)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p *Path) String() string { return p.src }
+
+// Get 在 doc（encoding/json 解码结果）上求值；未找到时 ok 为 false
+func (p *Path) Get(doc interface{}) (interface{}, bool) {
+	nodes := []interface{}{doc}
+	for _, st := range p.steps {
+		var next []interface{}
+		for _, n := range nodes {
+			next = apply(st, n, next)
+		}
+		nodes = next
+		if len(nodes) == 0 {
+			return nil, false
+		}
+	}
+	if p.multi {
+		return nodes, true
+	}
+	return nodes[0], true
+}
+
+// Get 编译并求值，便于一次性调用
+func Get(doc interface{}, src string) (interface{}, bool, error) {
+	p, err := Compile(src)
+	if err != nil {
+		return nil, false, err
+	}
+	v, ok := p.Get(doc)
+	return v, ok, nil
+}
+
+func apply(st step, n interface{}, out []interface{}) []interface{} {
+	switch st.kind {
+	case stepField:
+		if m, ok := n.(map[string]interface{}); ok {
+			if v, ok := m[st.name]; ok {
+				out = append(out, v)
+			}
+		}
+	case stepIndex:
+		if a, ok := n.([]interface{}); ok {
+			idx := st.index
+			if idx < 0 {
+				idx += len(a)
+			}
+			if idx >= 0 && idx < len(a) {
+				out = append(out, a[idx])
+			}
+		}
+	case stepWildcard:
+		switch x := n.(type) {
+		case map[string]interface{}:
+			for _, v := range x {
+				out = append(out, v)
+			}
+		case []interface{}:
+			out = append(out, x...)
+		}
+	case stepRecursive:
+		out = collect(st.name, n, out)
+	}
+	return out
+}
+
+// collect 深度优先收集所有名为 name 的成员
+func collect(name string, n interface{}, out []interface{}) []interface{} {
+	switch x := n.(type) {
+	case map[string]interface{}:
+		if v, ok := x[name]; ok {
+			out = append(out, v)
+		}
+		for _, v := range x {
+			out = collect(name, v, out)
+		}
+	case []interface{}:
+		for _, v := range x {
+			out = collect(name, v, out)
+		}
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	var doc interface{}
+	raw := `{"build":{"id":17,"status":"ok","ref":"refs/heads/main"},
+		"project":{"path with space":"infra/ci"},
+		"commits":[{"id":"a1","author":{"name":"alice"}},{"id":"b2","author":{"name":"bob"}}]}`
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		path string
+		want interface{}
+		ok   bool
+	}{
+		{"$.build.id", float64(17), true},
+		{"$['project']['path with space']", "infra/ci", true},
+		{"$.commits[0].id", "a1", true},
+		{"$.commits[-1].author.name", "bob", true},
+		{"$.commits[*].id", []interface{}{"a1", "b2"}, true},
+		{"$..name", []interface{}{"alice", "bob"}, true},
+		{"$.build.missing", nil, false},
+		{"$.commits[5]", nil, false},
+	}
+	for _, c := range cases {
+		got, ok, err := Get(doc, c.path)
+		if err != nil {
+			t.Fatalf("%s: %v", c.path, err)
+		}
+		if ok != c.ok || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s = %v (%v), want %v (%v)", c.path, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, src := range []string{"build.id", "$.", "$.a[", "$.a[x]", "$a"} {
+		if _, err := Compile(src); err == nil {
+			t.Errorf("Compile(%q) expected error", src)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// Package strutil 提供字符串工具函数。
+package strutil
+
+import "unicode/utf8"
+
+// Truncate 截断到最多 maxRunes 个字符，按字符边界截断，避免写入数据库的文本出现非法 UTF-8
+func Truncate(s string, maxRunes int) string {
+	if maxRunes <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	n := 0
+	for i := range s {
+		if n == maxRunes {
+			return s[:i]
+		}
+		n++
+	}
+	return s
+}
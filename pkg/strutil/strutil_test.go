@@ -0,0 +1,26 @@
+package strutil
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 3, "hel"},
+		{"推送失败：远程拒绝", 4, "推送失败"},
+		{"ab推送", 3, "ab推"},
+		{"推送", 0, ""},
+	}
+	for _, c := range cases {
+		got := Truncate(c.in, c.max)
+		if got != c.want || !utf8.ValidString(got) {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", c.in, c.max, got, c.want)
+		}
+	}
+}